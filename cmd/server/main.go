@@ -4,11 +4,22 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -19,10 +30,14 @@ import (
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/stuartshay/otel-worker/internal/config"
 	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/download"
 	grpcserver "github.com/stuartshay/otel-worker/internal/grpc"
+	"github.com/stuartshay/otel-worker/internal/storage"
 	"github.com/stuartshay/otel-worker/internal/tracing"
 	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
 )
@@ -47,12 +62,21 @@ func main() { // nolint:gocyclo // Main function complexity is acceptable for se
 		Str("service_name", cfg.ServiceName).
 		Str("environment", cfg.Environment).
 		Str("grpc_port", cfg.GRPCPort).
+		Str("location_source", cfg.LocationSource).
 		Str("db_host", cfg.PostgresHost).
 		Str("db_port", cfg.PostgresPort).
 		Float64("home_lat", cfg.HomeLatitude).
 		Float64("home_lon", cfg.HomeLongitude).
 		Msg("Configuration loaded")
 
+	// Subcommands run once and exit instead of serving
+	if len(os.Args) > 1 {
+		if err := runCommand(cfg, os.Args[1]); err != nil {
+			log.Fatal().Err(err).Str("command", os.Args[1]).Msg("Command failed")
+		}
+		return
+	}
+
 	// Initialize OpenTelemetry tracing
 	var shutdownTracer func(context.Context) error
 	if cfg.OTELEnabled {
@@ -85,30 +109,9 @@ func main() { // nolint:gocyclo // Main function complexity is acceptable for se
 		}
 	}()
 
-	// Initialize database client
-	dbClient, err := database.NewClient(cfg.DatabaseDSN())
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to initialize database client") // nolint:gocritic // Fatal error before service starts is acceptable
-	}
-	defer func() {
-		if closeErr := dbClient.Close(); closeErr != nil {
-			log.Error().Err(closeErr).Msg("Failed to close database connection")
-		}
-	}()
-
-	log.Info().Msg("Database connection established")
-
-	// Verify database connectivity
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-
-	if healthErr := dbClient.HealthCheck(ctx); healthErr != nil {
-		cancel()
-		log.Error().Err(healthErr).Msg("Database health check failed")
-		os.Exit(1) // nolint:gocritic // Immediate exit on health check failure is intentional
-	}
-	cancel()
-
-	log.Info().Msg("Database health check passed")
+	// Initialize the location store
+	store, closeStore := openLocationStore(cfg)
+	defer closeStore()
 
 	// Initialize gRPC server with OpenTelemetry interceptors
 	var serverOpts []grpc.ServerOption
@@ -119,8 +122,23 @@ func main() { // nolint:gocyclo // Main function complexity is acceptable for se
 	}
 	grpcServer := grpc.NewServer(serverOpts...)
 
+	// Initialize the store job outputs are written to and downloaded from
+	artifacts := openArtifactStore(cfg)
+
 	// Register distance service
-	distanceServer := grpcserver.NewServer(cfg, dbClient)
+	distanceServer, err := grpcserver.NewServer(cfg, store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create distance service")
+	}
+	distanceServer.SetArtifactStore(artifacts)
+	reconcileCtx, cancelReconcile := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := distanceServer.ReconcileArtifacts(reconcileCtx); err != nil {
+		log.Warn().Err(err).Msg("Failed to reconcile artifact catalog: only new artifacts will be listed")
+	}
+	cancelReconcile()
+	if cfg.DownloadSigningKey == "" {
+		log.Warn().Msg("DOWNLOAD_SIGNING_KEY not set: download URLs work only on this replica until it restarts")
+	}
 	distancev1.RegisterDistanceServiceServer(grpcServer, distanceServer)
 
 	// Register health check service
@@ -144,6 +162,16 @@ func main() { // nolint:gocyclo // Main function complexity is acceptable for se
 		}
 	}()
 
+	// Follow new locations in incremental mode; a no-op when it is off
+	incrementalCtx, stopIncremental := context.WithCancel(context.Background())
+	incrementalDone := make(chan struct{})
+	go func() {
+		defer close(incrementalDone)
+		if err := distanceServer.RunIncremental(incrementalCtx); err != nil {
+			log.Error().Err(err).Msg("Incremental mode failed")
+		}
+	}()
+
 	// Start HTTP health check server
 	httpServer := &http.Server{
 		Addr:              fmt.Sprintf(":%s", cfg.HTTPPort),
@@ -161,45 +189,17 @@ func main() { // nolint:gocyclo // Main function complexity is acceptable for se
 		_, _ = w.Write([]byte(`{"status":"healthy","service":"otel-worker"}`)) //nolint:errcheck // HTTP response write failure is not recoverable
 	})
 
-	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
-		// Readiness probe - checks database connectivity
-		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
-		defer cancel()
+	http.HandleFunc("/readyz", readyzHandler(store, newSchemaCache(store)))
 
-		if err := dbClient.HealthCheck(ctx); err != nil {
-			log.Warn().Err(err).Msg("Readiness check failed: database unhealthy")
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusServiceUnavailable)
-			_, _ = fmt.Fprintf(w, `{"status":"not_ready","reason":"database_unavailable","error":"%s"}`, err.Error()) //nolint:errcheck // HTTP response write failure is not recoverable
-			return
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"status":"ready","service":"otel-worker","database":"connected"}`)) //nolint:errcheck // HTTP response write failure is not recoverable
-	})
-
-	// CSV download endpoint
-	http.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
-		// Extract filename from path
-		filename := r.URL.Path[len("/download/"):]
-		if filename == "" {
-			http.Error(w, "Filename required", http.StatusBadRequest)
-			return
-		}
-
-		// Security: only allow distance_*.csv files
-		if len(filename) < 13 || filename[:9] != "distance_" || filename[len(filename)-4:] != ".csv" {
-			http.Error(w, "Invalid filename format", http.StatusBadRequest)
-			return
-		}
+	// Job output download endpoint
+	http.HandleFunc("/download/", downloadHandler(artifacts, distanceServer.DownloadSigner()))
 
-		// Construct file path using configured CSV output path
-		csvPath := fmt.Sprintf("%s/%s", cfg.CSVOutputPath, filename)
-
-		// Serve the file
-		http.ServeFile(w, r, csvPath)
-	})
+	// Artifact catalog endpoints, only with a token to authenticate callers
+	if cfg.ArtifactAPIToken != "" {
+		registerArtifactRoutes(http.DefaultServeMux, distanceServer, cfg.ArtifactAPIToken)
+	} else {
+		log.Info().Msg("ARTIFACT_API_TOKEN not set: artifact catalog served over gRPC only")
+	}
 
 	go func() {
 		log.Info().Str("port", cfg.HTTPPort).Msg("HTTP health server listening")
@@ -241,6 +241,10 @@ func main() { // nolint:gocyclo // Main function complexity is acceptable for se
 		log.Info().Msg("gRPC server stopped")
 	}
 
+	// Stop following new locations
+	stopIncremental()
+	<-incrementalDone
+
 	// Shutdown distance service workers
 	if err := distanceServer.Shutdown(10 * time.Second); err != nil {
 		log.Error().Err(err).Msg("Failed to shutdown distance service")
@@ -249,6 +253,587 @@ func main() { // nolint:gocyclo // Main function complexity is acceptable for se
 	log.Info().Msg("Service shutdown complete")
 }
 
+// schemaCheckInterval is how long /readyz reuses the result of a schema
+// contract check
+const schemaCheckInterval = time.Minute
+
+// schemaCache runs the schema contract check at most once per
+// schemaCheckInterval, since it queries information_schema. Only a passing
+// check or a mismatch is kept; other errors are retried on the next probe.
+type schemaCache struct {
+	checker database.SchemaChecker
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// newSchemaCache returns a schemaCache for store, or nil when the store has
+// no schema to check
+func newSchemaCache(store database.LocationStore) *schemaCache {
+	checker, ok := store.(database.SchemaChecker)
+	if !ok {
+		return nil
+	}
+	return &schemaCache{checker: checker}
+}
+
+// Check returns the cached schema check result, checking again once it is
+// older than schemaCheckInterval
+func (c *schemaCache) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.IsZero() && time.Since(c.checked) < schemaCheckInterval {
+		return c.err
+	}
+	err := c.checker.CheckSchema(ctx)
+	if err == nil || errors.Is(err, database.ErrSchemaMismatch) {
+		c.checked, c.err = time.Now(), err
+	}
+	return err
+}
+
+// readinessResponse is the JSON body of /readyz
+type readinessResponse struct {
+	Status   string `json:"status"`
+	Service  string `json:"service,omitempty"`
+	Database string `json:"database,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// readyzHandler is the readiness probe: it checks location store
+// connectivity and, when schema is not nil, the schema contract
+func readyzHandler(store database.LocationStore, schema *schemaCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		defer cancel()
+
+		reason := ""
+		err := store.HealthCheck(ctx)
+		switch {
+		case errors.Is(err, database.ErrCircuitOpen):
+			// While the circuit breaker is open the database is not contacted
+			reason = "circuit_open"
+		case err != nil:
+			reason = "database_unavailable"
+		case schema != nil:
+			// Serving against a schema the worker does not expect would fail
+			// or corrupt jobs, so a mismatch keeps the pod out of rotation
+			if err = schema.Check(ctx); errors.Is(err, database.ErrSchemaMismatch) {
+				reason = "schema_mismatch"
+			} else if err != nil {
+				reason = "database_unavailable"
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			log.Warn().Err(err).Str("reason", reason).Msg("Readiness check failed")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(readinessResponse{Status: "not_ready", Reason: reason, Error: err.Error()}) //nolint:errcheck // HTTP response write failure is not recoverable
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(readinessResponse{Status: "ready", Service: "otel-worker", Database: "connected"}) //nolint:errcheck // HTTP response write failure is not recoverable
+	}
+}
+
+// artifactCatalog is the part of the distance service behind the artifact
+// endpoints
+type artifactCatalog interface {
+	ListArtifacts(context.Context, *distancev1.ListArtifactsRequest) (*distancev1.ListArtifactsResponse, error)
+	GetArtifact(context.Context, *distancev1.GetArtifactRequest) (*distancev1.GetArtifactResponse, error)
+	DeleteArtifact(context.Context, *distancev1.DeleteArtifactRequest) (*distancev1.DeleteArtifactResponse, error)
+}
+
+// registerArtifactRoutes serves the artifact catalog RPCs over HTTP as JSON:
+// GET /artifacts lists, GET /artifacts/{key} inspects and DELETE
+// /artifacts/{key} deletes. Every route requires token as a bearer token.
+func registerArtifactRoutes(mux *http.ServeMux, catalog artifactCatalog, token string) {
+	mux.Handle("GET /artifacts", requireBearer(token, listArtifactsHandler(catalog)))
+	mux.Handle("GET /artifacts/{key}", requireBearer(token, artifactHandler(catalog)))
+	mux.Handle("DELETE /artifacts/{key}", requireBearer(token, artifactHandler(catalog)))
+}
+
+// requireBearer rejects requests whose Authorization header does not carry
+// token as a bearer token
+func requireBearer(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="artifacts"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// listArtifactsHandler lists artifacts, taking the ListArtifacts filters as
+// query parameters: job_id, date, device_id, format, limit and offset
+func listArtifactsHandler(catalog artifactCatalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		req := &distancev1.ListArtifactsRequest{
+			JobId:    query.Get("job_id"),
+			Date:     query.Get("date"),
+			DeviceId: query.Get("device_id"),
+			Format:   query.Get("format"),
+		}
+		for name, field := range map[string]*int32{"limit": &req.Limit, "offset": &req.Offset} {
+			value := query.Get(name)
+			if value == "" {
+				continue
+			}
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || n < 0 {
+				http.Error(w, fmt.Sprintf("Invalid %s", name), http.StatusBadRequest)
+				return
+			}
+			*field = int32(n)
+		}
+
+		resp, err := catalog.ListArtifacts(r.Context(), req)
+		writeArtifactResponse(w, r, resp, err)
+	}
+}
+
+// artifactHandler returns or deletes the artifact named in the path
+func artifactHandler(catalog artifactCatalog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("key")
+		if err := storage.ValidateKey(key); err != nil {
+			http.Error(w, "Invalid artifact key", http.StatusBadRequest)
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			resp, err := catalog.DeleteArtifact(r.Context(), &distancev1.DeleteArtifactRequest{Key: key})
+			writeArtifactResponse(w, r, resp, err)
+			return
+		}
+		resp, err := catalog.GetArtifact(r.Context(), &distancev1.GetArtifactRequest{Key: key})
+		writeArtifactResponse(w, r, resp, err)
+	}
+}
+
+// writeArtifactResponse writes an artifact RPC's response as JSON, or its
+// error with the matching status
+func writeArtifactResponse(w http.ResponseWriter, r *http.Request, resp proto.Message, err error) {
+	if errors.Is(err, grpcserver.ErrArtifactNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("path", r.URL.Path).Msg("Artifact request failed")
+		http.Error(w, "Artifact request failed", http.StatusInternalServerError)
+		return
+	}
+	body, err := protojson.Marshal(resp)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to encode artifact response")
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body) //nolint:errcheck // HTTP response write failure is not recoverable
+}
+
+// downloadHandler serves job outputs from the artifact store by key. Only
+// requests carrying a valid, unexpired signature from GetJobStatus are
+// served.
+func downloadHandler(artifacts storage.Store, signer *download.Signer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract the storage key from the path
+		key := strings.TrimPrefix(r.URL.Path, "/download/")
+		if key == "" {
+			http.Error(w, "Filename required", http.StatusBadRequest)
+			return
+		}
+
+		// Security: only allow distance_* job outputs
+		contentType, ok := downloadContentType(key)
+		if !ok {
+			http.Error(w, "Invalid filename format", http.StatusBadRequest)
+			return
+		}
+
+		jobID, err := signer.Verify(key, r.URL.Query())
+		if errors.Is(err, download.ErrUnsigned) {
+			http.Error(w, "Signed download URL required", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("key", key).Str("remote_addr", r.RemoteAddr).Msg("Rejected download")
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		content, info, err := artifacts.Open(r.Context(), key)
+		if errors.Is(err, storage.ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("key", key).Str("job_id", jobID).Msg("Failed to open artifact")
+			http.Error(w, "Failed to read artifact", http.StatusInternalServerError)
+			return
+		}
+		defer func() { _ = content.Close() }() // nolint:errcheck // read-only
+
+		h := w.Header()
+		h.Set("Content-Type", contentType)
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": key}))
+		if info.SHA256 != "" {
+			h.Set("X-Checksum-SHA256", info.SHA256)
+		}
+
+		encoding := ""
+		if storage.Compressible(key) {
+			h.Set("Vary", "Accept-Encoding")
+			encoding = negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		}
+		if encoding == "" {
+			setRepresentationHeaders(h, info.SHA256, "")
+			http.ServeContent(w, r, key, info.ModTime, content)
+			return
+		}
+		h.Set("Content-Encoding", encoding)
+
+		// Serve the pre-compressed variant when the store has one
+		variant, variantInfo, err := artifacts.Open(r.Context(), storage.EncodedKey(key, encoding))
+		if err == nil {
+			defer func() { _ = variant.Close() }() // nolint:errcheck // read-only
+			setRepresentationHeaders(h, info.SHA256, encoding)
+			setReprDigest(h, variantInfo.SHA256)
+			http.ServeContent(w, r, key, info.ModTime, variant)
+			return
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			log.Warn().Err(err).Str("key", key).Str("encoding", encoding).Msg("Failed to open compressed artifact")
+		}
+
+		// Otherwise compress on the fly. The output is not byte-for-byte
+		// reproducible, so the ETag is weak and ranges are not offered.
+		setRepresentationHeaders(h, info.SHA256, encoding)
+		if etag := h.Get("ETag"); etag != "" {
+			h.Set("ETag", "W/"+etag)
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		if !info.ModTime.IsZero() {
+			h.Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+		}
+		if r.Method == http.MethodHead {
+			return
+		}
+		encoder, err := storage.NewEncoder(w, encoding)
+		if err != nil {
+			log.Error().Err(err).Str("key", key).Msg("Failed to compress artifact")
+			return
+		}
+		if _, err := io.Copy(encoder, content); err != nil {
+			log.Warn().Err(err).Str("key", key).Str("job_id", jobID).Msg("Download interrupted")
+			return
+		}
+		if err := encoder.Close(); err != nil {
+			log.Warn().Err(err).Str("key", key).Str("job_id", jobID).Msg("Download interrupted")
+		}
+	}
+}
+
+// downloadEncodings lists the content encodings the download handler offers,
+// most preferred first
+var downloadEncodings = []string{storage.EncodingZstd, storage.EncodingGzip}
+
+// negotiateEncoding returns the offered encoding the Accept-Encoding header
+// ranks highest, preferring zstd on ties, or "" for identity
+func negotiateEncoding(acceptEncoding string) string {
+	weights := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if name == "*" {
+			wildcard = q
+		} else if name != "" {
+			weights[name] = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range downloadEncodings {
+		q, ok := weights[encoding]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// setRepresentationHeaders sets the ETag of the artifact as served with
+// encoding, derived from its checksum, and for identity its Repr-Digest.
+// Artifacts stored without a checksum get neither.
+func setRepresentationHeaders(h http.Header, sha256Hex, encoding string) {
+	if sha256Hex == "" {
+		return
+	}
+	if encoding == "" {
+		h.Set("ETag", `"`+sha256Hex+`"`)
+		setReprDigest(h, sha256Hex)
+		return
+	}
+	h.Set("ETag", `"`+sha256Hex+"-"+encoding+`"`)
+}
+
+// setReprDigest sends the SHA-256 checksum of the bytes served as an
+// RFC 9530 Repr-Digest
+func setReprDigest(h http.Header, sha256Hex string) {
+	sum, err := hex.DecodeString(sha256Hex)
+	if err != nil || len(sum) == 0 {
+		return
+	}
+	h.Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(sum)+":")
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 specifies for it
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// downloadContentTypes maps the extension of each downloadable job output to
+// its media type
+var downloadContentTypes = map[string]string{
+	".csv":     "text/csv; charset=utf-8",
+	".gpx":     "application/gpx+xml",
+	".geojson": "application/geo+json",
+	".kml":     "application/vnd.google-earth.kml+xml",
+	".parquet": "application/vnd.apache.parquet",
+}
+
+// downloadContentType returns the media type of a downloadable job output,
+// or false if filename is not a distance_* output file in the directory
+func downloadContentType(filename string) (string, bool) {
+	ext := filepath.Ext(filename)
+	contentType, ok := downloadContentTypes[ext]
+	if !ok || !strings.HasPrefix(filename, "distance_") || len(filename) <= len("distance_")+len(ext) {
+		return "", false
+	}
+	if strings.ContainsAny(filename, `/\`) || strings.Contains(filename, "..") {
+		return "", false
+	}
+	return contentType, true
+}
+
+// databaseOptions returns the database client settings from cfg
+func databaseOptions(cfg *config.Config) database.Options {
+	return database.Options{
+		Pool: database.PoolConfig{
+			MaxOpenConns:    cfg.PostgresMaxOpenConns,
+			MaxIdleConns:    cfg.PostgresMaxIdleConns,
+			ConnMaxLifetime: cfg.PostgresConnMaxLifetime,
+			ConnMaxIdleTime: cfg.PostgresConnMaxIdleTime,
+		},
+		Retry: database.RetryPolicy{
+			MaxAttempts:    cfg.PostgresRetryMaxAttempts,
+			InitialBackoff: cfg.PostgresRetryInitialBackoff,
+			MaxBackoff:     cfg.PostgresRetryMaxBackoff,
+		},
+		Breaker: database.BreakerConfig{
+			FailureThreshold: cfg.PostgresBreakerThreshold,
+			Cooldown:         cfg.PostgresBreakerCooldown,
+		},
+		ReplicaDSN:       cfg.ReplicaDSN(),
+		StatementTimeout: cfg.PostgresStatementTimeout,
+	}
+}
+
+// openArtifactStore returns the configured store for job outputs, writing
+// compressed variants when ARTIFACT_COMPRESSION is set
+func openArtifactStore(cfg *config.Config) storage.Store {
+	store := openArtifactBackend(cfg)
+	if len(cfg.ArtifactCompression) == 0 {
+		return store
+	}
+	log.Info().Strs("encodings", cfg.ArtifactCompression).Msg("Job outputs also stored compressed")
+	return storage.NewCompressedStore(store, cfg.ArtifactCompression)
+}
+
+// openArtifactBackend returns the configured local or S3 store. An
+// unreachable bucket is logged rather than fatal so the service still starts
+// while object storage recovers.
+func openArtifactBackend(cfg *config.Config) storage.Store {
+	if cfg.ArtifactStorage != "s3" {
+		log.Info().Str("path", cfg.CSVOutputPath).Msg("Job outputs stored on the local volume")
+		return storage.NewLocalStore(cfg.CSVOutputPath)
+	}
+
+	s3Store, err := storage.NewS3Store(storage.S3Config{
+		Endpoint:        cfg.S3Endpoint,
+		Bucket:          cfg.S3Bucket,
+		Region:          cfg.S3Region,
+		AccessKeyID:     cfg.S3AccessKeyID,
+		SecretAccessKey: cfg.S3SecretAccessKey,
+		UseSSL:          cfg.S3UseSSL,
+		Prefix:          cfg.S3Prefix,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize artifact storage")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s3Store.HealthCheck(ctx); err != nil {
+		log.Warn().Err(err).Str("bucket", cfg.S3Bucket).Msg("Artifact bucket unreachable")
+	} else {
+		log.Info().Str("endpoint", cfg.S3Endpoint).Str("bucket", cfg.S3Bucket).Msg("Job outputs stored in S3")
+	}
+	return s3Store
+}
+
+// openLocationStore opens the configured location store and returns it with a
+// function that releases it. It exits the process if the store is unusable.
+func openLocationStore(cfg *config.Config) (database.LocationStore, func()) {
+	if cfg.LocationSource == "files" {
+		fileStore, err := database.NewFileStore(cfg.LocationFilesPath)
+		if err != nil {
+			log.Fatal().Err(err).Str("path", cfg.LocationFilesPath).Msg("Failed to load location exports")
+		}
+		devices, _ := fileStore.GetDevices(context.Background()) // nolint:errcheck // in-memory index cannot fail
+		log.Info().
+			Str("path", cfg.LocationFilesPath).
+			Strs("devices", devices).
+			Msg("Serving locations from OwnTracks exports")
+		return fileStore, func() {}
+	}
+
+	dbClient, err := database.NewClientWithOptions(cfg.DatabaseDSN(), databaseOptions(cfg))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize database client")
+	}
+	closeDB := func() {
+		if closeErr := dbClient.Close(); closeErr != nil {
+			log.Error().Err(closeErr).Msg("Failed to close database connection")
+		}
+	}
+
+	dbClient.SetDecodePayload(cfg.IncludeRawPayload)
+
+	log.Info().Msg("Database connection established")
+	if cfg.PostgresReplicaHost != "" {
+		if dbClient.ReplicaAvailable() {
+			log.Info().Str("replica_host", cfg.PostgresReplicaHost).Msg("Location queries routed to read replica")
+		} else {
+			log.Warn().Str("replica_host", cfg.PostgresReplicaHost).Msg("Read replica unreachable, location queries use the primary")
+		}
+	}
+
+	// Verify database connectivity
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if healthErr := dbClient.HealthCheck(ctx); healthErr != nil {
+		cancel()
+		closeDB()
+		log.Error().Err(healthErr).Msg("Database health check failed")
+		os.Exit(1) // nolint:gocritic // Immediate exit on health check failure is intentional
+	}
+	cancel()
+
+	log.Info().Msg("Database health check passed")
+
+	// Bring worker-owned tables up to date; a remaining mismatch is
+	// reported by /readyz until it is resolved
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if cfg.MigrateOnStartup {
+		if migrateErr := migrate(ctx, dbClient); migrateErr != nil {
+			closeDB()
+			log.Fatal().Err(migrateErr).Msg("Failed to apply schema migrations") // nolint:gocritic // Fatal error before service starts is acceptable
+		}
+	}
+	if schemaErr := dbClient.CheckSchema(ctx); schemaErr != nil {
+		log.Warn().Err(schemaErr).Msg("Database schema does not match the worker contract")
+	}
+
+	return dbClient, closeDB
+}
+
+// runCommand runs a one-off subcommand of the server binary
+func runCommand(cfg *config.Config, command string) error {
+	switch command {
+	case "migrate":
+		return runMigrations(cfg)
+	default:
+		return fmt.Errorf("unknown command %q (available: migrate)", command)
+	}
+}
+
+// runMigrations applies pending schema migrations and verifies the schema
+// contract
+func runMigrations(cfg *config.Config) error {
+	if cfg.LocationSource != "postgres" {
+		return fmt.Errorf("migrate requires LOCATION_SOURCE=postgres")
+	}
+
+	dbClient, err := database.NewClientWithOptions(cfg.DatabaseDSN(), databaseOptions(cfg))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := dbClient.Close(); closeErr != nil {
+			log.Error().Err(closeErr).Msg("Failed to close database connection")
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := migrate(ctx, dbClient); err != nil {
+		return err
+	}
+	return dbClient.CheckSchema(ctx)
+}
+
+// migrate applies pending schema migrations and logs each one
+func migrate(ctx context.Context, dbClient *database.Client) error {
+	applied, err := dbClient.Migrate(ctx)
+	for _, m := range applied {
+		log.Info().Int("version", m.Version).Str("name", m.Name).Msg("Applied schema migration")
+	}
+	if err != nil {
+		return err
+	}
+
+	version, err := dbClient.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	log.Info().Int("schema_version", version).Int("applied", len(applied)).Msg("Database schema up to date")
+	return nil
+}
+
 // setLogLevel configures the global log level
 func setLogLevel(level string) {
 	switch level {
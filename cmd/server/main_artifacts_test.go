@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/stuartshay/otel-worker/internal/config"
+	"github.com/stuartshay/otel-worker/internal/database"
+	grpcserver "github.com/stuartshay/otel-worker/internal/grpc"
+	"github.com/stuartshay/otel-worker/internal/storage"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+func TestArtifactRoutes(t *testing.T) {
+	const jobID = "0b6f3c8e-2a4d-4f1e-9c7a-5d2e8f1b3a6c"
+	csvKey := "distance_20260124_pixel8_" + jobID + ".csv"
+	gpxKey := "distance_20260124_pixel8_" + jobID + ".gpx"
+
+	dir := t.TempDir()
+	artifacts := storage.NewLocalStore(dir)
+	for _, key := range []string{csvKey, gpxKey} {
+		w, err := artifacts.Create(context.Background(), key)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		_, _ = io.WriteString(w, "content")
+		if _, err := w.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+
+	server, err := grpcserver.NewServer(&config.Config{CSVOutputPath: dir}, database.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	t.Cleanup(func() { _ = server.Shutdown(5 * time.Second) })
+	server.SetArtifactStore(artifacts)
+	if err := server.ReconcileArtifacts(context.Background()); err != nil {
+		t.Fatalf("ReconcileArtifacts failed: %v", err)
+	}
+	token := strings.Repeat("t", 32)
+	mux := http.NewServeMux()
+	registerArtifactRoutes(mux, server, token)
+
+	do := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Every route requires the token
+	for _, auth := range []string{"", "Bearer wrong", token} {
+		for _, method := range []string{http.MethodGet, http.MethodDelete} {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(method, "/artifacts/"+csvKey, nil)
+			if auth != "" {
+				req.Header.Set("Authorization", auth)
+			}
+			mux.ServeHTTP(rec, req)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("%s with Authorization %q: expected 401, got %d", method, auth, rec.Code)
+			}
+		}
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/artifacts", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected listing without a token to be rejected, got %d", rec.Code)
+	}
+	if _, _, err := artifacts.Open(context.Background(), csvKey); err != nil {
+		t.Fatalf("expected unauthenticated requests to leave the artifact, got %v", err)
+	}
+
+	rec = do(http.MethodGet, "/artifacts?device_id=pixel8&format=gpx")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected JSON listing, got %d: %s", rec.Code, rec.Body)
+	}
+	var listed distancev1.ListArtifactsResponse
+	if err := protojson.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("invalid listing: %v", err)
+	}
+	if len(listed.Artifacts) != 1 || listed.Artifacts[0].Key != gpxKey || listed.Artifacts[0].JobId != jobID || listed.Artifacts[0].DownloadUrl != "" {
+		t.Errorf("expected the GPX artifact, got %+v", listed.Artifacts)
+	}
+
+	rec = do(http.MethodGet, "/artifacts/"+csvKey)
+	var got distancev1.GetArtifactResponse
+	if err := protojson.Unmarshal(rec.Body.Bytes(), &got); rec.Code != http.StatusOK || err != nil {
+		t.Fatalf("expected the artifact, got %d: %s", rec.Code, rec.Body)
+	}
+	if got.Artifact.SizeBytes != 7 || len(got.Artifact.Sha256) != 64 || got.Artifact.DownloadUrl != "" {
+		t.Errorf("unexpected artifact %+v", got.Artifact)
+	}
+
+	if rec := do(http.MethodDelete, "/artifacts/"+csvKey); rec.Code != http.StatusOK {
+		t.Errorf("expected delete to succeed, got %d: %s", rec.Code, rec.Body)
+	}
+	if _, _, err := artifacts.Open(context.Background(), csvKey); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected the artifact to be deleted, got %v", err)
+	}
+
+	tests := []struct {
+		method string
+		target string
+		status int
+	}{
+		{http.MethodGet, "/artifacts/" + csvKey, http.StatusNotFound},
+		{http.MethodDelete, "/artifacts/" + csvKey, http.StatusNotFound},
+		{http.MethodGet, "/artifacts/..distance_20260124.csv", http.StatusBadRequest},
+		{http.MethodGet, "/artifacts?limit=abc", http.StatusBadRequest},
+		{http.MethodGet, "/artifacts?offset=-1", http.StatusBadRequest},
+		{http.MethodPost, "/artifacts/" + gpxKey, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		if rec := do(tt.method, tt.target); rec.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.target, tt.status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stuartshay/otel-worker/internal/config"
+)
+
+func TestRunCommand(t *testing.T) {
+	cfg := &config.Config{LocationSource: "files"}
+
+	if err := runCommand(cfg, "serve-twice"); err == nil {
+		t.Error("expected error for unknown command, got nil")
+	}
+	if err := runCommand(cfg, "migrate"); err == nil {
+		t.Error("expected migrate to require the postgres location source, got nil")
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/stuartshay/otel-worker/internal/download"
+	"github.com/stuartshay/otel-worker/internal/storage"
+)
+
+func TestDownloadContentType(t *testing.T) {
+	tests := []struct {
+		filename    string
+		contentType string
+		ok          bool
+	}{
+		{"distance_20260124.csv", "text/csv; charset=utf-8", true},
+		{"distance_20260124_pixel8.gpx", "application/gpx+xml", true},
+		{"distance_20260124.geojson", "application/geo+json", true},
+		{"distance_20260124.kml", "application/vnd.google-earth.kml+xml", true},
+		{"distance_20260124.parquet", "application/vnd.apache.parquet", true},
+		{"distance_.csv", "", false},
+		{"report_20260124.csv", "", false},
+		{"distance_20260124.txt", "", false},
+		{"distance_../secret.csv", "", false},
+	}
+
+	for _, tt := range tests {
+		contentType, ok := downloadContentType(tt.filename)
+		if ok != tt.ok || contentType != tt.contentType {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", tt.filename, tt.contentType, tt.ok, contentType, ok)
+		}
+	}
+}
+
+func TestDownloadHandler(t *testing.T) {
+	artifacts := storage.NewLocalStore(t.TempDir())
+	w, err := artifacts.Create(context.Background(), "distance_20260124.csv")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, "timestamp,device_id\n")
+	if _, err := w.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	key := []byte(strings.Repeat("k", download.MinKeyLength))
+	signer := download.NewSigner(key, time.Hour, "")
+	handler := downloadHandler(artifacts, signer)
+
+	signed, _ := signer.URL("job-1", "distance_20260124.csv")
+	missing, _ := signer.URL("job-1", "distance_20260125.csv")
+	expired, _ := download.NewSigner(key, time.Nanosecond, "").URL("job-1", "distance_20260124.csv")
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"signed", signed, http.StatusOK},
+		{"missing artifact", missing, http.StatusNotFound},
+		{"unsigned", "/download/distance_20260124.csv", http.StatusUnauthorized},
+		{"signed for another artifact", strings.Replace(missing, "20260125", "20260124", 1), http.StatusForbidden},
+		{"expired", expired, http.StatusForbidden},
+		{"invalid name", "/download/report_20260124.csv", http.StatusBadRequest},
+		{"no name", "/download/", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, signed, nil))
+	if got := rec.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Errorf("unexpected Content-Type %q", got)
+	}
+	if rec.Body.String() != "timestamp,device_id\n" {
+		t.Errorf("unexpected body %q", rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Length"); got != "20" {
+		t.Errorf("unexpected Content-Length %q", got)
+	}
+	if got := rec.Header().Get("X-Checksum-SHA256"); got != "3c71ad2eac44e7e37acf7244daca84790f19c2594992aa23b4b914309e28710f" {
+		t.Errorf("unexpected X-Checksum-SHA256 %q", got)
+	}
+	if got := rec.Header().Get("Repr-Digest"); got != "sha-256=:PHGtLqxE5+N6z3JE2sqEeQ8ZwllJkqojtLkUMJ4ocQ8=:" {
+		t.Errorf("unexpected Repr-Digest %q", got)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br, zstd", "zstd"},
+		{"zstd;q=0.5, gzip", "gzip"},
+		{"GZIP;q=0.8", "gzip"},
+		{"*", "zstd"},
+		{"*;q=0.1, zstd;q=0", "gzip"},
+		{"gzip;q=0", ""},
+		{"gzip;q=high", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.header, tt.want, got)
+		}
+	}
+}
+
+func TestDownloadHandler_Encoding(t *testing.T) {
+	const csv = "timestamp,device_id\n2026-01-24T08:00:00Z,pixel8\n"
+	artifacts := storage.NewCompressedStore(storage.NewLocalStore(t.TempDir()), []string{storage.EncodingGzip})
+	w, err := artifacts.Create(context.Background(), "distance_20260124_job-1.csv")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, csv)
+	info, err := w.Commit()
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	signer := download.NewSigner([]byte(strings.Repeat("k", download.MinKeyLength)), time.Hour, "")
+	handler := downloadHandler(artifacts, signer)
+	signed, _ := signer.URL("job-1", "distance_20260124_job-1.csv")
+
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, signed, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	decode := func(t *testing.T, rec *httptest.ResponseRecorder) string {
+		t.Helper()
+		var r io.Reader
+		switch rec.Header().Get("Content-Encoding") {
+		case "gzip":
+			r, err = gzip.NewReader(rec.Body)
+		case "zstd":
+			r, err = zstd.NewReader(rec.Body)
+		default:
+			r = rec.Body
+		}
+		if err != nil {
+			t.Fatalf("failed to decode: %v", err)
+		}
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("failed to decode: %v", err)
+		}
+		return string(content)
+	}
+
+	t.Run("identity", func(t *testing.T) {
+		rec := get(nil)
+		if rec.Code != http.StatusOK || decode(t, rec) != csv {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+		h := rec.Header()
+		if h.Get("Content-Encoding") != "" || h.Get("ETag") != `"`+info.SHA256+`"` || h.Get("Vary") != "Accept-Encoding" {
+			t.Errorf("unexpected headers %v", h)
+		}
+		if got := h.Get("Content-Disposition"); got != `attachment; filename=distance_20260124_job-1.csv` {
+			t.Errorf("unexpected Content-Disposition %q", got)
+		}
+	})
+
+	t.Run("pre-compressed", func(t *testing.T) {
+		rec := get(map[string]string{"Accept-Encoding": "gzip, deflate"})
+		h := rec.Header()
+		if h.Get("Content-Encoding") != "gzip" || h.Get("ETag") != `"`+info.SHA256+`-gzip"` {
+			t.Errorf("unexpected headers %v", h)
+		}
+		if h.Get("X-Checksum-SHA256") != info.SHA256 {
+			t.Errorf("expected the checksum of the decoded artifact, got %q", h.Get("X-Checksum-SHA256"))
+		}
+		if got := decode(t, rec); got != csv {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+
+	t.Run("compressed on the fly", func(t *testing.T) {
+		rec := get(map[string]string{"Accept-Encoding": "zstd, gzip;q=0.5"})
+		h := rec.Header()
+		if h.Get("Content-Encoding") != "zstd" || h.Get("ETag") != `W/"`+info.SHA256+`-zstd"` {
+			t.Errorf("unexpected headers %v", h)
+		}
+		if got := decode(t, rec); got != csv {
+			t.Errorf("unexpected content %q", got)
+		}
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		if rec := get(map[string]string{"If-None-Match": `"` + info.SHA256 + `"`}); rec.Code != http.StatusNotModified {
+			t.Errorf("identity: expected 304, got %d", rec.Code)
+		}
+		rec := get(map[string]string{"Accept-Encoding": "zstd", "If-None-Match": `W/"` + info.SHA256 + `-zstd"`})
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("on the fly: expected 304, got %d", rec.Code)
+		}
+	})
+}
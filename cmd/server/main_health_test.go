@@ -2,9 +2,15 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/stuartshay/otel-worker/internal/database"
 )
 
 func TestHealthzEndpoint(t *testing.T) {
@@ -34,3 +40,66 @@ func TestHealthzEndpoint(t *testing.T) {
 		t.Errorf("Expected Content-Type application/json, got %s", contentType)
 	}
 }
+
+// schemaStore is a location store whose schema check returns err
+type schemaStore struct {
+	*database.MemoryStore
+	err   error
+	calls int
+}
+
+func (s *schemaStore) CheckSchema(context.Context) error {
+	s.calls++
+	return s.err
+}
+
+func TestReadyzHandler(t *testing.T) {
+	probe := func(store database.LocationStore, schema *schemaCache) (int, readinessResponse) {
+		rec := httptest.NewRecorder()
+		readyzHandler(store, schema).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got %s", ct)
+		}
+		var body readinessResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON body %q: %v", rec.Body, err)
+		}
+		return rec.Code, body
+	}
+
+	memory := database.NewMemoryStore()
+	if code, body := probe(memory, newSchemaCache(memory)); code != http.StatusOK || body.Status != "ready" {
+		t.Errorf("expected ready, got %d %+v", code, body)
+	}
+
+	// Quotes in the error must not break the JSON body
+	mismatch := fmt.Errorf(`%w: column "battery" has type "text"`, database.ErrSchemaMismatch)
+	store := &schemaStore{MemoryStore: memory, err: mismatch}
+	schema := newSchemaCache(store)
+	code, body := probe(store, schema)
+	if code != http.StatusServiceUnavailable || body.Reason != "schema_mismatch" || body.Error != mismatch.Error() {
+		t.Errorf("expected schema mismatch, got %d %+v", code, body)
+	}
+
+	// The result is reused instead of querying the schema on every probe
+	probe(store, schema)
+	if store.calls != 1 {
+		t.Errorf("expected 1 schema check, got %d", store.calls)
+	}
+	schema.checked = schema.checked.Add(-schemaCheckInterval)
+	store.err = nil
+	if code, _ := probe(store, schema); code != http.StatusOK || store.calls != 2 {
+		t.Errorf("expected a fresh check to pass, got %d after %d checks", code, store.calls)
+	}
+
+	// Other errors are not cached
+	store = &schemaStore{MemoryStore: memory, err: errors.New("connection reset")}
+	schema = newSchemaCache(store)
+	if code, body := probe(store, schema); code != http.StatusServiceUnavailable || body.Reason != "database_unavailable" {
+		t.Errorf("expected database_unavailable, got %d %+v", code, body)
+	}
+	probe(store, schema)
+	if store.calls != 2 {
+		t.Errorf("expected failed checks to be retried, got %d checks", store.calls)
+	}
+}
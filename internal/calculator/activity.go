@@ -0,0 +1,97 @@
+package calculator
+
+import (
+	"time"
+)
+
+// ActivitySample is one recorded workout point with its sensor readings.
+// Zero sensor values mean the device did not record them.
+type ActivitySample struct {
+	Location
+	SpeedKMH  float64 // device-reported speed
+	HeartRate int     // beats per minute
+	Cadence   int     // steps or revolutions per minute
+}
+
+// ActivityStats summarizes the path and sensor readings of a workout
+type ActivityStats struct {
+	Points         int
+	PathDistanceKM float64
+	Duration       time.Duration
+	AvgSpeedKMH    float64
+	MaxSpeedKMH    float64
+	AvgHeartRate   float64
+	MaxHeartRate   int
+	AvgCadence     float64
+	MaxCadence     int
+}
+
+// ActivityAnalyzer accumulates ActivityStats one sample at a time
+type ActivityAnalyzer struct {
+	stats        ActivityStats
+	first, last  ActivitySample
+	computedMax  float64 // max speed between fixes, used without device speed
+	heartRateSum int
+	heartRateN   int
+	cadenceSum   int
+	cadenceN     int
+}
+
+// NewActivityAnalyzer creates an analyzer for one activity
+func NewActivityAnalyzer() *ActivityAnalyzer {
+	return &ActivityAnalyzer{}
+}
+
+// Add records the next chronological sample
+func (a *ActivityAnalyzer) Add(sample ActivitySample) {
+	if a.stats.Points == 0 {
+		a.first = sample
+	} else {
+		segment := Haversine(a.last.Latitude, a.last.Longitude, sample.Latitude, sample.Longitude)
+		a.stats.PathDistanceKM += segment
+		if gap := sample.Timestamp.Sub(a.last.Timestamp); gap > 0 {
+			a.computedMax = max(a.computedMax, segment/gap.Hours())
+		}
+	}
+	a.stats.Points++
+	a.last = sample
+
+	a.stats.MaxSpeedKMH = max(a.stats.MaxSpeedKMH, sample.SpeedKMH)
+
+	if sample.HeartRate > 0 {
+		a.heartRateSum += sample.HeartRate
+		a.heartRateN++
+		a.stats.MaxHeartRate = max(a.stats.MaxHeartRate, sample.HeartRate)
+	}
+	if sample.Cadence > 0 {
+		a.cadenceSum += sample.Cadence
+		a.cadenceN++
+		a.stats.MaxCadence = max(a.stats.MaxCadence, sample.Cadence)
+	}
+}
+
+// Stats returns the statistics for every sample added so far. Average speed
+// is path distance over elapsed time; max speed is the device-reported
+// maximum, or the fastest segment between fixes when no speed was recorded.
+func (a *ActivityAnalyzer) Stats() ActivityStats {
+	stats := a.stats
+	if stats.Points == 0 {
+		return stats
+	}
+
+	stats.Duration = a.last.Timestamp.Sub(a.first.Timestamp)
+	if stats.Duration > 0 {
+		stats.AvgSpeedKMH = stats.PathDistanceKM / stats.Duration.Hours()
+	}
+	if stats.MaxSpeedKMH == 0 {
+		stats.MaxSpeedKMH = a.computedMax
+	}
+	if a.heartRateN > 0 {
+		stats.AvgHeartRate = float64(a.heartRateSum) / float64(a.heartRateN)
+	}
+	if a.cadenceN > 0 {
+		stats.AvgCadence = float64(a.cadenceSum) / float64(a.cadenceN)
+	}
+
+	return stats
+}
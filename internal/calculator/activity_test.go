@@ -0,0 +1,70 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestActivityAnalyzer(t *testing.T) {
+	start := time.Date(2026, 1, 24, 7, 0, 0, 0, time.UTC)
+	track := trackAtSpeed(start, 11, 12.0) // 10 one-minute segments at 12 km/h = 2 km
+
+	analyzer := NewActivityAnalyzer()
+	for i, loc := range track {
+		sample := ActivitySample{Location: loc, SpeedKMH: 12 + float64(i%3)}
+		if i != 4 { // one dropout in each sensor
+			sample.HeartRate = 140 + i
+			sample.Cadence = 170
+		}
+		analyzer.Add(sample)
+	}
+
+	stats := analyzer.Stats()
+	if stats.Points != 11 {
+		t.Errorf("expected 11 points, got %d", stats.Points)
+	}
+	if math.Abs(stats.PathDistanceKM-2.0) > 0.01 {
+		t.Errorf("expected ~2 km path, got %.3f", stats.PathDistanceKM)
+	}
+	if stats.Duration != 10*time.Minute {
+		t.Errorf("expected 10m duration, got %v", stats.Duration)
+	}
+	if math.Abs(stats.AvgSpeedKMH-12.0) > 0.1 {
+		t.Errorf("expected ~12 km/h average, got %.2f", stats.AvgSpeedKMH)
+	}
+	if stats.MaxSpeedKMH != 14 {
+		t.Errorf("expected device max speed 14, got %.2f", stats.MaxSpeedKMH)
+	}
+	if stats.MaxHeartRate != 150 {
+		t.Errorf("expected max heart rate 150, got %d", stats.MaxHeartRate)
+	}
+	// Mean of 140..150 without the 144 dropout
+	if math.Abs(stats.AvgHeartRate-145.1) > 0.01 {
+		t.Errorf("expected average heart rate 145.1, got %.2f", stats.AvgHeartRate)
+	}
+	if stats.AvgCadence != 170 || stats.MaxCadence != 170 {
+		t.Errorf("expected cadence 170, got avg %.1f max %d", stats.AvgCadence, stats.MaxCadence)
+	}
+}
+
+func TestActivityAnalyzer_NoSensors(t *testing.T) {
+	start := time.Date(2026, 1, 24, 7, 0, 0, 0, time.UTC)
+
+	analyzer := NewActivityAnalyzer()
+	for _, loc := range trackAtSpeed(start, 5, 30.0) {
+		analyzer.Add(ActivitySample{Location: loc})
+	}
+
+	stats := analyzer.Stats()
+	if math.Abs(stats.MaxSpeedKMH-30.0) > 0.5 {
+		t.Errorf("expected computed max speed ~30, got %.2f", stats.MaxSpeedKMH)
+	}
+	if stats.AvgHeartRate != 0 || stats.MaxCadence != 0 {
+		t.Errorf("expected no sensor stats, got %+v", stats)
+	}
+
+	if empty := NewActivityAnalyzer().Stats(); empty != (ActivityStats{}) {
+		t.Errorf("expected zero stats, got %+v", empty)
+	}
+}
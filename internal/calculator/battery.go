@@ -0,0 +1,200 @@
+package calculator
+
+import (
+	"math"
+	"time"
+)
+
+// OwnTracks battery status values as stored in public.locations.battery_status
+const (
+	BatteryStatusUnknown   = 1
+	BatteryStatusUnplugged = 2
+	BatteryStatusCharging  = 3
+	BatteryStatusFull      = 4
+)
+
+// MaxBatteryGap is the longest interval between two readings that is still
+// used for drain rate; longer gaps are likely missing data rather than idle time
+const MaxBatteryGap = 2 * time.Hour
+
+// BatteryReading is a single battery sample with the fix it was reported with
+type BatteryReading struct {
+	Timestamp time.Time
+	Level     int // percent, 1-100
+	Status    int
+	Latitude  float64
+	Longitude float64
+}
+
+// ChargingSession is a contiguous run of charging or full readings
+type ChargingSession struct {
+	Start      time.Time
+	End        time.Time
+	StartLevel int
+	EndLevel   int
+}
+
+// Duration returns how long the device was plugged in
+func (c ChargingSession) Duration() time.Duration {
+	return c.End.Sub(c.Start)
+}
+
+// DailyBattery holds battery statistics for a single UTC day
+type DailyBattery struct {
+	Date             string // YYYY-MM-DD
+	DrainedPercent   float64
+	DischargeHours   float64
+	DrainRatePerHour float64
+	MinLevel         int
+	MaxLevel         int
+	ChargingSessions int
+	DistanceKM       float64
+	Readings         int
+}
+
+// BatteryReport summarizes battery behaviour over a set of readings
+type BatteryReport struct {
+	Days             []DailyBattery
+	Sessions         []ChargingSession
+	DrainRatePerHour float64
+	// DistanceCorrelation is the Pearson correlation between daily path
+	// distance and daily drain rate; zero when fewer than three days qualify
+	DistanceCorrelation float64
+}
+
+// isCharging reports whether a battery status means the device is plugged in
+func isCharging(status int) bool {
+	return status == BatteryStatusCharging || status == BatteryStatusFull
+}
+
+// AnalyzeBattery computes drain rate per hour, charging sessions and the
+// correlation between distance travelled and drain for chronological readings
+// from a single device. Readings without a battery level are ignored.
+func AnalyzeBattery(readings []BatteryReading) BatteryReport {
+	analyzer := NewBatteryAnalyzer()
+	for _, r := range readings {
+		analyzer.Add(r)
+	}
+	return analyzer.Report()
+}
+
+// BatteryAnalyzer is the incremental form of AnalyzeBattery. Its memory use
+// grows with the number of days and charging sessions, not readings.
+type BatteryAnalyzer struct {
+	report   BatteryReport
+	prev     BatteryReading
+	hasPrev  bool
+	charging bool
+}
+
+// NewBatteryAnalyzer creates an analyzer for one device's readings
+func NewBatteryAnalyzer() *BatteryAnalyzer {
+	return &BatteryAnalyzer{}
+}
+
+// Add records the next chronological reading
+func (b *BatteryAnalyzer) Add(r BatteryReading) {
+	if r.Level <= 0 {
+		return
+	}
+
+	report := &b.report
+	date := r.Timestamp.UTC().Format("2006-01-02")
+	if len(report.Days) == 0 || report.Days[len(report.Days)-1].Date != date {
+		report.Days = append(report.Days, DailyBattery{Date: date, MinLevel: r.Level, MaxLevel: r.Level})
+	}
+	day := &report.Days[len(report.Days)-1]
+	day.Readings++
+	day.MinLevel = min(day.MinLevel, r.Level)
+	day.MaxLevel = max(day.MaxLevel, r.Level)
+
+	if isCharging(r.Status) {
+		if !b.charging {
+			report.Sessions = append(report.Sessions, ChargingSession{Start: r.Timestamp, StartLevel: r.Level})
+			day.ChargingSessions++
+			b.charging = true
+		}
+		session := &report.Sessions[len(report.Sessions)-1]
+		session.End = r.Timestamp
+		session.EndLevel = r.Level
+	} else {
+		b.charging = false
+	}
+
+	prev, hasPrev := b.prev, b.hasPrev
+	b.prev, b.hasPrev = r, true
+	if !hasPrev {
+		return
+	}
+
+	gap := r.Timestamp.Sub(prev.Timestamp)
+	if gap <= 0 {
+		return
+	}
+
+	day.DistanceKM += Haversine(prev.Latitude, prev.Longitude, r.Latitude, r.Longitude)
+
+	if gap > MaxBatteryGap || isCharging(prev.Status) || isCharging(r.Status) {
+		return
+	}
+
+	day.DischargeHours += gap.Hours()
+	if drop := prev.Level - r.Level; drop > 0 {
+		day.DrainedPercent += float64(drop)
+	}
+}
+
+// Report returns drain rates and correlation for every reading added so far
+func (b *BatteryAnalyzer) Report() BatteryReport {
+	report := BatteryReport{
+		Days:     append([]DailyBattery(nil), b.report.Days...),
+		Sessions: append([]ChargingSession(nil), b.report.Sessions...),
+	}
+
+	var totalDrained, totalHours float64
+	var distances, rates []float64
+	for i := range report.Days {
+		d := &report.Days[i]
+		if d.DischargeHours > 0 {
+			d.DrainRatePerHour = d.DrainedPercent / d.DischargeHours
+			distances = append(distances, d.DistanceKM)
+			rates = append(rates, d.DrainRatePerHour)
+		}
+		totalDrained += d.DrainedPercent
+		totalHours += d.DischargeHours
+	}
+
+	if totalHours > 0 {
+		report.DrainRatePerHour = totalDrained / totalHours
+	}
+	if len(rates) >= 3 {
+		report.DistanceCorrelation = pearson(distances, rates)
+	}
+
+	return report
+}
+
+// pearson returns the Pearson correlation coefficient of two equal-length
+// series, or zero when either series has no variance
+func pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	var sumX, sumY float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
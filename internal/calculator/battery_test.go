@@ -0,0 +1,95 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeBattery(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+
+	t.Run("no readings", func(t *testing.T) {
+		report := AnalyzeBattery(nil)
+		if len(report.Days) != 0 || report.DrainRatePerHour != 0 {
+			t.Errorf("expected empty report, got %+v", report)
+		}
+	})
+
+	t.Run("drain and charging session", func(t *testing.T) {
+		var readings []BatteryReading
+		// Discharge 100 -> 80 over four hours
+		for i := 0; i <= 4; i++ {
+			readings = append(readings, BatteryReading{
+				Timestamp: start.Add(time.Duration(i) * time.Hour),
+				Level:     100 - i*5,
+				Status:    BatteryStatusUnplugged,
+			})
+		}
+		// Charge 80 -> 100 over one hour
+		readings = append(readings,
+			BatteryReading{Timestamp: start.Add(4*time.Hour + 30*time.Minute), Level: 90, Status: BatteryStatusCharging},
+			BatteryReading{Timestamp: start.Add(5 * time.Hour), Level: 100, Status: BatteryStatusFull},
+			BatteryReading{Timestamp: start.Add(6 * time.Hour), Level: 98, Status: BatteryStatusUnplugged},
+			// Missing level (NULL in the database) is ignored
+			BatteryReading{Timestamp: start.Add(6*time.Hour + time.Minute), Level: 0, Status: BatteryStatusUnknown},
+		)
+
+		report := AnalyzeBattery(readings)
+
+		if len(report.Sessions) != 1 {
+			t.Fatalf("expected 1 charging session, got %d", len(report.Sessions))
+		}
+		session := report.Sessions[0]
+		if session.StartLevel != 90 || session.EndLevel != 100 {
+			t.Errorf("unexpected session levels %d -> %d", session.StartLevel, session.EndLevel)
+		}
+		if session.Duration() != 30*time.Minute {
+			t.Errorf("expected 30m session, got %s", session.Duration())
+		}
+
+		if math.Abs(report.DrainRatePerHour-5.0) > 0.001 {
+			t.Errorf("expected 5%%/h drain, got %.3f", report.DrainRatePerHour)
+		}
+
+		if len(report.Days) != 1 {
+			t.Fatalf("expected 1 day, got %d", len(report.Days))
+		}
+		day := report.Days[0]
+		if day.Readings != 8 || day.MinLevel != 80 || day.MaxLevel != 100 || day.ChargingSessions != 1 {
+			t.Errorf("unexpected day stats %+v", day)
+		}
+	})
+
+	t.Run("long gaps are excluded from drain", func(t *testing.T) {
+		readings := []BatteryReading{
+			{Timestamp: start, Level: 90, Status: BatteryStatusUnplugged},
+			{Timestamp: start.Add(5 * time.Hour), Level: 40, Status: BatteryStatusUnplugged},
+		}
+
+		report := AnalyzeBattery(readings)
+		if report.DrainRatePerHour != 0 {
+			t.Errorf("expected no drain rate across a 5h gap, got %.2f", report.DrainRatePerHour)
+		}
+	})
+
+	t.Run("distance correlation", func(t *testing.T) {
+		var readings []BatteryReading
+		for d := 0; d < 4; d++ {
+			dayStart := start.AddDate(0, 0, d)
+			// Days with more travel drain faster
+			readings = append(readings,
+				BatteryReading{Timestamp: dayStart, Level: 100, Status: BatteryStatusUnplugged, Latitude: 40, Longitude: -74},
+				BatteryReading{Timestamp: dayStart.Add(time.Hour), Level: 100 - (d+1)*5, Status: BatteryStatusUnplugged, Latitude: 40 + float64(d+1)*0.01, Longitude: -74},
+			)
+		}
+
+		report := AnalyzeBattery(readings)
+		if len(report.Days) != 4 {
+			t.Fatalf("expected 4 days, got %d", len(report.Days))
+		}
+		if report.DistanceCorrelation < 0.99 {
+			t.Errorf("expected strong positive correlation, got %.3f", report.DistanceCorrelation)
+		}
+	})
+}
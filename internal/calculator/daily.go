@@ -0,0 +1,78 @@
+package calculator
+
+import (
+	"time"
+)
+
+// DaySummary holds the per-device daily aggregates stored for dashboards
+type DaySummary struct {
+	PathDistanceKM float64
+	MaxDistanceKM  float64
+	MinDistanceKM  float64
+	AvgDistanceKM  float64
+	PointCount     int
+	TimeAway       time.Duration
+}
+
+// SummarizeDay computes path distance, distance-from-home statistics and time
+// spent further than awayThresholdKM from home for one device's chronological
+// locations. A segment counts fully as away when both ends are beyond the
+// threshold and half when only one end is.
+func SummarizeDay(homeLat, homeLon, awayThresholdKM float64, locations []Location) DaySummary {
+	summarizer := NewDaySummarizer(homeLat, homeLon, awayThresholdKM)
+	for _, loc := range locations {
+		summarizer.Add(loc)
+	}
+	return summarizer.Summary()
+}
+
+// DaySummarizer is the incremental form of SummarizeDay
+type DaySummarizer struct {
+	metrics         metricsAccumulator
+	awayThresholdKM float64
+	summary         DaySummary
+	prev            Location
+	prevAway        bool
+}
+
+// NewDaySummarizer creates a summarizer for one device's day
+func NewDaySummarizer(homeLat, homeLon, awayThresholdKM float64) *DaySummarizer {
+	return &DaySummarizer{
+		metrics:         newMetricsAccumulator(homeLat, homeLon),
+		awayThresholdKM: awayThresholdKM,
+	}
+}
+
+// Add records the next chronological location
+func (d *DaySummarizer) Add(loc Location) {
+	first := d.metrics.metrics.TotalLocations == 0
+	fromHome := d.metrics.add(loc)
+	away := fromHome > d.awayThresholdKM
+
+	if !first {
+		d.summary.PathDistanceKM += Haversine(d.prev.Latitude, d.prev.Longitude, loc.Latitude, loc.Longitude)
+
+		if gap := loc.Timestamp.Sub(d.prev.Timestamp); gap > 0 {
+			switch {
+			case away && d.prevAway:
+				d.summary.TimeAway += gap
+			case away || d.prevAway:
+				d.summary.TimeAway += gap / 2
+			}
+		}
+	}
+
+	d.prev = loc
+	d.prevAway = away
+}
+
+// Summary returns the aggregates for every location added so far
+func (d *DaySummarizer) Summary() DaySummary {
+	metrics := d.metrics.result()
+	summary := d.summary
+	summary.PointCount = metrics.TotalLocations
+	summary.MaxDistanceKM = metrics.MaxDistanceKM
+	summary.MinDistanceKM = metrics.MinDistanceKM
+	summary.AvgDistanceKM = metrics.AvgDistanceKM
+	return summary
+}
@@ -0,0 +1,49 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSummarizeDay(t *testing.T) {
+	homeLat, homeLon := 40.736097, -74.039373
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+
+	t.Run("empty locations", func(t *testing.T) {
+		summary := SummarizeDay(homeLat, homeLon, 0.5, nil)
+		if summary.PointCount != 0 || summary.MinDistanceKM != 0 {
+			t.Errorf("expected zero summary, got %+v", summary)
+		}
+	})
+
+	t.Run("out and back", func(t *testing.T) {
+		away := Location{Latitude: 40.748817, Longitude: -73.985428} // ~5 km
+		locations := []Location{
+			{Latitude: homeLat, Longitude: homeLon, Timestamp: start},
+			{Latitude: away.Latitude, Longitude: away.Longitude, Timestamp: start.Add(time.Hour)},
+			{Latitude: away.Latitude, Longitude: away.Longitude, Timestamp: start.Add(3 * time.Hour)},
+			{Latitude: homeLat, Longitude: homeLon, Timestamp: start.Add(4 * time.Hour)},
+		}
+
+		summary := SummarizeDay(homeLat, homeLon, 0.5, locations)
+
+		if summary.PointCount != 4 {
+			t.Errorf("expected 4 points, got %d", summary.PointCount)
+		}
+		oneWay := Haversine(homeLat, homeLon, away.Latitude, away.Longitude)
+		if math.Abs(summary.PathDistanceKM-2*oneWay) > 0.001 {
+			t.Errorf("expected path %.3f km, got %.3f", 2*oneWay, summary.PathDistanceKM)
+		}
+		if math.Abs(summary.MaxDistanceKM-oneWay) > 0.001 || summary.MinDistanceKM > 0.001 {
+			t.Errorf("unexpected min/max %.3f/%.3f", summary.MinDistanceKM, summary.MaxDistanceKM)
+		}
+		if math.Abs(summary.AvgDistanceKM-oneWay/2) > 0.001 {
+			t.Errorf("expected avg %.3f km, got %.3f", oneWay/2, summary.AvgDistanceKM)
+		}
+		// Half of each transition hour plus the two hours spent away
+		if summary.TimeAway != 3*time.Hour {
+			t.Errorf("expected 3h away, got %s", summary.TimeAway)
+		}
+	})
+}
@@ -4,6 +4,7 @@ package calculator
 
 import (
 	"math"
+	"time"
 )
 
 const (
@@ -11,10 +12,13 @@ const (
 	EarthRadiusKM = 6371.0
 )
 
-// Location represents a GPS coordinate
+// Location represents a GPS coordinate with the time it was recorded
 type Location struct {
+	DeviceID  string // device that recorded the fix, empty if unknown
 	Latitude  float64
 	Longitude float64
+	Altitude  float64 // meters above sea level
+	Timestamp time.Time
 }
 
 // DistanceFromHome calculates the great-circle distance between a location
@@ -72,36 +76,51 @@ type DistanceMetrics struct {
 
 // CalculateMetrics computes distance metrics for a set of locations
 func CalculateMetrics(homeLat, homeLon float64, locations []Location) DistanceMetrics {
-	if len(locations) == 0 {
-		return DistanceMetrics{}
+	acc := newMetricsAccumulator(homeLat, homeLon)
+	for _, loc := range locations {
+		acc.add(loc)
 	}
+	return acc.result()
+}
+
+// metricsAccumulator is the incremental form of CalculateMetrics
+type metricsAccumulator struct {
+	homeLat, homeLon float64
+	metrics          DistanceMetrics
+}
 
-	metrics := DistanceMetrics{
-		TotalLocations: len(locations),
-		MinDistanceKM:  math.MaxFloat64,
+// newMetricsAccumulator creates an accumulator measuring from home
+func newMetricsAccumulator(homeLat, homeLon float64) metricsAccumulator {
+	return metricsAccumulator{
+		homeLat: homeLat,
+		homeLon: homeLon,
+		metrics: DistanceMetrics{MinDistanceKM: math.MaxFloat64},
 	}
+}
 
-	var totalDistance float64
+// add records a location and returns its distance from home
+func (a *metricsAccumulator) add(loc Location) float64 {
+	distance := DistanceFromHome(a.homeLat, a.homeLon, loc.Latitude, loc.Longitude)
+	a.metrics.TotalLocations++
+	a.metrics.TotalDistanceKM += distance
 
-	for _, loc := range locations {
-		distance := DistanceFromHome(homeLat, homeLon, loc.Latitude, loc.Longitude)
-		totalDistance += distance
-
-		if distance > metrics.MaxDistanceKM {
-			metrics.MaxDistanceKM = distance
-		}
-		if distance < metrics.MinDistanceKM {
-			metrics.MinDistanceKM = distance
-		}
+	if distance > a.metrics.MaxDistanceKM {
+		a.metrics.MaxDistanceKM = distance
+	}
+	if distance < a.metrics.MinDistanceKM {
+		a.metrics.MinDistanceKM = distance
 	}
 
-	metrics.TotalDistanceKM = totalDistance
-	metrics.AvgDistanceKM = totalDistance / float64(len(locations))
+	return distance
+}
 
-	// Handle case where no locations were processed
-	if metrics.MinDistanceKM == math.MaxFloat64 {
-		metrics.MinDistanceKM = 0
+// result returns the metrics for every location added so far
+func (a *metricsAccumulator) result() DistanceMetrics {
+	if a.metrics.TotalLocations == 0 {
+		return DistanceMetrics{}
 	}
 
+	metrics := a.metrics
+	metrics.AvgDistanceKM = metrics.TotalDistanceKM / float64(metrics.TotalLocations)
 	return metrics
 }
@@ -0,0 +1,173 @@
+package calculator
+
+import (
+	"math"
+)
+
+const (
+	// ElevationSmoothingWindow is the number of fixes in the centered moving
+	// average applied to raw GPS altitudes
+	ElevationSmoothingWindow = 5
+
+	// ClimbThresholdM is the minimum change in smoothed altitude (meters)
+	// counted as ascent or descent, filtering out residual GPS noise
+	ClimbThresholdM = 3.0
+
+	// MaxProfileSamples caps the number of samples in each trip profile
+	MaxProfileSamples = 200
+)
+
+// ElevationStats holds climb statistics for a track or trip
+type ElevationStats struct {
+	AscentM      float64
+	DescentM     float64
+	MinAltitudeM float64
+	MaxAltitudeM float64
+}
+
+// ElevationSample is one point on an elevation profile
+type ElevationSample struct {
+	DistanceKM float64 // distance along the trip from its first fix
+	AltitudeM  float64 // smoothed altitude
+}
+
+// TripElevation is the elevation profile and climb statistics for one trip
+type TripElevation struct {
+	Trip
+	ElevationStats
+	Profile []ElevationSample
+}
+
+// meanAltitude returns the arithmetic mean of a non-empty window
+func meanAltitude(window []float64) float64 {
+	var sum float64
+	for _, alt := range window {
+		sum += alt
+	}
+	return sum / float64(len(window))
+}
+
+// climber accumulates ascent and descent from smoothed altitudes using
+// ClimbThresholdM hysteresis
+type climber struct {
+	started   bool
+	reference float64
+	stats     ElevationStats
+}
+
+// add records the next smoothed altitude and returns the running ascent
+func (c *climber) add(alt float64) float64 {
+	if !c.started {
+		c.started = true
+		c.reference = alt
+		c.stats.MinAltitudeM = alt
+		c.stats.MaxAltitudeM = alt
+	}
+
+	c.stats.MinAltitudeM = math.Min(c.stats.MinAltitudeM, alt)
+	c.stats.MaxAltitudeM = math.Max(c.stats.MaxAltitudeM, alt)
+
+	switch delta := alt - c.reference; {
+	case delta >= ClimbThresholdM:
+		c.stats.AscentM += delta
+		c.reference = alt
+	case -delta >= ClimbThresholdM:
+		c.stats.DescentM -= delta
+		c.reference = alt
+	}
+
+	return c.stats.AscentM
+}
+
+// result returns the accumulated statistics (all zero if nothing was added)
+func (c *climber) result() ElevationStats {
+	return c.stats
+}
+
+// profileBuilder collects elevation samples with bounded memory. When the
+// sample count exceeds MaxProfileSamples every other sample is dropped and the
+// sampling stride doubles, so the profile stays evenly spaced by fix.
+type profileBuilder struct {
+	samples []ElevationSample
+	stride  int
+	seen    int
+	last    ElevationSample
+}
+
+// add offers the next sample to the profile
+func (p *profileBuilder) add(sample ElevationSample) {
+	if p.stride == 0 {
+		p.stride = 1
+	}
+
+	if p.seen%p.stride == 0 {
+		p.samples = append(p.samples, sample)
+		if len(p.samples) > MaxProfileSamples {
+			kept := p.samples[:0]
+			for i := 0; i < len(p.samples); i += 2 {
+				kept = append(kept, p.samples[i])
+			}
+			p.samples = kept
+			p.stride *= 2
+		}
+	}
+
+	p.seen++
+	p.last = sample
+}
+
+// result returns the sampled profile, always ending with the last sample added
+func (p *profileBuilder) result() []ElevationSample {
+	profile := append([]ElevationSample(nil), p.samples...)
+	if p.seen > 0 && (len(profile) == 0 || profile[len(profile)-1] != p.last) {
+		profile = append(profile, p.last)
+	}
+	return profile
+}
+
+// tripBuilder accumulates the elevation profile of a trip in progress.
+// Fixes are added tentatively and only become part of the trip on commit, so
+// a trailing stationary dwell that ends the trip is left out.
+type tripBuilder struct {
+	committed climber
+	pending   climber
+	distance  float64
+	last      Location
+	profile   profileBuilder
+	tail      []ElevationSample
+}
+
+// newTripBuilder starts a trip profile at the given fix and smoothed altitude
+func newTripBuilder(start Location, altitude float64) *tripBuilder {
+	b := &tripBuilder{last: start}
+	b.pending.add(altitude)
+	b.committed = b.pending
+	b.profile.add(ElevationSample{DistanceKM: 0, AltitudeM: altitude})
+	return b
+}
+
+// add tentatively extends the trip to loc
+func (b *tripBuilder) add(loc Location, altitude float64) {
+	b.distance += Haversine(b.last.Latitude, b.last.Longitude, loc.Latitude, loc.Longitude)
+	b.last = loc
+	b.pending.add(altitude)
+	b.tail = append(b.tail, ElevationSample{DistanceKM: b.distance, AltitudeM: altitude})
+}
+
+// commit makes every tentatively added fix part of the trip
+func (b *tripBuilder) commit() {
+	for _, sample := range b.tail {
+		b.profile.add(sample)
+	}
+	b.tail = b.tail[:0]
+	b.committed = b.pending
+}
+
+// finish returns the committed elevation profile for trip
+func (b *tripBuilder) finish(trip Trip) TripElevation {
+	return TripElevation{
+		Trip:           trip,
+		ElevationStats: b.committed.result(),
+		Profile:        b.profile.result(),
+	}
+}
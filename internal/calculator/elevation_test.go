@@ -0,0 +1,103 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTrackAnalyzer_SmoothedAltitude(t *testing.T) {
+	locations := trackAtSpeed(time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC), 5, 15)
+	for i, alt := range []float64{10, 10, 25, 10, 10} {
+		locations[i].Altitude = alt
+	}
+
+	points, _ := analyzeTrack(t, 0, 0, locations)
+	expected := []float64{15, 13.75, 13, 13.75, 15}
+	for i := range expected {
+		if math.Abs(points[i].SmoothedAltitudeM-expected[i]) > 0.001 {
+			t.Errorf("index %d: expected %.2f, got %.2f", i, expected[i], points[i].SmoothedAltitudeM)
+		}
+	}
+}
+
+func TestTrackAnalyzer_Elevation(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+
+	t.Run("empty locations", func(t *testing.T) {
+		_, summary := analyzeTrack(t, 0, 0, nil)
+		if summary.Elevation.AscentM != 0 || summary.Elevation.DescentM != 0 {
+			t.Errorf("expected zero climb, got +%.1f/-%.1f", summary.Elevation.AscentM, summary.Elevation.DescentM)
+		}
+	})
+
+	t.Run("noise below threshold is ignored", func(t *testing.T) {
+		locations := trackAtSpeed(start, 20, 15)
+		for i := range locations {
+			locations[i].Altitude = 50 + float64(i%2) // 1 m jitter
+		}
+
+		_, summary := analyzeTrack(t, 0, 0, locations)
+		if summary.Elevation.AscentM != 0 || summary.Elevation.DescentM != 0 {
+			t.Errorf("expected jitter to be filtered, got +%.1f/-%.1f", summary.Elevation.AscentM, summary.Elevation.DescentM)
+		}
+	})
+
+	t.Run("climb and descent", func(t *testing.T) {
+		locations := trackAtSpeed(start, 41, 15)
+		for i := range locations {
+			// Climb 100 m over 20 fixes, then descend 60 m
+			if i <= 20 {
+				locations[i].Altitude = 10 + float64(i)*5
+			} else {
+				locations[i].Altitude = 110 - float64(i-20)*3
+			}
+		}
+
+		points, summary := analyzeTrack(t, 0, 0, locations)
+		elevation := summary.Elevation
+
+		// Smoothing rounds off the start, peak and end of the raw profile
+		if elevation.AscentM < 80 || elevation.AscentM > 100 {
+			t.Errorf("expected ~90 m ascent, got %.1f", elevation.AscentM)
+		}
+		if elevation.DescentM < 45 || elevation.DescentM > 60 {
+			t.Errorf("expected ~55 m descent, got %.1f", elevation.DescentM)
+		}
+		if elevation.MaxAltitudeM > 110 || elevation.MaxAltitudeM < 95 {
+			t.Errorf("expected smoothed max near 110 m, got %.1f", elevation.MaxAltitudeM)
+		}
+		if got := points[len(points)-1].CumulativeAscentM; got != elevation.AscentM {
+			t.Errorf("expected cumulative ascent to end at %.1f, got %.1f", elevation.AscentM, got)
+		}
+
+		if len(summary.Trips) != 1 {
+			t.Fatalf("expected 1 trip, got %d", len(summary.Trips))
+		}
+		profile := summary.Trips[0].Profile
+		if len(profile) != len(locations) {
+			t.Errorf("expected %d profile samples, got %d", len(locations), len(profile))
+		}
+		if profile[0].DistanceKM != 0 {
+			t.Errorf("expected profile to start at 0 km, got %.3f", profile[0].DistanceKM)
+		}
+		if math.Abs(profile[len(profile)-1].DistanceKM-10) > 0.05 {
+			t.Errorf("expected profile to end near 10 km, got %.3f", profile[len(profile)-1].DistanceKM)
+		}
+	})
+
+	t.Run("long profile is downsampled", func(t *testing.T) {
+		_, summary := analyzeTrack(t, 0, 0, trackAtSpeed(start, 1000, 15))
+		if len(summary.Trips) != 1 {
+			t.Fatalf("expected 1 trip, got %d", len(summary.Trips))
+		}
+
+		trip := summary.Trips[0]
+		if n := len(trip.Profile); n > MaxProfileSamples+1 || n < MaxProfileSamples/2 {
+			t.Errorf("expected %d-%d profile samples, got %d", MaxProfileSamples/2, MaxProfileSamples+1, n)
+		}
+		if last := trip.Profile[len(trip.Profile)-1]; math.Abs(last.DistanceKM-trip.DistanceKM) > 0.001 {
+			t.Errorf("expected profile to end at trip distance %.3f, got %.3f", trip.DistanceKM, last.DistanceKM)
+		}
+	})
+}
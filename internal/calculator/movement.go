@@ -0,0 +1,109 @@
+package calculator
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MovementMode classifies how a device was moving between two fixes
+type MovementMode string
+
+// Movement mode constants ordered by increasing speed
+const (
+	ModeStationary MovementMode = "stationary"
+	ModeWalking    MovementMode = "walking"
+	ModeCycling    MovementMode = "cycling"
+	ModeDriving    MovementMode = "driving"
+	ModeFlying     MovementMode = "flying"
+)
+
+// MovementModes lists every mode in classification order
+var MovementModes = []MovementMode{
+	ModeStationary,
+	ModeWalking,
+	ModeCycling,
+	ModeDriving,
+	ModeFlying,
+}
+
+// Upper speed bounds (km/h) used by ClassifySpeed. Speeds at or above
+// DrivingMaxSpeedKMH are treated as flying.
+const (
+	StationaryMaxSpeedKMH = 1.5
+	WalkingMaxSpeedKMH    = 7.0
+	CyclingMaxSpeedKMH    = 25.0
+	DrivingMaxSpeedKMH    = 200.0
+)
+
+// smoothingWindow is the number of segments used for the rolling median
+// that damps speed jitter before classification
+const smoothingWindow = 3
+
+// spikeRatio is how much faster both segments around a fix must be than the
+// segment bypassing it for the fix to be treated as a GPS jump
+const spikeRatio = 2.0
+
+// PointKinematics holds the derived motion values for a single fix,
+// computed from the segment that ends at that fix
+type PointKinematics struct {
+	SpeedKMH        float64
+	AccelerationMS2 float64
+	Mode            MovementMode
+}
+
+// ModeTotal accumulates distance and time spent in one movement mode
+type ModeTotal struct {
+	Mode       MovementMode
+	DistanceKM float64
+	Duration   time.Duration
+}
+
+// ClassifySpeed maps a speed in km/h to a movement mode
+func ClassifySpeed(speedKMH float64) MovementMode {
+	switch {
+	case speedKMH < StationaryMaxSpeedKMH:
+		return ModeStationary
+	case speedKMH < WalkingMaxSpeedKMH:
+		return ModeWalking
+	case speedKMH < CyclingMaxSpeedKMH:
+		return ModeCycling
+	case speedKMH < DrivingMaxSpeedKMH:
+		return ModeDriving
+	default:
+		return ModeFlying
+	}
+}
+
+// isSpike reports whether the fix between prev and next is a single
+// out-of-line GPS jump, returning the bypass speed to use in its place.
+// inSpeed and outSpeed are the raw speeds of the segments into and out of it.
+func isSpike(prev, next Location, inSpeed, outSpeed float64) (float64, bool) {
+	elapsed := next.Timestamp.Sub(prev.Timestamp)
+	if elapsed <= 0 {
+		return 0, false
+	}
+
+	bypass := Haversine(prev.Latitude, prev.Longitude, next.Latitude, next.Longitude) / elapsed.Hours()
+	slowest := math.Min(inSpeed, outSpeed)
+	if slowest > bypass*spikeRatio && ClassifySpeed(slowest) != ClassifySpeed(bypass) {
+		return bypass, true
+	}
+	return 0, false
+}
+
+// medianSpeed returns the median of a small window of speeds
+func medianSpeed(speeds []float64) float64 {
+	window := append([]float64(nil), speeds...)
+	sort.Float64s(window)
+	return window[len(window)/2]
+}
+
+// collectTotals flattens the totals map into MovementModes order
+func collectTotals(totals map[MovementMode]*ModeTotal) []ModeTotal {
+	result := make([]ModeTotal, 0, len(MovementModes))
+	for _, mode := range MovementModes {
+		result = append(result, *totals[mode])
+	}
+	return result
+}
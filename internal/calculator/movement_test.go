@@ -0,0 +1,135 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestClassifySpeed(t *testing.T) {
+	tests := []struct {
+		speed    float64
+		expected MovementMode
+	}{
+		{0, ModeStationary},
+		{1.0, ModeStationary},
+		{4.5, ModeWalking},
+		{18, ModeCycling},
+		{90, ModeDriving},
+		{850, ModeFlying},
+	}
+
+	for _, tt := range tests {
+		if mode := ClassifySpeed(tt.speed); mode != tt.expected {
+			t.Errorf("ClassifySpeed(%.1f) = %s, expected %s", tt.speed, mode, tt.expected)
+		}
+	}
+}
+
+// trackAtSpeed builds a northbound track of n fixes one minute apart at the given speed
+func trackAtSpeed(start time.Time, n int, speedKMH float64) []Location {
+	// One degree of latitude is ~111.195 km for EarthRadiusKM
+	kmPerDegree := EarthRadiusKM * math.Pi / 180
+	step := speedKMH / 60 / kmPerDegree
+
+	locations := make([]Location, n)
+	for i := range locations {
+		locations[i] = Location{
+			Latitude:  40.0 + float64(i)*step,
+			Longitude: -74.0,
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return locations
+}
+
+func TestTrackAnalyzer_Movement(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+
+	t.Run("empty locations", func(t *testing.T) {
+		points, summary := analyzeTrack(t, 0, 0, nil)
+		if len(points) != 0 {
+			t.Errorf("expected no points, got %d", len(points))
+		}
+		if len(summary.ModeTotals) != len(MovementModes) {
+			t.Errorf("expected %d totals, got %d", len(MovementModes), len(summary.ModeTotals))
+		}
+	})
+
+	t.Run("constant walking speed", func(t *testing.T) {
+		points, summary := analyzeTrack(t, 0, 0, trackAtSpeed(start, 11, 5.0))
+
+		for i, p := range points[1:] {
+			if math.Abs(p.SpeedKMH-5.0) > 0.01 {
+				t.Errorf("point %d: expected speed 5.0 km/h, got %.3f", i+1, p.SpeedKMH)
+			}
+			if p.Mode != ModeWalking {
+				t.Errorf("point %d: expected walking, got %s", i+1, p.Mode)
+			}
+			if math.Abs(p.AccelerationMS2) > 0.001 {
+				t.Errorf("point %d: expected zero acceleration, got %.4f", i+1, p.AccelerationMS2)
+			}
+		}
+
+		walking := summary.ModeTotals[1]
+		if walking.Mode != ModeWalking {
+			t.Fatalf("expected totals in MovementModes order, got %s", walking.Mode)
+		}
+		if walking.Duration != 10*time.Minute {
+			t.Errorf("expected 10m walking, got %s", walking.Duration)
+		}
+		if math.Abs(walking.DistanceKM-5.0/6) > 0.01 {
+			t.Errorf("expected ~0.83 km walking, got %.3f", walking.DistanceKM)
+		}
+	})
+
+	t.Run("single GPS jump is smoothed", func(t *testing.T) {
+		locations := trackAtSpeed(start, 6, 0)
+		locations[3].Latitude += 0.01 // ~1.1 km spike for one fix
+
+		points, summary := analyzeTrack(t, 0, 0, locations)
+		if summary.MaxSpeedKMH < 60 {
+			t.Errorf("expected raw spike in MaxSpeedKMH, got %.1f", summary.MaxSpeedKMH)
+		}
+		for i, p := range points {
+			if p.Mode != ModeStationary {
+				t.Errorf("point %d: expected stationary after smoothing, got %s", i, p.Mode)
+			}
+		}
+	})
+
+	t.Run("acceleration from walking to driving", func(t *testing.T) {
+		walk := trackAtSpeed(start, 2, 3.6)
+		last := walk[1]
+		kmPerDegree := EarthRadiusKM * math.Pi / 180
+		drive := Location{
+			Latitude:  last.Latitude + 36.0/60/kmPerDegree,
+			Longitude: last.Longitude,
+			Timestamp: last.Timestamp.Add(time.Minute),
+		}
+
+		points, _ := analyzeTrack(t, 0, 0, append(walk, drive))
+		// 1 m/s to 10 m/s over 60 seconds
+		if got := points[2].AccelerationMS2; math.Abs(got-0.15) > 0.001 {
+			t.Errorf("expected acceleration 0.15 m/s², got %.4f", got)
+		}
+	})
+
+	t.Run("duplicate fixes have zero speed", func(t *testing.T) {
+		locations := trackAtSpeed(start, 3, 5.0)
+		locations[2].Timestamp = locations[1].Timestamp
+
+		points, summary := analyzeTrack(t, 0, 0, locations)
+		if points[2].SpeedKMH != 0 {
+			t.Errorf("expected zero speed for a duplicate fix, got %.2f", points[2].SpeedKMH)
+		}
+
+		var total time.Duration
+		for _, mt := range summary.ModeTotals {
+			total += mt.Duration
+		}
+		if total != time.Minute {
+			t.Errorf("expected 1m of totals, got %s", total)
+		}
+	})
+}
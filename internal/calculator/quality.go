@@ -0,0 +1,188 @@
+package calculator
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DefaultGapThreshold is the shortest interval without fixes reported as a
+// coverage gap when no threshold is given
+const DefaultGapThreshold = time.Hour
+
+// accuracyBounds are the upper bounds in meters of the accuracy histogram
+// buckets; a final bucket holds everything above the last bound
+var accuracyBounds = []int{5, 10, 25, 50, 100, 500}
+
+// QualityFix is a stored location as seen by the data quality checks
+type QualityFix struct {
+	Timestamp time.Time // when the device recorded the fix
+	Latitude  float64
+	Longitude float64
+	Accuracy  int      // meters; 0 when not reported
+	Missing   []string // columns that were NULL or empty
+}
+
+// CoverageGap is a period in which a device reported no fixes
+type CoverageGap struct {
+	Start time.Time // last fix before the gap
+	End   time.Time // first fix after the gap
+}
+
+// Duration returns how long the device was silent
+func (g CoverageGap) Duration() time.Duration {
+	return g.End.Sub(g.Start)
+}
+
+// AccuracyBucket counts fixes whose accuracy is above the previous bucket's
+// bound and at most MaxM meters. MaxM is 0 for the open-ended last bucket.
+type AccuracyBucket struct {
+	MaxM  int
+	Count int
+}
+
+// AccuracyStats describes the reported horizontal accuracy of a device's fixes
+type AccuracyStats struct {
+	Reported int // fixes with an accuracy
+	MedianM  float64
+	P90M     float64
+	MaxM     int
+	Buckets  []AccuracyBucket
+}
+
+// QualityReport summarizes data quality problems in one device's fixes
+type QualityReport struct {
+	Points     int
+	FirstFix   time.Time
+	LastFix    time.Time
+	Gaps       []CoverageGap
+	GapTotal   time.Duration
+	Duplicates int // fixes repeating an earlier fix's time and position
+	OutOfOrder int // fixes recorded before the fix stored ahead of them
+	Missing    map[string]int
+	Accuracy   AccuracyStats
+}
+
+// fixKey identifies a fix for duplicate detection; retried uploads repeat
+// the device timestamp and position exactly
+type fixKey struct {
+	unix     int64
+	lat, lon float64
+}
+
+// QualityAnalyzer checks one device's fixes in the order they were stored.
+// Its memory use grows with the number of fixes, since duplicates may be
+// stored far apart.
+type QualityAnalyzer struct {
+	gapThreshold time.Duration
+	report       QualityReport
+	seen         map[fixKey]struct{}
+	prev         time.Time // timestamp of the previous non-duplicate fix
+	accuracies   []int
+}
+
+// NewQualityAnalyzer creates an analyzer reporting gaps longer than
+// gapThreshold, or DefaultGapThreshold if it is not positive
+func NewQualityAnalyzer(gapThreshold time.Duration) *QualityAnalyzer {
+	if gapThreshold <= 0 {
+		gapThreshold = DefaultGapThreshold
+	}
+	return &QualityAnalyzer{
+		gapThreshold: gapThreshold,
+		report:       QualityReport{Missing: make(map[string]int)},
+		seen:         make(map[fixKey]struct{}),
+	}
+}
+
+// Add records the next fix in storage order. Duplicates are counted and
+// otherwise ignored, so a retried upload is not also out of order.
+func (q *QualityAnalyzer) Add(fix QualityFix) {
+	report := &q.report
+	report.Points++
+	for _, field := range fix.Missing {
+		report.Missing[field]++
+	}
+
+	key := fixKey{unix: fix.Timestamp.Unix(), lat: fix.Latitude, lon: fix.Longitude}
+	if _, ok := q.seen[key]; ok {
+		report.Duplicates++
+		return
+	}
+	q.seen[key] = struct{}{}
+
+	if fix.Accuracy > 0 {
+		q.accuracies = append(q.accuracies, fix.Accuracy)
+	}
+
+	if q.prev.IsZero() {
+		report.FirstFix, report.LastFix = fix.Timestamp, fix.Timestamp
+		q.prev = fix.Timestamp
+		return
+	}
+
+	if fix.Timestamp.Before(q.prev) {
+		report.OutOfOrder++
+	}
+	q.prev = fix.Timestamp
+
+	// Gaps are measured from the latest fix so far, so a late upload of an
+	// old fix does not open one
+	if gap := fix.Timestamp.Sub(report.LastFix); gap > q.gapThreshold {
+		report.Gaps = append(report.Gaps, CoverageGap{Start: report.LastFix, End: fix.Timestamp})
+		report.GapTotal += gap
+	}
+	if fix.Timestamp.Before(report.FirstFix) {
+		report.FirstFix = fix.Timestamp
+	}
+	if fix.Timestamp.After(report.LastFix) {
+		report.LastFix = fix.Timestamp
+	}
+}
+
+// Report returns the quality checks for every fix added so far
+func (q *QualityAnalyzer) Report() QualityReport {
+	report := q.report
+	report.Gaps = append([]CoverageGap(nil), q.report.Gaps...)
+	report.Missing = make(map[string]int, len(q.report.Missing))
+	for field, n := range q.report.Missing {
+		report.Missing[field] = n
+	}
+	report.Accuracy = accuracyStats(q.accuracies)
+	return report
+}
+
+// accuracyStats returns the median, 90th percentile, maximum and histogram
+// of accuracies in meters
+func accuracyStats(accuracies []int) AccuracyStats {
+	stats := AccuracyStats{Reported: len(accuracies)}
+	for _, bound := range accuracyBounds {
+		stats.Buckets = append(stats.Buckets, AccuracyBucket{MaxM: bound})
+	}
+	stats.Buckets = append(stats.Buckets, AccuracyBucket{})
+
+	if len(accuracies) == 0 {
+		return stats
+	}
+
+	sorted := append([]int(nil), accuracies...)
+	sort.Ints(sorted)
+	stats.MedianM = percentile(sorted, 0.5)
+	stats.P90M = percentile(sorted, 0.9)
+	stats.MaxM = sorted[len(sorted)-1]
+
+	for _, a := range sorted {
+		i := sort.SearchInts(accuracyBounds, a)
+		stats.Buckets[i].Count++
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating
+// linearly between the closest ranks
+func percentile(sorted []int, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	frac := rank - float64(lo)
+	return float64(sorted[lo]) + frac*float64(sorted[hi]-sorted[lo])
+}
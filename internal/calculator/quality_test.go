@@ -0,0 +1,86 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestQualityAnalyzer(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	fix := func(offset time.Duration, lat float64, accuracy int, missing ...string) QualityFix {
+		return QualityFix{Timestamp: start.Add(offset), Latitude: lat, Longitude: -74.0, Accuracy: accuracy, Missing: missing}
+	}
+
+	t.Run("no fixes", func(t *testing.T) {
+		report := NewQualityAnalyzer(0).Report()
+		if report.Points != 0 || len(report.Gaps) != 0 || report.Accuracy.Reported != 0 {
+			t.Errorf("expected empty report, got %+v", report)
+		}
+		if len(report.Accuracy.Buckets) != len(accuracyBounds)+1 {
+			t.Errorf("expected %d empty buckets, got %d", len(accuracyBounds)+1, len(report.Accuracy.Buckets))
+		}
+	})
+
+	t.Run("gaps, duplicates and out of order", func(t *testing.T) {
+		analyzer := NewQualityAnalyzer(30 * time.Minute)
+		for _, f := range []QualityFix{
+			fix(0, 40.0, 5),
+			fix(10*time.Minute, 40.1, 8),
+			fix(10*time.Minute, 40.1, 8), // retried upload
+			fix(3*time.Hour, 40.2, 20, "battery"),
+			fix(2*time.Hour, 40.3, 0, "accuracy"), // late upload from inside the gap
+			fix(3*time.Hour+20*time.Minute, 40.4, 800, "battery", "trigger"),
+		} {
+			analyzer.Add(f)
+		}
+
+		report := analyzer.Report()
+		if report.Points != 6 {
+			t.Errorf("expected 6 points, got %d", report.Points)
+		}
+		if report.Duplicates != 1 {
+			t.Errorf("expected 1 duplicate, got %d", report.Duplicates)
+		}
+		if report.OutOfOrder != 1 {
+			t.Errorf("expected 1 out-of-order fix, got %d", report.OutOfOrder)
+		}
+		if len(report.Gaps) != 1 || report.Gaps[0].Duration() != 2*time.Hour+50*time.Minute {
+			t.Fatalf("expected one 2h50m gap, got %+v", report.Gaps)
+		}
+		if report.GapTotal != report.Gaps[0].Duration() {
+			t.Errorf("expected gap total %s, got %s", report.Gaps[0].Duration(), report.GapTotal)
+		}
+		if !report.FirstFix.Equal(start) || !report.LastFix.Equal(start.Add(3*time.Hour+20*time.Minute)) {
+			t.Errorf("unexpected fix range %s - %s", report.FirstFix, report.LastFix)
+		}
+		if report.Missing["battery"] != 2 || report.Missing["accuracy"] != 1 || report.Missing["trigger"] != 1 {
+			t.Errorf("unexpected missing counts %v", report.Missing)
+		}
+	})
+
+	t.Run("accuracy distribution", func(t *testing.T) {
+		analyzer := NewQualityAnalyzer(0)
+		for i, accuracy := range []int{3, 5, 10, 12, 40, 90, 600, 0} {
+			analyzer.Add(fix(time.Duration(i)*time.Minute, 40.0+float64(i)*0.001, accuracy))
+		}
+
+		stats := analyzer.Report().Accuracy
+		if stats.Reported != 7 || stats.MaxM != 600 {
+			t.Errorf("expected 7 reported up to 600 m, got %d up to %d", stats.Reported, stats.MaxM)
+		}
+		if stats.MedianM != 12 {
+			t.Errorf("expected median 12 m, got %.1f", stats.MedianM)
+		}
+		if math.Abs(stats.P90M-294) > 1e-9 {
+			t.Errorf("expected p90 294 m, got %.1f", stats.P90M)
+		}
+
+		want := []int{2, 1, 1, 1, 1, 0, 1}
+		for i, bucket := range stats.Buckets {
+			if bucket.Count != want[i] {
+				t.Errorf("bucket %d (<= %d m): expected %d, got %d", i, bucket.MaxM, want[i], bucket.Count)
+			}
+		}
+	})
+}
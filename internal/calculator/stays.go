@@ -0,0 +1,70 @@
+package calculator
+
+import (
+	"time"
+)
+
+// MinStayDuration is the shortest time between trips reported as a stay
+const MinStayDuration = TripGapThreshold
+
+// Stay is a period a device spent in one place between trips, identified by
+// index into the location slice it was detected from (EndIndex is inclusive)
+type Stay struct {
+	StartIndex int
+	EndIndex   int
+	StartTime  time.Time
+	EndTime    time.Time
+	Latitude   float64 // mean position of the fixes
+	Longitude  float64
+	PointCount int
+}
+
+// Duration returns the elapsed time between the first and last fix of the stay
+func (s Stay) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// DetectStays returns the runs of fixes outside trips that last at least
+// MinStayDuration. Trips must come from the same chronological locations,
+// as reported in TrackAnalyzer.Summary. The fix a trip starts from
+// belongs to the stay before it.
+func DetectStays(locations []Location, trips []Trip) []Stay {
+	var stays []Stay
+	start := 0
+	for _, trip := range trips {
+		if stay, ok := newStay(locations, start, trip.StartIndex); ok {
+			stays = append(stays, stay)
+		}
+		start = trip.EndIndex + 1
+	}
+	if stay, ok := newStay(locations, start, len(locations)-1); ok {
+		stays = append(stays, stay)
+	}
+	return stays
+}
+
+// newStay returns the stay covering locations[from:to+1], or false if it is
+// shorter than MinStayDuration
+func newStay(locations []Location, from, to int) (Stay, bool) {
+	if from < 0 || to >= len(locations) || to <= from {
+		return Stay{}, false
+	}
+	stay := Stay{
+		StartIndex: from,
+		EndIndex:   to,
+		StartTime:  locations[from].Timestamp,
+		EndTime:    locations[to].Timestamp,
+		PointCount: to - from + 1,
+	}
+	if stay.Duration() < MinStayDuration {
+		return Stay{}, false
+	}
+
+	for _, loc := range locations[from : to+1] {
+		stay.Latitude += loc.Latitude
+		stay.Longitude += loc.Longitude
+	}
+	stay.Latitude /= float64(stay.PointCount)
+	stay.Longitude /= float64(stay.PointCount)
+	return stay, true
+}
@@ -0,0 +1,50 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDetectStays(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	fixes := func(from time.Time, n int, lat float64) []Location {
+		locations := make([]Location, n)
+		for i := range locations {
+			locations[i] = Location{Latitude: lat, Longitude: -74.0, Timestamp: from.Add(time.Duration(i) * time.Minute)}
+		}
+		return locations
+	}
+
+	// 20 minutes at home, a trip, 5 minutes at a light, a trip, 30 minutes at work
+	locations := fixes(start, 21, 40.0)
+	locations = append(locations, fixes(start.Add(21*time.Minute), 10, 40.01)...)
+	locations = append(locations, fixes(start.Add(31*time.Minute), 6, 40.02)...)
+	locations = append(locations, fixes(start.Add(37*time.Minute), 10, 40.03)...)
+	locations = append(locations, fixes(start.Add(47*time.Minute), 31, 40.04)...)
+
+	trips := []Trip{
+		{StartIndex: 20, EndIndex: 30},
+		{StartIndex: 36, EndIndex: 46},
+	}
+
+	stays := DetectStays(locations, trips)
+	if len(stays) != 2 {
+		t.Fatalf("expected home and work stays, got %+v", stays)
+	}
+
+	home, work := stays[0], stays[1]
+	if home.StartIndex != 0 || home.EndIndex != 20 || home.Duration() != 20*time.Minute || home.Latitude != 40.0 {
+		t.Errorf("unexpected home stay %+v", home)
+	}
+	if work.StartIndex != 47 || work.EndIndex != len(locations)-1 || work.PointCount != 31 || math.Abs(work.Latitude-40.04) > 1e-9 {
+		t.Errorf("unexpected work stay %+v", work)
+	}
+
+	if stays := DetectStays(locations[:1], nil); len(stays) != 0 {
+		t.Errorf("expected no stay for a single fix, got %+v", stays)
+	}
+	if stays := DetectStays(locations, nil); len(stays) != 1 || stays[0].PointCount != len(locations) {
+		t.Errorf("expected one stay for a track without trips, got %+v", stays)
+	}
+}
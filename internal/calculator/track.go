@@ -0,0 +1,314 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// analyzerLookahead is how many later fixes must arrive before a fix is
+	// final: the centered altitude average looks two fixes ahead, and spike
+	// removal (which needs one) is covered by that
+	analyzerLookahead = ElevationSmoothingWindow / 2
+
+	// analyzerHistory is how many already emitted fixes are kept for the
+	// trailing speed median and the centered altitude average
+	analyzerHistory = max(smoothingWindow-1, ElevationSmoothingWindow/2)
+)
+
+var (
+	// ErrDeviceChanged is returned by TrackAnalyzer.Push for a fix recorded
+	// by a different device than the fixes before it
+	ErrDeviceChanged = errors.New("fix is from a different device")
+
+	// ErrOutOfOrder is returned by TrackAnalyzer.Push for a fix recorded
+	// before the previous one
+	ErrOutOfOrder = errors.New("fix is older than the previous fix")
+)
+
+// AnalyzedPoint is a fix with every per-point value derived by TrackAnalyzer
+type AnalyzedPoint struct {
+	Location
+	PointKinematics
+	DistanceFromHomeKM float64
+	SmoothedAltitudeM  float64
+	CumulativeAscentM  float64
+}
+
+// TrackSummary holds the track-level results of a TrackAnalyzer
+type TrackSummary struct {
+	Metrics     DistanceMetrics
+	ModeTotals  []ModeTotal
+	MaxSpeedKMH float64
+	Elevation   ElevationStats
+	Trips       []TripElevation
+}
+
+// bufferedPoint is a fix held by TrackAnalyzer until it is final
+type bufferedPoint struct {
+	loc      Location
+	distance float64       // km from the previous fix
+	duration time.Duration // time since the previous fix
+	rawSpeed float64       // km/h over the segment ending at this fix
+	cleaned  float64       // rawSpeed after spike removal, used to classify
+}
+
+// TrackAnalyzer computes per-fix kinematics, smoothed elevation and trip
+// statistics for a chronological track one fix at a time. It holds only a
+// small window of fixes, so memory use does not depend on track length.
+//
+// A track is the fixes of a single device in time order. Push rejects a fix
+// from another device (ErrDeviceChanged) or older than the previous fix
+// (ErrOutOfOrder), leaving the track unchanged, since speeds computed across
+// either would be meaningless. Callers with several devices use one analyzer
+// per device and combine the results with MergeSummaries.
+//
+// Each call to Push returns the fixes that became final (their values no
+// longer depend on later fixes), in order. Flush returns the rest once the
+// track has ended; Summary is complete after Flush.
+type TrackAnalyzer struct {
+	metrics metricsAccumulator
+
+	buffer  []bufferedPoint
+	first   int // track index of buffer[0]
+	pushed  int // number of fixes pushed
+	emitted int // number of fixes returned
+
+	totals   map[MovementMode]*ModeTotal
+	maxSpeed float64
+	climb    climber
+
+	tracker  tripTracker
+	trip     *tripBuilder
+	trips    []TripElevation
+	previous AnalyzedPoint
+}
+
+// NewTrackAnalyzer creates an analyzer measuring distance from the given home
+func NewTrackAnalyzer(homeLat, homeLon float64) *TrackAnalyzer {
+	a := &TrackAnalyzer{
+		metrics: newMetricsAccumulator(homeLat, homeLon),
+		totals:  make(map[MovementMode]*ModeTotal, len(MovementModes)),
+	}
+	for _, mode := range MovementModes {
+		a.totals[mode] = &ModeTotal{Mode: mode}
+	}
+	return a
+}
+
+// Push adds the next fix and returns any fixes that are now final. A
+// rejected fix returns an error and is not part of the track.
+func (a *TrackAnalyzer) Push(loc Location) ([]AnalyzedPoint, error) {
+	n := a.pushed
+	if n > 0 {
+		prev := a.at(n - 1).loc
+		if loc.DeviceID != prev.DeviceID {
+			return nil, fmt.Errorf("%w: %q after %q", ErrDeviceChanged, loc.DeviceID, prev.DeviceID)
+		}
+		if loc.Timestamp.Before(prev.Timestamp) {
+			return nil, fmt.Errorf("%w: %s after %s", ErrOutOfOrder, loc.Timestamp.Format(time.RFC3339), prev.Timestamp.Format(time.RFC3339))
+		}
+	}
+	a.pushed++
+
+	p := bufferedPoint{loc: loc}
+	if n > 0 {
+		prev := a.at(n - 1).loc
+		p.distance = Haversine(prev.Latitude, prev.Longitude, loc.Latitude, loc.Longitude)
+		p.duration = loc.Timestamp.Sub(prev.Timestamp)
+		if p.duration > 0 {
+			p.rawSpeed = p.distance / p.duration.Hours()
+		}
+	}
+	p.cleaned = p.rawSpeed
+	a.buffer = append(a.buffer, p)
+
+	// The fix before this one can now be checked for a GPS jump
+	if n >= 2 {
+		before, mid, after := a.at(n-2), a.at(n-1), a.at(n)
+		if bypass, ok := isSpike(before.loc, after.loc, mid.rawSpeed, after.rawSpeed); ok {
+			mid.cleaned = bypass
+			after.cleaned = bypass
+		}
+	}
+
+	var ready []AnalyzedPoint
+	for a.emitted+analyzerLookahead <= n {
+		ready = append(ready, a.emit())
+	}
+	a.trim()
+	return ready, nil
+}
+
+// Flush returns every remaining fix and closes any trip in progress
+func (a *TrackAnalyzer) Flush() []AnalyzedPoint {
+	var ready []AnalyzedPoint
+	for a.emitted < a.pushed {
+		ready = append(ready, a.emit())
+	}
+	a.trim()
+
+	if finished := a.tracker.close(); finished != nil && a.trip != nil {
+		a.trips = append(a.trips, a.trip.finish(*finished))
+	}
+	a.trip = nil
+
+	return ready
+}
+
+// Summary returns the track-level results for every fix emitted so far
+func (a *TrackAnalyzer) Summary() TrackSummary {
+	return TrackSummary{
+		Metrics:     a.metrics.result(),
+		ModeTotals:  collectTotals(a.totals),
+		MaxSpeedKMH: a.maxSpeed,
+		Elevation:   a.climb.result(),
+		Trips:       append([]TripElevation(nil), a.trips...),
+	}
+}
+
+// MergeSummaries combines the summaries of tracks analyzed separately, such
+// as one per device, into one. Trips are ordered by start time; their
+// indexes stay positions in their own track.
+func MergeSummaries(summaries ...TrackSummary) TrackSummary {
+	merged := TrackSummary{ModeTotals: make([]ModeTotal, len(MovementModes))}
+	for i, mode := range MovementModes {
+		merged.ModeTotals[i].Mode = mode
+	}
+
+	metrics, elevation := &merged.Metrics, &merged.Elevation
+	for _, summary := range summaries {
+		if summary.Metrics.TotalLocations == 0 {
+			continue
+		}
+		if metrics.TotalLocations == 0 {
+			metrics.MinDistanceKM = summary.Metrics.MinDistanceKM
+			elevation.MinAltitudeM = summary.Elevation.MinAltitudeM
+			elevation.MaxAltitudeM = summary.Elevation.MaxAltitudeM
+		}
+
+		metrics.TotalLocations += summary.Metrics.TotalLocations
+		metrics.TotalDistanceKM += summary.Metrics.TotalDistanceKM
+		metrics.MaxDistanceKM = math.Max(metrics.MaxDistanceKM, summary.Metrics.MaxDistanceKM)
+		metrics.MinDistanceKM = math.Min(metrics.MinDistanceKM, summary.Metrics.MinDistanceKM)
+
+		// Mode totals are always in MovementModes order
+		for i, total := range summary.ModeTotals {
+			merged.ModeTotals[i].DistanceKM += total.DistanceKM
+			merged.ModeTotals[i].Duration += total.Duration
+		}
+		merged.MaxSpeedKMH = math.Max(merged.MaxSpeedKMH, summary.MaxSpeedKMH)
+
+		elevation.AscentM += summary.Elevation.AscentM
+		elevation.DescentM += summary.Elevation.DescentM
+		elevation.MinAltitudeM = math.Min(elevation.MinAltitudeM, summary.Elevation.MinAltitudeM)
+		elevation.MaxAltitudeM = math.Max(elevation.MaxAltitudeM, summary.Elevation.MaxAltitudeM)
+
+		merged.Trips = append(merged.Trips, summary.Trips...)
+	}
+	if metrics.TotalLocations > 0 {
+		metrics.AvgDistanceKM = metrics.TotalDistanceKM / float64(metrics.TotalLocations)
+	}
+
+	sort.SliceStable(merged.Trips, func(i, j int) bool {
+		return merged.Trips[i].StartTime.Before(merged.Trips[j].StartTime)
+	})
+	return merged
+}
+
+// CurrentTrip returns the trip in progress up to its last moving fix, and
+// whether there is one. Indexes are track positions as in Summary.
+func (a *TrackAnalyzer) CurrentTrip() (Trip, bool) {
+	if !a.tracker.active {
+		return Trip{}, false
+	}
+	return a.tracker.trip, true
+}
+
+// at returns the buffered fix with track index i
+func (a *TrackAnalyzer) at(i int) *bufferedPoint {
+	return &a.buffer[i-a.first]
+}
+
+// trim drops buffered fixes that no later fix depends on
+func (a *TrackAnalyzer) trim() {
+	drop := a.emitted - analyzerHistory - a.first
+	if drop <= 0 {
+		return
+	}
+	a.buffer = append(a.buffer[:0], a.buffer[drop:]...)
+	a.first += drop
+}
+
+// emit finalizes the next fix and feeds it to the track-level accumulators
+func (a *TrackAnalyzer) emit() AnalyzedPoint {
+	k := a.emitted
+	a.emitted++
+	p := a.at(k)
+
+	point := AnalyzedPoint{Location: p.loc}
+	point.Mode = ModeStationary
+
+	if k > 0 {
+		point.SpeedKMH = p.rawSpeed
+
+		window := make([]float64, 0, smoothingWindow)
+		for i := max(1, k-smoothingWindow+1); i <= k; i++ {
+			window = append(window, a.at(i).cleaned)
+		}
+		point.Mode = ClassifySpeed(medianSpeed(window))
+
+		if p.duration > 0 && k > 1 {
+			deltaMS := (p.rawSpeed - a.at(k-1).rawSpeed) / 3.6
+			point.AccelerationMS2 = deltaMS / p.duration.Seconds()
+		}
+	}
+
+	half := ElevationSmoothingWindow / 2
+	altitudes := make([]float64, 0, ElevationSmoothingWindow)
+	for i := max(0, k-half); i <= min(a.pushed-1, k+half); i++ {
+		altitudes = append(altitudes, a.at(i).loc.Altitude)
+	}
+	point.SmoothedAltitudeM = meanAltitude(altitudes)
+
+	point.DistanceFromHomeKM = a.metrics.add(p.loc)
+	point.CumulativeAscentM = a.climb.add(point.SmoothedAltitudeM)
+
+	if point.SpeedKMH > a.maxSpeed {
+		a.maxSpeed = point.SpeedKMH
+	}
+	if k > 0 && p.duration > 0 {
+		total := a.totals[point.Mode]
+		total.DistanceKM += p.distance
+		total.Duration += p.duration
+	}
+
+	a.trackTrip(k, point)
+	a.previous = point
+	return point
+}
+
+// trackTrip advances trip detection and the elevation profile of the
+// current trip with fix k
+func (a *TrackAnalyzer) trackTrip(k int, point AnalyzedPoint) {
+	finished, started, moved := a.tracker.observe(k, point.Location, point.Mode)
+	if finished != nil && a.trip != nil {
+		a.trips = append(a.trips, a.trip.finish(*finished))
+	}
+	if finished != nil || (!a.tracker.active && !started) {
+		a.trip = nil
+	}
+
+	if started {
+		a.trip = newTripBuilder(a.previous.Location, a.previous.SmoothedAltitudeM)
+	}
+	if a.trip != nil && a.tracker.active {
+		a.trip.add(point.Location, point.SmoothedAltitudeM)
+	}
+	if moved && a.trip != nil {
+		a.trip.commit()
+	}
+}
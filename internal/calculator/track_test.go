@@ -0,0 +1,247 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// mixedTrack builds a walk, a stop and a drive with varying altitude
+func mixedTrack(start time.Time) []Location {
+	walk := trackAtSpeed(start, 15, 5.0)
+	last := walk[len(walk)-1]
+
+	var stop []Location
+	for i := 1; i <= 15; i++ {
+		stop = append(stop, Location{
+			Latitude:  last.Latitude,
+			Longitude: last.Longitude,
+			Timestamp: last.Timestamp.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	drive := trackAtSpeed(stop[len(stop)-1].Timestamp.Add(time.Minute), 30, 60.0)
+	for i := range drive {
+		drive[i].Latitude += last.Latitude - 40.0
+	}
+
+	locations := append(append(walk, stop...), drive...)
+	for i := range locations {
+		locations[i].Altitude = 20 + 15*math.Sin(float64(i)/6)
+	}
+	return locations
+}
+
+// analyzeTrack pushes every location through a TrackAnalyzer and returns the
+// analyzed points and the track summary
+func analyzeTrack(t *testing.T, homeLat, homeLon float64, locations []Location) ([]AnalyzedPoint, TrackSummary) {
+	t.Helper()
+	analyzer := NewTrackAnalyzer(homeLat, homeLon)
+	var points []AnalyzedPoint
+	for _, loc := range locations {
+		ready, err := analyzer.Push(loc)
+		if err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		points = append(points, ready...)
+	}
+	points = append(points, analyzer.Flush()...)
+	return points, analyzer.Summary()
+}
+
+func TestTrackAnalyzer(t *testing.T) {
+	homeLat, homeLon := 40.0, -74.0
+	locations := mixedTrack(time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC))
+
+	analyzer := NewTrackAnalyzer(homeLat, homeLon)
+	var points []AnalyzedPoint
+	for _, loc := range locations {
+		ready, err := analyzer.Push(loc)
+		if err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		points = append(points, ready...)
+		if len(analyzer.buffer) > analyzerHistory+analyzerLookahead+1 {
+			t.Fatalf("buffer grew to %d fixes", len(analyzer.buffer))
+		}
+	}
+	points = append(points, analyzer.Flush()...)
+	summary := analyzer.Summary()
+
+	if len(points) != len(locations) {
+		t.Fatalf("expected %d points, got %d", len(locations), len(points))
+	}
+
+	half := ElevationSmoothingWindow / 2
+	maxSpeed := 0.0
+	for i, p := range points {
+		if p.Timestamp != locations[i].Timestamp {
+			t.Fatalf("point %d out of order", i)
+		}
+		// Altitudes are a centered moving average of the raw fixes
+		window := locations[max(0, i-half):min(len(locations), i+half+1)]
+		var sum float64
+		for _, loc := range window {
+			sum += loc.Altitude
+		}
+		if want := sum / float64(len(window)); math.Abs(p.SmoothedAltitudeM-want) > 1e-9 {
+			t.Errorf("point %d: smoothed altitude %.3f, expected %.3f", i, p.SmoothedAltitudeM, want)
+		}
+		if i > 0 && p.CumulativeAscentM < points[i-1].CumulativeAscentM {
+			t.Errorf("point %d: cumulative ascent decreased", i)
+		}
+		maxSpeed = math.Max(maxSpeed, p.SpeedKMH)
+	}
+
+	if metrics := CalculateMetrics(homeLat, homeLon, locations); summary.Metrics != metrics {
+		t.Errorf("metrics %+v, expected %+v", summary.Metrics, metrics)
+	}
+	if got := points[len(points)-1].CumulativeAscentM; got != summary.Elevation.AscentM {
+		t.Errorf("expected cumulative ascent to end at %.3f, got %.3f", summary.Elevation.AscentM, got)
+	}
+	if summary.MaxSpeedKMH != maxSpeed {
+		t.Errorf("max speed %.2f, expected %.2f", summary.MaxSpeedKMH, maxSpeed)
+	}
+
+	// The walk and the drive, split by the stop
+	if len(summary.Trips) != 2 {
+		t.Fatalf("expected 2 trips, got %d", len(summary.Trips))
+	}
+	for i, trip := range summary.Trips {
+		if len(trip.Profile) != trip.EndIndex-trip.StartIndex+1 {
+			t.Errorf("trip %d: %d profile samples for fixes %d-%d", i, len(trip.Profile), trip.StartIndex, trip.EndIndex)
+		}
+		if last := trip.Profile[len(trip.Profile)-1]; math.Abs(last.DistanceKM-trip.DistanceKM) > 1e-9 {
+			t.Errorf("trip %d: profile ends at %.3f km, trip is %.3f km", i, last.DistanceKM, trip.DistanceKM)
+		}
+	}
+}
+
+func TestTrackAnalyzer_Empty(t *testing.T) {
+	analyzer := NewTrackAnalyzer(0, 0)
+	if points := analyzer.Flush(); len(points) != 0 {
+		t.Errorf("expected no points, got %d", len(points))
+	}
+
+	summary := analyzer.Summary()
+	if summary.Metrics.TotalLocations != 0 || len(summary.Trips) != 0 {
+		t.Errorf("expected empty summary, got %+v", summary)
+	}
+	if len(summary.ModeTotals) != len(MovementModes) {
+		t.Errorf("expected %d mode totals, got %d", len(MovementModes), len(summary.ModeTotals))
+	}
+}
+
+func TestTrackAnalyzer_CurrentTrip(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	analyzer := NewTrackAnalyzer(40.0, -74.0)
+
+	if _, ok := analyzer.CurrentTrip(); ok {
+		t.Fatal("expected no trip before any fix")
+	}
+
+	for _, loc := range trackAtSpeed(start, 10, 5.0) {
+		if _, err := analyzer.Push(loc); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+	trip, ok := analyzer.CurrentTrip()
+	if !ok {
+		t.Fatal("expected a walk to be in progress")
+	}
+	if !trip.StartTime.Equal(start) || trip.DistanceKM <= 0 {
+		t.Errorf("unexpected trip in progress %+v", trip)
+	}
+
+	analyzer.Flush()
+	if _, ok := analyzer.CurrentTrip(); ok {
+		t.Error("expected Flush to close the trip")
+	}
+}
+
+func TestMergeSummaries(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	walk := trackAtSpeed(start, 11, 5.0)
+	drive := trackAtSpeed(start.Add(-time.Hour), 11, 60.0)
+	for i := range drive {
+		drive[i].DeviceID = "car"
+		drive[i].Altitude = float64(i) * 10
+	}
+
+	_, walked := analyzeTrack(t, 40.0, -74.0, walk)
+	_, driven := analyzeTrack(t, 40.0, -74.0, drive)
+	merged := MergeSummaries(walked, driven, TrackSummary{})
+
+	metrics := merged.Metrics
+	if metrics.TotalLocations != 22 || metrics.TotalDistanceKM != walked.Metrics.TotalDistanceKM+driven.Metrics.TotalDistanceKM {
+		t.Errorf("unexpected merged metrics %+v", metrics)
+	}
+	if metrics.MinDistanceKM != 0 || metrics.MaxDistanceKM != driven.Metrics.MaxDistanceKM {
+		t.Errorf("unexpected merged distance range %+v", metrics)
+	}
+	if math.Abs(metrics.AvgDistanceKM-metrics.TotalDistanceKM/22) > 1e-9 {
+		t.Errorf("unexpected average %.3f", metrics.AvgDistanceKM)
+	}
+	if merged.MaxSpeedKMH != driven.MaxSpeedKMH {
+		t.Errorf("expected the driving max speed, got %.1f", merged.MaxSpeedKMH)
+	}
+	if merged.Elevation.AscentM != driven.Elevation.AscentM || merged.Elevation.MinAltitudeM != 0 {
+		t.Errorf("unexpected merged elevation %+v", merged.Elevation)
+	}
+	if merged.ModeTotals[1].Duration != 10*time.Minute || merged.ModeTotals[3].Duration != 10*time.Minute {
+		t.Errorf("unexpected merged mode totals %+v", merged.ModeTotals)
+	}
+
+	// The drive started first
+	if len(merged.Trips) != 2 || merged.Trips[0].DeviceID != "car" || merged.Trips[1].DeviceID != "" {
+		t.Errorf("expected the drive then the walk, got %+v", merged.Trips)
+	}
+
+	if empty := MergeSummaries(); empty.Metrics.TotalLocations != 0 || len(empty.ModeTotals) != len(MovementModes) {
+		t.Errorf("unexpected empty merge %+v", empty)
+	}
+}
+
+func TestTrackAnalyzer_RejectsOtherTracks(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	locations := trackAtSpeed(start, 4, 5.0)
+	for i := range locations {
+		locations[i].DeviceID = "pixel8"
+	}
+	analyzer := NewTrackAnalyzer(40.0, -74.0)
+	var points []AnalyzedPoint
+	push := func(loc Location) error {
+		ready, err := analyzer.Push(loc)
+		points = append(points, ready...)
+		return err
+	}
+	for _, loc := range locations[:2] {
+		if err := push(loc); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+
+	other := locations[2]
+	other.DeviceID = "iphone"
+	if err := push(other); !errors.Is(err, ErrDeviceChanged) {
+		t.Errorf("expected ErrDeviceChanged, got %v", err)
+	}
+	older := locations[2]
+	older.Timestamp = locations[1].Timestamp.Add(-time.Second)
+	if err := push(older); !errors.Is(err, ErrOutOfOrder) {
+		t.Errorf("expected ErrOutOfOrder, got %v", err)
+	}
+
+	// Rejected fixes leave the track as it was
+	if err := push(locations[2]); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	points = append(points, analyzer.Flush()...)
+	if summary := analyzer.Summary(); summary.Metrics.TotalLocations != 3 || len(points) != 3 {
+		t.Errorf("expected 3 fixes in the track, got %d (%d points)", summary.Metrics.TotalLocations, len(points))
+	}
+	if speed := points[2].SpeedKMH; math.Abs(speed-5.0) > 0.01 {
+		t.Errorf("expected 5 km/h from the previous accepted fix, got %.2f", speed)
+	}
+}
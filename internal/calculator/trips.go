@@ -0,0 +1,94 @@
+package calculator
+
+import (
+	"time"
+)
+
+// TripGapThreshold is how long a device must stay stationary, or stop
+// reporting, before the current trip is considered finished
+const TripGapThreshold = 10 * time.Minute
+
+// Trip is a contiguous run of moving fixes, identified by their position in
+// the track (EndIndex is inclusive)
+type Trip struct {
+	DeviceID   string
+	StartIndex int
+	EndIndex   int
+	StartTime  time.Time
+	EndTime    time.Time
+	DistanceKM float64
+}
+
+// Duration returns the elapsed time between the first and last fix of the trip
+func (t Trip) Duration() time.Duration {
+	return t.EndTime.Sub(t.StartTime)
+}
+
+// tripTracker is the state machine behind trip detection in TrackAnalyzer.
+// Fixes are observed one at a time in chronological order. Short stops
+// (traffic lights, GPS jitter) do not end a trip; a stationary dwell or
+// reporting gap of at least TripGapThreshold does.
+type tripTracker struct {
+	active  bool
+	trip    Trip    // bounds up to the last moving fix
+	pending float64 // distance travelled since the last moving fix
+	prev    Location
+	hasPrev bool
+}
+
+// observe processes fix i and reports a trip that finished before it, whether
+// this fix started a new trip, and whether it extended the current trip
+func (t *tripTracker) observe(i int, loc Location, mode MovementMode) (finished *Trip, started, moved bool) {
+	if !t.hasPrev {
+		t.prev, t.hasPrev = loc, true
+		return nil, false, false
+	}
+
+	prev := t.prev
+	t.prev = loc
+
+	if loc.Timestamp.Sub(prev.Timestamp) >= TripGapThreshold {
+		return t.close(), false, false
+	}
+
+	step := Haversine(prev.Latitude, prev.Longitude, loc.Latitude, loc.Longitude)
+
+	if mode != ModeStationary {
+		if !t.active {
+			t.active = true
+			t.trip = Trip{DeviceID: loc.DeviceID, StartIndex: i - 1, StartTime: prev.Timestamp}
+			t.pending = 0
+			started = true
+		}
+		t.trip.EndIndex = i
+		t.trip.EndTime = loc.Timestamp
+		t.trip.DistanceKM += t.pending + step
+		t.pending = 0
+		return nil, started, true
+	}
+
+	if t.active {
+		t.pending += step
+		if loc.Timestamp.Sub(t.trip.EndTime) >= TripGapThreshold {
+			return t.close(), false, false
+		}
+	}
+
+	return nil, false, false
+}
+
+// close ends the current trip, returning it if it covered at least two fixes
+func (t *tripTracker) close() *Trip {
+	if !t.active {
+		return nil
+	}
+
+	t.active = false
+	t.pending = 0
+	if t.trip.EndIndex <= t.trip.StartIndex {
+		return nil
+	}
+
+	trip := t.trip
+	return &trip
+}
@@ -0,0 +1,80 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// detectTrips returns the trips a TrackAnalyzer finds in locations
+func detectTrips(t *testing.T, locations []Location) []Trip {
+	t.Helper()
+	_, summary := analyzeTrack(t, 0, 0, locations)
+	trips := make([]Trip, len(summary.Trips))
+	for i, trip := range summary.Trips {
+		trips[i] = trip.Trip
+	}
+	return trips
+}
+
+func TestTrackAnalyzer_Trips(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+
+	t.Run("no movement", func(t *testing.T) {
+		trips := detectTrips(t, trackAtSpeed(start, 30, 0))
+		if len(trips) != 0 {
+			t.Errorf("expected no trips, got %d", len(trips))
+		}
+	})
+
+	t.Run("two trips separated by a long stop", func(t *testing.T) {
+		first := trackAtSpeed(start, 11, 5.0)
+
+		// Stay put for 20 minutes after the first walk
+		last := first[len(first)-1]
+		var stop []Location
+		for i := 1; i <= 20; i++ {
+			stop = append(stop, Location{
+				Latitude:  last.Latitude,
+				Longitude: last.Longitude,
+				Timestamp: last.Timestamp.Add(time.Duration(i) * time.Minute),
+			})
+		}
+
+		second := trackAtSpeed(stop[len(stop)-1].Timestamp.Add(time.Minute), 6, 5.0)
+		for i := range second {
+			second[i].Latitude += last.Latitude - 40.0
+		}
+
+		locations := append(append(first, stop...), second...)
+		trips := detectTrips(t, locations)
+
+		if len(trips) != 2 {
+			t.Fatalf("expected 2 trips, got %d", len(trips))
+		}
+		// The rolling median may extend a trip by one fix into the stop
+		if trips[0].StartIndex != 0 || trips[0].EndIndex < 10 || trips[0].EndIndex > 11 {
+			t.Errorf("unexpected first trip bounds %d-%d", trips[0].StartIndex, trips[0].EndIndex)
+		}
+		if math.Abs(trips[0].DistanceKM-5.0/6) > 0.01 {
+			t.Errorf("expected first trip of ~0.83 km, got %.3f", trips[0].DistanceKM)
+		}
+		if trips[1].EndIndex != len(locations)-1 {
+			t.Errorf("expected second trip to end at last fix, got %d", trips[1].EndIndex)
+		}
+	})
+
+	t.Run("reporting gap ends a trip", func(t *testing.T) {
+		first := trackAtSpeed(start, 5, 5.0)
+		second := trackAtSpeed(start.Add(time.Hour), 5, 5.0)
+
+		locations := append(first, second...)
+		trips := detectTrips(t, locations)
+		if len(trips) != 2 {
+			t.Fatalf("expected 2 trips, got %d", len(trips))
+		}
+		if trips[0].EndIndex != 4 || trips[1].StartIndex != 5 {
+			t.Errorf("expected split at gap, got %d and %d", trips[0].EndIndex, trips[1].StartIndex)
+		}
+	})
+}
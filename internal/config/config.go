@@ -5,7 +5,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +23,11 @@ type Config struct {
 	GRPCPort         string
 	HTTPPort         string
 
+	// Location source: "postgres" or "files" (OwnTracks exports in
+	// LocationFilesPath, for offline runs)
+	LocationSource    string
+	LocationFilesPath string
+
 	// Database configuration
 	PostgresHost     string
 	PostgresPort     string
@@ -27,6 +35,38 @@ type Config struct {
 	PostgresUser     string
 	PostgresPassword string
 
+	// Database TLS: sslmode is one of disable, require, verify-ca or
+	// verify-full, the modes lib/pq supports; certificate paths are optional
+	PostgresSSLMode     string
+	PostgresSSLRootCert string
+	PostgresSSLCert     string
+	PostgresSSLKey      string
+
+	// Database connection pool
+	PostgresMaxOpenConns    int
+	PostgresMaxIdleConns    int
+	PostgresConnMaxLifetime time.Duration
+	PostgresConnMaxIdleTime time.Duration
+
+	// Database retries and circuit breaker
+	PostgresRetryMaxAttempts    int
+	PostgresRetryInitialBackoff time.Duration
+	PostgresRetryMaxBackoff     time.Duration
+	PostgresBreakerThreshold    int
+	PostgresBreakerCooldown     time.Duration
+
+	// PostgresApplicationName tags connections in pg_stat_activity
+	PostgresApplicationName string
+
+	// Read replica for location queries; unset routes everything to the
+	// primary. Database name, credentials and TLS settings are shared.
+	PostgresReplicaHost string
+	PostgresReplicaPort string
+
+	// PostgresStatementTimeout bounds each statement of a streaming
+	// location query; 0 keeps the server default
+	PostgresStatementTimeout time.Duration
+
 	// Home location coordinates
 	HomeLatitude  float64
 	HomeLongitude float64
@@ -34,9 +74,52 @@ type Config struct {
 	// Distance thresholds
 	AwayThresholdKM float64
 
+	// Incremental mode keeps today's per-device aggregates and trip state
+	// current as locations arrive: "off", "poll" (read new rows every
+	// IncrementalPollInterval) or "listen" (also wake on NOTIFY to
+	// IncrementalNotifyChannel)
+	IncrementalMode          string
+	IncrementalPollInterval  time.Duration
+	IncrementalNotifyChannel string
+
+	// MigrateOnStartup applies pending schema migrations before serving;
+	// when false they are applied with the migrate subcommand
+	MigrateOnStartup bool
+
 	// CSV output path
 	CSVOutputPath string
 
+	// Artifact storage for job outputs: "local" (files in CSVOutputPath) or
+	// "s3" (a bucket on S3 or an S3-compatible server such as MinIO, so any
+	// replica can serve downloads)
+	ArtifactStorage   string
+	S3Endpoint        string
+	S3Bucket          string
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UseSSL          bool
+	S3Prefix          string
+
+	// ArtifactCompression lists the encodings ("gzip", "zstd") every
+	// artifact is also stored compressed with; empty stores plain files only
+	ArtifactCompression []string
+
+	// Download URLs: DownloadSigningKey signs them (shared by all replicas;
+	// a random per-process key is used when empty), DownloadURLTTL is how
+	// long they stay valid and DownloadBaseURL is prepended to their path
+	DownloadSigningKey string
+	DownloadURLTTL     time.Duration
+	DownloadBaseURL    string
+
+	// ArtifactAPIToken is the bearer token the /artifacts HTTP endpoints
+	// require; empty leaves them unmounted, so the catalog is gRPC only
+	ArtifactAPIToken string
+
+	// IncludeRawPayload decodes locations.raw_payload and adds its region,
+	// Wi-Fi, course and pressure fields to distance CSV reports
+	IncludeRawPayload bool
+
 	// OpenTelemetry configuration
 	OTELEnabled  bool
 	OTELEndpoint string
@@ -58,16 +141,45 @@ func Load() (*Config, error) {
 		GRPCPort:         getEnv("GRPC_PORT", "50051"),
 		HTTPPort:         getEnv("HTTP_PORT", "8080"),
 
+		LocationSource:    getEnv("LOCATION_SOURCE", "postgres"),
+		LocationFilesPath: getEnv("LOCATION_FILES_PATH", ""),
+
 		PostgresHost:     getEnv("POSTGRES_HOST", "192.168.1.175"),
 		PostgresPort:     getEnv("POSTGRES_PORT", "6432"),
 		PostgresDB:       getEnv("POSTGRES_DB", "owntracks"),
 		PostgresUser:     getEnv("POSTGRES_USER", "development"),
 		PostgresPassword: getEnv("POSTGRES_PASSWORD", "development"),
 
-		CSVOutputPath: getEnv("CSV_OUTPUT_PATH", "/data/csv"),
-		OTELEnabled:   getEnv("OTEL_ENABLED", "false") == "true",
-		OTELEndpoint:  getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317"),
-		LogLevel:      getEnv("LOG_LEVEL", "info"),
+		PostgresSSLMode:     getEnv("POSTGRES_SSLMODE", "disable"),
+		PostgresSSLRootCert: getEnv("POSTGRES_SSLROOTCERT", ""),
+		PostgresSSLCert:     getEnv("POSTGRES_SSLCERT", ""),
+		PostgresSSLKey:      getEnv("POSTGRES_SSLKEY", ""),
+
+		PostgresApplicationName: getEnv("POSTGRES_APPLICATION_NAME", "otel-worker"),
+		PostgresReplicaHost:     getEnv("POSTGRES_REPLICA_HOST", ""),
+
+		IncrementalMode:          getEnv("INCREMENTAL_MODE", "off"),
+		IncrementalNotifyChannel: getEnv("INCREMENTAL_NOTIFY_CHANNEL", "locations_inserted"),
+
+		MigrateOnStartup:  getEnv("MIGRATE_ON_STARTUP", "true") == "true",
+		CSVOutputPath:     getEnv("CSV_OUTPUT_PATH", "/data/csv"),
+		ArtifactStorage:   getEnv("ARTIFACT_STORAGE", "local"),
+		S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+		S3Bucket:          getEnv("S3_BUCKET", ""),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+		S3UseSSL:          getEnv("S3_USE_SSL", "true") == "true",
+		S3Prefix:          getEnv("S3_PREFIX", ""),
+
+		DownloadSigningKey: getEnv("DOWNLOAD_SIGNING_KEY", ""),
+		DownloadBaseURL:    strings.TrimSuffix(getEnv("DOWNLOAD_BASE_URL", ""), "/"),
+		ArtifactAPIToken:   getEnv("ARTIFACT_API_TOKEN", ""),
+
+		IncludeRawPayload: getEnv("INCLUDE_RAW_PAYLOAD", "false") == "true",
+		OTELEnabled:       getEnv("OTEL_ENABLED", "false") == "true",
+		OTELEndpoint:      getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
 	}
 
 	// Parse float values
@@ -87,19 +199,228 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid AWAY_THRESHOLD_KM: %w", err)
 	}
 
+	if err := cfg.loadDatabaseResilience(); err != nil {
+		return nil, err
+	}
+	cfg.PostgresReplicaPort = getEnv("POSTGRES_REPLICA_PORT", cfg.PostgresPort)
+
+	if err := cfg.loadIncremental(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateArtifactStorage(); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadArtifactCompression(); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadDownloads(); err != nil {
+		return nil, err
+	}
+
+	switch cfg.LocationSource {
+	case "postgres":
+	case "files":
+		if cfg.LocationFilesPath == "" {
+			return nil, fmt.Errorf("LOCATION_FILES_PATH is required when LOCATION_SOURCE is files")
+		}
+	default:
+		return nil, fmt.Errorf("invalid LOCATION_SOURCE %q: must be postgres or files", cfg.LocationSource)
+	}
+
 	return cfg, nil
 }
 
+// loadDatabaseResilience reads and validates the database TLS, pool, retry
+// and circuit breaker settings
+func (c *Config) loadDatabaseResilience() error {
+	switch c.PostgresSSLMode {
+	case "disable", "require", "verify-ca", "verify-full":
+	default:
+		return fmt.Errorf("invalid POSTGRES_SSLMODE %q: must be disable, require, verify-ca or verify-full", c.PostgresSSLMode)
+	}
+	if (c.PostgresSSLCert == "") != (c.PostgresSSLKey == "") {
+		return fmt.Errorf("POSTGRES_SSLCERT and POSTGRES_SSLKEY must be set together")
+	}
+
+	ints := []struct {
+		key, def string
+		min      int
+		dest     *int
+	}{
+		{"POSTGRES_MAX_OPEN_CONNS", "25", 1, &c.PostgresMaxOpenConns},
+		{"POSTGRES_MAX_IDLE_CONNS", "5", 0, &c.PostgresMaxIdleConns},
+		{"POSTGRES_RETRY_MAX_ATTEMPTS", "3", 1, &c.PostgresRetryMaxAttempts},
+		{"POSTGRES_BREAKER_THRESHOLD", "5", 0, &c.PostgresBreakerThreshold},
+	}
+	for _, v := range ints {
+		n, err := strconv.Atoi(getEnv(v.key, v.def))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", v.key, err)
+		}
+		if n < v.min {
+			return fmt.Errorf("invalid %s: must be at least %d", v.key, v.min)
+		}
+		*v.dest = n
+	}
+
+	durations := []struct {
+		key, def string
+		dest     *time.Duration
+	}{
+		{"POSTGRES_CONN_MAX_LIFETIME", "5m", &c.PostgresConnMaxLifetime},
+		{"POSTGRES_CONN_MAX_IDLE_TIME", "1m", &c.PostgresConnMaxIdleTime},
+		{"POSTGRES_RETRY_INITIAL_BACKOFF", "100ms", &c.PostgresRetryInitialBackoff},
+		{"POSTGRES_RETRY_MAX_BACKOFF", "2s", &c.PostgresRetryMaxBackoff},
+		{"POSTGRES_BREAKER_COOLDOWN", "30s", &c.PostgresBreakerCooldown},
+		{"POSTGRES_STATEMENT_TIMEOUT", "0s", &c.PostgresStatementTimeout},
+	}
+	for _, v := range durations {
+		d, err := time.ParseDuration(getEnv(v.key, v.def))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", v.key, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("invalid %s: must not be negative", v.key)
+		}
+		*v.dest = d
+	}
+
+	return nil
+}
+
+// loadIncremental reads and validates the incremental mode settings
+func (c *Config) loadIncremental() error {
+	switch c.IncrementalMode {
+	case "off", "poll", "listen":
+	default:
+		return fmt.Errorf("invalid INCREMENTAL_MODE %q: must be off, poll or listen", c.IncrementalMode)
+	}
+
+	interval, err := time.ParseDuration(getEnv("INCREMENTAL_POLL_INTERVAL", "30s"))
+	if err != nil {
+		return fmt.Errorf("invalid INCREMENTAL_POLL_INTERVAL: %w", err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("invalid INCREMENTAL_POLL_INTERVAL: must be positive")
+	}
+	c.IncrementalPollInterval = interval
+	return nil
+}
+
+// validateArtifactStorage checks the artifact storage backend and that a
+// bucket is configured for s3
+func (c *Config) validateArtifactStorage() error {
+	switch c.ArtifactStorage {
+	case "local":
+	case "s3":
+		if c.S3Endpoint == "" || c.S3Bucket == "" {
+			return fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required when ARTIFACT_STORAGE is s3")
+		}
+		if strings.Contains(c.S3Endpoint, "://") {
+			return fmt.Errorf("invalid S3_ENDPOINT %q: use host[:port] and S3_USE_SSL", c.S3Endpoint)
+		}
+	default:
+		return fmt.Errorf("invalid ARTIFACT_STORAGE %q: must be local or s3", c.ArtifactStorage)
+	}
+	return nil
+}
+
+// loadArtifactCompression reads the comma separated ARTIFACT_COMPRESSION
+// encodings, dropping duplicates; "none" or empty disables compression
+func (c *Config) loadArtifactCompression() error {
+	c.ArtifactCompression = nil
+	for _, encoding := range strings.Split(getEnv("ARTIFACT_COMPRESSION", ""), ",") {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		switch encoding {
+		case "", "none":
+		case "gzip", "zstd":
+			if !slices.Contains(c.ArtifactCompression, encoding) {
+				c.ArtifactCompression = append(c.ArtifactCompression, encoding)
+			}
+		default:
+			return fmt.Errorf("invalid ARTIFACT_COMPRESSION %q: must be gzip, zstd or none", encoding)
+		}
+	}
+	return nil
+}
+
+// loadDownloads reads and validates the download URL and artifact API
+// settings
+func (c *Config) loadDownloads() error {
+	if c.DownloadSigningKey != "" && len(c.DownloadSigningKey) < 32 {
+		return fmt.Errorf("invalid DOWNLOAD_SIGNING_KEY: must be at least 32 bytes")
+	}
+
+	ttl, err := time.ParseDuration(getEnv("DOWNLOAD_URL_TTL", "1h"))
+	if err != nil {
+		return fmt.Errorf("invalid DOWNLOAD_URL_TTL: %w", err)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("invalid DOWNLOAD_URL_TTL: must be positive")
+	}
+	c.DownloadURLTTL = ttl
+
+	if c.ArtifactAPIToken != "" && len(c.ArtifactAPIToken) < 32 {
+		return fmt.Errorf("invalid ARTIFACT_API_TOKEN: must be at least 32 bytes")
+	}
+	return nil
+}
+
 // DatabaseDSN returns the PostgreSQL connection string
 func (c *Config) DatabaseDSN() string {
-	return fmt.Sprintf(
-		"host=%s port=%s dbname=%s user=%s password=%s sslmode=disable",
-		c.PostgresHost,
-		c.PostgresPort,
-		c.PostgresDB,
-		c.PostgresUser,
-		c.PostgresPassword,
+	return c.dsn(c.PostgresHost, c.PostgresPort)
+}
+
+// ReplicaDSN returns the connection string of the read replica, or "" when
+// no replica is configured
+func (c *Config) ReplicaDSN() string {
+	if c.PostgresReplicaHost == "" {
+		return ""
+	}
+	port := c.PostgresReplicaPort
+	if port == "" {
+		port = c.PostgresPort
+	}
+	return c.dsn(c.PostgresReplicaHost, port)
+}
+
+// dsn returns the connection string for a server, sharing the database,
+// credentials and TLS settings of the primary
+func (c *Config) dsn(host, port string) string {
+	sslMode := c.PostgresSSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s port=%s dbname=%s user=%s password=%s sslmode=%s",
+		dsnValue(host),
+		dsnValue(port),
+		dsnValue(c.PostgresDB),
+		dsnValue(c.PostgresUser),
+		dsnValue(c.PostgresPassword),
+		sslMode,
 	)
+	if c.PostgresSSLRootCert != "" {
+		dsn += " sslrootcert=" + dsnValue(c.PostgresSSLRootCert)
+	}
+	if c.PostgresSSLCert != "" {
+		dsn += " sslcert=" + dsnValue(c.PostgresSSLCert) + " sslkey=" + dsnValue(c.PostgresSSLKey)
+	}
+	if c.PostgresApplicationName != "" {
+		dsn += " application_name=" + dsnValue(c.PostgresApplicationName)
+	}
+	return dsn
+}
+
+// dsnValue quotes a connection string value if it is empty or contains
+// spaces, quotes or backslashes
+func dsnValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, " '\\") {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
 }
 
 // getEnv retrieves an environment variable or returns a default value
@@ -2,7 +2,10 @@ package config
 
 import (
 	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 // nolint:gocyclo // Test function complexity from multiple subtests and assertions
@@ -96,6 +99,43 @@ func TestLoad(t *testing.T) {
 	})
 }
 
+func TestLoadLocationSource(t *testing.T) {
+	t.Run("defaults to postgres", func(t *testing.T) {
+		t.Setenv("LOCATION_SOURCE", "")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.LocationSource != "postgres" {
+			t.Errorf("expected LocationSource 'postgres', got '%s'", cfg.LocationSource)
+		}
+	})
+
+	t.Run("files requires a path", func(t *testing.T) {
+		t.Setenv("LOCATION_SOURCE", "files")
+		t.Setenv("LOCATION_FILES_PATH", "")
+		if _, err := Load(); err == nil {
+			t.Error("expected error for missing LOCATION_FILES_PATH, got nil")
+		}
+
+		t.Setenv("LOCATION_FILES_PATH", "/data/exports")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.LocationFilesPath != "/data/exports" {
+			t.Errorf("expected LocationFilesPath '/data/exports', got '%s'", cfg.LocationFilesPath)
+		}
+	})
+
+	t.Run("rejects unknown source", func(t *testing.T) {
+		t.Setenv("LOCATION_SOURCE", "sqlite")
+		if _, err := Load(); err == nil {
+			t.Error("expected error for unknown LOCATION_SOURCE, got nil")
+		}
+	})
+}
+
 func TestDatabaseDSN(t *testing.T) {
 	cfg := &Config{
 		PostgresHost:     "192.168.1.175",
@@ -110,3 +150,261 @@ func TestDatabaseDSN(t *testing.T) {
 		t.Errorf("expected DSN '%s', got '%s'", expected, dsn)
 	}
 }
+
+func TestDatabaseDSN_TLS(t *testing.T) {
+	cfg := &Config{
+		PostgresHost:        "db.example.com",
+		PostgresPort:        "5432",
+		PostgresDB:          "owntracks",
+		PostgresUser:        "worker",
+		PostgresPassword:    "it's secret",
+		PostgresSSLMode:     "verify-full",
+		PostgresSSLRootCert: "/etc/ssl/ca.pem",
+		PostgresSSLCert:     "/etc/ssl/client.pem",
+		PostgresSSLKey:      "/etc/ssl/client.key",
+	}
+
+	expected := `host=db.example.com port=5432 dbname=owntracks user=worker password='it\'s secret' sslmode=verify-full` +
+		` sslrootcert=/etc/ssl/ca.pem sslcert=/etc/ssl/client.pem sslkey=/etc/ssl/client.key`
+	if dsn := cfg.DatabaseDSN(); dsn != expected {
+		t.Errorf("expected DSN '%s', got '%s'", expected, dsn)
+	}
+}
+
+func TestReplicaDSN(t *testing.T) {
+	cfg := &Config{
+		PostgresHost:            "192.168.1.175",
+		PostgresPort:            "6432",
+		PostgresDB:              "owntracks",
+		PostgresUser:            "testuser",
+		PostgresPassword:        "testpass",
+		PostgresApplicationName: "otel-worker",
+	}
+
+	if dsn := cfg.ReplicaDSN(); dsn != "" {
+		t.Errorf("expected no replica DSN, got '%s'", dsn)
+	}
+
+	cfg.PostgresReplicaHost = "192.168.1.176"
+	expected := "host=192.168.1.176 port=6432 dbname=owntracks user=testuser password=testpass sslmode=disable application_name=otel-worker"
+	if dsn := cfg.ReplicaDSN(); dsn != expected {
+		t.Errorf("expected DSN '%s', got '%s'", expected, dsn)
+	}
+
+	cfg.PostgresReplicaPort = "5433"
+	expected = "host=192.168.1.176 port=5433 dbname=owntracks user=testuser password=testpass sslmode=disable application_name=otel-worker"
+	if dsn := cfg.ReplicaDSN(); dsn != expected {
+		t.Errorf("expected DSN '%s', got '%s'", expected, dsn)
+	}
+}
+
+func TestLoadDatabaseResilience(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.PostgresSSLMode != "disable" || cfg.PostgresMaxOpenConns != 25 || cfg.PostgresMaxIdleConns != 5 {
+			t.Errorf("unexpected defaults %+v", cfg)
+		}
+		if cfg.PostgresRetryMaxAttempts != 3 || cfg.PostgresRetryInitialBackoff != 100*time.Millisecond {
+			t.Errorf("unexpected retry defaults %d, %v", cfg.PostgresRetryMaxAttempts, cfg.PostgresRetryInitialBackoff)
+		}
+		if cfg.PostgresBreakerThreshold != 5 || cfg.PostgresBreakerCooldown != 30*time.Second {
+			t.Errorf("unexpected breaker defaults %d, %v", cfg.PostgresBreakerThreshold, cfg.PostgresBreakerCooldown)
+		}
+		if cfg.PostgresStatementTimeout != 0 || cfg.PostgresReplicaHost != "" || cfg.PostgresReplicaPort != cfg.PostgresPort {
+			t.Errorf("unexpected replica defaults %v, %q, %q", cfg.PostgresStatementTimeout, cfg.PostgresReplicaHost, cfg.PostgresReplicaPort)
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Setenv("POSTGRES_MAX_OPEN_CONNS", "50")
+		t.Setenv("POSTGRES_CONN_MAX_LIFETIME", "10m")
+		t.Setenv("POSTGRES_BREAKER_THRESHOLD", "0")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.PostgresMaxOpenConns != 50 || cfg.PostgresConnMaxLifetime != 10*time.Minute || cfg.PostgresBreakerThreshold != 0 {
+			t.Errorf("overrides not applied: %+v", cfg)
+		}
+	})
+
+	invalid := map[string]string{
+		"POSTGRES_SSLMODE":            "on",
+		"POSTGRES_MAX_OPEN_CONNS":     "0",
+		"POSTGRES_RETRY_MAX_ATTEMPTS": "three",
+		"POSTGRES_BREAKER_COOLDOWN":   "-1s",
+		"POSTGRES_STATEMENT_TIMEOUT":  "5",
+		"POSTGRES_SSLCERT":            "/etc/ssl/client.pem", // without POSTGRES_SSLKEY
+	}
+	for key, value := range invalid {
+		t.Run("rejects "+key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := Load(); err == nil {
+				t.Errorf("expected error for %s=%s, got nil", key, value)
+			}
+		})
+	}
+	// lib/pq fails every connection in the modes it does not implement
+	for _, mode := range []string{"allow", "prefer"} {
+		t.Run("rejects POSTGRES_SSLMODE="+mode, func(t *testing.T) {
+			t.Setenv("POSTGRES_SSLMODE", mode)
+			if _, err := Load(); err == nil {
+				t.Errorf("expected error for POSTGRES_SSLMODE=%s, got nil", mode)
+			}
+		})
+	}
+}
+
+func TestLoadIncremental(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.IncrementalMode != "off" || cfg.IncrementalPollInterval != 30*time.Second || cfg.IncrementalNotifyChannel != "locations_inserted" {
+			t.Errorf("unexpected incremental defaults %q, %v, %q", cfg.IncrementalMode, cfg.IncrementalPollInterval, cfg.IncrementalNotifyChannel)
+		}
+	})
+
+	t.Run("listen", func(t *testing.T) {
+		t.Setenv("INCREMENTAL_MODE", "listen")
+		t.Setenv("INCREMENTAL_POLL_INTERVAL", "5m")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.IncrementalMode != "listen" || cfg.IncrementalPollInterval != 5*time.Minute {
+			t.Errorf("overrides not applied: %q, %v", cfg.IncrementalMode, cfg.IncrementalPollInterval)
+		}
+	})
+
+	invalid := map[string]string{
+		"INCREMENTAL_MODE":          "stream",
+		"INCREMENTAL_POLL_INTERVAL": "0s",
+	}
+	for key, value := range invalid {
+		t.Run("rejects "+key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := Load(); err == nil {
+				t.Errorf("expected error for %s=%s, got nil", key, value)
+			}
+		})
+	}
+}
+
+func TestLoadArtifactStorage(t *testing.T) {
+	t.Run("defaults to local", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.ArtifactStorage != "local" || cfg.S3Region != "us-east-1" || !cfg.S3UseSSL {
+			t.Errorf("unexpected artifact storage defaults %q, %q, %v", cfg.ArtifactStorage, cfg.S3Region, cfg.S3UseSSL)
+		}
+	})
+
+	t.Run("s3 requires a bucket", func(t *testing.T) {
+		t.Setenv("ARTIFACT_STORAGE", "s3")
+		t.Setenv("S3_ENDPOINT", "minio.minio.svc:9000")
+		if _, err := Load(); err == nil {
+			t.Error("expected error for missing S3_BUCKET, got nil")
+		}
+
+		t.Setenv("S3_BUCKET", "otel-worker")
+		t.Setenv("S3_USE_SSL", "false")
+		t.Setenv("S3_PREFIX", "jobs/")
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.S3Bucket != "otel-worker" || cfg.S3UseSSL || cfg.S3Prefix != "jobs/" {
+			t.Errorf("overrides not applied: %q, %v, %q", cfg.S3Bucket, cfg.S3UseSSL, cfg.S3Prefix)
+		}
+	})
+
+	invalid := map[string]string{
+		"ARTIFACT_STORAGE": "gcs",
+		"S3_ENDPOINT":      "http://minio:9000",
+	}
+	for key, value := range invalid {
+		t.Run("rejects "+key, func(t *testing.T) {
+			t.Setenv("ARTIFACT_STORAGE", "s3")
+			t.Setenv("S3_BUCKET", "otel-worker")
+			t.Setenv("S3_ENDPOINT", "minio:9000")
+			t.Setenv(key, value)
+			if _, err := Load(); err == nil {
+				t.Errorf("expected error for %s=%s, got nil", key, value)
+			}
+		})
+	}
+}
+
+func TestLoadDownloads(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.DownloadSigningKey != "" || cfg.DownloadURLTTL != time.Hour || cfg.DownloadBaseURL != "" {
+			t.Errorf("unexpected download defaults %q, %v, %q", cfg.DownloadSigningKey, cfg.DownloadURLTTL, cfg.DownloadBaseURL)
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Setenv("DOWNLOAD_SIGNING_KEY", strings.Repeat("k", 32))
+		t.Setenv("DOWNLOAD_URL_TTL", "15m")
+		t.Setenv("DOWNLOAD_BASE_URL", "https://otel-worker.example.com/")
+		t.Setenv("ARTIFACT_API_TOKEN", strings.Repeat("t", 32))
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if cfg.DownloadURLTTL != 15*time.Minute || cfg.DownloadBaseURL != "https://otel-worker.example.com" || cfg.ArtifactAPIToken != strings.Repeat("t", 32) {
+			t.Errorf("overrides not applied: %v, %q, %q", cfg.DownloadURLTTL, cfg.DownloadBaseURL, cfg.ArtifactAPIToken)
+		}
+	})
+
+	invalid := map[string]string{
+		"DOWNLOAD_SIGNING_KEY": "short",
+		"DOWNLOAD_URL_TTL":     "-1m",
+		"ARTIFACT_API_TOKEN":   "short",
+	}
+	for key, value := range invalid {
+		t.Run("rejects "+key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, err := Load(); err == nil {
+				t.Errorf("expected error for %s=%s, got nil", key, value)
+			}
+		})
+	}
+}
+
+func TestLoadArtifactCompression(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"none", nil},
+		{"gzip", []string{"gzip"}},
+		{" ZSTD , gzip,zstd", []string{"zstd", "gzip"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("ARTIFACT_COMPRESSION", tt.value)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("%q: Load() failed: %v", tt.value, err)
+		}
+		if !slices.Equal(cfg.ArtifactCompression, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.value, tt.want, cfg.ArtifactCompression)
+		}
+	}
+
+	t.Setenv("ARTIFACT_COMPRESSION", "brotli")
+	if _, err := Load(); err == nil {
+		t.Error("expected error for ARTIFACT_COMPRESSION=brotli, got nil")
+	}
+}
@@ -5,6 +5,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,13 +13,18 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var tracer = otel.Tracer("github.com/stuartshay/otel-worker/internal/database")
 
 // Client wraps a PostgreSQL database connection
 type Client struct {
-	db *sql.DB
+	primary       *pool
+	replica       *pool // nil unless a read replica is configured
+	opts          Options
+	decodePayload bool
+	postgis       postgisCheck
 }
 
 // Location represents a GPS location record from the database
@@ -37,43 +43,116 @@ type Location struct {
 	Trigger        string
 	Timestamp      int64 // Extracted from TIMESTAMP WITH TIME ZONE via EXTRACT(EPOCH)
 	CreatedAt      time.Time
+	Payload        *RawPayload // decoded raw_payload; nil unless payload decoding is enabled
 }
 
 // NewClient creates a new database client with connection pooling
 func NewClient(dsn string) (*Client, error) {
-	db, err := sql.Open("postgres", dsn)
+	return NewClientWithOptions(dsn, DefaultOptions())
+}
+
+// NewClientWithOptions creates a new database client with the given pool,
+// retry, circuit breaker and read replica settings
+func NewClientWithOptions(dsn string, opts Options) (*Client, error) {
+	primary, err := openPool(targetPrimary, dsn, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		return nil, err
 	}
 
-	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
-	db.SetConnMaxIdleTime(1 * time.Minute)
+	c := &Client{primary: primary, opts: opts}
 
-	// Verify connection
+	// Verify connection, retrying while the server is starting up
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := db.PingContext(ctx); err != nil {
-		if closeErr := db.Close(); closeErr != nil {
+	if err := c.retry(ctx, func() error { return primary.db.PingContext(ctx) }); err != nil {
+		if closeErr := primary.db.Close(); closeErr != nil {
 			return nil, fmt.Errorf("failed to ping database: %w (also failed to close: %w)", err, closeErr)
 		}
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Client{db: db}, nil
+	if opts.ReplicaDSN != "" {
+		if err := c.attachReplica(ctx, opts.ReplicaDSN); err != nil {
+			_ = primary.db.Close() // nolint:errcheck // the replica error is the one reported
+			return nil, err
+		}
+	}
+
+	return c, nil
 }
 
-// Close closes the database connection
+// Close closes the database connections
 func (c *Client) Close() error {
-	return c.db.Close()
+	err := c.primary.db.Close()
+	if c.replica != nil {
+		err = errors.Join(err, c.replica.db.Close())
+	}
+	return err
+}
+
+// LocationFunc is called once per location row in query order. Returning a
+// non-nil error stops iteration and is returned by the streaming method.
+type LocationFunc func(Location) error
+
+// cursorBatchSize is the number of rows fetched per round trip from a
+// server-side cursor, bounding client memory regardless of result size
+const cursorBatchSize = 1000
+
+// locationColumns is the select list shared by every location query; the
+// column order must match scanLocation
+const locationColumns = `
+			id, device_id, tid, latitude, longitude, accuracy,
+			altitude, velocity, battery, battery_status,
+			connection_type, trigger, EXTRACT(EPOCH FROM timestamp)::bigint AS timestamp, created_at`
+
+// SetDecodePayload enables reading and decoding raw_payload into
+// Location.Payload. It is off by default because the JSONB column is
+// several times larger than the mapped columns.
+func (c *Client) SetDecodePayload(decode bool) {
+	c.decodePayload = decode
+}
+
+// selectLocations returns the SELECT clause of a location query, including
+// raw_payload when payload decoding is enabled
+func (c *Client) selectLocations() string {
+	if c.decodePayload {
+		return `SELECT` + locationColumns + `, raw_payload::text`
+	}
+	return `SELECT` + locationColumns + `, NULL::text`
 }
 
 // GetLocationsByDate retrieves GPS locations for a specific date
 // Date should be in YYYY-MM-DD format
 func (c *Client) GetLocationsByDate(ctx context.Context, date string, deviceID string) ([]Location, error) {
+	var locations []Location
+	err := c.StreamLocationsByDate(ctx, date, deviceID, func(loc Location) error {
+		locations = append(locations, loc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// GetLocationsByDateRange retrieves GPS locations within a date range
+func (c *Client) GetLocationsByDateRange(ctx context.Context, startDate, endDate string, deviceID string) ([]Location, error) {
+	var locations []Location
+	err := c.StreamLocationsByDateRange(ctx, startDate, endDate, deviceID, func(loc Location) error {
+		locations = append(locations, loc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// StreamLocationsByDate calls fn for each GPS location on a specific date in
+// created_at order without loading the result set into memory
+// Date should be in YYYY-MM-DD format
+func (c *Client) StreamLocationsByDate(ctx context.Context, date string, deviceID string, fn LocationFunc) error {
 	ctx, span := tracer.Start(ctx, "GetLocationsByDate")
 	defer span.End()
 
@@ -84,11 +163,7 @@ func (c *Client) GetLocationsByDate(ctx context.Context, date string, deviceID s
 		attribute.String("db.operation", "SELECT"),
 	)
 
-	query := `
-		SELECT
-			id, device_id, tid, latitude, longitude, accuracy,
-			altitude, velocity, battery, battery_status,
-			connection_type, trigger, EXTRACT(EPOCH FROM timestamp)::bigint AS timestamp, created_at
+	query := c.selectLocations() + `
 		FROM public.locations
 		WHERE DATE(created_at) = $1
 	`
@@ -103,86 +178,12 @@ func (c *Client) GetLocationsByDate(ctx context.Context, date string, deviceID s
 
 	query += " ORDER BY created_at ASC"
 
-	rows, err := c.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "query failed")
-		return nil, fmt.Errorf("query failed: %w", err)
-	}
-	defer func() { _ = rows.Close() }() // nolint:errcheck // Close in defer, error not actionable
-
-	var locations []Location
-	for rows.Next() {
-		var loc Location
-		var accuracy, velocity, battery, timestamp sql.NullInt64
-		var altitude sql.NullFloat64
-		var batteryStatus sql.NullInt64
-		var connectionType, trigger sql.NullString
-
-		err := rows.Scan(
-			&loc.ID,
-			&loc.DeviceID,
-			&loc.TID,
-			&loc.Latitude,
-			&loc.Longitude,
-			&accuracy,
-			&altitude,
-			&velocity,
-			&battery,
-			&batteryStatus,
-			&connectionType,
-			&trigger,
-			&timestamp,
-			&loc.CreatedAt,
-		)
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "scan failed")
-			return nil, fmt.Errorf("scan failed: %w", err)
-		}
-
-		// Convert NULL values to zero values
-		if accuracy.Valid {
-			loc.Accuracy = int(accuracy.Int64)
-		}
-		if altitude.Valid {
-			loc.Altitude = altitude.Float64
-		}
-		if velocity.Valid {
-			loc.Velocity = int(velocity.Int64)
-		}
-		if battery.Valid {
-			loc.Battery = int(battery.Int64)
-		}
-		if timestamp.Valid {
-			loc.Timestamp = timestamp.Int64
-		}
-		if batteryStatus.Valid {
-			loc.BatteryStatus = int(batteryStatus.Int64)
-		}
-		if connectionType.Valid {
-			loc.ConnectionType = connectionType.String
-		}
-		if trigger.Valid {
-			loc.Trigger = trigger.String
-		}
-
-		locations = append(locations, loc)
-	}
-
-	if err := rows.Err(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "rows iteration failed")
-		return nil, fmt.Errorf("rows iteration failed: %w", err)
-	}
-
-	span.SetAttributes(attribute.Int("db.result_count", len(locations)))
-	span.SetStatus(codes.Ok, "query succeeded")
-	return locations, nil
+	return c.streamLocations(ctx, span, query, args, fn)
 }
 
-// GetLocationsByDateRange retrieves GPS locations within a date range
-func (c *Client) GetLocationsByDateRange(ctx context.Context, startDate, endDate string, deviceID string) ([]Location, error) {
+// StreamLocationsByDateRange calls fn for each GPS location within a date
+// range in created_at order without loading the result set into memory
+func (c *Client) StreamLocationsByDateRange(ctx context.Context, startDate, endDate string, deviceID string, fn LocationFunc) error {
 	ctx, span := tracer.Start(ctx, "GetLocationsByDateRange")
 	defer span.End()
 
@@ -194,11 +195,7 @@ func (c *Client) GetLocationsByDateRange(ctx context.Context, startDate, endDate
 		attribute.String("db.operation", "SELECT"),
 	)
 
-	query := `
-		SELECT
-			id, device_id, tid, latitude, longitude, accuracy,
-			altitude, velocity, battery, battery_status,
-			connection_type, trigger, EXTRACT(EPOCH FROM timestamp)::bigint AS timestamp, created_at
+	query := c.selectLocations() + `
 		FROM public.locations
 		WHERE created_at >= $1::date AND created_at < $2::date + interval '1 day'
 	`
@@ -212,82 +209,172 @@ func (c *Client) GetLocationsByDateRange(ctx context.Context, startDate, endDate
 
 	query += " ORDER BY created_at ASC"
 
-	rows, err := c.db.QueryContext(ctx, query, args...)
+	return c.streamLocations(ctx, span, query, args, fn)
+}
+
+// streamLocations runs query through a server-side cursor, preferring the
+// read replica, and passes each scanned location to fn. Errors are recorded
+// on span.
+func (c *Client) streamLocations(ctx context.Context, span trace.Span, query string, args []interface{}, fn LocationFunc) error {
+	return c.streamCursor(ctx, span, routeReplica, query, args, func(rows *sql.Rows) error {
+		loc, err := scanLocation(rows)
+		if err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		if err := fn(loc); err != nil {
+			return callbackError{err: err}
+		}
+		return nil
+	})
+}
+
+// streamCursor runs query through a server-side cursor inside a read-only
+// transaction on the pool chosen by route, fetching cursorBatchSize rows at a
+// time and calling handle for each. handle wraps errors from the caller's
+// callback in callbackError so they are returned unchanged. Errors are
+// recorded on span.
+func (c *Client) streamCursor(ctx context.Context, span trace.Span, route route, query string, args []interface{}, handle func(*sql.Rows) error) error {
+	fail := func(err error, msg string) error {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, msg)
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	// Opening the cursor is retried; once rows reach handle a failure is
+	// returned, since retrying would deliver them twice
+	tx, p, err := c.openCursor(ctx, route, query, args)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "query failed")
-		return nil, fmt.Errorf("query failed: %w", err)
+		return err
+	}
+	// Rollback after Commit is a no-op; on early return it closes the cursor
+	defer func() { _ = tx.Rollback() }() // nolint:errcheck // Rollback in defer, error not actionable
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM stream_cursor", cursorBatchSize)
+	count := 0
+	for {
+		fetched, err := fetchRows(ctx, tx, fetch, handle)
+		count += fetched
+		var stopped callbackError
+		if errors.As(err, &stopped) {
+			// The caller stopped iteration; return its error unchanged
+			span.SetAttributes(attribute.Int("db.result_count", count))
+			span.RecordError(stopped.err)
+			span.SetStatus(codes.Error, "iteration stopped")
+			return stopped.err
+		}
+		if err != nil {
+			p.breaker.record(err)
+			span.SetAttributes(attribute.Int("db.result_count", count))
+			return fail(err, "rows iteration failed")
+		}
+		if fetched < cursorBatchSize {
+			break
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fail(err, "commit failed")
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", count))
+	span.SetStatus(codes.Ok, "query succeeded")
+	return nil
+}
+
+// callbackError wraps an error returned by a streaming callback so it can be
+// told apart from database errors and handed back to the caller unwrapped
+type callbackError struct {
+	err error
+}
+
+func (e callbackError) Error() string {
+	return e.err.Error()
+}
+
+// fetchRows runs one FETCH against the open cursor and returns the number of
+// rows passed to handle
+func fetchRows(ctx context.Context, tx *sql.Tx, fetch string, handle func(*sql.Rows) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return 0, err
 	}
 	defer func() { _ = rows.Close() }() // nolint:errcheck // Close in defer, error not actionable
 
-	var locations []Location
+	count := 0
 	for rows.Next() {
-		var loc Location
-		var accuracy, velocity, battery, timestamp sql.NullInt64
-		var altitude sql.NullFloat64
-		var batteryStatus sql.NullInt64
-		var connectionType, trigger sql.NullString
-
-		err := rows.Scan(
-			&loc.ID,
-			&loc.DeviceID,
-			&loc.TID,
-			&loc.Latitude,
-			&loc.Longitude,
-			&accuracy,
-			&altitude,
-			&velocity,
-			&battery,
-			&batteryStatus,
-			&connectionType,
-			&trigger,
-			&timestamp,
-			&loc.CreatedAt,
-		)
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "scan failed")
-			return nil, fmt.Errorf("scan failed: %w", err)
+		count++
+		if err := handle(rows); err != nil {
+			return count, err
 		}
+	}
 
-		// Convert NULL values to zero values
-		if accuracy.Valid {
-			loc.Accuracy = int(accuracy.Int64)
-		}
-		if altitude.Valid {
-			loc.Altitude = altitude.Float64
-		}
-		if velocity.Valid {
-			loc.Velocity = int(velocity.Int64)
-		}
-		if battery.Valid {
-			loc.Battery = int(battery.Int64)
-		}
-		if timestamp.Valid {
-			loc.Timestamp = timestamp.Int64
-		}
-		if batteryStatus.Valid {
-			loc.BatteryStatus = int(batteryStatus.Int64)
-		}
-		if connectionType.Valid {
-			loc.ConnectionType = connectionType.String
-		}
-		if trigger.Valid {
-			loc.Trigger = trigger.String
-		}
+	return count, rows.Err()
+}
 
-		locations = append(locations, loc)
+// scanLocation scans a row selected with selectLocations, converting NULL
+// values to zero values
+func scanLocation(rows *sql.Rows) (Location, error) {
+	var loc Location
+	var accuracy, velocity, battery, timestamp sql.NullInt64
+	var altitude sql.NullFloat64
+	var batteryStatus sql.NullInt64
+	var connectionType, trigger, payload sql.NullString
+
+	err := rows.Scan(
+		&loc.ID,
+		&loc.DeviceID,
+		&loc.TID,
+		&loc.Latitude,
+		&loc.Longitude,
+		&accuracy,
+		&altitude,
+		&velocity,
+		&battery,
+		&batteryStatus,
+		&connectionType,
+		&trigger,
+		&timestamp,
+		&loc.CreatedAt,
+		&payload,
+	)
+	if err != nil {
+		return loc, err
 	}
 
-	if err := rows.Err(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "rows iteration failed")
-		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	// Convert NULL values to zero values
+	if accuracy.Valid {
+		loc.Accuracy = int(accuracy.Int64)
+	}
+	if altitude.Valid {
+		loc.Altitude = altitude.Float64
+	}
+	if velocity.Valid {
+		loc.Velocity = int(velocity.Int64)
+	}
+	if battery.Valid {
+		loc.Battery = int(battery.Int64)
+	}
+	if timestamp.Valid {
+		loc.Timestamp = timestamp.Int64
+	}
+	if batteryStatus.Valid {
+		loc.BatteryStatus = int(batteryStatus.Int64)
+	}
+	if connectionType.Valid {
+		loc.ConnectionType = connectionType.String
+	}
+	if trigger.Valid {
+		loc.Trigger = trigger.String
+	}
+	if payload.Valid {
+		// A payload that is not valid JSON is treated as absent rather than
+		// failing the whole query
+		loc.Payload, _ = ParseRawPayload([]byte(payload.String)) // nolint:errcheck // malformed payloads are skipped
 	}
 
-	span.SetAttributes(attribute.Int("db.result_count", len(locations)))
-	span.SetStatus(codes.Ok, "query succeeded")
-	return locations, nil
+	return loc, nil
 }
 
 // GetDevices returns a list of unique device IDs from the database
@@ -307,7 +394,7 @@ func (c *Client) GetDevices(ctx context.Context) ([]string, error) {
 		ORDER BY device_id
 	`
 
-	rows, err := c.db.QueryContext(ctx, query)
+	rows, err := c.query(ctx, query)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "query failed")
@@ -348,7 +435,7 @@ func (c *Client) GetLocationCount(ctx context.Context, date string, deviceID str
 	}
 
 	var count int
-	err := c.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	err := c.queryRow(ctx, query, args, &count)
 	if err != nil {
 		return 0, fmt.Errorf("count query failed: %w", err)
 	}
@@ -356,7 +443,22 @@ func (c *Client) GetLocationCount(ctx context.Context, date string, deviceID str
 	return count, nil
 }
 
-// HealthCheck verifies database connectivity
+// HealthCheck verifies database connectivity. The read replica is probed as
+// well, but its failure only routes reads to the primary.
 func (c *Client) HealthCheck(ctx context.Context) error {
-	return c.db.PingContext(ctx)
+	// Readiness probes double as circuit breaker probes: once the cooldown
+	// has passed, a successful ping closes the breaker
+	if err := c.primary.breaker.allow(); err != nil {
+		return err
+	}
+	err := c.primary.db.PingContext(ctx)
+	c.primary.breaker.record(err)
+	if err != nil {
+		return err
+	}
+
+	if c.replica != nil && c.replica.breaker.allow() == nil {
+		c.replica.breaker.record(c.replica.db.PingContext(ctx))
+	}
+	return nil
 }
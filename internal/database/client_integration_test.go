@@ -4,6 +4,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -43,7 +44,7 @@ func TestNewClient_Success(t *testing.T) {
 	client, err := NewClient(cfg.DatabaseDSN())
 	require.NoError(t, err, "Should connect to database successfully")
 	require.NotNil(t, client)
-	require.NotNil(t, client.db)
+	require.NotNil(t, client.primary.db)
 
 	defer client.Close()
 }
@@ -398,7 +399,7 @@ func TestClient_ConnectionPooling(t *testing.T) {
 	defer client.Close()
 
 	// Verify connection pool settings
-	stats := client.db.Stats()
+	stats := client.primary.db.Stats()
 
 	// Pool should have configured max connections
 	assert.Equal(t, 25, stats.MaxOpenConnections, "Should have 25 max open connections")
@@ -412,7 +413,7 @@ func TestClient_ConnectionPooling(t *testing.T) {
 	}
 
 	// Check pool stats after queries
-	statsAfter := client.db.Stats()
+	statsAfter := client.primary.db.Stats()
 	t.Logf("Pool stats: OpenConnections=%d, InUse=%d, Idle=%d",
 		statsAfter.OpenConnections, statsAfter.InUse, statsAfter.Idle)
 
@@ -954,6 +955,96 @@ func TestGetLocationsByDateRange_ReversedDates(t *testing.T) {
 	assert.LessOrEqual(t, len(locations), 0, "Reversed range should return no results")
 }
 
+func TestStreamLocationsByDateRange_MatchesSlice(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	endDate := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	startDate := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+
+	expected, err := client.GetLocationsByDateRange(ctx, startDate, endDate, "")
+	require.NoError(t, err)
+
+	var streamed []Location
+	err = client.StreamLocationsByDateRange(ctx, startDate, endDate, "", func(loc Location) error {
+		streamed = append(streamed, loc)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, streamed, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].ID, streamed[i].ID, "Streamed rows should keep query order")
+	}
+}
+
+func TestStreamLocationsByDateRange_DecodePayload(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	endDate := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	startDate := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+
+	withoutPayload, err := client.GetLocationsByDateRange(ctx, startDate, endDate, "")
+	require.NoError(t, err)
+	for _, loc := range withoutPayload {
+		assert.Nil(t, loc.Payload, "Payload should not be read unless enabled")
+	}
+
+	client.SetDecodePayload(true)
+	withPayload, err := client.GetLocationsByDateRange(ctx, startDate, endDate, "")
+	require.NoError(t, err)
+	require.Len(t, withPayload, len(withoutPayload))
+
+	decoded := 0
+	for _, loc := range withPayload {
+		if loc.Payload != nil {
+			decoded++
+		}
+	}
+	t.Logf("Decoded raw_payload for %d of %d locations", decoded, len(withPayload))
+}
+
+func TestStreamLocationsByDate_CallbackError(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	count, err := client.GetLocationCount(ctx, date, "")
+	require.NoError(t, err)
+	if count == 0 {
+		t.Skip("No locations for yesterday")
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = client.StreamLocationsByDate(ctx, date, "", func(Location) error {
+		calls++
+		return stop
+	})
+
+	assert.ErrorIs(t, err, stop, "Callback error should be returned unwrapped by errors.Is")
+	assert.Equal(t, 1, calls, "Streaming should stop at the first callback error")
+}
+
 func TestGetDevices(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test")
@@ -0,0 +1,226 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxExportLine bounds the length of a single line in an export file
+const maxExportLine = 1 << 20
+
+// FileStore is a read-only LocationStore over a directory of OwnTracks
+// exports. It reads recorder .rec files (`<time>\t<comment>\t<json>` per line)
+// and JSONL files (one OwnTracks JSON message per line) anywhere below the
+// directory, and indexes every location message in memory when opened.
+type FileStore struct {
+	dir   string
+	index *MemoryStore
+}
+
+// ownTracksMessage holds the OwnTracks location fields the worker uses
+type ownTracksMessage struct {
+	Type      string  `json:"_type"`
+	Topic     string  `json:"topic"`
+	TID       string  `json:"tid"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Accuracy  int     `json:"acc"`
+	Altitude  float64 `json:"alt"`
+	Velocity  int     `json:"vel"`
+	Battery   int     `json:"batt"`
+	Status    int     `json:"bs"`
+	Conn      string  `json:"conn"`
+	Trigger   string  `json:"t"`
+	Timestamp int64   `json:"tst"`
+	CreatedAt int64   `json:"created_at"`
+
+	RawPayload
+}
+
+// NewFileStore loads every .rec, .jsonl and .json export below dir
+func NewFileStore(dir string) (*FileStore, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("export path %s is not a directory", dir)
+	}
+
+	var locations []Location
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		switch filepath.Ext(path) {
+		case ".rec", ".jsonl", ".json":
+		default:
+			return nil
+		}
+
+		loaded, err := readExportFile(dir, path)
+		if err != nil {
+			return err
+		}
+		locations = append(locations, loaded...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exports: %w", err)
+	}
+
+	for i := range locations {
+		locations[i].ID = int64(i + 1)
+	}
+
+	return &FileStore{dir: dir, index: NewMemoryStore(locations...)}, nil
+}
+
+// readExportFile parses the location messages in one export file
+func readExportFile(root, path string) ([]Location, error) {
+	// #nosec G304 -- path comes from walking the configured export directory
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }() // nolint:errcheck // Close on read-only file, error not actionable
+
+	device := deviceFromPath(root, path)
+	rec := filepath.Ext(path) == ".rec"
+
+	var locations []Location
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxExportLine)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var received time.Time
+		if rec {
+			// Recorder lines are "<RFC3339 time>\t<comment>\t<json>"
+			parts := strings.SplitN(line, "\t", 3)
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("%s:%d: malformed .rec line", path, lineNo)
+			}
+			received, err = time.Parse(time.RFC3339, strings.TrimSpace(parts[0]))
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid time: %w", path, lineNo, err)
+			}
+			line = parts[2]
+		}
+
+		var msg ownTracksMessage
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid JSON: %w", path, lineNo, err)
+		}
+		if msg.Type != "" && msg.Type != "location" {
+			continue
+		}
+
+		locations = append(locations, msg.toLocation(device, received))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return locations, nil
+}
+
+// deviceFromPath derives a device ID from an export's location: the parent
+// directory for the recorder's <user>/<device>/YYYY-MM.rec layout, otherwise
+// the file name without its extension
+func deviceFromPath(root, path string) string {
+	if parent := filepath.Dir(path); filepath.Clean(parent) != filepath.Clean(root) {
+		return filepath.Base(parent)
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// toLocation converts a message to a Location. The device comes from the MQTT
+// topic when present; CreatedAt is the recorder receive time, the message's
+// created_at, or its tst, in that order of preference.
+func (m ownTracksMessage) toLocation(device string, received time.Time) Location {
+	if parts := strings.Split(m.Topic, "/"); len(parts) >= 3 && parts[len(parts)-1] != "" {
+		device = parts[len(parts)-1]
+	}
+
+	// The export is the original message, so its payload is always available
+	payload := m.RawPayload
+
+	createdAt := received
+	switch {
+	case !createdAt.IsZero():
+	case m.CreatedAt > 0:
+		createdAt = time.Unix(m.CreatedAt, 0)
+	default:
+		createdAt = time.Unix(m.Timestamp, 0)
+	}
+
+	return Location{
+		DeviceID:       device,
+		TID:            m.TID,
+		Latitude:       m.Latitude,
+		Longitude:      m.Longitude,
+		Accuracy:       m.Accuracy,
+		Altitude:       m.Altitude,
+		Velocity:       m.Velocity,
+		Battery:        m.Battery,
+		BatteryStatus:  m.Status,
+		ConnectionType: m.Conn,
+		Trigger:        m.Trigger,
+		Timestamp:      m.Timestamp,
+		CreatedAt:      createdAt.UTC(),
+		Payload:        &payload,
+	}
+}
+
+// GetLocationsByDate retrieves GPS locations for a specific date
+func (f *FileStore) GetLocationsByDate(ctx context.Context, date string, deviceID string) ([]Location, error) {
+	return f.index.GetLocationsByDate(ctx, date, deviceID)
+}
+
+// GetLocationsByDateRange retrieves GPS locations within a date range
+func (f *FileStore) GetLocationsByDateRange(ctx context.Context, startDate, endDate string, deviceID string) ([]Location, error) {
+	return f.index.GetLocationsByDateRange(ctx, startDate, endDate, deviceID)
+}
+
+// StreamLocationsByDate calls fn for each GPS location on a specific date
+func (f *FileStore) StreamLocationsByDate(ctx context.Context, date string, deviceID string, fn LocationFunc) error {
+	return f.index.StreamLocationsByDate(ctx, date, deviceID, fn)
+}
+
+// StreamLocationsByDateRange calls fn for each GPS location within a date range
+func (f *FileStore) StreamLocationsByDateRange(ctx context.Context, startDate, endDate string, deviceID string, fn LocationFunc) error {
+	return f.index.StreamLocationsByDateRange(ctx, startDate, endDate, deviceID, fn)
+}
+
+// GetDevices returns the sorted unique device IDs found in the exports
+func (f *FileStore) GetDevices(ctx context.Context) ([]string, error) {
+	return f.index.GetDevices(ctx)
+}
+
+// GetLocationCount returns the count of locations for a specific date
+func (f *FileStore) GetLocationCount(ctx context.Context, date string, deviceID string) (int, error) {
+	return f.index.GetLocationCount(ctx, date, deviceID)
+}
+
+// HealthCheck verifies the export directory is still readable
+func (f *FileStore) HealthCheck(_ context.Context) error {
+	if _, err := os.Stat(f.dir); err != nil {
+		return fmt.Errorf("export directory unavailable: %w", err)
+	}
+	return nil
+}
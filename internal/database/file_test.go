@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeExport(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+
+	// Recorder layout: rec/<user>/<device>/YYYY-MM.rec
+	writeExport(t, filepath.Join(dir, "rec", "stuart", "pixel8", "2026-01.rec"),
+		"2026-01-24T08:00:05Z\t*                 \t"+
+			`{"_type":"location","tid":"p8","lat":40.7361,"lon":-74.0394,"acc":10,"alt":12.5,"batt":80,"bs":2,"tst":1769241600}`+"\n"+
+			"2026-01-24T08:01:00Z\tlwt               \t"+`{"_type":"lwt","tst":1769241660}`+"\n"+
+			"2026-01-24T08:05:05Z\t*                 \t"+
+			`{"_type":"location","tid":"p8","lat":40.7400,"lon":-74.0300,"tst":1769241900}`+"\n")
+
+	// JSONL export: device from the topic, time from created_at
+	writeExport(t, filepath.Join(dir, "iphone.jsonl"),
+		`{"_type":"location","topic":"owntracks/stuart/iphone15","lat":40.75,"lon":-73.99,"tst":1769241700,"created_at":1769241702,"SSID":"office","inregions":["work"]}`+"\n\n")
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := store.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck failed: %v", err)
+	}
+
+	devices, err := store.GetDevices(ctx)
+	if err != nil {
+		t.Fatalf("GetDevices failed: %v", err)
+	}
+	if len(devices) != 2 || devices[0] != "iphone15" || devices[1] != "pixel8" {
+		t.Errorf("expected [iphone15 pixel8], got %v", devices)
+	}
+
+	locations, err := store.GetLocationsByDate(ctx, "2026-01-24", "")
+	if err != nil {
+		t.Fatalf("GetLocationsByDate failed: %v", err)
+	}
+	if len(locations) != 3 {
+		t.Fatalf("expected 3 locations (lwt skipped), got %d", len(locations))
+	}
+
+	first := locations[0]
+	if first.DeviceID != "pixel8" || first.TID != "p8" || first.Battery != 80 || first.Altitude != 12.5 {
+		t.Errorf("unexpected first location: %+v", first)
+	}
+	if !first.CreatedAt.Equal(time.Date(2026, 1, 24, 8, 0, 5, 0, time.UTC)) {
+		t.Errorf("expected CreatedAt from the .rec line, got %v", first.CreatedAt)
+	}
+	if locations[1].DeviceID != "iphone15" || locations[1].CreatedAt.Unix() != 1769241702 {
+		t.Errorf("expected iphone15 fix second, got %+v", locations[1])
+	}
+	if p := locations[1].Payload; p == nil || p.SSID != "office" || len(p.InRegions) != 1 {
+		t.Errorf("expected payload fields from the export, got %+v", p)
+	}
+	if locations[2].ID == 0 {
+		t.Error("expected file locations to be assigned IDs")
+	}
+}
+
+func TestFileStore_Errors(t *testing.T) {
+	if _, err := NewFileStore(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing directory, got nil")
+	}
+
+	dir := t.TempDir()
+	writeExport(t, filepath.Join(dir, "bad.jsonl"), "{not json}\n")
+	if _, err := NewFileStore(dir); err == nil {
+		t.Error("expected error for malformed JSON, got nil")
+	}
+}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// LocationFollower reads locations as they are inserted, using the
+// monotonically increasing id of public.locations as a cursor. It is
+// optional: stores over static data do not implement it.
+//
+// Following by id assumes rows become visible in id order, which holds for
+// the single OwnTracks recorder inserting into public.locations.
+type LocationFollower interface {
+	// LatestLocationID returns the highest location id, or 0 if there are none
+	LatestLocationID(ctx context.Context) (int64, error)
+	// StreamLocationsAfter calls fn for each location with an id greater
+	// than afterID, in id order
+	StreamLocationsAfter(ctx context.Context, afterID int64, fn LocationFunc) error
+}
+
+// LocationNotifier signals when new locations may have been inserted, so a
+// follower can read them without waiting for its next poll
+type LocationNotifier interface {
+	ListenLocations(ctx context.Context, channel string) (<-chan struct{}, error)
+}
+
+// LatestLocationID returns the highest location id, or 0 if there are none
+func (c *Client) LatestLocationID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := c.queryRow(ctx, `SELECT COALESCE(MAX(id), 0) FROM public.locations`, nil, &id); err != nil {
+		return 0, fmt.Errorf("latest id query failed: %w", err)
+	}
+	return id, nil
+}
+
+// StreamLocationsAfter calls fn for each location with an id greater than
+// afterID, in id order. It reads from the primary so that rows are seen as
+// soon as they are committed.
+func (c *Client) StreamLocationsAfter(ctx context.Context, afterID int64, fn LocationFunc) error {
+	ctx, span := tracer.Start(ctx, "StreamLocationsAfter")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("db.after_id", afterID),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	query := c.selectLocations() + `
+		FROM public.locations
+		WHERE id > $1
+		ORDER BY id ASC`
+
+	return c.streamCursor(ctx, span, routePrimary, query, []interface{}{afterID}, func(rows *sql.Rows) error {
+		loc, err := scanLocation(rows)
+		if err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		if err := fn(loc); err != nil {
+			return callbackError{err: err}
+		}
+		return nil
+	})
+}
+
+// listenerPingInterval is how often an idle listener checks its connection
+const listenerPingInterval = 90 * time.Second
+
+// ListenLocations runs LISTEN channel on a dedicated connection to the
+// primary and signals the returned channel when a notification arrives, or
+// after a reconnect when notifications may have been missed. Signals are
+// coalesced; the listener stops when ctx ends. LISTEN needs a session, so the
+// primary must not be behind PgBouncer in transaction pooling mode.
+func (c *Client) ListenLocations(ctx context.Context, channel string) (<-chan struct{}, error) {
+	listener := pq.NewListener(c.primary.dsn, time.Second, time.Minute, nil)
+	if err := listener.Listen(channel); err != nil {
+		_ = listener.Close() // nolint:errcheck // the listen error is the one reported
+		return nil, fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	wake := make(chan struct{}, 1)
+	go func() {
+		defer func() { _ = listener.Close() }() // nolint:errcheck // Close in defer, error not actionable
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-listener.Notify:
+				// A nil notification follows a reconnect
+				select {
+				case wake <- struct{}{}:
+				default:
+				}
+			case <-time.After(listenerPingInterval):
+				go func() { _ = listener.Ping() }() // nolint:errcheck // a failed ping triggers a reconnect
+			}
+		}
+	}()
+
+	return wake, nil
+}
+
+// LatestLocationID returns the highest location id, or 0 if there are none
+func (m *MemoryStore) LatestLocationID(_ context.Context) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest int64
+	for _, loc := range m.locations {
+		latest = max(latest, loc.ID)
+	}
+	return latest, nil
+}
+
+// StreamLocationsAfter calls fn for each location with an id greater than
+// afterID, in id order
+func (m *MemoryStore) StreamLocationsAfter(ctx context.Context, afterID int64, fn LocationFunc) error {
+	// Copy the matches first so fn may call back into the store
+	m.mu.RLock()
+	var locations []Location
+	for _, loc := range m.locations {
+		if loc.ID > afterID {
+			locations = append(locations, loc)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.SliceStable(locations, func(i, j int) bool { return locations[i].ID < locations[j].ID })
+	for _, loc := range locations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(loc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamLocationsAfter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	latest, err := client.LatestLocationID(ctx)
+	require.NoError(t, err)
+	if latest < 10 {
+		t.Skip("Fewer than 10 locations")
+	}
+
+	var ids []int64
+	err = client.StreamLocationsAfter(ctx, latest-10, func(loc Location) error {
+		ids = append(ids, loc.ID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, ids)
+	for i, id := range ids {
+		assert.Greater(t, id, latest-10)
+		if i > 0 {
+			assert.Greater(t, id, ids[i-1], "Locations should be in id order")
+		}
+	}
+}
+
+func TestListenLocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wake, err := client.ListenLocations(ctx, "otel_worker_test")
+	require.NoError(t, err)
+
+	_, err = client.exec(ctx, `SELECT pg_notify('otel_worker_test', '')`)
+	require.NoError(t, err)
+
+	select {
+	case <-wake:
+	case <-time.After(10 * time.Second):
+		t.Fatal("No signal after NOTIFY")
+	}
+}
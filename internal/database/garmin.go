@@ -0,0 +1,308 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ErrActivityNotFound is returned when a Garmin activity ID does not exist
+var ErrActivityNotFound = errors.New("garmin activity not found")
+
+// GarminActivity is a workout row from public.garmin_activities. Summary
+// values are as reported by Garmin Connect; zero means not recorded.
+type GarminActivity struct {
+	ActivityID      int64
+	Name            string
+	Sport           string
+	SubSport        string
+	StartTime       time.Time
+	EndTime         time.Time
+	DurationSeconds float64
+	DistanceKM      float64
+	Calories        int
+	AvgHeartRate    int
+	MaxHeartRate    int
+	AvgCadence      int
+	MaxCadence      int
+	AvgSpeedKMH     float64
+	MaxSpeedKMH     float64
+	TotalAscentM    float64
+	TotalDescentM   float64
+}
+
+// GarminTrackPoint is a GPS sample from public.garmin_track_points; sensor
+// values are zero when the device did not record them
+type GarminTrackPoint struct {
+	ID                  int64
+	ActivityID          int64
+	Latitude            float64
+	Longitude           float64
+	Altitude            float64
+	Timestamp           time.Time
+	DistanceFromStartKM float64
+	SpeedKMH            float64
+	HeartRate           int
+	Cadence             int
+	TemperatureC        float64
+}
+
+// GarminTrackPointFunc is called once per track point in time order.
+// Returning a non-nil error stops iteration and is returned by the caller.
+type GarminTrackPointFunc func(GarminTrackPoint) error
+
+// GarminStore reads Garmin Connect activities and their GPS tracks. It is
+// optional: stores without Garmin data do not implement it.
+type GarminStore interface {
+	GetGarminActivitiesByDateRange(ctx context.Context, startDate, endDate string, sport string) ([]GarminActivity, error)
+	GetGarminActivity(ctx context.Context, activityID int64) (*GarminActivity, error)
+	GetGarminTrackPoints(ctx context.Context, activityID int64) ([]GarminTrackPoint, error)
+	StreamGarminTrackPoints(ctx context.Context, activityID int64, fn GarminTrackPointFunc) error
+}
+
+// garminActivityColumns is the select list shared by activity queries; the
+// column order must match scanGarminActivity
+const garminActivityColumns = `
+			activity_id, activity_name, sport, sub_sport, start_time, end_time,
+			duration_seconds, distance_km, calories, avg_heart_rate, max_heart_rate,
+			avg_cadence, max_cadence, avg_speed_kmh, max_speed_kmh,
+			total_ascent_m, total_descent_m`
+
+// GetGarminActivitiesByDateRange returns activities that started within a
+// date range, optionally filtered by sport, ordered by start time
+func (c *Client) GetGarminActivitiesByDateRange(ctx context.Context, startDate, endDate string, sport string) ([]GarminActivity, error) {
+	ctx, span := tracer.Start(ctx, "GetGarminActivitiesByDateRange")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.start_date", startDate),
+		attribute.String("db.end_date", endDate),
+		attribute.String("db.sport", sport),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	query := `SELECT` + garminActivityColumns + `
+		FROM public.garmin_activities
+		WHERE start_time >= $1::date AND start_time < $2::date + interval '1 day'
+	`
+
+	args := []interface{}{startDate, endDate}
+
+	if sport != "" {
+		query += " AND sport = $3"
+		args = append(args, sport)
+	}
+
+	query += " ORDER BY start_time ASC"
+
+	rows, err := c.query(ctx, query, args...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "query failed")
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }() // nolint:errcheck // Close in defer, error not actionable
+
+	var activities []GarminActivity
+	for rows.Next() {
+		activity, err := scanGarminActivity(rows)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "scan failed")
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		activities = append(activities, activity)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "rows iteration failed")
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(activities)))
+	span.SetStatus(codes.Ok, "query succeeded")
+	return activities, nil
+}
+
+// GetGarminActivity returns a single activity or ErrActivityNotFound
+func (c *Client) GetGarminActivity(ctx context.Context, activityID int64) (*GarminActivity, error) {
+	ctx, span := tracer.Start(ctx, "GetGarminActivity")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("db.activity_id", activityID),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	query := `SELECT` + garminActivityColumns + `
+		FROM public.garmin_activities
+		WHERE activity_id = $1
+	`
+
+	rows, err := c.query(ctx, query, activityID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "query failed")
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }() // nolint:errcheck // Close in defer, error not actionable
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "rows iteration failed")
+			return nil, fmt.Errorf("rows iteration failed: %w", err)
+		}
+		span.SetStatus(codes.Error, "activity not found")
+		return nil, fmt.Errorf("activity %d: %w", activityID, ErrActivityNotFound)
+	}
+
+	activity, err := scanGarminActivity(rows)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "scan failed")
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+
+	span.SetStatus(codes.Ok, "query succeeded")
+	return &activity, nil
+}
+
+// GetGarminTrackPoints returns every track point of an activity in time order
+func (c *Client) GetGarminTrackPoints(ctx context.Context, activityID int64) ([]GarminTrackPoint, error) {
+	var points []GarminTrackPoint
+	err := c.StreamGarminTrackPoints(ctx, activityID, func(p GarminTrackPoint) error {
+		points = append(points, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// StreamGarminTrackPoints calls fn for each track point of an activity in
+// time order without loading the track into memory
+func (c *Client) StreamGarminTrackPoints(ctx context.Context, activityID int64, fn GarminTrackPointFunc) error {
+	ctx, span := tracer.Start(ctx, "GetGarminTrackPoints")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int64("db.activity_id", activityID),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	query := `
+		SELECT
+			id, activity_id, latitude, longitude, altitude, timestamp,
+			distance_from_start_km, speed_kmh, heart_rate, cadence, temperature_c
+		FROM public.garmin_track_points
+		WHERE activity_id = $1
+		ORDER BY timestamp ASC, id ASC
+	`
+
+	return c.streamCursor(ctx, span, routePrimary, query, []interface{}{activityID}, func(rows *sql.Rows) error {
+		point, err := scanGarminTrackPoint(rows)
+		if err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		if err := fn(point); err != nil {
+			return callbackError{err: err}
+		}
+		return nil
+	})
+}
+
+// scanGarminActivity scans a row selected with garminActivityColumns,
+// converting NULL values to zero values
+func scanGarminActivity(rows *sql.Rows) (GarminActivity, error) {
+	var a GarminActivity
+	var name, sport, subSport sql.NullString
+	var endTime sql.NullTime
+	var duration, distance, avgSpeed, maxSpeed, ascent, descent sql.NullFloat64
+	var calories, avgHR, maxHR, avgCadence, maxCadence sql.NullInt64
+
+	err := rows.Scan(
+		&a.ActivityID,
+		&name,
+		&sport,
+		&subSport,
+		&a.StartTime,
+		&endTime,
+		&duration,
+		&distance,
+		&calories,
+		&avgHR,
+		&maxHR,
+		&avgCadence,
+		&maxCadence,
+		&avgSpeed,
+		&maxSpeed,
+		&ascent,
+		&descent,
+	)
+	if err != nil {
+		return a, err
+	}
+
+	a.Name = name.String
+	a.Sport = sport.String
+	a.SubSport = subSport.String
+	a.EndTime = endTime.Time
+	a.DurationSeconds = duration.Float64
+	a.DistanceKM = distance.Float64
+	a.Calories = int(calories.Int64)
+	a.AvgHeartRate = int(avgHR.Int64)
+	a.MaxHeartRate = int(maxHR.Int64)
+	a.AvgCadence = int(avgCadence.Int64)
+	a.MaxCadence = int(maxCadence.Int64)
+	a.AvgSpeedKMH = avgSpeed.Float64
+	a.MaxSpeedKMH = maxSpeed.Float64
+	a.TotalAscentM = ascent.Float64
+	a.TotalDescentM = descent.Float64
+
+	return a, nil
+}
+
+// scanGarminTrackPoint scans a track point row, converting NULL sensor values
+// to zero values
+func scanGarminTrackPoint(rows *sql.Rows) (GarminTrackPoint, error) {
+	var p GarminTrackPoint
+	var altitude, fromStart, speed, temperature sql.NullFloat64
+	var heartRate, cadence sql.NullInt64
+
+	err := rows.Scan(
+		&p.ID,
+		&p.ActivityID,
+		&p.Latitude,
+		&p.Longitude,
+		&altitude,
+		&p.Timestamp,
+		&fromStart,
+		&speed,
+		&heartRate,
+		&cadence,
+		&temperature,
+	)
+	if err != nil {
+		return p, err
+	}
+
+	p.Altitude = altitude.Float64
+	p.DistanceFromStartKM = fromStart.Float64
+	p.SpeedKMH = speed.Float64
+	p.HeartRate = int(heartRate.Int64)
+	p.Cadence = int(cadence.Int64)
+	p.TemperatureC = temperature.Float64
+
+	return p, nil
+}
@@ -0,0 +1,100 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGarminActivities(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	endDate := time.Now().Format("2006-01-02")
+	startDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+
+	activities, err := client.GetGarminActivitiesByDateRange(ctx, startDate, endDate, "")
+	require.NoError(t, err)
+
+	t.Logf("Found %d Garmin activities between %s and %s", len(activities), startDate, endDate)
+	if len(activities) == 0 {
+		t.Skip("No Garmin activities in the last year")
+	}
+
+	activity, err := client.GetGarminActivity(ctx, activities[0].ActivityID)
+	require.NoError(t, err)
+	assert.Equal(t, activities[0].ActivityID, activity.ActivityID)
+
+	points, err := client.GetGarminTrackPoints(ctx, activity.ActivityID)
+	require.NoError(t, err)
+
+	for i := 1; i < len(points); i++ {
+		assert.False(t, points[i].Timestamp.Before(points[i-1].Timestamp), "Track points should be in time order")
+		assert.Equal(t, activity.ActivityID, points[i].ActivityID)
+	}
+}
+
+func TestGetGarminActivity_NotFound(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	_, err := client.GetGarminActivity(context.Background(), -1)
+	assert.ErrorIs(t, err, ErrActivityNotFound)
+}
+
+func TestStreamGPSPointsByDate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	endDate := time.Now().Format("2006-01-02")
+	startDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+
+	activities, err := client.GetGarminActivitiesByDateRange(ctx, startDate, endDate, "")
+	require.NoError(t, err)
+	if len(activities) == 0 {
+		t.Skip("No Garmin activities in the last year")
+	}
+	date := activities[0].StartTime.UTC().Format("2006-01-02")
+
+	var garmin, merged []GPSPoint
+	err = client.StreamGPSPointsByDate(ctx, date, "", []Source{SourceGarmin}, func(p GPSPoint) error {
+		garmin = append(garmin, p)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = client.StreamGPSPointsByDate(ctx, date, "", []Source{SourceOwnTracks, SourceGarmin}, func(p GPSPoint) error {
+		merged = append(merged, p)
+		return nil
+	})
+	require.NoError(t, err)
+
+	t.Logf("Found %d Garmin and %d merged points on %s", len(garmin), len(merged), date)
+
+	// De-duplication only ever drops OwnTracks fixes
+	assert.GreaterOrEqual(t, len(merged), len(garmin))
+	for i := 1; i < len(merged); i++ {
+		assert.False(t, merged[i].Timestamp.Before(merged[i-1].Timestamp), "Points should be in time order")
+	}
+}
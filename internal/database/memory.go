@@ -0,0 +1,287 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory LocationStore, SummaryStore and GarminStore,
+// used by tests and as the backing index of FileStore
+type MemoryStore struct {
+	mu          sync.RWMutex
+	locations   []Location              // sorted by CreatedAt
+	summaries   map[string]DailySummary // keyed by device ID and date
+	activities  []GarminActivity        // sorted by StartTime
+	trackPoints map[int64][]GarminTrackPoint
+}
+
+// NewMemoryStore creates a store holding the given locations
+func NewMemoryStore(locations ...Location) *MemoryStore {
+	m := &MemoryStore{
+		summaries:   make(map[string]DailySummary),
+		trackPoints: make(map[int64][]GarminTrackPoint),
+	}
+	m.Add(locations...)
+	return m
+}
+
+// Add inserts locations, keeping the store in CreatedAt order. Locations with
+// equal CreatedAt keep their insertion order.
+func (m *MemoryStore) Add(locations ...Location) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.locations = append(m.locations, locations...)
+	sort.SliceStable(m.locations, func(i, j int) bool {
+		return m.locations[i].CreatedAt.Before(m.locations[j].CreatedAt)
+	})
+}
+
+// GetLocationsByDate retrieves GPS locations for a specific date
+func (m *MemoryStore) GetLocationsByDate(ctx context.Context, date string, deviceID string) ([]Location, error) {
+	return m.GetLocationsByDateRange(ctx, date, date, deviceID)
+}
+
+// GetLocationsByDateRange retrieves GPS locations within a date range
+func (m *MemoryStore) GetLocationsByDateRange(_ context.Context, startDate, endDate string, deviceID string) ([]Location, error) {
+	from, to, err := parseDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var locations []Location
+	for _, loc := range m.locations {
+		if matchesLocation(loc, from, to, deviceID) {
+			locations = append(locations, loc)
+		}
+	}
+	return locations, nil
+}
+
+// StreamLocationsByDate calls fn for each GPS location on a specific date
+func (m *MemoryStore) StreamLocationsByDate(ctx context.Context, date string, deviceID string, fn LocationFunc) error {
+	return m.StreamLocationsByDateRange(ctx, date, date, deviceID, fn)
+}
+
+// StreamLocationsByDateRange calls fn for each GPS location within a date range
+func (m *MemoryStore) StreamLocationsByDateRange(ctx context.Context, startDate, endDate string, deviceID string, fn LocationFunc) error {
+	// Copy the matches first so fn may call back into the store
+	locations, err := m.GetLocationsByDateRange(ctx, startDate, endDate, deviceID)
+	if err != nil {
+		return err
+	}
+
+	for _, loc := range locations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(loc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDevices returns the sorted unique device IDs in the store
+func (m *MemoryStore) GetDevices(_ context.Context) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var devices []string
+	for _, loc := range m.locations {
+		if loc.DeviceID != "" && !seen[loc.DeviceID] {
+			seen[loc.DeviceID] = true
+			devices = append(devices, loc.DeviceID)
+		}
+	}
+	sort.Strings(devices)
+	return devices, nil
+}
+
+// GetLocationCount returns the count of locations for a specific date
+func (m *MemoryStore) GetLocationCount(ctx context.Context, date string, deviceID string) (int, error) {
+	locations, err := m.GetLocationsByDate(ctx, date, deviceID)
+	if err != nil {
+		return 0, fmt.Errorf("count query failed: %w", err)
+	}
+	return len(locations), nil
+}
+
+// HealthCheck always succeeds for an in-memory store
+func (m *MemoryStore) HealthCheck(_ context.Context) error {
+	return nil
+}
+
+// UpsertDailySummary inserts or replaces the aggregate row for a device and day
+func (m *MemoryStore) UpsertDailySummary(_ context.Context, summary DailySummary) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	summary.UpdatedAt = time.Now().UTC()
+	m.summaries[summary.DeviceID+"/"+summary.Date] = summary
+	return nil
+}
+
+// DeleteDailySummaries removes the aggregate rows for a day, for every device
+// when deviceID is empty, and returns the number of rows removed
+func (m *MemoryStore) DeleteDailySummaries(_ context.Context, date string, deviceID string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deleted := 0
+	for key, s := range m.summaries {
+		if s.Date == date && (deviceID == "" || s.DeviceID == deviceID) {
+			delete(m.summaries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// GetDailySummaries returns stored aggregates for a date range, ordered by
+// date then device
+func (m *MemoryStore) GetDailySummaries(_ context.Context, startDate, endDate string, deviceID string) ([]DailySummary, error) {
+	if _, _, err := parseDateRange(startDate, endDate); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var summaries []DailySummary
+	for _, s := range m.summaries {
+		// YYYY-MM-DD strings compare in date order
+		if s.Date < startDate || s.Date > endDate {
+			continue
+		}
+		if deviceID != "" && s.DeviceID != deviceID {
+			continue
+		}
+		summaries = append(summaries, s)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Date != summaries[j].Date {
+			return summaries[i].Date < summaries[j].Date
+		}
+		return summaries[i].DeviceID < summaries[j].DeviceID
+	})
+	return summaries, nil
+}
+
+// AddGarminActivity stores an activity and its track points, replacing any
+// activity with the same ID
+func (m *MemoryStore) AddGarminActivity(activity GarminActivity, points ...GarminTrackPoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.activities[:0]
+	for _, a := range m.activities {
+		if a.ActivityID != activity.ActivityID {
+			kept = append(kept, a)
+		}
+	}
+	m.activities = append(kept, activity)
+	sort.SliceStable(m.activities, func(i, j int) bool {
+		return m.activities[i].StartTime.Before(m.activities[j].StartTime)
+	})
+
+	track := append([]GarminTrackPoint(nil), points...)
+	sort.SliceStable(track, func(i, j int) bool {
+		return track[i].Timestamp.Before(track[j].Timestamp)
+	})
+	m.trackPoints[activity.ActivityID] = track
+}
+
+// GetGarminActivitiesByDateRange returns activities that started within a
+// date range, optionally filtered by sport, ordered by start time
+func (m *MemoryStore) GetGarminActivitiesByDateRange(_ context.Context, startDate, endDate string, sport string) ([]GarminActivity, error) {
+	from, to, err := parseDateRange(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var activities []GarminActivity
+	for _, a := range m.activities {
+		if a.StartTime.Before(from) || !a.StartTime.Before(to) {
+			continue
+		}
+		if sport != "" && a.Sport != sport {
+			continue
+		}
+		activities = append(activities, a)
+	}
+	return activities, nil
+}
+
+// GetGarminActivity returns a single activity or ErrActivityNotFound
+func (m *MemoryStore) GetGarminActivity(_ context.Context, activityID int64) (*GarminActivity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, a := range m.activities {
+		if a.ActivityID == activityID {
+			activity := a
+			return &activity, nil
+		}
+	}
+	return nil, fmt.Errorf("activity %d: %w", activityID, ErrActivityNotFound)
+}
+
+// GetGarminTrackPoints returns every track point of an activity in time order
+func (m *MemoryStore) GetGarminTrackPoints(_ context.Context, activityID int64) ([]GarminTrackPoint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]GarminTrackPoint(nil), m.trackPoints[activityID]...), nil
+}
+
+// StreamGarminTrackPoints calls fn for each track point of an activity in time order
+func (m *MemoryStore) StreamGarminTrackPoints(ctx context.Context, activityID int64, fn GarminTrackPointFunc) error {
+	points, err := m.GetGarminTrackPoints(ctx, activityID)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range points {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDateRange returns the half-open UTC interval [start, end+1 day)
+func parseDateRange(startDate, endDate string) (time.Time, time.Time, error) {
+	from, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+	}
+	to, err := time.Parse(time.DateOnly, endDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// matchesLocation reports whether loc falls in [from, to) and belongs to
+// deviceID (any device when empty)
+func matchesLocation(loc Location, from, to time.Time, deviceID string) bool {
+	if deviceID != "" && loc.DeviceID != deviceID {
+		return false
+	}
+	return !loc.CreatedAt.Before(from) && loc.CreatedAt.Before(to)
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func memoryFixture() *MemoryStore {
+	day := time.Date(2026, 1, 24, 0, 0, 0, 0, time.UTC)
+	return NewMemoryStore(
+		Location{ID: 3, DeviceID: "pixel8", CreatedAt: day.Add(10 * time.Hour)},
+		Location{ID: 1, DeviceID: "pixel8", CreatedAt: day.Add(8 * time.Hour)},
+		Location{ID: 2, DeviceID: "iphone", CreatedAt: day.Add(9 * time.Hour)},
+		Location{ID: 4, DeviceID: "pixel8", CreatedAt: day.Add(30 * time.Hour)},
+	)
+}
+
+func TestMemoryStore_GetLocationsByDate(t *testing.T) {
+	store := memoryFixture()
+	ctx := context.Background()
+
+	locations, err := store.GetLocationsByDate(ctx, "2026-01-24", "")
+	if err != nil {
+		t.Fatalf("GetLocationsByDate failed: %v", err)
+	}
+	if len(locations) != 3 {
+		t.Fatalf("expected 3 locations, got %d", len(locations))
+	}
+	for i, want := range []int64{1, 2, 3} {
+		if locations[i].ID != want {
+			t.Errorf("location %d: expected ID %d, got %d", i, want, locations[i].ID)
+		}
+	}
+
+	locations, err = store.GetLocationsByDate(ctx, "2026-01-24", "pixel8")
+	if err != nil {
+		t.Fatalf("GetLocationsByDate failed: %v", err)
+	}
+	if len(locations) != 2 {
+		t.Errorf("expected 2 pixel8 locations, got %d", len(locations))
+	}
+
+	if _, err := store.GetLocationsByDate(ctx, "24/01/2026", ""); err == nil {
+		t.Error("expected error for invalid date, got nil")
+	}
+}
+
+func TestMemoryStore_GetLocationsByDateRange(t *testing.T) {
+	store := memoryFixture()
+	ctx := context.Background()
+
+	locations, err := store.GetLocationsByDateRange(ctx, "2026-01-24", "2026-01-25", "pixel8")
+	if err != nil {
+		t.Fatalf("GetLocationsByDateRange failed: %v", err)
+	}
+	if len(locations) != 3 {
+		t.Errorf("expected 3 locations, got %d", len(locations))
+	}
+
+	locations, err = store.GetLocationsByDateRange(ctx, "2026-01-25", "2026-01-24", "")
+	if err != nil {
+		t.Fatalf("GetLocationsByDateRange failed: %v", err)
+	}
+	if len(locations) != 0 {
+		t.Errorf("expected no locations for reversed range, got %d", len(locations))
+	}
+}
+
+func TestMemoryStore_StreamStopsOnError(t *testing.T) {
+	store := memoryFixture()
+	stop := errors.New("stop")
+
+	calls := 0
+	err := store.StreamLocationsByDate(context.Background(), "2026-01-24", "", func(Location) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("expected callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestMemoryStore_DevicesAndCount(t *testing.T) {
+	store := memoryFixture()
+	ctx := context.Background()
+
+	devices, err := store.GetDevices(ctx)
+	if err != nil {
+		t.Fatalf("GetDevices failed: %v", err)
+	}
+	if len(devices) != 2 || devices[0] != "iphone" || devices[1] != "pixel8" {
+		t.Errorf("expected [iphone pixel8], got %v", devices)
+	}
+
+	count, err := store.GetLocationCount(ctx, "2026-01-25", "")
+	if err != nil {
+		t.Fatalf("GetLocationCount failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 location, got %d", count)
+	}
+}
+
+func TestMemoryStore_DailySummaries(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	for _, s := range []DailySummary{
+		{DeviceID: "pixel8", Date: "2026-01-25", PointCount: 1},
+		{DeviceID: "pixel8", Date: "2026-01-24", PointCount: 2},
+		{DeviceID: "iphone", Date: "2026-01-24", PointCount: 3},
+		{DeviceID: "pixel8", Date: "2026-01-24", PointCount: 4}, // replaces the earlier row
+	} {
+		if err := store.UpsertDailySummary(ctx, s); err != nil {
+			t.Fatalf("UpsertDailySummary failed: %v", err)
+		}
+	}
+
+	summaries, err := store.GetDailySummaries(ctx, "2026-01-24", "2026-01-25", "")
+	if err != nil {
+		t.Fatalf("GetDailySummaries failed: %v", err)
+	}
+	if len(summaries) != 3 {
+		t.Fatalf("expected 3 summaries, got %d", len(summaries))
+	}
+	if summaries[0].DeviceID != "iphone" || summaries[1].PointCount != 4 || summaries[2].Date != "2026-01-25" {
+		t.Errorf("unexpected order or values: %+v", summaries)
+	}
+	if summaries[0].UpdatedAt.IsZero() {
+		t.Error("expected UpdatedAt to be set")
+	}
+	deleted, err := store.DeleteDailySummaries(ctx, "2026-01-24", "")
+	if err != nil || deleted != 2 {
+		t.Fatalf("expected 2 summaries deleted, got %d (%v)", deleted, err)
+	}
+	summaries, _ = store.GetDailySummaries(ctx, "2026-01-24", "2026-01-25", "")
+	if len(summaries) != 1 || summaries[0].Date != "2026-01-25" {
+		t.Errorf("expected only the next day to remain, got %+v", summaries)
+	}
+}
+
+func TestMemoryStore_Garmin(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	start := time.Date(2026, 1, 24, 7, 0, 0, 0, time.UTC)
+
+	store.AddGarminActivity(
+		GarminActivity{ActivityID: 1, Sport: "cycling", StartTime: start},
+		GarminTrackPoint{ActivityID: 1, Timestamp: start.Add(time.Second)},
+		GarminTrackPoint{ActivityID: 1, Timestamp: start},
+	)
+	store.AddGarminActivity(GarminActivity{ActivityID: 2, Sport: "running", StartTime: start.Add(-time.Hour)})
+
+	activities, err := store.GetGarminActivitiesByDateRange(ctx, "2026-01-24", "2026-01-24", "")
+	if err != nil {
+		t.Fatalf("GetGarminActivitiesByDateRange failed: %v", err)
+	}
+	if len(activities) != 2 || activities[0].ActivityID != 2 {
+		t.Errorf("expected activities in start order, got %+v", activities)
+	}
+
+	points, err := store.GetGarminTrackPoints(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetGarminTrackPoints failed: %v", err)
+	}
+	if len(points) != 2 || !points[0].Timestamp.Equal(start) {
+		t.Errorf("expected 2 points in time order, got %+v", points)
+	}
+
+	if _, err := store.GetGarminActivity(ctx, 3); !errors.Is(err, ErrActivityNotFound) {
+		t.Errorf("expected ErrActivityNotFound, got %v", err)
+	}
+}
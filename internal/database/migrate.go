@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// migrationFiles holds the schema of the worker-owned tables. Files are named
+// NNNN_description.sql and applied once each in version order; an applied
+// migration must never be edited, only followed by a new one.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationName matches a migration file name and captures its version and
+// description
+var migrationName = regexp.MustCompile(`^(\d{4})_([a-z0-9_]+)\.sql$`)
+
+// migrationLockKey identifies the advisory lock held while a migration is
+// applied, so concurrently starting workers apply each migration once
+const migrationLockKey int64 = 0x6f74656c // "otel"
+
+// migrationsTableDDL creates the table recording applied migrations
+const migrationsTableDDL = `
+	CREATE TABLE IF NOT EXISTS public.worker_schema_migrations (
+		version    INTEGER     PRIMARY KEY,
+		name       TEXT        NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`
+
+// Migration is an embedded schema change to the worker-owned tables
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// Migrations returns the embedded migrations in version order
+func Migrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles, "migrations")
+}
+
+// loadMigrations reads the migrations in dir of fsys, checking that file
+// names are well formed and versions unique
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q: want NNNN_description.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1]) // nolint:errcheck // four digits by the pattern
+		if previous, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migration version %d used by both %s and %s", version, previous, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		body, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: match[2], SQL: string(body)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrate applies the embedded migrations that have not been applied yet and
+// returns them. Each runs in its own transaction, so a failure leaves the
+// earlier ones in place.
+func (c *Client) Migrate(ctx context.Context) ([]Migration, error) {
+	ctx, span := tracer.Start(ctx, "Migrate")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "MIGRATE"),
+	)
+
+	migrations, err := Migrations()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid migrations")
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		ok, err := c.applyMigration(ctx, m)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "migration failed")
+			return applied, fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+		if ok {
+			applied = append(applied, m)
+		}
+	}
+
+	span.SetAttributes(attribute.Int("db.migrations_applied", len(applied)))
+	span.SetStatus(codes.Ok, "migrations applied")
+	return applied, nil
+}
+
+// applyMigration applies m unless it is already recorded, holding the
+// migration lock for the transaction. It reports whether m was applied.
+func (c *Client) applyMigration(ctx context.Context, m Migration) (bool, error) {
+	var applied bool
+	err := c.retry(ctx, func() error {
+		applied = false
+		tx, err := c.primary.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }() // nolint:errcheck // Rollback after Commit is a no-op
+
+		// A transaction-scoped lock is released on commit, which also works
+		// through PgBouncer in transaction pooling mode
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockKey); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrationsTableDDL); err != nil {
+			return err
+		}
+
+		var done bool
+		err = tx.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM public.worker_schema_migrations WHERE version = $1)`, m.Version).Scan(&done)
+		if err != nil || done {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO public.worker_schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+// SchemaVersion returns the highest applied migration version, or 0 if no
+// migration has been applied
+func (c *Client) SchemaVersion(ctx context.Context) (int, error) {
+	// The table name is resolved when a query is parsed, so its existence
+	// is checked separately
+	var exists bool
+	if err := c.queryRow(ctx, `SELECT to_regclass('public.worker_schema_migrations') IS NOT NULL`, nil, &exists); err != nil {
+		return 0, fmt.Errorf("schema version query failed: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	var version sql.NullInt64
+	if err := c.queryRow(ctx, `SELECT MAX(version) FROM public.worker_schema_migrations`, nil, &version); err != nil {
+		return 0, fmt.Errorf("schema version query failed: %w", err)
+	}
+	return int(version.Int64), nil
+}
@@ -0,0 +1,53 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	latest := migrations[len(migrations)-1].Version
+
+	// Concurrent runners serialize on the advisory lock, so every migration
+	// is applied at most once between them
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = client.Migrate(ctx)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	// Everything is applied, so another run is a no-op
+	applied, err := client.Migrate(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	version, err := client.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+
+	assert.NoError(t, client.CheckSchema(ctx))
+}
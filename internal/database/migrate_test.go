@@ -0,0 +1,54 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Migrations() failed: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("expected contiguous versions, got %d at position %d", m.Version, i)
+		}
+		if strings.TrimSpace(m.SQL) == "" {
+			t.Errorf("migration %04d_%s is empty", m.Version, m.Name)
+		}
+	}
+	if migrations[0].Name != "daily_distance_summary" {
+		t.Errorf("expected first migration daily_distance_summary, got %s", migrations[0].Name)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	sql := &fstest.MapFile{Data: []byte("SELECT 1;")}
+
+	migrations, err := loadMigrations(fstest.MapFS{
+		"m/0002_places.sql":  sql,
+		"m/0001_summary.sql": sql,
+	}, "m")
+	if err != nil {
+		t.Fatalf("loadMigrations() failed: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[1].Name != "places" {
+		t.Errorf("expected migrations in version order, got %+v", migrations)
+	}
+
+	invalid := map[string]fstest.MapFS{
+		"bad name":          {"m/add_places.sql": sql},
+		"uppercase":         {"m/0001_Places.sql": sql},
+		"duplicate version": {"m/0001_summary.sql": sql, "m/0001_places.sql": sql},
+	}
+	for name, fsys := range invalid {
+		if _, err := loadMigrations(fsys, "m"); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
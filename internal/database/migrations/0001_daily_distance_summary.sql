@@ -0,0 +1,17 @@
+-- Per-device per-day distance aggregates written by distance and backfill jobs.
+-- IF NOT EXISTS adopts tables created before migrations were introduced.
+CREATE TABLE IF NOT EXISTS public.daily_distance_summary (
+    device_id         TEXT             NOT NULL,
+    date              DATE             NOT NULL,
+    path_distance_km  DOUBLE PRECISION NOT NULL,
+    max_distance_km   DOUBLE PRECISION NOT NULL,
+    min_distance_km   DOUBLE PRECISION NOT NULL,
+    avg_distance_km   DOUBLE PRECISION NOT NULL,
+    point_count       INTEGER          NOT NULL,
+    time_away_seconds BIGINT           NOT NULL,
+    updated_at        TIMESTAMPTZ      NOT NULL DEFAULT now(),
+    PRIMARY KEY (device_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS daily_distance_summary_date_idx
+    ON public.daily_distance_summary (date);
@@ -0,0 +1,29 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RawPayload holds fields of the original OwnTracks message that have no
+// column of their own in public.locations. Fields the device did not send
+// are zero, except CourseDeg, which is nil so a due-north course of 0 can be
+// told apart from a missing one.
+type RawPayload struct {
+	InRegions        []string `json:"inregions"` // regions the device is currently inside
+	SSID             string   `json:"SSID"`      // Wi-Fi network name
+	BSSID            string   `json:"BSSID"`     // Wi-Fi access point MAC address
+	CourseDeg        *int     `json:"cog"`       // course over ground in degrees
+	VerticalAccuracy int      `json:"vac"`       // vertical accuracy in meters
+	PressureKPa      float64  `json:"p"`         // barometric pressure in kPa
+	MonitoringMode   int      `json:"m"`         // 1 = significant changes, 2 = move
+}
+
+// ParseRawPayload decodes an OwnTracks message into a RawPayload
+func ParseRawPayload(data []byte) (*RawPayload, error) {
+	var payload RawPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("invalid raw payload: %w", err)
+	}
+	return &payload, nil
+}
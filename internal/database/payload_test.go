@@ -0,0 +1,32 @@
+package database
+
+import "testing"
+
+func TestParseRawPayload(t *testing.T) {
+	payload, err := ParseRawPayload([]byte(`{"_type":"location","lat":40.7361,"inregions":["home","office"],"SSID":"homenet","BSSID":"aa:bb:cc:dd:ee:ff","cog":270,"vac":3,"p":101.325,"m":2}`))
+	if err != nil {
+		t.Fatalf("ParseRawPayload failed: %v", err)
+	}
+
+	if len(payload.InRegions) != 2 || payload.InRegions[1] != "office" {
+		t.Errorf("unexpected regions %v", payload.InRegions)
+	}
+	if payload.SSID != "homenet" || payload.BSSID != "aa:bb:cc:dd:ee:ff" {
+		t.Errorf("unexpected Wi-Fi fields %q %q", payload.SSID, payload.BSSID)
+	}
+	if payload.CourseDeg == nil || *payload.CourseDeg != 270 || payload.VerticalAccuracy != 3 || payload.PressureKPa != 101.325 || payload.MonitoringMode != 2 {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+
+	// A due-north course is kept apart from a missing one
+	if payload, err := ParseRawPayload([]byte(`{"cog":0}`)); err != nil || payload.CourseDeg == nil || *payload.CourseDeg != 0 {
+		t.Errorf("expected a course of 0, got %+v (%v)", payload, err)
+	}
+	if payload, err := ParseRawPayload([]byte(`{"SSID":"homenet"}`)); err != nil || payload.CourseDeg != nil {
+		t.Errorf("expected no course, got %+v (%v)", payload, err)
+	}
+
+	if _, err := ParseRawPayload([]byte(`{"cog":"north"}`)); err == nil {
+		t.Error("expected error for mistyped field, got nil")
+	}
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Connection targets, recorded as the db.target span attribute
+const (
+	targetPrimary = "primary"
+	targetReplica = "replica"
+)
+
+// pool is a connection pool to one server with its own circuit breaker
+type pool struct {
+	target  string
+	dsn     string
+	db      *sql.DB
+	breaker *circuitBreaker
+}
+
+// openPool opens a connection pool sized by opts without connecting
+func openPool(target, dsn string, opts Options) (*pool, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %w", target, err)
+	}
+
+	db.SetMaxOpenConns(opts.Pool.MaxOpenConns)
+	db.SetMaxIdleConns(opts.Pool.MaxIdleConns)
+	db.SetConnMaxLifetime(opts.Pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(opts.Pool.ConnMaxIdleTime)
+
+	return &pool{target: target, dsn: dsn, db: db, breaker: newCircuitBreaker(opts.Breaker)}, nil
+}
+
+// route selects the pool a read runs on
+type route int
+
+const (
+	// routePrimary always reads from the primary
+	routePrimary route = iota
+	// routeReplica reads from the replica while it is healthy, and from the
+	// primary otherwise
+	routeReplica
+)
+
+// attachReplica opens the read replica pool. An unreachable replica does not
+// fail startup: its breaker is opened so reads go to the primary until a
+// health check reaches it.
+func (c *Client) attachReplica(ctx context.Context, dsn string) error {
+	replica, err := openPool(targetReplica, dsn, c.opts)
+	if err != nil {
+		return err
+	}
+	if err := replica.db.PingContext(ctx); err != nil {
+		replica.breaker.trip()
+	}
+	c.replica = replica
+	return nil
+}
+
+// ReplicaAvailable reports whether location queries are currently routed to
+// a read replica
+func (c *Client) ReplicaAvailable() bool {
+	return c.replica != nil && c.replica.breaker.available()
+}
+
+// openCursor begins a read-only transaction and declares stream_cursor for
+// query on the pool chosen by route. If the replica cannot open the cursor
+// the primary is tried instead; no rows have been read at that point, so
+// the fallback is invisible to the caller.
+func (c *Client) openCursor(ctx context.Context, route route, query string, args []interface{}) (*sql.Tx, *pool, error) {
+	p := c.primary
+	if route == routeReplica && c.ReplicaAvailable() {
+		p = c.replica
+	}
+
+	tx, err := c.declareCursor(ctx, p, query, args)
+	if err != nil && p == c.replica && (IsRetryable(err) || errors.Is(err, ErrCircuitOpen)) {
+		trace.SpanFromContext(ctx).AddEvent("db.replica_fallback", trace.WithAttributes(
+			attribute.String("db.error", err.Error()),
+		))
+		p = c.primary
+		tx, err = c.declareCursor(ctx, p, query, args)
+	}
+	return tx, p, err
+}
+
+// declareCursor opens stream_cursor for query on p with retries, applying
+// the statement timeout to every statement in the transaction
+func (c *Client) declareCursor(ctx context.Context, p *pool, query string, args []interface{}) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := c.retryOn(ctx, p, func() error {
+		var err error
+		tx, err = p.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return fmt.Errorf("begin transaction failed: %w", err)
+		}
+		if c.opts.StatementTimeout > 0 {
+			// SET LOCAL ends with the transaction, so pooled connections
+			// and PgBouncer sessions keep their own setting
+			timeout := fmt.Sprintf("SET LOCAL statement_timeout = %d", c.opts.StatementTimeout.Milliseconds())
+			if _, err := tx.ExecContext(ctx, timeout); err != nil {
+				_ = tx.Rollback() // nolint:errcheck // the SET error is the one reported
+				return fmt.Errorf("set statement timeout failed: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "DECLARE stream_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+			_ = tx.Rollback() // nolint:errcheck // the declare error is the one reported
+			return fmt.Errorf("query failed: %w", err)
+		}
+		return nil
+	})
+	return tx, err
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// unreachableDSN points at a port nothing listens on
+const unreachableDSN = "host=127.0.0.1 port=1 dbname=owntracks user=test sslmode=disable connect_timeout=1"
+
+func TestAttachReplica_Unreachable(t *testing.T) {
+	opts := DefaultOptions()
+	c := testClient(opts)
+
+	if c.ReplicaAvailable() {
+		t.Fatal("expected no replica before one is attached")
+	}
+	if err := c.attachReplica(context.Background(), unreachableDSN); err != nil {
+		t.Fatalf("attachReplica() failed: %v", err)
+	}
+	defer func() { _ = c.replica.db.Close() }() // nolint:errcheck // test cleanup
+
+	if c.ReplicaAvailable() {
+		t.Error("expected an unreachable replica to be skipped until the cooldown ends")
+	}
+}
+
+func TestOpenCursor_ReplicaFallback(t *testing.T) {
+	opts := Options{
+		Retry:   RetryPolicy{MaxAttempts: 1},
+		Breaker: BreakerConfig{FailureThreshold: 5, Cooldown: time.Minute},
+	}
+	primary, err := openPool(targetPrimary, unreachableDSN, opts)
+	if err != nil {
+		t.Fatalf("openPool() failed: %v", err)
+	}
+	replica, err := openPool(targetReplica, unreachableDSN, opts)
+	if err != nil {
+		t.Fatalf("openPool() failed: %v", err)
+	}
+	defer func() { _, _ = primary.db.Close(), replica.db.Close() }() // nolint:errcheck // test cleanup
+	c := &Client{primary: primary, replica: replica, opts: opts}
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "query")
+
+	if _, _, err := c.openCursor(ctx, routeReplica, "SELECT 1", nil); err == nil {
+		t.Fatal("expected error with both servers down, got nil")
+	}
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	events := ended[0].Events()
+	if len(events) != 1 || events[0].Name != "db.replica_fallback" {
+		t.Errorf("expected a db.replica_fallback event, got %+v", events)
+	}
+	target := ""
+	for _, attr := range ended[0].Attributes() {
+		if attr.Key == "db.target" {
+			target = attr.Value.AsString()
+		}
+	}
+	if target != targetPrimary {
+		t.Errorf("expected db.target %q after fallback, got %q", targetPrimary, target)
+	}
+
+	// A primary-only read never touches the replica
+	ctx, span = provider.Tracer("test").Start(context.Background(), "garmin")
+	_, p, _ := c.openCursor(ctx, routePrimary, "SELECT 1", nil) // nolint:errcheck // both servers are down
+	span.End()
+	if p != primary {
+		t.Errorf("expected routePrimary to use the primary, got %s", p.target)
+	}
+}
@@ -0,0 +1,285 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrCircuitOpen is returned without contacting the database while the
+// circuit breaker is open after repeated connection failures
+var ErrCircuitOpen = errors.New("database unavailable: circuit breaker open")
+
+// Options configures a Client's connection pool, retries, circuit breaker
+// and read replica
+type Options struct {
+	Pool    PoolConfig
+	Retry   RetryPolicy
+	Breaker BreakerConfig
+
+	// ReplicaDSN, when set, routes location queries to a read-only replica,
+	// falling back to the primary while the replica is unhealthy
+	ReplicaDSN string
+
+	// StatementTimeout bounds each statement of a streaming query; 0 keeps
+	// the server default
+	StatementTimeout time.Duration
+}
+
+// PoolConfig sizes the connection pool
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// RetryPolicy controls retries of statements that fail with a retryable
+// error. Backoff doubles from InitialBackoff up to MaxBackoff, with jitter.
+type RetryPolicy struct {
+	MaxAttempts    int // total attempts, including the first; 1 disables retries
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// BreakerConfig controls the circuit breaker. After FailureThreshold
+// consecutive connection failures, calls fail fast with ErrCircuitOpen for
+// Cooldown; the next call after that is a probe that closes the breaker on
+// success or reopens it on failure.
+type BreakerConfig struct {
+	FailureThreshold int // 0 disables the breaker
+	Cooldown         time.Duration
+}
+
+// DefaultOptions returns the settings used by NewClient
+func DefaultOptions() Options {
+	return Options{
+		Pool: PoolConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnMaxIdleTime: 1 * time.Minute,
+		},
+		Retry: RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     2 * time.Second,
+		},
+		Breaker: BreakerConfig{
+			FailureThreshold: 5,
+			Cooldown:         30 * time.Second,
+		},
+	}
+}
+
+// Availability reports whether a store is currently reachable. Stores that
+// cannot become unavailable do not implement it.
+type Availability interface {
+	Available() bool
+}
+
+// retryableClasses are Postgres SQLSTATE classes that indicate a transient
+// condition: connection exceptions and insufficient resources
+var retryableClasses = map[pq.ErrorClass]bool{
+	"08": true, // connection_exception
+	"53": true, // insufficient_resources (too_many_connections, out_of_memory)
+}
+
+// retryableCodes are individual Postgres SQLSTATE codes worth retrying
+var retryableCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"55P03": true, // lock_not_available
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// IsRetryable reports whether err is a transient database or network error
+// that may succeed if the statement is run again
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return retryableCodes[pqErr.Code] || retryableClasses[pqErr.Code.Class()]
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// circuitBreaker fails calls fast while the database is unreachable
+type circuitBreaker struct {
+	cfg BreakerConfig
+	now func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// newCircuitBreaker creates a closed breaker
+func newCircuitBreaker(cfg BreakerConfig) *circuitBreaker {
+	return &circuitBreaker{cfg: cfg, now: time.Now}
+}
+
+// allow returns ErrCircuitOpen while the breaker is open
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of a call. Connection failures
+// count towards opening it; a success or a response from the server closes
+// it. Other errors, such as cancellation, leave it unchanged.
+func (b *circuitBreaker) record(err error) {
+	if b.cfg.FailureThreshold <= 0 || errors.Is(err, ErrCircuitOpen) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var pqErr *pq.Error
+	switch {
+	case IsRetryable(err):
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.openUntil = b.now().Add(b.cfg.Cooldown)
+		}
+	case err == nil || errors.Is(err, sql.ErrNoRows) || errors.As(err, &pqErr):
+		b.failures = 0
+		b.openUntil = time.Time{}
+	}
+}
+
+// trip opens the breaker for one cooldown without waiting for the failure
+// threshold
+func (b *circuitBreaker) trip() {
+	if b.cfg.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = b.cfg.FailureThreshold
+	b.openUntil = b.now().Add(b.cfg.Cooldown)
+}
+
+// available reports whether calls are currently allowed
+func (b *circuitBreaker) available() bool {
+	return b.allow() == nil
+}
+
+// Available reports whether the database is considered reachable; it is
+// false while the primary's circuit breaker is open
+func (c *Client) Available() bool {
+	return c.primary.breaker.available()
+}
+
+// retry runs op against the primary with retries
+func (c *Client) retry(ctx context.Context, op func() error) error {
+	return c.retryOn(ctx, c.primary, op)
+}
+
+// retryOn runs op until it succeeds, fails with an error that is not
+// retryable, or the retry policy is exhausted. Every attempt passes through
+// the circuit breaker of p, and p is recorded as the db.target of the
+// current span. op must not have side effects visible to the caller when it
+// fails.
+func (c *Client) retryOn(ctx context.Context, p *pool, op func() error) error {
+	policy := c.opts.Retry
+	backoff := policy.InitialBackoff
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("db.target", p.target))
+
+	for attempt := 1; ; attempt++ {
+		if err := p.breaker.allow(); err != nil {
+			return err
+		}
+
+		err := op()
+		p.breaker.record(err)
+		if err == nil || attempt >= policy.MaxAttempts || !IsRetryable(err) {
+			return err
+		}
+
+		// Up to 50% jitter so clients recovering together spread out
+		delay := backoff
+		if backoff > 0 {
+			delay += time.Duration(rand.Int64N(int64(backoff)/2 + 1)) // #nosec G404 -- jitter, not security sensitive
+		}
+		trace.SpanFromContext(ctx).AddEvent("db.retry", trace.WithAttributes(
+			attribute.Int("db.attempt", attempt),
+			attribute.String("db.error", err.Error()),
+			attribute.String("db.backoff", delay.String()),
+		))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// query runs a query with retries
+func (c *Client) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := c.retry(ctx, func() error {
+		var err error
+		rows, err = c.primary.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// exec runs a statement with retries
+func (c *Client) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := c.retry(ctx, func() error {
+		var err error
+		result, err = c.primary.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// queryRow runs a single-row query with retries and scans the result into dest
+func (c *Client) queryRow(ctx context.Context, query string, args []interface{}, dest ...interface{}) error {
+	return c.retry(ctx, func() error {
+		return c.primary.db.QueryRowContext(ctx, query, args...).Scan(dest...)
+	})
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"connection exception", &pq.Error{Code: "08006"}, true},
+		{"too many connections", &pq.Error{Code: "53300"}, true},
+		{"serialization failure", fmt.Errorf("query failed: %w", &pq.Error{Code: "40001"}), true},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, true},
+		{"undefined table", &pq.Error{Code: "42P01"}, false},
+		{"bad connection", driver.ErrBadConn, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"context canceled", context.Canceled, false},
+		{"circuit open", ErrCircuitOpen, false},
+		{"other", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: IsRetryable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 3, Cooldown: 30 * time.Second})
+	b.now = func() time.Time { return now }
+
+	down := &pq.Error{Code: "08006"}
+
+	// Errors the server answered, and cancellations, do not count
+	b.record(down)
+	b.record(down)
+	b.record(context.Canceled)
+	b.record(&pq.Error{Code: "42P01"}) // resets the count
+	b.record(down)
+	b.record(down)
+	if !b.available() {
+		t.Fatal("expected breaker to stay closed below the threshold")
+	}
+
+	b.record(down)
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen after 3 failures, got %v", err)
+	}
+
+	// After the cooldown a probe is allowed; a failure reopens immediately
+	now = now.Add(31 * time.Second)
+	if !b.available() {
+		t.Fatal("expected probe to be allowed after the cooldown")
+	}
+	b.record(down)
+	if b.available() {
+		t.Fatal("expected failed probe to reopen the breaker")
+	}
+
+	now = now.Add(31 * time.Second)
+	b.record(nil)
+	b.record(down)
+	if !b.available() {
+		t.Error("expected a success to close the breaker and reset the count")
+	}
+}
+
+func testClient(opts Options) *Client {
+	return &Client{opts: opts, primary: &pool{target: targetPrimary, breaker: newCircuitBreaker(opts.Breaker)}}
+}
+
+func TestClient_Retry(t *testing.T) {
+	opts := Options{
+		Retry:   RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond},
+		Breaker: BreakerConfig{FailureThreshold: 5, Cooldown: time.Minute},
+	}
+	ctx := context.Background()
+	down := &pq.Error{Code: "57P03"}
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		c := testClient(opts)
+		attempts := 0
+		err := c.retry(ctx, func() error {
+			attempts++
+			if attempts < 3 {
+				return down
+			}
+			return nil
+		})
+		if err != nil || attempts != 3 {
+			t.Errorf("expected success on attempt 3, got %v after %d", err, attempts)
+		}
+	})
+
+	t.Run("stops on a permanent error", func(t *testing.T) {
+		c := testClient(opts)
+		attempts := 0
+		permanent := &pq.Error{Code: "42601"}
+		err := c.retry(ctx, func() error {
+			attempts++
+			return permanent
+		})
+		if !errors.Is(err, permanent) || attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d (%v)", attempts, err)
+		}
+	})
+
+	t.Run("opens the breaker", func(t *testing.T) {
+		c := testClient(opts)
+		attempts := 0
+		op := func() error {
+			attempts++
+			return down
+		}
+		_ = c.retry(ctx, op) // nolint:errcheck // 3 failures
+		_ = c.retry(ctx, op) // nolint:errcheck // 2 more open the breaker
+
+		if attempts != 5 {
+			t.Errorf("expected 5 attempts before the breaker opened, got %d", attempts)
+		}
+		if c.Available() {
+			t.Error("expected client to report unavailable")
+		}
+		if err := c.retry(ctx, op); !errors.Is(err, ErrCircuitOpen) || attempts != 5 {
+			t.Errorf("expected fast ErrCircuitOpen, got %v after %d attempts", err, attempts)
+		}
+	})
+
+	t.Run("gives up when the context ends", func(t *testing.T) {
+		c := testClient(Options{Retry: RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour}})
+		ctx, cancel := context.WithCancel(ctx)
+		attempts := 0
+		err := c.retry(ctx, func() error {
+			attempts++
+			cancel()
+			return down
+		})
+		if !errors.Is(err, down) || attempts != 1 {
+			t.Errorf("expected the last error after 1 attempt, got %v after %d", err, attempts)
+		}
+	})
+}
+
+func TestNewClientWithOptions_Unreachable(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Retry = RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	// Nothing listens on port 1, so every attempt is refused
+	_, err := NewClientWithOptions("host=127.0.0.1 port=1 dbname=owntracks user=test sslmode=disable connect_timeout=1", opts)
+	if err == nil {
+		t.Fatal("expected error for unreachable database, got nil")
+	}
+	if !IsRetryable(err) {
+		t.Errorf("expected a retryable connection error, got %v", err)
+	}
+}
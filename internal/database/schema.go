@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// ErrSchemaMismatch is returned by CheckSchema when the database does not
+// match the columns the worker reads and writes
+var ErrSchemaMismatch = errors.New("database schema does not match the worker contract")
+
+// SchemaChecker is implemented by stores whose schema is managed outside the
+// worker and can drift from what it expects
+type SchemaChecker interface {
+	CheckSchema(ctx context.Context) error
+}
+
+// tableContract lists the columns of a table the worker depends on and the
+// information_schema data types each may have
+type tableContract struct {
+	table   string
+	columns map[string][]string
+}
+
+// schemaContract is the expected schema: public.locations as mapped by
+// Location and scanLocation, and the tables created by migrations
+var schemaContract = []tableContract{
+	{
+		table: "locations",
+		columns: map[string][]string{
+			"id":              {"integer", "bigint"},
+			"device_id":       {"character varying", "text"},
+			"tid":             {"character varying", "text"},
+			"latitude":        {"double precision"},
+			"longitude":       {"double precision"},
+			"accuracy":        {"integer"},
+			"altitude":        {"double precision"},
+			"velocity":        {"integer"},
+			"battery":         {"integer"},
+			"battery_status":  {"integer"},
+			"connection_type": {"character varying", "text"},
+			"trigger":         {"character varying", "text"},
+			"timestamp":       {"timestamp with time zone"},
+			"created_at":      {"timestamp with time zone"},
+			"raw_payload":     {"jsonb"},
+		},
+	},
+	{
+		table: "daily_distance_summary",
+		columns: map[string][]string{
+			"device_id":         {"text"},
+			"date":              {"date"},
+			"path_distance_km":  {"double precision"},
+			"max_distance_km":   {"double precision"},
+			"min_distance_km":   {"double precision"},
+			"avg_distance_km":   {"double precision"},
+			"point_count":       {"integer"},
+			"time_away_seconds": {"bigint"},
+			"updated_at":        {"timestamp with time zone"},
+		},
+	},
+}
+
+// CheckSchema compares the database with the worker's schema contract: every
+// expected column must exist with a compatible type, and every embedded
+// migration must have been applied. Mismatches are returned together,
+// wrapping ErrSchemaMismatch.
+func (c *Client) CheckSchema(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "CheckSchema")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	actual, err := c.tableColumns(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "query failed")
+		return err
+	}
+	problems := schemaProblems(schemaContract, actual)
+
+	version, err := c.SchemaVersion(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "query failed")
+		return err
+	}
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	// A newer worker may already have migrated further during a rolling
+	// deploy; only a schema behind this binary is a mismatch
+	if latest := migrations[len(migrations)-1].Version; version < latest {
+		problems = append(problems, fmt.Sprintf("schema version %d, want %d (run otel-worker migrate)", version, latest))
+	}
+
+	span.SetAttributes(attribute.Int("db.schema_version", version))
+	if len(problems) > 0 {
+		err := fmt.Errorf("%w: %s", ErrSchemaMismatch, strings.Join(problems, "; "))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "schema mismatch")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "schema matches")
+	return nil
+}
+
+// tableColumns returns the data type of each column of the contract tables,
+// keyed by table and column name
+func (c *Client) tableColumns(ctx context.Context) (map[string]map[string]string, error) {
+	tables := make([]string, len(schemaContract))
+	for i, t := range schemaContract {
+		tables[i] = t.table
+	}
+
+	rows, err := c.query(ctx, `
+		SELECT table_name, column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = ANY($1)`, pq.Array(tables))
+	if err != nil {
+		return nil, fmt.Errorf("column query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }() // nolint:errcheck // Close in defer, error not actionable
+
+	columns := make(map[string]map[string]string)
+	for rows.Next() {
+		var table, column, dataType string
+		if err := rows.Scan(&table, &column, &dataType); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		if columns[table] == nil {
+			columns[table] = make(map[string]string)
+		}
+		columns[table][column] = dataType
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+	return columns, nil
+}
+
+// schemaProblems describes every difference between contract and the actual
+// column types, in a stable order. Extra columns are allowed.
+func schemaProblems(contract []tableContract, actual map[string]map[string]string) []string {
+	var problems []string
+	for _, t := range contract {
+		columns, ok := actual[t.table]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("table %s is missing", t.table))
+			continue
+		}
+
+		names := make([]string, 0, len(t.columns))
+		for name := range t.columns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			want := t.columns[name]
+			got, ok := columns[name]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("column %s.%s is missing", t.table, name))
+			case !slices.Contains(want, got):
+				problems = append(problems, fmt.Sprintf("column %s.%s is %s, want %s", t.table, name, got, strings.Join(want, " or ")))
+			}
+		}
+	}
+	return problems
+}
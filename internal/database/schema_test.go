@@ -0,0 +1,51 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaProblems(t *testing.T) {
+	contract := []tableContract{
+		{table: "locations", columns: map[string][]string{
+			"id":       {"integer", "bigint"},
+			"altitude": {"double precision"},
+			"battery":  {"integer"},
+		}},
+		{table: "daily_distance_summary", columns: map[string][]string{
+			"date": {"date"},
+		}},
+	}
+
+	t.Run("matching schema", func(t *testing.T) {
+		actual := map[string]map[string]string{
+			"locations": {
+				"id":       "bigint",
+				"altitude": "double precision",
+				"battery":  "integer",
+				"extra":    "text", // columns the worker does not read are allowed
+			},
+			"daily_distance_summary": {"date": "date"},
+		}
+		if problems := schemaProblems(contract, actual); len(problems) != 0 {
+			t.Errorf("expected no problems, got %v", problems)
+		}
+	})
+
+	t.Run("mismatches", func(t *testing.T) {
+		actual := map[string]map[string]string{
+			"locations": {
+				"id":       "integer",
+				"altitude": "integer",
+			},
+		}
+		want := []string{
+			"column locations.altitude is integer, want double precision",
+			"column locations.battery is missing",
+			"table daily_distance_summary is missing",
+		}
+		if problems := schemaProblems(contract, actual); !reflect.DeepEqual(problems, want) {
+			t.Errorf("schemaProblems() = %v, want %v", problems, want)
+		}
+	})
+}
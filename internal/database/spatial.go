@@ -0,0 +1,271 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+)
+
+// earthRadiusKM matches the sphere used by calculator.Haversine
+const earthRadiusKM = 6371.0
+
+// BoundingBox is a latitude/longitude rectangle in decimal degrees. A box
+// crossing the antimeridian has MinLon greater than MaxLon.
+type BoundingBox struct {
+	MinLat float64
+	MinLon float64
+	MaxLat float64
+	MaxLon float64
+}
+
+// BoundingBoxAround returns the smallest box containing every point within
+// radiusKM of lat, lon. Near the poles it widens to all longitudes.
+func BoundingBoxAround(lat, lon, radiusKM float64) BoundingBox {
+	dLat := radiusKM / earthRadiusKM * 180 / math.Pi
+	box := BoundingBox{
+		MinLat: math.Max(lat-dLat, -90),
+		MaxLat: math.Min(lat+dLat, 90),
+		MinLon: -180,
+		MaxLon: 180,
+	}
+
+	// The circle reaches a pole, so every longitude is inside it
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		return box
+	}
+
+	dLon := math.Asin(math.Min(math.Sin(radiusKM/earthRadiusKM)/math.Cos(lat*math.Pi/180), 1)) * 180 / math.Pi
+	if dLon >= 180 {
+		return box
+	}
+	box.MinLon = normalizeLongitude(lon - dLon)
+	box.MaxLon = normalizeLongitude(lon + dLon)
+	return box
+}
+
+// normalizeLongitude wraps lon into [-180, 180]
+func normalizeLongitude(lon float64) float64 {
+	for lon < -180 {
+		lon += 360
+	}
+	for lon > 180 {
+		lon -= 360
+	}
+	return lon
+}
+
+// Validate checks that the box has valid coordinates
+func (b BoundingBox) Validate() error {
+	if b.MinLat < -90 || b.MaxLat > 90 || b.MinLat > b.MaxLat {
+		return fmt.Errorf("invalid latitude range %.6f to %.6f", b.MinLat, b.MaxLat)
+	}
+	if b.MinLon < -180 || b.MinLon > 180 || b.MaxLon < -180 || b.MaxLon > 180 {
+		return fmt.Errorf("invalid longitude range %.6f to %.6f", b.MinLon, b.MaxLon)
+	}
+	return nil
+}
+
+// Contains reports whether a point lies inside the box
+func (b BoundingBox) Contains(lat, lon float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon <= b.MaxLon {
+		return lon >= b.MinLon && lon <= b.MaxLon
+	}
+	return lon >= b.MinLon || lon <= b.MaxLon
+}
+
+// NearbyLocation is a location and its distance from a query point
+type NearbyLocation struct {
+	Location
+	DistanceKM float64
+}
+
+// NearbyLocationFunc is called once per nearby location in created_at order.
+// Returning a non-nil error stops iteration and is returned by the caller.
+type NearbyLocationFunc func(NearbyLocation) error
+
+// SpatialStore finds locations by position. Date ranges and device filters
+// behave as in LocationStore.
+type SpatialStore interface {
+	StreamLocationsInBox(ctx context.Context, box BoundingBox, startDate, endDate string, deviceID string, fn LocationFunc) error
+	StreamLocationsNear(ctx context.Context, lat, lon, radiusKM float64, startDate, endDate string, deviceID string, fn NearbyLocationFunc) error
+}
+
+// validateRadius checks the centre and radius of a radius query
+func validateRadius(lat, lon, radiusKM float64) error {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return fmt.Errorf("invalid point %.6f, %.6f", lat, lon)
+	}
+	if radiusKM <= 0 {
+		return fmt.Errorf("radius must be positive, got %.3f km", radiusKM)
+	}
+	return nil
+}
+
+// withinRadius wraps fn with the exact great-circle check that refines a
+// bounding-box pre-filter
+func withinRadius(lat, lon, radiusKM float64, fn NearbyLocationFunc) LocationFunc {
+	return func(loc Location) error {
+		distance := calculator.Haversine(lat, lon, loc.Latitude, loc.Longitude)
+		if distance > radiusKM {
+			return nil
+		}
+		return fn(NearbyLocation{Location: loc, DistanceKM: distance})
+	}
+}
+
+// boxCondition returns the SQL condition selecting rows inside box, with
+// placeholders numbered from next
+func boxCondition(box BoundingBox, next int) (string, []interface{}) {
+	args := []interface{}{box.MinLat, box.MaxLat, box.MinLon, box.MaxLon}
+	lonOp := "AND"
+	if box.MinLon > box.MaxLon {
+		lonOp = "OR" // crosses the antimeridian
+	}
+	cond := fmt.Sprintf("latitude BETWEEN $%d AND $%d AND (longitude >= $%d %s longitude <= $%d)",
+		next, next+1, next+2, lonOp, next+3)
+	return cond, args
+}
+
+// StreamLocationsInBox calls fn for each location inside box within a date
+// range, in created_at order
+func (c *Client) StreamLocationsInBox(ctx context.Context, box BoundingBox, startDate, endDate string, deviceID string, fn LocationFunc) error {
+	if err := box.Validate(); err != nil {
+		return err
+	}
+
+	ctx, span := tracer.Start(ctx, "StreamLocationsInBox")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.start_date", startDate),
+		attribute.String("db.end_date", endDate),
+		attribute.String("db.device_id", deviceID),
+		attribute.Float64Slice("db.bbox", []float64{box.MinLat, box.MinLon, box.MaxLat, box.MaxLon}),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	cond, boxArgs := boxCondition(box, 3)
+	query := c.selectLocations() + `
+		FROM public.locations
+		WHERE created_at >= $1::date AND created_at < $2::date + interval '1 day'
+		AND ` + cond
+	args := append([]interface{}{startDate, endDate}, boxArgs...)
+
+	if deviceID != "" {
+		args = append(args, deviceID)
+		query += fmt.Sprintf(" AND device_id = $%d", len(args))
+	}
+
+	query += " ORDER BY created_at ASC"
+
+	return c.streamLocations(ctx, span, query, args, fn)
+}
+
+// StreamLocationsNear calls fn for each location within radiusKM of lat, lon
+// within a date range, in created_at order. A bounding box narrows the rows in
+// SQL, with ST_DWithin as a further filter when PostGIS is installed; the
+// exact Haversine distance is then checked in Go so results match the
+// distance calculations.
+func (c *Client) StreamLocationsNear(ctx context.Context, lat, lon, radiusKM float64, startDate, endDate string, deviceID string, fn NearbyLocationFunc) error {
+	if err := validateRadius(lat, lon, radiusKM); err != nil {
+		return err
+	}
+
+	ctx, span := tracer.Start(ctx, "StreamLocationsNear")
+	defer span.End()
+
+	postgis := c.hasPostGIS(ctx)
+	span.SetAttributes(
+		attribute.String("db.start_date", startDate),
+		attribute.String("db.end_date", endDate),
+		attribute.String("db.device_id", deviceID),
+		attribute.Float64("db.latitude", lat),
+		attribute.Float64("db.longitude", lon),
+		attribute.Float64("db.radius_km", radiusKM),
+		attribute.Bool("db.postgis", postgis),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	cond, boxArgs := boxCondition(BoundingBoxAround(lat, lon, radiusKM), 3)
+	query := c.selectLocations() + `
+		FROM public.locations
+		WHERE created_at >= $1::date AND created_at < $2::date + interval '1 day'
+		AND ` + cond
+	args := append([]interface{}{startDate, endDate}, boxArgs...)
+
+	if postgis {
+		// The spheroid distance can differ from Haversine by up to 0.5%, so
+		// the SQL radius is padded and the exact check left to Go
+		args = append(args, lon, lat, radiusKM*1000*1.01)
+		query += fmt.Sprintf(` AND ST_DWithin(
+			ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography,
+			ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography,
+			$%d)`, len(args)-2, len(args)-1, len(args))
+	}
+
+	if deviceID != "" {
+		args = append(args, deviceID)
+		query += fmt.Sprintf(" AND device_id = $%d", len(args))
+	}
+
+	query += " ORDER BY created_at ASC"
+
+	return c.streamLocations(ctx, span, query, args, withinRadius(lat, lon, radiusKM, fn))
+}
+
+// postgisCheck caches whether the PostGIS extension is installed
+type postgisCheck struct {
+	mu        sync.Mutex
+	checked   bool
+	available bool
+}
+
+// hasPostGIS reports whether the PostGIS extension is installed. The answer
+// is cached after the first successful check; on error PostGIS is assumed
+// absent and the check is retried next time.
+func (c *Client) hasPostGIS(ctx context.Context) bool {
+	c.postgis.mu.Lock()
+	defer c.postgis.mu.Unlock()
+
+	if !c.postgis.checked {
+		var available bool
+		err := c.queryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'postgis')`, nil, &available)
+		if err != nil {
+			return false
+		}
+		c.postgis.checked, c.postgis.available = true, available
+	}
+	return c.postgis.available
+}
+
+// StreamLocationsInBox calls fn for each location inside box within a date range
+func (m *MemoryStore) StreamLocationsInBox(ctx context.Context, box BoundingBox, startDate, endDate string, deviceID string, fn LocationFunc) error {
+	if err := box.Validate(); err != nil {
+		return err
+	}
+	return m.StreamLocationsByDateRange(ctx, startDate, endDate, deviceID, func(loc Location) error {
+		if !box.Contains(loc.Latitude, loc.Longitude) {
+			return nil
+		}
+		return fn(loc)
+	})
+}
+
+// StreamLocationsNear calls fn for each location within radiusKM of lat, lon
+// within a date range
+func (m *MemoryStore) StreamLocationsNear(ctx context.Context, lat, lon, radiusKM float64, startDate, endDate string, deviceID string, fn NearbyLocationFunc) error {
+	if err := validateRadius(lat, lon, radiusKM); err != nil {
+		return err
+	}
+	return m.StreamLocationsInBox(ctx, BoundingBoxAround(lat, lon, radiusKM), startDate, endDate, deviceID, withinRadius(lat, lon, radiusKM, fn))
+}
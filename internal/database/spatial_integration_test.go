@@ -0,0 +1,88 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamLocationsNear(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	endDate := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	startDate := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+
+	locations, err := client.GetLocationsByDateRange(ctx, startDate, endDate, "")
+	require.NoError(t, err)
+	if len(locations) == 0 {
+		t.Skip("No locations in the last week")
+	}
+	centre := locations[len(locations)/2]
+
+	t.Logf("PostGIS available: %v", client.hasPostGIS(ctx))
+
+	var nearby []NearbyLocation
+	err = client.StreamLocationsNear(ctx, centre.Latitude, centre.Longitude, 0.2, startDate, endDate, "", func(n NearbyLocation) error {
+		nearby = append(nearby, n)
+		return nil
+	})
+	require.NoError(t, err)
+
+	found := false
+	for i, n := range nearby {
+		assert.LessOrEqual(t, n.DistanceKM, 0.2)
+		if n.ID == centre.ID {
+			found = true
+		}
+		if i > 0 {
+			assert.False(t, n.CreatedAt.Before(nearby[i-1].CreatedAt), "Matches should be in created_at order")
+		}
+	}
+	assert.True(t, found, "The centre location should be within the radius")
+}
+
+func TestStreamLocationsInBox_MatchesFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	date := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	locations, err := client.GetLocationsByDate(ctx, date, "")
+	require.NoError(t, err)
+	if len(locations) == 0 {
+		t.Skip("No locations yesterday")
+	}
+
+	box := BoundingBoxAround(locations[0].Latitude, locations[0].Longitude, 1)
+	expected := 0
+	for _, loc := range locations {
+		if box.Contains(loc.Latitude, loc.Longitude) {
+			expected++
+		}
+	}
+
+	count := 0
+	err = client.StreamLocationsInBox(ctx, box, date, date, "", func(Location) error {
+		count++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, count)
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+)
+
+func TestBoundingBoxAround(t *testing.T) {
+	lat, lon := 40.736097, -74.039373
+	box := BoundingBoxAround(lat, lon, 1)
+
+	// Points exactly 1 km north, south, east and west lie on the box edges
+	if d := calculator.Haversine(lat, lon, box.MaxLat, lon); math.Abs(d-1) > 0.001 {
+		t.Errorf("expected north edge 1 km away, got %.4f", d)
+	}
+	if d := calculator.Haversine(lat, lon, box.MinLat, lon); math.Abs(d-1) > 0.001 {
+		t.Errorf("expected south edge 1 km away, got %.4f", d)
+	}
+	if !box.Contains(lat, lon) || box.Contains(lat, box.MaxLon+0.001) {
+		t.Error("unexpected containment at the box edge")
+	}
+
+	// The widest point of the circle is not on the centre's parallel, so the
+	// box must still contain every point of the circle
+	for bearing := 0.0; bearing < 360; bearing += 5 {
+		pLat, pLon := destination(lat, lon, bearing, 0.999)
+		if !box.Contains(pLat, pLon) {
+			t.Errorf("point at bearing %.0f outside box %+v", bearing, box)
+		}
+	}
+
+	// Crossing the antimeridian wraps the longitude range
+	wrapped := BoundingBoxAround(0, 179.999, 5)
+	if wrapped.MinLon <= wrapped.MaxLon {
+		t.Errorf("expected wrapped longitudes, got %+v", wrapped)
+	}
+	if !wrapped.Contains(0, -179.99) || !wrapped.Contains(0, 179.99) || wrapped.Contains(0, 0) {
+		t.Errorf("unexpected containment for wrapped box %+v", wrapped)
+	}
+
+	// Reaching a pole covers every longitude
+	polar := BoundingBoxAround(89.99, 10, 5)
+	if polar.MinLon != -180 || polar.MaxLon != 180 || polar.MaxLat != 90 {
+		t.Errorf("expected full longitude range near the pole, got %+v", polar)
+	}
+}
+
+// destination returns the point distanceKM from lat, lon along bearing
+func destination(lat, lon, bearing, distanceKM float64) (float64, float64) {
+	toRad, toDeg := math.Pi/180, 180/math.Pi
+	d := distanceKM / earthRadiusKM
+	lat1, lon1, brng := lat*toRad, lon*toRad, bearing*toRad
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(brng))
+	lon2 := lon1 + math.Atan2(math.Sin(brng)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+	return lat2 * toDeg, lon2 * toDeg
+}
+
+func TestBoundingBox_Validate(t *testing.T) {
+	if err := (BoundingBox{MinLat: 40, MinLon: -75, MaxLat: 41, MaxLon: -74}).Validate(); err != nil {
+		t.Errorf("expected valid box, got %v", err)
+	}
+	if err := (BoundingBox{MinLat: 41, MinLon: -75, MaxLat: 40, MaxLon: -74}).Validate(); err == nil {
+		t.Error("expected error for reversed latitudes, got nil")
+	}
+	if err := (BoundingBox{MinLat: 40, MinLon: -190, MaxLat: 41, MaxLon: -74}).Validate(); err == nil {
+		t.Error("expected error for out-of-range longitude, got nil")
+	}
+}
+
+func TestMemoryStore_Spatial(t *testing.T) {
+	day := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	home := Location{ID: 1, DeviceID: "pixel8", Latitude: 40.736097, Longitude: -74.039373, CreatedAt: day}
+	corner := Location{ID: 2, DeviceID: "pixel8", Latitude: 40.7425, Longitude: -74.0310, CreatedAt: day.Add(time.Minute)} // in the box, ~1 km away
+	far := Location{ID: 3, DeviceID: "iphone", Latitude: 40.7580, Longitude: -73.9855, CreatedAt: day.Add(2 * time.Minute)}
+	near := Location{ID: 4, DeviceID: "iphone", Latitude: 40.7400, Longitude: -74.0394, CreatedAt: day.Add(3 * time.Minute)}
+	store := NewMemoryStore(home, corner, far, near)
+	ctx := context.Background()
+
+	var inBox []int64
+	box := BoundingBox{MinLat: 40.73, MinLon: -74.05, MaxLat: 40.75, MaxLon: -74.03}
+	err := store.StreamLocationsInBox(ctx, box, "2026-01-24", "2026-01-24", "", func(loc Location) error {
+		inBox = append(inBox, loc.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamLocationsInBox failed: %v", err)
+	}
+	if len(inBox) != 3 || inBox[0] != 1 || inBox[1] != 2 || inBox[2] != 4 {
+		t.Errorf("expected locations 1, 2 and 4 in the box, got %v", inBox)
+	}
+
+	var nearby []NearbyLocation
+	err = store.StreamLocationsNear(ctx, home.Latitude, home.Longitude, 0.5, "2026-01-24", "2026-01-24", "", func(n NearbyLocation) error {
+		nearby = append(nearby, n)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamLocationsNear failed: %v", err)
+	}
+	if len(nearby) != 2 || nearby[0].ID != 1 || nearby[1].ID != 4 {
+		t.Fatalf("expected locations 1 and 4 within 0.5 km, got %+v", nearby)
+	}
+	if nearby[0].DistanceKM != 0 || nearby[1].DistanceKM < 0.4 || nearby[1].DistanceKM > 0.5 {
+		t.Errorf("unexpected distances %.3f, %.3f", nearby[0].DistanceKM, nearby[1].DistanceKM)
+	}
+
+	if err := store.StreamLocationsNear(ctx, 40, -74, 0, "2026-01-24", "2026-01-24", "", func(NearbyLocation) error { return nil }); err == nil {
+		t.Error("expected error for zero radius, got nil")
+	}
+}
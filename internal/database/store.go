@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+)
+
+// LocationStore is a read-only source of GPS locations. Client reads from
+// PostgreSQL; MemoryStore and FileStore serve tests and offline runs.
+//
+// Locations are returned in CreatedAt order. Dates are YYYY-MM-DD and match the
+// calendar date of CreatedAt (in UTC for the in-process stores).
+type LocationStore interface {
+	GetLocationsByDate(ctx context.Context, date string, deviceID string) ([]Location, error)
+	GetLocationsByDateRange(ctx context.Context, startDate, endDate string, deviceID string) ([]Location, error)
+	StreamLocationsByDate(ctx context.Context, date string, deviceID string, fn LocationFunc) error
+	StreamLocationsByDateRange(ctx context.Context, startDate, endDate string, deviceID string, fn LocationFunc) error
+	GetDevices(ctx context.Context) ([]string, error)
+	GetLocationCount(ctx context.Context, date string, deviceID string) (int, error)
+	HealthCheck(ctx context.Context) error
+}
+
+// SummaryStore persists per-device daily aggregates. It is optional: stores
+// that cannot write (such as FileStore) do not implement it.
+type SummaryStore interface {
+	UpsertDailySummary(ctx context.Context, summary DailySummary) error
+	DeleteDailySummaries(ctx context.Context, date string, deviceID string) (int, error)
+	GetDailySummaries(ctx context.Context, startDate, endDate string, deviceID string) ([]DailySummary, error)
+}
+
+var (
+	_ LocationStore    = (*Client)(nil)
+	_ SummaryStore     = (*Client)(nil)
+	_ GarminStore      = (*Client)(nil)
+	_ UnifiedStore     = (*Client)(nil)
+	_ SpatialStore     = (*Client)(nil)
+	_ LocationFollower = (*Client)(nil)
+	_ LocationNotifier = (*Client)(nil)
+	_ LocationStore    = (*MemoryStore)(nil)
+	_ SummaryStore     = (*MemoryStore)(nil)
+	_ GarminStore      = (*MemoryStore)(nil)
+	_ UnifiedStore     = (*MemoryStore)(nil)
+	_ SpatialStore     = (*MemoryStore)(nil)
+	_ LocationFollower = (*MemoryStore)(nil)
+	_ LocationStore    = (*FileStore)(nil)
+)
@@ -0,0 +1,182 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// DailySummary is a per-device per-day aggregate row in
+// public.daily_distance_summary
+type DailySummary struct {
+	DeviceID        string
+	Date            string // YYYY-MM-DD
+	PathDistanceKM  float64
+	MaxDistanceKM   float64
+	MinDistanceKM   float64
+	AvgDistanceKM   float64
+	PointCount      int
+	TimeAwaySeconds int64
+	UpdatedAt       time.Time
+}
+
+// UpsertDailySummary inserts or replaces the aggregate row for a device and day
+func (c *Client) UpsertDailySummary(ctx context.Context, summary DailySummary) error {
+	ctx, span := tracer.Start(ctx, "UpsertDailySummary")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.date", summary.Date),
+		attribute.String("db.device_id", summary.DeviceID),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "INSERT"),
+	)
+
+	query := `
+		INSERT INTO public.daily_distance_summary (
+			device_id, date, path_distance_km, max_distance_km, min_distance_km,
+			avg_distance_km, point_count, time_away_seconds, updated_at
+		) VALUES ($1, $2::date, $3, $4, $5, $6, $7, $8, now())
+		ON CONFLICT (device_id, date) DO UPDATE SET
+			path_distance_km  = EXCLUDED.path_distance_km,
+			max_distance_km   = EXCLUDED.max_distance_km,
+			min_distance_km   = EXCLUDED.min_distance_km,
+			avg_distance_km   = EXCLUDED.avg_distance_km,
+			point_count       = EXCLUDED.point_count,
+			time_away_seconds = EXCLUDED.time_away_seconds,
+			updated_at        = now()
+	`
+
+	_, err := c.exec(ctx, query,
+		summary.DeviceID,
+		summary.Date,
+		summary.PathDistanceKM,
+		summary.MaxDistanceKM,
+		summary.MinDistanceKM,
+		summary.AvgDistanceKM,
+		summary.PointCount,
+		summary.TimeAwaySeconds,
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "upsert failed")
+		return fmt.Errorf("upsert failed: %w", err)
+	}
+
+	span.SetStatus(codes.Ok, "upsert succeeded")
+	return nil
+}
+
+// DeleteDailySummaries removes the aggregate rows for a day, for every device
+// when deviceID is empty, and returns the number of rows removed
+func (c *Client) DeleteDailySummaries(ctx context.Context, date string, deviceID string) (int, error) {
+	ctx, span := tracer.Start(ctx, "DeleteDailySummaries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.date", date),
+		attribute.String("db.device_id", deviceID),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "DELETE"),
+	)
+
+	query := `DELETE FROM public.daily_distance_summary WHERE date = $1::date`
+	args := []interface{}{date}
+
+	if deviceID != "" {
+		query += " AND device_id = $2"
+		args = append(args, deviceID)
+	}
+
+	result, err := c.exec(ctx, query, args...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete failed")
+		return 0, fmt.Errorf("delete failed: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete failed")
+		return 0, fmt.Errorf("delete failed: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", deleted))
+	span.SetStatus(codes.Ok, "delete succeeded")
+	return int(deleted), nil
+}
+
+// GetDailySummaries returns stored aggregates for a date range, ordered by
+// date then device. It reads only public.daily_distance_summary.
+func (c *Client) GetDailySummaries(ctx context.Context, startDate, endDate string, deviceID string) ([]DailySummary, error) {
+	ctx, span := tracer.Start(ctx, "GetDailySummaries")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.start_date", startDate),
+		attribute.String("db.end_date", endDate),
+		attribute.String("db.device_id", deviceID),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	query := `
+		SELECT
+			device_id, to_char(date, 'YYYY-MM-DD'), path_distance_km, max_distance_km,
+			min_distance_km, avg_distance_km, point_count, time_away_seconds, updated_at
+		FROM public.daily_distance_summary
+		WHERE date >= $1::date AND date <= $2::date
+	`
+
+	args := []interface{}{startDate, endDate}
+
+	if deviceID != "" {
+		query += " AND device_id = $3"
+		args = append(args, deviceID)
+	}
+
+	query += " ORDER BY date ASC, device_id ASC"
+
+	rows, err := c.query(ctx, query, args...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "query failed")
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }() // nolint:errcheck // Close in defer, error not actionable
+
+	var summaries []DailySummary
+	for rows.Next() {
+		var s DailySummary
+		if err := rows.Scan(
+			&s.DeviceID,
+			&s.Date,
+			&s.PathDistanceKM,
+			&s.MaxDistanceKM,
+			&s.MinDistanceKM,
+			&s.AvgDistanceKM,
+			&s.PointCount,
+			&s.TimeAwaySeconds,
+			&s.UpdatedAt,
+		); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "scan failed")
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "rows iteration failed")
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(summaries)))
+	span.SetStatus(codes.Ok, "query succeeded")
+	return summaries, nil
+}
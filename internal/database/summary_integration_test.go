@@ -0,0 +1,64 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailySummary_UpsertAndQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	client, cleanup := setupTestClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := client.Migrate(ctx)
+	require.NoError(t, err)
+
+	deviceID := "integration-test-summary"
+	defer func() {
+		_, _ = client.primary.db.ExecContext(ctx, "DELETE FROM public.daily_distance_summary WHERE device_id = $1", deviceID)
+	}()
+
+	summary := DailySummary{
+		DeviceID:        deviceID,
+		Date:            "2020-02-29",
+		PathDistanceKM:  12.5,
+		MaxDistanceKM:   6.1,
+		MinDistanceKM:   0.01,
+		AvgDistanceKM:   2.4,
+		PointCount:      120,
+		TimeAwaySeconds: 3600,
+	}
+	require.NoError(t, client.UpsertDailySummary(ctx, summary))
+
+	// Second upsert replaces the first
+	summary.PointCount = 121
+	require.NoError(t, client.UpsertDailySummary(ctx, summary))
+
+	summaries, err := client.GetDailySummaries(ctx, "2020-02-01", "2020-03-31", deviceID)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+
+	got := summaries[0]
+	assert.Equal(t, "2020-02-29", got.Date)
+	assert.Equal(t, 121, got.PointCount)
+	assert.InDelta(t, 12.5, got.PathDistanceKM, 0.0001)
+	assert.Equal(t, int64(3600), got.TimeAwaySeconds)
+	assert.False(t, got.UpdatedAt.IsZero())
+
+	deleted, err := client.DeleteDailySummaries(ctx, "2020-02-29", deviceID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	summaries, err = client.GetDailySummaries(ctx, "2020-02-01", "2020-03-31", deviceID)
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
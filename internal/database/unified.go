@@ -0,0 +1,363 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+)
+
+// Source identifies where a GPS point was recorded
+type Source string
+
+// GPS point sources
+const (
+	SourceOwnTracks Source = "owntracks"
+	SourceGarmin    Source = "garmin"
+)
+
+// GarminDeviceID is the device ID given to Garmin points in unified queries
+const GarminDeviceID = "garmin"
+
+const (
+	// DedupWindow is how close in time an OwnTracks fix must be to a Garmin
+	// point to be treated as the same position
+	DedupWindow = 30 * time.Second
+
+	// DedupDistanceKM is how close in space an OwnTracks fix must be to a
+	// Garmin point to be treated as the same position
+	DedupDistanceKM = 0.05
+)
+
+// GPSPoint is a source-agnostic GPS fix. Fields a source does not record
+// are zero.
+type GPSPoint struct {
+	Source     Source
+	DeviceID   string // OwnTracks device, or GarminDeviceID
+	ActivityID int64  // Garmin activity, zero for OwnTracks
+	Latitude   float64
+	Longitude  float64
+	Altitude   float64
+	Accuracy   int
+	Battery    int
+	SpeedKMH   float64
+	HeartRate  int
+	Timestamp  time.Time // when the fix was recorded
+}
+
+// GPSPointFunc is called once per point in time order. Returning a non-nil
+// error stops iteration and is returned by the caller.
+type GPSPointFunc func(GPSPoint) error
+
+// UnifiedStore reads OwnTracks and Garmin points as one time-ordered track.
+// Where both sources cover the same moment, the OwnTracks fix is dropped in
+// favour of the denser Garmin recording (see DedupWindow and DedupDistanceKM).
+//
+// OwnTracks fixes are selected by the date of created_at and optionally by
+// device, as in LocationStore; Garmin points by the date of their timestamp
+// and are never filtered by device.
+type UnifiedStore interface {
+	StreamGPSPointsByDate(ctx context.Context, date string, deviceID string, sources []Source, fn GPSPointFunc) error
+}
+
+// ParseSourceFilter parses a source filter: "owntracks" (the default when
+// empty), "garmin", "all", or a comma-separated list of sources
+func ParseSourceFilter(filter string) ([]Source, error) {
+	switch strings.TrimSpace(filter) {
+	case "":
+		return []Source{SourceOwnTracks}, nil
+	case "all":
+		return []Source{SourceOwnTracks, SourceGarmin}, nil
+	}
+
+	var sources []Source
+	seen := make(map[Source]bool)
+	for _, part := range strings.Split(filter, ",") {
+		source := Source(strings.TrimSpace(part))
+		switch source {
+		case SourceOwnTracks, SourceGarmin:
+		default:
+			return nil, fmt.Errorf("invalid source %q: must be owntracks, garmin or all", part)
+		}
+		if !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+	return sources, nil
+}
+
+// hasSource reports whether sources includes source
+func hasSource(sources []Source, source Source) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamGPSPointsByDate calls fn for each de-duplicated OwnTracks and Garmin
+// point on a date in time order, reading through a server-side cursor
+func (c *Client) StreamGPSPointsByDate(ctx context.Context, date string, deviceID string, sources []Source, fn GPSPointFunc) error {
+	ctx, span := tracer.Start(ctx, "StreamGPSPointsByDate")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.date", date),
+		attribute.String("db.device_id", deviceID),
+		attribute.String("db.sources", fmt.Sprint(sources)),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", "SELECT"),
+	)
+
+	// Equivalent to the unified_gps_points view, with per-source filters
+	// applied before the union so each side can use its own indexes
+	var parts []string
+	args := []interface{}{date}
+
+	if hasSource(sources, SourceOwnTracks) {
+		part := `
+			SELECT 'owntracks' AS source, device_id, 0::bigint AS activity_id,
+				latitude, longitude, COALESCE(altitude, 0)::float8 AS altitude,
+				COALESCE(accuracy, 0) AS accuracy, COALESCE(battery, 0) AS battery,
+				COALESCE(velocity, 0)::float8 AS speed_kmh, 0 AS heart_rate,
+				COALESCE(timestamp, created_at) AS recorded_at
+			FROM public.locations
+			WHERE DATE(created_at) = $1`
+		if deviceID != "" {
+			args = append(args, deviceID)
+			part += fmt.Sprintf(" AND device_id = $%d", len(args))
+		}
+		parts = append(parts, part)
+	}
+
+	if hasSource(sources, SourceGarmin) {
+		parts = append(parts, `
+			SELECT 'garmin', '`+GarminDeviceID+`', activity_id,
+				latitude, longitude, COALESCE(altitude, 0)::float8,
+				0, 0,
+				COALESCE(speed_kmh, 0)::float8, COALESCE(heart_rate, 0),
+				timestamp
+			FROM public.garmin_track_points
+			WHERE timestamp >= $1::date AND timestamp < $1::date + interval '1 day'`)
+	}
+
+	if len(parts) == 0 {
+		return fmt.Errorf("at least one source is required")
+	}
+
+	query := `
+		SELECT source, device_id, activity_id, latitude, longitude, altitude,
+			accuracy, battery, speed_kmh, heart_rate, recorded_at
+		FROM (` + strings.Join(parts, "\n\t\t\tUNION ALL") + `
+		) points
+		ORDER BY recorded_at ASC, source ASC`
+
+	dedup := newDeduplicator(fn)
+	err := c.streamCursor(ctx, span, routeReplica, query, args, func(rows *sql.Rows) error {
+		var p GPSPoint
+		var source string
+		if err := rows.Scan(
+			&source,
+			&p.DeviceID,
+			&p.ActivityID,
+			&p.Latitude,
+			&p.Longitude,
+			&p.Altitude,
+			&p.Accuracy,
+			&p.Battery,
+			&p.SpeedKMH,
+			&p.HeartRate,
+			&p.Timestamp,
+		); err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		p.Source = Source(source)
+
+		if err := dedup.add(p); err != nil {
+			return callbackError{err: err}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(attribute.Int("db.duplicates_dropped", dedup.dropped))
+	return dedup.flush()
+}
+
+// StreamGPSPointsByDate calls fn for each de-duplicated OwnTracks and Garmin
+// point on a date in time order
+func (m *MemoryStore) StreamGPSPointsByDate(ctx context.Context, date string, deviceID string, sources []Source, fn GPSPointFunc) error {
+	if len(sources) == 0 {
+		return fmt.Errorf("at least one source is required")
+	}
+
+	from, to, err := parseDateRange(date, date)
+	if err != nil {
+		return err
+	}
+
+	var points []GPSPoint
+	if hasSource(sources, SourceOwnTracks) {
+		locations, err := m.GetLocationsByDate(ctx, date, deviceID)
+		if err != nil {
+			return err
+		}
+		for _, loc := range locations {
+			points = append(points, locationToGPSPoint(loc))
+		}
+	}
+
+	if hasSource(sources, SourceGarmin) {
+		m.mu.RLock()
+		for _, track := range m.trackPoints {
+			for _, p := range track {
+				if !p.Timestamp.Before(from) && p.Timestamp.Before(to) {
+					points = append(points, trackPointToGPSPoint(p))
+				}
+			}
+		}
+		m.mu.RUnlock()
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		if !points[i].Timestamp.Equal(points[j].Timestamp) {
+			return points[i].Timestamp.Before(points[j].Timestamp)
+		}
+		return points[i].Source < points[j].Source
+	})
+
+	dedup := newDeduplicator(fn)
+	for _, p := range points {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := dedup.add(p); err != nil {
+			return err
+		}
+	}
+	return dedup.flush()
+}
+
+// locationToGPSPoint converts an OwnTracks location, timing it by the device
+// timestamp when present
+func locationToGPSPoint(loc Location) GPSPoint {
+	recorded := loc.CreatedAt
+	if loc.Timestamp > 0 {
+		recorded = time.Unix(loc.Timestamp, 0).UTC()
+	}
+	return GPSPoint{
+		Source:    SourceOwnTracks,
+		DeviceID:  loc.DeviceID,
+		Latitude:  loc.Latitude,
+		Longitude: loc.Longitude,
+		Altitude:  loc.Altitude,
+		Accuracy:  loc.Accuracy,
+		Battery:   loc.Battery,
+		SpeedKMH:  float64(loc.Velocity),
+		Timestamp: recorded,
+	}
+}
+
+// trackPointToGPSPoint converts a Garmin track point
+func trackPointToGPSPoint(p GarminTrackPoint) GPSPoint {
+	return GPSPoint{
+		Source:     SourceGarmin,
+		DeviceID:   GarminDeviceID,
+		ActivityID: p.ActivityID,
+		Latitude:   p.Latitude,
+		Longitude:  p.Longitude,
+		Altitude:   p.Altitude,
+		SpeedKMH:   p.SpeedKMH,
+		HeartRate:  p.HeartRate,
+		Timestamp:  p.Timestamp,
+	}
+}
+
+// deduplicator drops OwnTracks fixes that duplicate a Garmin point within
+// DedupWindow and DedupDistanceKM. Points must arrive in time order; each is
+// held until no later point can fall inside its window, so memory use is
+// bounded by the number of points in one window.
+type deduplicator struct {
+	fn      GPSPointFunc
+	buffer  []bufferedGPSPoint
+	dropped int
+}
+
+// bufferedGPSPoint is a point waiting for its dedup window to close
+type bufferedGPSPoint struct {
+	point   GPSPoint
+	dropped bool
+}
+
+// newDeduplicator creates a deduplicator that passes kept points to fn
+func newDeduplicator(fn GPSPointFunc) *deduplicator {
+	return &deduplicator{fn: fn}
+}
+
+// add offers the next point and emits every point whose window has closed
+func (d *deduplicator) add(p GPSPoint) error {
+	// Everything that could pair with p is still buffered
+	dropped := false
+	for i := range d.buffer {
+		other := &d.buffer[i]
+		if other.dropped || other.point.Source == p.Source || !duplicates(other.point, p) {
+			continue
+		}
+		if p.Source == SourceOwnTracks {
+			dropped = true
+		} else {
+			other.dropped = true
+			d.dropped++
+		}
+	}
+	if dropped {
+		d.dropped++
+	}
+	d.buffer = append(d.buffer, bufferedGPSPoint{point: p, dropped: dropped})
+
+	return d.emitBefore(p.Timestamp.Add(-DedupWindow))
+}
+
+// flush emits every remaining point
+func (d *deduplicator) flush() error {
+	return d.emitBefore(time.Time{})
+}
+
+// emitBefore emits buffered points recorded before cutoff, or all points
+// when cutoff is zero
+func (d *deduplicator) emitBefore(cutoff time.Time) error {
+	n := 0
+	for ; n < len(d.buffer); n++ {
+		b := d.buffer[n]
+		if !cutoff.IsZero() && !b.point.Timestamp.Before(cutoff) {
+			break
+		}
+		if b.dropped {
+			continue
+		}
+		if err := d.fn(b.point); err != nil {
+			return err
+		}
+	}
+	d.buffer = append(d.buffer[:0], d.buffer[n:]...)
+	return nil
+}
+
+// duplicates reports whether two points are the same position in time and space
+func duplicates(a, b GPSPoint) bool {
+	gap := a.Timestamp.Sub(b.Timestamp)
+	if gap < -DedupWindow || gap > DedupWindow {
+		return false
+	}
+	return calculator.Haversine(a.Latitude, a.Longitude, b.Latitude, b.Longitude) <= DedupDistanceKM
+}
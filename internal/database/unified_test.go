@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseSourceFilter(t *testing.T) {
+	tests := []struct {
+		filter  string
+		want    []Source
+		wantErr bool
+	}{
+		{"", []Source{SourceOwnTracks}, false},
+		{"owntracks", []Source{SourceOwnTracks}, false},
+		{"garmin", []Source{SourceGarmin}, false},
+		{"all", []Source{SourceOwnTracks, SourceGarmin}, false},
+		{"garmin, owntracks,garmin", []Source{SourceGarmin, SourceOwnTracks}, false},
+		{"strava", nil, true},
+		{"owntracks,", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSourceFilter(tt.filter)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSourceFilter(%q) error = %v, wantErr %v", tt.filter, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseSourceFilter(%q) = %v, want %v", tt.filter, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseSourceFilter(%q) = %v, want %v", tt.filter, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestDeduplicator(t *testing.T) {
+	start := time.Date(2026, 1, 24, 7, 0, 0, 0, time.UTC)
+	at := func(source Source, offset time.Duration, lon float64) GPSPoint {
+		return GPSPoint{Source: source, Latitude: 40.7361, Longitude: lon, Timestamp: start.Add(offset)}
+	}
+
+	input := []GPSPoint{
+		at(SourceOwnTracks, 0, -74.0394),             // before the ride, kept
+		at(SourceOwnTracks, 2*time.Minute, -74.0394), // arrives before its Garmin twin, dropped
+		at(SourceGarmin, 2*time.Minute+10*time.Second, -74.0394),
+		at(SourceGarmin, 3*time.Minute, -74.0300),
+		at(SourceOwnTracks, 3*time.Minute+5*time.Second, -74.0300), // arrives after, dropped
+		at(SourceOwnTracks, 3*time.Minute+6*time.Second, -74.0100), // same time, 1.7 km away, kept
+		at(SourceOwnTracks, 10*time.Minute, -74.0300),              // outside the window, kept
+	}
+
+	var got []GPSPoint
+	dedup := newDeduplicator(func(p GPSPoint) error {
+		got = append(got, p)
+		return nil
+	})
+	for _, p := range input {
+		if err := dedup.add(p); err != nil {
+			t.Fatalf("add failed: %v", err)
+		}
+	}
+	if err := dedup.flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	want := []int{0, 2, 3, 5, 6}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d points, got %d: %+v", len(want), len(got), got)
+	}
+	for i, idx := range want {
+		if got[i] != input[idx] {
+			t.Errorf("point %d: expected input %d, got %+v", i, idx, got[i])
+		}
+	}
+	if dedup.dropped != 2 {
+		t.Errorf("expected 2 dropped points, got %d", dedup.dropped)
+	}
+}
+
+func TestMemoryStore_StreamGPSPointsByDate(t *testing.T) {
+	day := time.Date(2026, 1, 24, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(
+		Location{ID: 1, DeviceID: "pixel8", Latitude: 40.7361, Longitude: -74.0394, CreatedAt: day.Add(7 * time.Hour)},
+		Location{ID: 2, DeviceID: "iphone", Latitude: 40.7361, Longitude: -74.0394, CreatedAt: day.Add(9 * time.Hour)},
+	)
+	store.AddGarminActivity(
+		GarminActivity{ActivityID: 1, StartTime: day.Add(7 * time.Hour)},
+		GarminTrackPoint{ActivityID: 1, Latitude: 40.7361, Longitude: -74.0394, Timestamp: day.Add(7*time.Hour + 5*time.Second)},
+		GarminTrackPoint{ActivityID: 1, Latitude: 40.7361, Longitude: -74.0394, Timestamp: day.Add(31 * time.Hour)}, // next day
+	)
+	ctx := context.Background()
+
+	collect := func(deviceID string, sources ...Source) []GPSPoint {
+		t.Helper()
+		var points []GPSPoint
+		err := store.StreamGPSPointsByDate(ctx, "2026-01-24", deviceID, sources, func(p GPSPoint) error {
+			points = append(points, p)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamGPSPointsByDate failed: %v", err)
+		}
+		return points
+	}
+
+	if points := collect("", SourceOwnTracks); len(points) != 2 {
+		t.Errorf("expected 2 OwnTracks points, got %d", len(points))
+	}
+
+	points := collect("", SourceGarmin)
+	if len(points) != 1 || points[0].DeviceID != GarminDeviceID || points[0].ActivityID != 1 {
+		t.Errorf("expected the single Garmin point on the date, got %+v", points)
+	}
+
+	// The pixel8 fix duplicates the Garmin point; the device filter does not
+	// apply to Garmin points
+	points = collect("pixel8", SourceOwnTracks, SourceGarmin)
+	if len(points) != 1 || points[0].Source != SourceGarmin {
+		t.Errorf("expected only the Garmin point, got %+v", points)
+	}
+
+	points = collect("", SourceOwnTracks, SourceGarmin)
+	if len(points) != 2 || points[0].Source != SourceGarmin || points[1].DeviceID != "iphone" {
+		t.Errorf("expected Garmin then iphone points, got %+v", points)
+	}
+
+	if err := store.StreamGPSPointsByDate(ctx, "2026-01-24", "", nil, func(GPSPoint) error { return nil }); err == nil {
+		t.Error("expected error for empty source list, got nil")
+	}
+}
@@ -0,0 +1,100 @@
+// Package download signs and verifies expiring job artifact download URLs.
+package download
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MinKeyLength is the shortest accepted signing key, in bytes
+const MinKeyLength = 32
+
+// Query parameters of a signed download URL
+const (
+	paramJob       = "job"
+	paramExpires   = "expires"
+	paramSignature = "signature"
+)
+
+// Verification errors
+var (
+	ErrUnsigned         = errors.New("download URL is not signed")
+	ErrExpired          = errors.New("download URL has expired")
+	ErrInvalidSignature = errors.New("download URL signature is invalid")
+)
+
+// Signer issues download URLs signed with HMAC-SHA256 over the job ID,
+// artifact key and expiry, so a URL cannot be reused for another artifact
+// or past its expiry
+type Signer struct {
+	key     []byte
+	ttl     time.Duration
+	baseURL string
+	now     func() time.Time
+}
+
+// NewSigner returns a signer whose URLs are valid for ttl. baseURL, such as
+// "https://otel-worker.example.com", is prepended to the /download/ path;
+// empty yields relative URLs.
+func NewSigner(key []byte, ttl time.Duration, baseURL string) *Signer {
+	return &Signer{key: key, ttl: ttl, baseURL: baseURL, now: time.Now}
+}
+
+// RandomKey returns a signing key for a single process, for deployments
+// that do not configure one
+func RandomKey() ([]byte, error) {
+	key := make([]byte, MinKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return key, nil
+}
+
+// URL returns a signed URL for downloading the artifact of a job, and when
+// it expires
+func (s *Signer) URL(jobID, artifactKey string) (string, time.Time) {
+	expires := s.now().Add(s.ttl).Truncate(time.Second)
+	query := url.Values{
+		paramJob:       {jobID},
+		paramExpires:   {strconv.FormatInt(expires.Unix(), 10)},
+		paramSignature: {s.signature(jobID, artifactKey, expires.Unix())},
+	}
+	return s.baseURL + "/download/" + url.PathEscape(artifactKey) + "?" + query.Encode(), expires
+}
+
+// Verify checks the signature and expiry of a download request for the
+// artifact and returns the job it was issued for
+func (s *Signer) Verify(artifactKey string, query url.Values) (string, error) {
+	jobID, expiresParam, signature := query.Get(paramJob), query.Get(paramExpires), query.Get(paramSignature)
+	if jobID == "" || expiresParam == "" || signature == "" {
+		return "", ErrUnsigned
+	}
+
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+	expected := s.signature(jobID, artifactKey, expires)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return "", ErrInvalidSignature
+	}
+	if !s.now().Before(time.Unix(expires, 0)) {
+		return "", ErrExpired
+	}
+	return jobID, nil
+}
+
+// signature returns the URL-safe HMAC of a job, artifact and expiry
+func (s *Signer) signature(jobID, artifactKey string, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	// Fields are length-prefixed so no two distinct triples sign the same
+	fmt.Fprintf(mac, "%d:%s%d:%s%d", len(jobID), jobID, len(artifactKey), artifactKey, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,79 @@
+package download
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2026, 1, 24, 12, 0, 0, 0, time.UTC)
+	signer := NewSigner([]byte(strings.Repeat("k", MinKeyLength)), time.Hour, "https://worker.example.com")
+	signer.now = func() time.Time { return now }
+
+	raw, expires := signer.URL("job-1", "distance_20260124.csv")
+	if !expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected expiry %s, got %s", now.Add(time.Hour), expires)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("invalid URL %q: %v", raw, err)
+	}
+	if u.Host != "worker.example.com" || u.Path != "/download/distance_20260124.csv" {
+		t.Errorf("unexpected URL %s", raw)
+	}
+
+	jobID, err := signer.Verify("distance_20260124.csv", u.Query())
+	if err != nil || jobID != "job-1" {
+		t.Errorf("expected job-1 to verify, got %q (%v)", jobID, err)
+	}
+
+	tamper := func(key, value string) url.Values {
+		query := u.Query()
+		query.Set(key, value)
+		return query
+	}
+	tests := []struct {
+		name     string
+		artifact string
+		query    url.Values
+		want     error
+	}{
+		{"unsigned", "distance_20260124.csv", url.Values{}, ErrUnsigned},
+		{"other artifact", "distance_20260125.csv", u.Query(), ErrInvalidSignature},
+		{"other job", "distance_20260124.csv", tamper("job", "job-2"), ErrInvalidSignature},
+		{"extended expiry", "distance_20260124.csv", tamper("expires", "1999999999"), ErrInvalidSignature},
+		{"bad expiry", "distance_20260124.csv", tamper("expires", "soon"), ErrInvalidSignature},
+		{"bad signature", "distance_20260124.csv", tamper("signature", "AAAA"), ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		if _, err := signer.Verify(tt.artifact, tt.query); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// A different key rejects the URL
+	other := NewSigner([]byte(strings.Repeat("x", MinKeyLength)), time.Hour, "")
+	other.now = signer.now
+	if _, err := other.Verify("distance_20260124.csv", u.Query()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected another key to reject the URL, got %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := signer.Verify("distance_20260124.csv", u.Query()); !errors.Is(err, ErrExpired) {
+		t.Errorf("expected ErrExpired, got %v", err)
+	}
+}
+
+func TestRandomKey(t *testing.T) {
+	a, err := RandomKey()
+	if err != nil {
+		t.Fatalf("RandomKey failed: %v", err)
+	}
+	b, _ := RandomKey()
+	if len(a) != MinKeyLength || string(a) == string(b) {
+		t.Errorf("expected distinct %d byte keys", MinKeyLength)
+	}
+}
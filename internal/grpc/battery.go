@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/database"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// GetBatteryReport returns battery drain, charging sessions and distance
+// correlation for each device over a date range
+func (s *Server) GetBatteryReport(ctx context.Context, req *distancev1.GetBatteryReportRequest) (*distancev1.GetBatteryReportResponse, error) {
+	log.Info().
+		Str("start_date", req.StartDate).
+		Str("end_date", req.EndDate).
+		Str("device_id", req.DeviceId).
+		Msg("Received battery report request")
+
+	if err := validateDateRange(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	// Stream readings into one analyzer per device, preserving chronological order
+	analyzers := make(map[string]*calculator.BatteryAnalyzer)
+	err := s.store.StreamLocationsByDateRange(ctx, req.StartDate, req.EndDate, req.DeviceId, func(loc database.Location) error {
+		analyzer, ok := analyzers[loc.DeviceID]
+		if !ok {
+			analyzer = calculator.NewBatteryAnalyzer()
+			analyzers[loc.DeviceID] = analyzer
+		}
+		analyzer.Add(calculator.BatteryReading{
+			Timestamp: fixTime(loc),
+			Level:     loc.Battery,
+			Status:    loc.BatteryStatus,
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+		})
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch locations from database")
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	deviceIDs := make([]string, 0, len(analyzers))
+	for deviceID := range analyzers {
+		deviceIDs = append(deviceIDs, deviceID)
+	}
+	sort.Strings(deviceIDs)
+
+	resp := &distancev1.GetBatteryReportResponse{}
+	for _, deviceID := range deviceIDs {
+		report := analyzers[deviceID].Report()
+		resp.Devices = append(resp.Devices, batteryReportToProto(deviceID, report))
+	}
+
+	return resp, nil
+}
+
+// batteryReportToProto converts a calculator battery report to its protobuf form
+func batteryReportToProto(deviceID string, report calculator.BatteryReport) *distancev1.DeviceBatteryReport {
+	out := &distancev1.DeviceBatteryReport{
+		DeviceId:            deviceID,
+		DrainRatePerHour:    report.DrainRatePerHour,
+		DistanceCorrelation: report.DistanceCorrelation,
+	}
+
+	for _, day := range report.Days {
+		out.Days = append(out.Days, &distancev1.DailyBattery{
+			Date:             day.Date,
+			DrainRatePerHour: day.DrainRatePerHour,
+			DrainedPercent:   day.DrainedPercent,
+			DischargeHours:   day.DischargeHours,
+			MinLevel:         int32(day.MinLevel),         // #nosec G115 -- battery level is 0-100
+			MaxLevel:         int32(day.MaxLevel),         // #nosec G115 -- battery level is 0-100
+			ChargingSessions: int32(day.ChargingSessions), // #nosec G115 -- bounded by readings per day
+			DistanceKm:       day.DistanceKM,
+			Readings:         int32(day.Readings), // #nosec G115 -- bounded by readings per day
+		})
+	}
+
+	for _, session := range report.Sessions {
+		out.ChargingSessions = append(out.ChargingSessions, &distancev1.ChargingSession{
+			StartTime:  timestamppb.New(session.Start),
+			EndTime:    timestamppb.New(session.End),
+			StartLevel: int32(session.StartLevel), // #nosec G115 -- battery level is 0-100
+			EndLevel:   int32(session.EndLevel),   // #nosec G115 -- battery level is 0-100
+		})
+	}
+
+	return out
+}
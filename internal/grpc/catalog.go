@@ -0,0 +1,447 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	"github.com/stuartshay/otel-worker/internal/storage"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// ErrArtifactNotFound is returned for a key that is neither in the catalog
+// nor in the artifact store
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// artifactExtensions maps the file extensions of artifacts to their formats
+var artifactExtensions = map[string]string{
+	".csv":     formatCSV,
+	".gpx":     formatGPX,
+	".geojson": formatGeoJSON,
+	".kml":     formatKML,
+	".parquet": formatParquet,
+}
+
+// catalogEntry is one stored artifact and the job that wrote it
+type catalogEntry struct {
+	queue.Artifact
+	JobID     string
+	Date      string // YYYY-MM-DD; empty for activity artifacts
+	DeviceID  string
+	CreatedAt time.Time
+}
+
+// artifactFilter selects catalog entries; empty fields match every entry
+type artifactFilter struct {
+	jobID    string
+	date     string
+	deviceID string
+	format   string
+}
+
+// matches reports whether e passes every filter
+func (f artifactFilter) matches(e catalogEntry) bool {
+	return (f.jobID == "" || e.JobID == f.jobID) &&
+		(f.date == "" || e.Date == f.date) &&
+		(f.deviceID == "" || e.DeviceID == f.deviceID) &&
+		(f.format == "" || e.Format == f.format)
+}
+
+// catalogRefreshInterval is how long ListArtifacts serves the catalog
+// before reconciling it with the artifact store again
+const catalogRefreshInterval = 30 * time.Second
+
+// artifactCatalog indexes the artifacts in the artifact store by key. Jobs
+// add what they write; the catalog is reconciled with the store on startup
+// and then at most every catalogRefreshInterval when listed, to pick up
+// artifacts written and deleted by earlier runs and other replicas.
+type artifactCatalog struct {
+	mu           sync.RWMutex
+	entries      map[string]catalogEntry
+	reconciledAt time.Time // zero until first reconciled
+}
+
+// newArtifactCatalog returns an empty catalog
+func newArtifactCatalog() *artifactCatalog {
+	return &artifactCatalog{entries: make(map[string]catalogEntry)}
+}
+
+// add records the artifacts a job wrote
+func (c *artifactCatalog) add(job *queue.Job, artifacts []queue.Artifact, createdAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, a := range artifacts {
+		c.entries[a.Key] = catalogEntry{Artifact: a, JobID: job.ID, Date: job.Date, DeviceID: job.DeviceID, CreatedAt: createdAt}
+	}
+}
+
+// put records one entry, replacing any entry for its key
+func (c *artifactCatalog) put(e catalogEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[e.Key] = e
+}
+
+// get returns the entry for key
+func (c *artifactCatalog) get(key string) (catalogEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+// remove drops the entry for key and reports whether there was one
+func (c *artifactCatalog) remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[key]
+	delete(c.entries, key)
+	return ok
+}
+
+// list returns the entries matching filter, newest first
+func (c *artifactCatalog) list(filter artifactFilter) []catalogEntry {
+	c.mu.RLock()
+	var entries []catalogEntry
+	for _, e := range c.entries {
+		if filter.matches(e) {
+			entries = append(entries, e)
+		}
+	}
+	c.mu.RUnlock()
+
+	slices.SortFunc(entries, func(a, b catalogEntry) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return entries
+}
+
+// stale reports whether the catalog was last reconciled more than
+// catalogRefreshInterval before now
+func (c *artifactCatalog) stale(now time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return now.Sub(c.reconciledAt) > catalogRefreshInterval
+}
+
+// reconcile makes the catalog match a listing of the artifact store taken
+// at listedAt: keys no longer stored are dropped, unless added by a job
+// since, and stored artifacts missing from the catalog are added from what
+// their keys tell. Files that are not artifacts are ignored.
+func (c *artifactCatalog) reconcile(infos []storage.Info, listedAt time.Time) (added, removed int) {
+	stored := make(map[string]catalogEntry, len(infos))
+	for _, info := range infos {
+		if e, ok := parseArtifactKey(info); ok {
+			stored[e.Key] = e
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if _, ok := stored[key]; !ok && e.CreatedAt.Before(listedAt) {
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	for key, e := range stored {
+		if _, ok := c.entries[key]; !ok {
+			c.entries[key] = e
+			added++
+		}
+	}
+	c.reconciledAt = listedAt
+	return added, removed
+}
+
+// parseArtifactKey recovers a catalog entry from a stored artifact's key,
+// which jobs name distance_YYYYMMDD[_device][_source]_<job ID>[_summary]
+// or distance_activity_<activity ID>_<job ID>, with the format's extension.
+// Keys from before job IDs were added leave the job ID empty. A device named
+// like a source ("owntracks", "garmin" or "all") cannot be told from one and
+// is read as the source.
+func parseArtifactKey(info storage.Info) (catalogEntry, bool) {
+	ext := filepath.Ext(info.Key)
+	format, ok := artifactExtensions[ext]
+	name := strings.TrimSuffix(info.Key, ext)
+	if !ok || !strings.HasPrefix(name, "distance_") {
+		return catalogEntry{}, false
+	}
+	if format == formatCSV && strings.HasSuffix(name, "_summary") {
+		format = formatCSVSummary
+		name = strings.TrimSuffix(name, "_summary")
+	}
+
+	e := catalogEntry{
+		Artifact:  queue.Artifact{Format: format, Key: info.Key, Size: info.Size, SHA256: info.SHA256},
+		CreatedAt: info.ModTime,
+	}
+	parts := strings.Split(strings.TrimPrefix(name, "distance_"), "_")
+	if last := parts[len(parts)-1]; len(parts) > 1 && uuid.Validate(last) == nil {
+		e.JobID = last
+		parts = parts[:len(parts)-1]
+	}
+	if parts[0] == "activity" {
+		return e, true
+	}
+
+	date, err := time.Parse("20060102", parts[0])
+	if err != nil {
+		return catalogEntry{}, false
+	}
+	e.Date = date.Format("2006-01-02")
+	parts = parts[1:]
+	if n := len(parts); n > 0 && (parts[n-1] == "all" || parts[n-1] == string(database.SourceOwnTracks) || parts[n-1] == string(database.SourceGarmin)) {
+		parts = parts[:n-1]
+	}
+	e.DeviceID = strings.Join(parts, "_")
+	return e, true
+}
+
+// ReconcileArtifacts lists the artifact store and brings the artifact
+// catalog in line with it. Call it on startup, after SetArtifactStore.
+func (s *Server) ReconcileArtifacts(ctx context.Context) error {
+	added, removed, err := s.reconcileArtifacts(ctx)
+	if err != nil {
+		return err
+	}
+	log.Info().
+		Int("added", added).
+		Int("removed", removed).
+		Msg("Reconciled artifact catalog with storage")
+	return nil
+}
+
+// reconcileArtifacts lists the artifact store and reconciles the catalog
+// with the listing
+func (s *Server) reconcileArtifacts(ctx context.Context) (added, removed int, err error) {
+	listedAt := time.Now()
+	infos, err := s.artifacts.List(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	added, removed = s.catalog.reconcile(infos, listedAt)
+	return added, removed, nil
+}
+
+// refreshCatalog reconciles a stale catalog with the artifact store. If the
+// store cannot be listed the catalog is served as it is.
+func (s *Server) refreshCatalog(ctx context.Context) {
+	if !s.catalog.stale(time.Now()) {
+		return
+	}
+	added, removed, err := s.reconcileArtifacts(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to refresh artifact catalog: listing may miss other replicas' artifacts")
+		return
+	}
+	log.Debug().
+		Int("added", added).
+		Int("removed", removed).
+		Msg("Refreshed artifact catalog from storage")
+}
+
+// ListArtifacts returns a page of the artifact catalog, first refreshing it
+// from the artifact store if it is stale
+func (s *Server) ListArtifacts(ctx context.Context, req *distancev1.ListArtifactsRequest) (*distancev1.ListArtifactsResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	offset := max(int(req.Offset), 0)
+
+	s.refreshCatalog(ctx)
+	entries := s.catalog.list(artifactFilter{jobID: req.JobId, date: req.Date, deviceID: req.DeviceId, format: req.Format})
+	resp := &distancev1.ListArtifactsResponse{
+		TotalCount: int32(len(entries)), // #nosec G115 -- bounded by the artifacts stored
+		Limit:      int32(limit),        // #nosec G115 -- limit is validated and capped at 500
+		Offset:     int32(offset),       // #nosec G115 -- offset is reasonable pagination value
+	}
+	for _, e := range entries[min(offset, len(entries)):min(offset+limit, len(entries))] {
+		resp.Artifacts = append(resp.Artifacts, catalogEntryToProto(e))
+	}
+	return resp, nil
+}
+
+// GetArtifact returns one artifact. Artifacts stored since the catalog was
+// last reconciled are looked up in the store and added; checksums missing
+// from a storage listing are filled in from the stored file.
+func (s *Server) GetArtifact(ctx context.Context, req *distancev1.GetArtifactRequest) (*distancev1.GetArtifactResponse, error) {
+	if err := storage.ValidateKey(req.Key); err != nil {
+		return nil, err
+	}
+
+	e, ok := s.catalog.get(req.Key)
+	if !ok || e.SHA256 == "" {
+		info, err := s.statArtifact(ctx, req.Key)
+		if errors.Is(err, ErrArtifactNotFound) {
+			s.catalog.remove(req.Key) // deleted by another replica
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			if e, ok = parseArtifactKey(info); !ok {
+				return nil, fmt.Errorf("%w: %s", ErrArtifactNotFound, req.Key)
+			}
+		}
+		e.SHA256, e.Size = info.SHA256, info.Size
+		s.catalog.put(e)
+	}
+	return &distancev1.GetArtifactResponse{Artifact: catalogEntryToProto(e)}, nil
+}
+
+// DeleteArtifact removes an artifact from the store and the catalog
+func (s *Server) DeleteArtifact(ctx context.Context, req *distancev1.DeleteArtifactRequest) (*distancev1.DeleteArtifactResponse, error) {
+	if err := storage.ValidateKey(req.Key); err != nil {
+		return nil, err
+	}
+	if _, ok := s.catalog.get(req.Key); !ok {
+		info, err := s.statArtifact(ctx, req.Key)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := parseArtifactKey(info); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrArtifactNotFound, req.Key)
+		}
+	}
+
+	if err := s.artifacts.Delete(ctx, req.Key); err != nil {
+		return nil, fmt.Errorf("failed to delete artifact: %w", err)
+	}
+	s.catalog.remove(req.Key)
+	log.Info().Str("key", req.Key).Msg("Deleted artifact")
+	return &distancev1.DeleteArtifactResponse{Key: req.Key}, nil
+}
+
+// statArtifact returns the details of a stored artifact
+func (s *Server) statArtifact(ctx context.Context, key string) (storage.Info, error) {
+	r, info, err := s.artifacts.Open(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return storage.Info{}, fmt.Errorf("%w: %s", ErrArtifactNotFound, key)
+	}
+	if err != nil {
+		return storage.Info{}, fmt.Errorf("failed to open artifact: %w", err)
+	}
+	_ = r.Close() // nolint:errcheck // read-only
+	return info, nil
+}
+
+// catalogEntryToProto converts a catalog entry to its protobuf form. It
+// carries no download URL: those are signed only for the job that wrote the
+// artifact, by GetJobStatus.
+func catalogEntryToProto(e catalogEntry) *distancev1.Artifact {
+	return &distancev1.Artifact{
+		Format:    e.Format,
+		Key:       e.Key,
+		SizeBytes: e.Size,
+		Sha256:    e.SHA256,
+		JobId:     e.JobID,
+		Date:      e.Date,
+		DeviceId:  e.DeviceID,
+		CreatedAt: timestamppb.New(e.CreatedAt),
+	}
+}
+
+// artifactChunkSize is the most DownloadArtifact sends in one message
+const artifactChunkSize = 64 << 10
+
+// checksumTrailer is the trailer DownloadArtifact sends the file's
+// checksum in
+const checksumTrailer = "x-checksum-sha256"
+
+// DownloadArtifact streams an artifact from the artifact store in chunks
+// from the requested offset, then sends its checksum in the trailer. When
+// the store keeps no checksum for the file, it is computed as the file is
+// read, including the part before offset.
+func (s *Server) DownloadArtifact(req *distancev1.DownloadArtifactRequest, stream grpc.ServerStreamingServer[distancev1.ArtifactChunk]) error {
+	if err := storage.ValidateKey(req.Key); err != nil {
+		return err
+	}
+	if _, ok := parseArtifactKey(storage.Info{Key: req.Key}); !ok {
+		return fmt.Errorf("%w: %s", ErrArtifactNotFound, req.Key)
+	}
+
+	content, info, err := s.artifacts.Open(stream.Context(), req.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrArtifactNotFound, req.Key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open artifact: %w", err)
+	}
+	defer func() { _ = content.Close() }() // nolint:errcheck // read-only
+
+	if req.Offset < 0 || req.Offset > info.Size {
+		return fmt.Errorf("invalid offset %d: artifact is %d bytes", req.Offset, info.Size)
+	}
+
+	var digest hash.Hash
+	if info.SHA256 == "" {
+		digest = sha256.New()
+		if _, err := io.CopyN(digest, content, req.Offset); err != nil {
+			return fmt.Errorf("failed to read artifact: %w", err)
+		}
+	} else if _, err := content.Seek(req.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek artifact: %w", err)
+	}
+
+	offset := req.Offset
+	for {
+		// gRPC may hold on to a sent message, so every chunk gets its own
+		// buffer
+		chunk := make([]byte, artifactChunkSize)
+		n, readErr := io.ReadFull(content, chunk)
+		if n > 0 {
+			if digest != nil {
+				_, _ = digest.Write(chunk[:n]) // nolint:errcheck // hashes never fail
+			}
+			if err := stream.Send(&distancev1.ArtifactChunk{Offset: offset, Data: chunk[:n], SizeBytes: info.Size}); err != nil {
+				log.Warn().Err(err).Str("key", req.Key).Int64("offset", offset).Msg("Artifact download interrupted")
+				return err
+			}
+			offset += int64(n)
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read artifact: %w", readErr)
+		}
+	}
+
+	sum := info.SHA256
+	if digest != nil {
+		sum = hex.EncodeToString(digest.Sum(nil))
+	}
+	stream.SetTrailer(metadata.Pairs(checksumTrailer, sum))
+
+	log.Info().
+		Str("key", req.Key).
+		Int64("offset", req.Offset).
+		Int64("sent_bytes", offset-req.Offset).
+		Msg("Artifact streamed")
+	return nil
+}
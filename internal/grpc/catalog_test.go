@@ -0,0 +1,321 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/storage"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+func TestParseArtifactKey(t *testing.T) {
+	const jobID = "0b6f3c8e-2a4d-4f1e-9c7a-5d2e8f1b3a6c"
+	tests := []struct {
+		key      string
+		ok       bool
+		format   string
+		jobID    string
+		date     string
+		deviceID string
+	}{
+		{"distance_20260124_" + jobID + ".csv", true, formatCSV, jobID, "2026-01-24", ""},
+		{"distance_20260124_pixel8_" + jobID + ".gpx", true, formatGPX, jobID, "2026-01-24", "pixel8"},
+		{"distance_20260124_pixel_8a_" + jobID + ".kml", true, formatKML, jobID, "2026-01-24", "pixel_8a"},
+		{"distance_20260124_pixel8_all_" + jobID + ".parquet", true, formatParquet, jobID, "2026-01-24", "pixel8"},
+		{"distance_20260124_garmin_" + jobID + ".geojson", true, formatGeoJSON, jobID, "2026-01-24", ""},
+		{"distance_20260124_pixel8_" + jobID + "_summary.csv", true, formatCSVSummary, jobID, "2026-01-24", "pixel8"},
+		{"distance_activity_42_" + jobID + ".csv", true, formatCSV, jobID, "", ""},
+		{"distance_20260124_pixel8.csv", true, formatCSV, "", "2026-01-24", "pixel8"},
+		{"distance_20260124_" + jobID + ".csv.gz", false, "", "", "", ""},
+		{"distance_2026012_" + jobID + ".csv", false, "", "", "", ""},
+		{"report_20260124_" + jobID + ".csv", false, "", "", "", ""},
+		{"distance_20260124_" + jobID + ".txt", false, "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		e, ok := parseArtifactKey(storage.Info{Key: tt.key, Size: 10})
+		if ok != tt.ok {
+			t.Errorf("%s: expected ok %v, got %v", tt.key, tt.ok, ok)
+			continue
+		}
+		if ok && (e.Format != tt.format || e.JobID != tt.jobID || e.Date != tt.date || e.DeviceID != tt.deviceID || e.Size != 10) {
+			t.Errorf("%s: unexpected entry %+v", tt.key, e)
+		}
+	}
+}
+
+// storeArtifact writes an artifact to the server's artifact store
+func storeArtifact(t *testing.T, server *Server, key, content string) {
+	t.Helper()
+	w, err := server.artifacts.Create(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, content)
+	if _, err := w.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+}
+
+func TestArtifactCatalog(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	server := newMemoryServer(t, database.NewMemoryStore(walkFromHome("pixel8", start, 5)...))
+	ctx := context.Background()
+
+	// A job's artifacts are cataloged when it completes
+	created, err := server.CalculateDistanceFromHome(ctx, &distancev1.CalculateDistanceRequest{
+		Date:          "2026-01-24",
+		DeviceId:      "pixel8",
+		OutputFormats: []string{"csv", "gpx"},
+	})
+	if err != nil {
+		t.Fatalf("CalculateDistanceFromHome failed: %v", err)
+	}
+	if resp := waitForJob(t, server, created.JobId); resp.Result == nil {
+		t.Fatalf("job failed: %s", resp.ErrorMessage)
+	}
+	listed, err := server.ListArtifacts(ctx, &distancev1.ListArtifactsRequest{JobId: created.JobId})
+	if err != nil {
+		t.Fatalf("ListArtifacts failed: %v", err)
+	}
+	if listed.TotalCount != 2 || len(listed.Artifacts) != 2 {
+		t.Fatalf("expected the job's 2 artifacts, got %+v", listed)
+	}
+	for _, a := range listed.Artifacts {
+		if a.JobId != created.JobId || a.Date != "2026-01-24" || a.DeviceId != "pixel8" || a.SizeBytes == 0 || len(a.Sha256) != 64 || a.DownloadUrl != "" || a.CreatedAt == nil {
+			t.Errorf("unexpected artifact %+v", a)
+		}
+	}
+
+	// Reconciling adds artifacts stored by earlier runs and drops deleted ones
+	const earlierJob = "0b6f3c8e-2a4d-4f1e-9c7a-5d2e8f1b3a6c"
+	storeArtifact(t, server, "distance_20260123_pixel8_"+earlierJob+".csv", "timestamp,device_id\n")
+	storeArtifact(t, server, "notes.txt", "not an artifact")
+	gpxKey := "distance_20260124_pixel8_" + created.JobId + ".gpx"
+	if err := server.artifacts.Delete(ctx, gpxKey); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := server.ReconcileArtifacts(ctx); err != nil {
+		t.Fatalf("ReconcileArtifacts failed: %v", err)
+	}
+
+	listed, err = server.ListArtifacts(ctx, &distancev1.ListArtifactsRequest{DeviceId: "pixel8", Format: "csv"})
+	if err != nil {
+		t.Fatalf("ListArtifacts failed: %v", err)
+	}
+	if listed.TotalCount != 2 {
+		t.Fatalf("expected 2 CSV artifacts, got %+v", listed.Artifacts)
+	}
+	if _, err := server.GetArtifact(ctx, &distancev1.GetArtifactRequest{Key: gpxKey}); !errors.Is(err, ErrArtifactNotFound) {
+		t.Errorf("expected ErrArtifactNotFound for a deleted artifact, got %v", err)
+	}
+
+	// Pagination
+	page, err := server.ListArtifacts(ctx, &distancev1.ListArtifactsRequest{Limit: 1, Offset: 1})
+	if err != nil || page.TotalCount != 2 || len(page.Artifacts) != 1 || page.Limit != 1 || page.Offset != 1 {
+		t.Errorf("unexpected page %+v (%v)", page, err)
+	}
+	if page, err := server.ListArtifacts(ctx, &distancev1.ListArtifactsRequest{Offset: 5}); err != nil || len(page.Artifacts) != 0 {
+		t.Errorf("expected an empty page past the end, got %+v (%v)", page, err)
+	}
+
+	// GetArtifact fills in the checksum a listing leaves out
+	got, err := server.GetArtifact(ctx, &distancev1.GetArtifactRequest{Key: "distance_20260123_pixel8_" + earlierJob + ".csv"})
+	if err != nil {
+		t.Fatalf("GetArtifact failed: %v", err)
+	}
+	a := got.Artifact
+	if a.JobId != earlierJob || a.Date != "2026-01-23" || a.DeviceId != "pixel8" || a.SizeBytes != 20 || len(a.Sha256) != 64 || a.DownloadUrl != "" {
+		t.Errorf("unexpected artifact %+v", a)
+	}
+
+	// DeleteArtifact removes the file and its catalog entry
+	if _, err := server.DeleteArtifact(ctx, &distancev1.DeleteArtifactRequest{Key: a.Key}); err != nil {
+		t.Fatalf("DeleteArtifact failed: %v", err)
+	}
+	if _, err := readArtifact(t, server, a.Key); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected the artifact to be deleted, got %v", err)
+	}
+	if listed, _ := server.ListArtifacts(ctx, &distancev1.ListArtifactsRequest{JobId: earlierJob}); len(listed.Artifacts) != 0 {
+		t.Errorf("expected no artifacts after delete, got %+v", listed.Artifacts)
+	}
+	if _, err := server.DeleteArtifact(ctx, &distancev1.DeleteArtifactRequest{Key: a.Key}); !errors.Is(err, ErrArtifactNotFound) {
+		t.Errorf("expected ErrArtifactNotFound deleting twice, got %v", err)
+	}
+
+	// Only artifacts can be inspected or deleted
+	for _, key := range []string{"notes.txt", "../distance_20260124.csv", ""} {
+		if _, err := server.GetArtifact(ctx, &distancev1.GetArtifactRequest{Key: key}); err == nil {
+			t.Errorf("expected error getting %q", key)
+		}
+		if _, err := server.DeleteArtifact(ctx, &distancev1.DeleteArtifactRequest{Key: key}); err == nil {
+			t.Errorf("expected error deleting %q", key)
+		}
+	}
+	if _, err := readArtifact(t, server, "notes.txt"); err != nil {
+		t.Errorf("expected notes.txt to be kept, got %v", err)
+	}
+}
+
+func TestListArtifacts_OtherReplicas(t *testing.T) {
+	server := newMemoryServer(t, database.NewMemoryStore())
+	ctx := context.Background()
+	const otherJob = "0b6f3c8e-2a4d-4f1e-9c7a-5d2e8f1b3a6c"
+	first := "distance_20260123_pixel8_" + otherJob + ".csv"
+	second := "distance_20260124_pixel8_" + otherJob + ".csv"
+
+	// Artifacts written by another replica are listed once the catalog is
+	// refreshed from the store
+	storeArtifact(t, server, first, "timestamp,device_id\n")
+	listed, err := server.ListArtifacts(ctx, &distancev1.ListArtifactsRequest{JobId: otherJob})
+	if err != nil || len(listed.Artifacts) != 1 || listed.Artifacts[0].Key != first {
+		t.Fatalf("expected %s to be listed, got %+v (%v)", first, listed, err)
+	}
+
+	storeArtifact(t, server, second, "timestamp,device_id\n")
+	if err := server.artifacts.Delete(ctx, first); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if listed, _ := server.ListArtifacts(ctx, &distancev1.ListArtifactsRequest{JobId: otherJob}); len(listed.Artifacts) != 1 || listed.Artifacts[0].Key != first {
+		t.Errorf("expected the fresh catalog to be served as is, got %+v", listed.Artifacts)
+	}
+
+	server.catalog.mu.Lock()
+	server.catalog.reconciledAt = server.catalog.reconciledAt.Add(-catalogRefreshInterval - time.Second)
+	server.catalog.mu.Unlock()
+	if listed, _ := server.ListArtifacts(ctx, &distancev1.ListArtifactsRequest{JobId: otherJob}); len(listed.Artifacts) != 1 || listed.Artifacts[0].Key != second {
+		t.Errorf("expected the stale catalog to be refreshed, got %+v", listed.Artifacts)
+	}
+}
+
+// chunkStream collects the chunks and trailer sent by DownloadArtifact
+type chunkStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	chunks  []*distancev1.ArtifactChunk
+	trailer metadata.MD
+}
+
+func (s *chunkStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *chunkStream) Send(chunk *distancev1.ArtifactChunk) error {
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+func (s *chunkStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+// uncheckedStore is a store that keeps no checksums, like S3 objects
+// uploaded by other tools
+type uncheckedStore struct {
+	storage.Store
+}
+
+func (s uncheckedStore) Open(ctx context.Context, key string) (io.ReadSeekCloser, storage.Info, error) {
+	r, info, err := s.Store.Open(ctx, key)
+	info.SHA256 = ""
+	return r, info, err
+}
+
+func TestDownloadArtifact(t *testing.T) {
+	server := newMemoryServer(t, database.NewMemoryStore())
+	key := "distance_20260124_0b6f3c8e-2a4d-4f1e-9c7a-5d2e8f1b3a6c.csv"
+	content := strings.Repeat("2026-01-24T08:00:00Z,pixel8,40.736097,-74.039373\n", 3000)
+	storeArtifact(t, server, key, content)
+	sum := sha256.Sum256([]byte(content))
+	wantSum := hex.EncodeToString(sum[:])
+
+	download := func(t *testing.T, offset int64) (*chunkStream, error) {
+		t.Helper()
+		stream := &chunkStream{ctx: context.Background()}
+		return stream, server.DownloadArtifact(&distancev1.DownloadArtifactRequest{Key: key, Offset: offset}, stream)
+	}
+	check := func(t *testing.T, stream *chunkStream, offset int64) {
+		t.Helper()
+		var received []byte
+		for _, chunk := range stream.chunks {
+			if chunk.Offset != offset+int64(len(received)) || chunk.SizeBytes != int64(len(content)) || len(chunk.Data) > artifactChunkSize {
+				t.Fatalf("unexpected chunk at %d: offset %d, size %d, %d bytes", len(received), chunk.Offset, chunk.SizeBytes, len(chunk.Data))
+			}
+			received = append(received, chunk.Data...)
+		}
+		if string(received) != content[offset:] {
+			t.Errorf("expected %d bytes from offset %d, got %d", len(content)-int(offset), offset, len(received))
+		}
+		if got := stream.trailer.Get(checksumTrailer); len(got) != 1 || got[0] != wantSum {
+			t.Errorf("expected checksum trailer %s, got %v", wantSum, got)
+		}
+	}
+
+	t.Run("whole file", func(t *testing.T) {
+		stream, err := download(t, 0)
+		if err != nil {
+			t.Fatalf("DownloadArtifact failed: %v", err)
+		}
+		if len(stream.chunks) != 3 {
+			t.Errorf("expected %d bytes in 3 chunks, got %d", len(content), len(stream.chunks))
+		}
+		check(t, stream, 0)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		stream, err := download(t, 70000)
+		if err != nil {
+			t.Fatalf("DownloadArtifact failed: %v", err)
+		}
+		check(t, stream, 70000)
+	})
+
+	t.Run("at end", func(t *testing.T) {
+		stream, err := download(t, int64(len(content)))
+		if err != nil {
+			t.Fatalf("DownloadArtifact failed: %v", err)
+		}
+		if len(stream.chunks) != 0 {
+			t.Errorf("expected no chunks, got %d", len(stream.chunks))
+		}
+		check(t, stream, int64(len(content)))
+	})
+
+	t.Run("store without checksums", func(t *testing.T) {
+		artifacts := server.artifacts
+		server.artifacts = uncheckedStore{artifacts}
+		defer func() { server.artifacts = artifacts }()
+		stream, err := download(t, 70000)
+		if err != nil {
+			t.Fatalf("DownloadArtifact failed: %v", err)
+		}
+		check(t, stream, 70000)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, offset := range []int64{-1, int64(len(content)) + 1} {
+			if _, err := download(t, offset); err == nil {
+				t.Errorf("expected error for offset %d", offset)
+			}
+		}
+		for _, k := range []string{"distance_20260125_0b6f3c8e-2a4d-4f1e-9c7a-5d2e8f1b3a6c.csv", "notes.txt"} {
+			err := server.DownloadArtifact(&distancev1.DownloadArtifactRequest{Key: k}, &chunkStream{ctx: context.Background()})
+			if !errors.Is(err, ErrArtifactNotFound) {
+				t.Errorf("%s: expected ErrArtifactNotFound, got %v", k, err)
+			}
+		}
+		if err := server.DownloadArtifact(&distancev1.DownloadArtifactRequest{Key: "../distance_20260124.csv"}, &chunkStream{ctx: context.Background()}); err == nil {
+			t.Error("expected error for an invalid key")
+		}
+	})
+}
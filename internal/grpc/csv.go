@@ -0,0 +1,231 @@
+package grpc
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	"github.com/stuartshay/otel-worker/internal/storage"
+)
+
+// distanceCSVHeader is the column header of a distance CSV report
+var distanceCSVHeader = []string{
+	"timestamp", "device_id", "latitude", "longitude",
+	"distance_from_home_km", "accuracy", "battery", "velocity",
+	"speed_kmh", "acceleration_ms2", "mode",
+	"altitude_m", "smoothed_altitude_m", "cumulative_ascent_m",
+}
+
+// payloadCSVHeader is the optional raw_payload columns appended to a
+// distance CSV report when IncludeRawPayload is set
+var payloadCSVHeader = []string{
+	"regions", "ssid", "bssid", "course_deg", "pressure_kpa",
+}
+
+// csvHeaderFor returns every column of a distance CSV report over sources,
+// before a template selects them
+func (s *Server) csvHeaderFor(sources []database.Source) []string {
+	if !ownTracksOnly(sources) {
+		return unifiedCSVHeader
+	}
+	if s.cfg.IncludeRawPayload {
+		return append(append([]string{}, distanceCSVHeader...), payloadCSVHeader...)
+	}
+	return distanceCSVHeader
+}
+
+// csvReport writes a CSV report one row at a time
+type csvReport struct {
+	key    string
+	file   storage.Writer
+	writer *csv.Writer
+}
+
+// createCSVReport creates the artifact filename and writes the header row
+func (s *Server) createCSVReport(ctx context.Context, filename string, header []string) (*csvReport, error) {
+	return s.createDelimitedReport(ctx, filename, header, ',')
+}
+
+// createDelimitedReport creates a CSV report whose fields are separated by
+// delimiter
+func (s *Server) createDelimitedReport(ctx context.Context, filename string, header []string, delimiter rune) (*csvReport, error) {
+	file, err := s.artifacts.Create(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CSV file: %w", err)
+	}
+
+	report := &csvReport{key: filename, file: file, writer: csv.NewWriter(file)}
+	report.writer.Comma = delimiter
+	if err := report.writer.Write(header); err != nil {
+		report.abort()
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	return report, nil
+}
+
+// writeRow writes one data row
+func (r *csvReport) writeRow(row []string) error {
+	if err := r.writer.Write(row); err != nil {
+		return fmt.Errorf("failed to write CSV row: %w", err)
+	}
+	return nil
+}
+
+// finish writes the summary footer and closes the file
+func (r *csvReport) finish(footer [][]string) (storage.Info, error) {
+	for _, row := range footer {
+		if err := r.writer.Write(row); err != nil {
+			r.abort()
+			return storage.Info{}, fmt.Errorf("failed to write CSV summary: %w", err)
+		}
+	}
+
+	r.writer.Flush()
+	if err := r.writer.Error(); err != nil {
+		r.abort()
+		return storage.Info{}, fmt.Errorf("failed to flush CSV file: %w", err)
+	}
+	info, err := r.file.Commit()
+	if err != nil {
+		r.file.Abort()
+		return storage.Info{}, fmt.Errorf("failed to store CSV file: %w", err)
+	}
+
+	log.Info().Str("csv_key", r.key).Str("sha256", info.SHA256).Msg("CSV file generated successfully")
+
+	return info, nil
+}
+
+// abort discards a partially written report
+func (r *csvReport) abort() {
+	r.file.Abort()
+}
+
+// pointQueue pairs source rows with the points TrackAnalyzer emits for them.
+// The analyzer holds back a few fixes until later ones arrive, so each row
+// waits here until its analyzed point is ready.
+type pointQueue[T any] struct {
+	pending []T
+	write   func(T, calculator.AnalyzedPoint) error
+}
+
+// push queues the source row for the fix just passed to the analyzer
+func (q *pointQueue[T]) push(row T) {
+	q.pending = append(q.pending, row)
+}
+
+// emit writes the oldest queued rows with the points the analyzer returned
+func (q *pointQueue[T]) emit(points []calculator.AnalyzedPoint) error {
+	for _, point := range points {
+		if err := q.write(q.pending[0], point); err != nil {
+			return err
+		}
+		q.pending = q.pending[1:]
+	}
+	return nil
+}
+
+// distanceName returns the output file name, without extension, for a date
+// and optional device, e.g. distance_20260124_pixel8. The job's ID is
+// appended by artifactName.
+func distanceName(date, deviceID string) string {
+	dateStr := date
+	if len(date) == 10 {
+		dateStr = date[0:4] + date[5:7] + date[8:10] // YYYYMMDD
+	}
+	if deviceID != "" {
+		return fmt.Sprintf("distance_%s_%s", dateStr, deviceID)
+	}
+	return fmt.Sprintf("distance_%s", dateStr)
+}
+
+// artifactName appends the job's ID to an output file name, so concurrent
+// jobs for the same date never write the same artifact
+func artifactName(name string, job *queue.Job) string {
+	return name + "_" + job.ID
+}
+
+// distanceRow returns the cells of the data row for one location and its
+// analyzed values
+func distanceRow(loc database.Location, point calculator.AnalyzedPoint) []csvCell {
+	return []csvCell{
+		timeCell(loc.CreatedAt),
+		textCell(loc.DeviceID),
+		coordinateCell(loc.Latitude),
+		coordinateCell(loc.Longitude),
+		distanceCell(point.DistanceFromHomeKM, 2),
+		intCell(loc.Accuracy),
+		intCell(loc.Battery),
+		intCell(loc.Velocity),
+		speedCell(point.SpeedKMH, 2),
+		numberCell(point.AccelerationMS2, 3),
+		textCell(string(point.Mode)),
+		numberCell(loc.Altitude, 1),
+		numberCell(point.SmoothedAltitudeM, 1),
+		numberCell(point.CumulativeAscentM, 1),
+	}
+}
+
+// payloadColumns returns the cells of the optional raw_payload columns; all
+// are empty when the location has no decoded payload. Regions are separated
+// by ';'.
+func payloadColumns(payload *database.RawPayload) []csvCell {
+	if payload == nil {
+		cells := make([]csvCell, len(payloadCSVHeader))
+		for i := range cells {
+			cells[i] = textCell("")
+		}
+		return cells
+	}
+
+	course, pressure := textCell(""), textCell("")
+	if payload.CourseDeg != nil {
+		course = intCell(*payload.CourseDeg)
+	}
+	if payload.PressureKPa != 0 {
+		pressure = numberCell(payload.PressureKPa, 3)
+	}
+
+	return []csvCell{
+		textCell(strings.Join(payload.InRegions, ";")),
+		textCell(payload.SSID),
+		textCell(payload.BSSID),
+		course,
+		pressure,
+	}
+}
+
+// distanceFooter returns the summary rows of a distance report
+func distanceFooter(summary calculator.TrackSummary) []csvSummaryRow {
+	metrics, elevation := summary.Metrics, summary.Elevation
+	rows := []csvSummaryRow{
+		{"Total Distance", distanceCell(metrics.TotalDistanceKM, 2)},
+		{"Max Distance", distanceCell(metrics.MaxDistanceKM, 2)},
+		{"Min Distance", distanceCell(metrics.MinDistanceKM, 2)},
+		{"Total Locations", intCell(metrics.TotalLocations)},
+		{"Average Distance", distanceCell(metrics.AvgDistanceKM, 2)},
+		{"Max Speed", speedCell(summary.MaxSpeedKMH, 2)},
+		{"Total Ascent (m)", numberCell(elevation.AscentM, 1)},
+		{"Total Descent (m)", numberCell(elevation.DescentM, 1)},
+		{"Min Altitude (m)", numberCell(elevation.MinAltitudeM, 1)},
+		{"Max Altitude (m)", numberCell(elevation.MaxAltitudeM, 1)},
+		{"Trips", intCell(len(summary.Trips))},
+	}
+
+	for _, mt := range summary.ModeTotals {
+		label := strings.ToUpper(string(mt.Mode[:1])) + string(mt.Mode[1:])
+		rows = append(rows,
+			csvSummaryRow{label + " Distance", distanceCell(mt.DistanceKM, 2)},
+			csvSummaryRow{label + " Time (min)", numberCell(mt.Duration.Minutes(), 1)},
+		)
+	}
+
+	return rows
+}
@@ -0,0 +1,294 @@
+package grpc
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/queue"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// kmPerMile converts kilometers to miles
+const kmPerMile = 1.609344
+
+// Footer placements of a CSV template
+const (
+	footerInline   = "inline"
+	footerSeparate = "separate"
+	footerNone     = "none"
+)
+
+// formatCSVSummary is the artifact format of a summary written by a CSV
+// template with a separate footer
+const formatCSVSummary = "csv_summary"
+
+// spreadsheetTimeLayout is a timestamp layout spreadsheets parse as a date
+// and time
+const spreadsheetTimeLayout = "2006-01-02 15:04:05"
+
+// csvDelimiters maps the accepted delimiters to the runes written
+var csvDelimiters = map[string]rune{",": ',', ";": ';', "\t": '\t', `\t`: '\t', "|": '|'}
+
+// mileColumns maps the metric columns renamed in miles to their new names
+var mileColumns = map[string]string{
+	"distance_from_home_km": "distance_from_home_mi",
+	"speed_kmh":             "speed_mph",
+}
+
+// csvCellKind says how a template formats a cell
+type csvCellKind int
+
+const (
+	cellText       csvCellKind = iota
+	cellTime                   // a timestamp
+	cellCoordinate             // latitude or longitude, always six decimals
+	cellNumber                 // a measurement whose decimals follow the template
+	cellDistance               // kilometers, converted to miles
+	cellSpeed                  // km/h, converted to mph
+)
+
+// csvCell is one value of a distance CSV row or summary before formatting
+type csvCell struct {
+	kind   csvCellKind
+	text   string
+	number float64
+	places int // default decimals of numeric cells
+	time   time.Time
+}
+
+// textCell returns a cell written as is
+func textCell(s string) csvCell {
+	return csvCell{kind: cellText, text: s}
+}
+
+// intCell returns an integer cell
+func intCell(n int) csvCell {
+	return textCell(strconv.Itoa(n))
+}
+
+// timeCell returns a timestamp cell
+func timeCell(t time.Time) csvCell {
+	return csvCell{kind: cellTime, time: t}
+}
+
+// coordinateCell returns a latitude or longitude cell
+func coordinateCell(v float64) csvCell {
+	return csvCell{kind: cellCoordinate, number: v, places: 6}
+}
+
+// numberCell returns a measurement cell with places decimals by default
+func numberCell(v float64, places int) csvCell {
+	return csvCell{kind: cellNumber, number: v, places: places}
+}
+
+// distanceCell returns a distance in kilometers
+func distanceCell(km float64, places int) csvCell {
+	return csvCell{kind: cellDistance, number: km, places: places}
+}
+
+// speedCell returns a speed in km/h
+func speedCell(kmh float64, places int) csvCell {
+	return csvCell{kind: cellSpeed, number: kmh, places: places}
+}
+
+// csvSummaryRow is one labelled summary value; distance and speed labels get
+// their unit appended, e.g. "Total Distance (km)"
+type csvSummaryRow struct {
+	label string
+	value csvCell
+}
+
+// csvTemplate is a validated CSV template for one report header
+type csvTemplate struct {
+	columns    []int    // indexes into the full row, in output order
+	header     []string // output header
+	miles      bool
+	precision  int            // decimals of measurements; -1 keeps each default
+	location   *time.Location // nil keeps each timestamp's zone
+	timeLayout string
+	delimiter  rune
+	footer     string
+}
+
+// csvTemplateFromProto converts a requested CSV template; nil keeps the
+// default layout
+func csvTemplateFromProto(t *distancev1.CsvTemplate) *queue.CSVTemplate {
+	if t == nil {
+		return nil
+	}
+	template := &queue.CSVTemplate{
+		Columns:         t.Columns,
+		Units:           t.Units,
+		Timezone:        t.Timezone,
+		TimestampFormat: t.TimestampFormat,
+		Delimiter:       t.Delimiter,
+		Footer:          t.Footer,
+	}
+	if t.Precision != nil {
+		precision := int(*t.Precision)
+		template.Precision = &precision
+	}
+	return template
+}
+
+// newCSVTemplate validates a requested template against the full header of
+// the report it shapes. A nil template keeps the default layout.
+func newCSVTemplate(t *queue.CSVTemplate, header []string) (*csvTemplate, error) {
+	if t == nil {
+		t = &queue.CSVTemplate{}
+	}
+	tmpl := &csvTemplate{precision: -1, timeLayout: time.RFC3339, delimiter: ',', footer: footerInline}
+
+	switch strings.ToLower(t.Units) {
+	case "", "km":
+	case "mi":
+		tmpl.miles = true
+	default:
+		return nil, fmt.Errorf("invalid CSV units %q: want km or mi", t.Units)
+	}
+
+	if t.Precision != nil {
+		if *t.Precision < 0 || *t.Precision > 9 {
+			return nil, fmt.Errorf("invalid CSV precision %d: want 0 to 9", *t.Precision)
+		}
+		tmpl.precision = *t.Precision
+	}
+
+	if t.Timezone != "" {
+		location, err := time.LoadLocation(t.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV timezone %q: %w", t.Timezone, err)
+		}
+		tmpl.location = location
+	}
+
+	switch strings.ToLower(t.TimestampFormat) {
+	case "", "rfc3339":
+	case "spreadsheet":
+		tmpl.timeLayout = spreadsheetTimeLayout
+	default:
+		return nil, fmt.Errorf("invalid CSV timestamp format %q: want rfc3339 or spreadsheet", t.TimestampFormat)
+	}
+
+	if t.Delimiter != "" {
+		delimiter, ok := csvDelimiters[t.Delimiter]
+		if !ok {
+			return nil, fmt.Errorf(`invalid CSV delimiter %q: want ",", ";", "\t" or "|"`, t.Delimiter)
+		}
+		tmpl.delimiter = delimiter
+	}
+
+	switch strings.ToLower(t.Footer) {
+	case "", footerInline:
+	case footerSeparate, footerNone:
+		tmpl.footer = strings.ToLower(t.Footer)
+	default:
+		return nil, fmt.Errorf("invalid CSV footer %q: want inline, separate or none", t.Footer)
+	}
+
+	columns := t.Columns
+	if len(columns) == 0 {
+		columns = header
+	}
+	for _, name := range columns {
+		i := csvColumnIndex(header, strings.TrimSpace(name))
+		if i < 0 {
+			return nil, fmt.Errorf("unknown CSV column %q: want any of %s", name, strings.Join(header, ", "))
+		}
+		if slices.Contains(tmpl.columns, i) {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		tmpl.columns = append(tmpl.columns, i)
+		tmpl.header = append(tmpl.header, tmpl.columnName(header[i]))
+	}
+	return tmpl, nil
+}
+
+// csvColumnIndex returns the index of a column by its name or its name in
+// miles, or -1
+func csvColumnIndex(header []string, name string) int {
+	for i, column := range header {
+		if column == name || (mileColumns[column] != "" && mileColumns[column] == name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// columnName returns a column's header in the template's units
+func (t *csvTemplate) columnName(name string) string {
+	if renamed, ok := mileColumns[name]; ok && t.miles {
+		return renamed
+	}
+	return name
+}
+
+// row formats the selected cells of a full row in output order
+func (t *csvTemplate) row(cells []csvCell) []string {
+	row := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		row[i] = t.format(cells[column])
+	}
+	return row
+}
+
+// summary formats summary rows as label and value pairs
+func (t *csvTemplate) summary(rows []csvSummaryRow) [][]string {
+	formatted := make([][]string, len(rows))
+	for i, r := range rows {
+		formatted[i] = []string{t.summaryLabel(r), t.format(r.value)}
+	}
+	return formatted
+}
+
+// footerRows returns the summary rows written after the data rows, which
+// are none unless the footer is inline
+func (t *csvTemplate) footerRows(rows []csvSummaryRow) [][]string {
+	if t.footer != footerInline {
+		return nil
+	}
+	return append([][]string{{}, {"Summary"}}, t.summary(rows)...)
+}
+
+// summaryLabel appends the unit of distances and speeds to a summary label
+func (t *csvTemplate) summaryLabel(r csvSummaryRow) string {
+	switch {
+	case r.value.kind == cellDistance && t.miles:
+		return r.label + " (mi)"
+	case r.value.kind == cellDistance:
+		return r.label + " (km)"
+	case r.value.kind == cellSpeed && t.miles:
+		return r.label + " (mph)"
+	case r.value.kind == cellSpeed:
+		return r.label + " (km/h)"
+	}
+	return r.label
+}
+
+// format writes a cell in the template's units, precision and time zone
+func (t *csvTemplate) format(c csvCell) string {
+	switch c.kind {
+	case cellTime:
+		at := c.time
+		if t.location != nil {
+			at = at.In(t.location)
+		}
+		return at.Format(t.timeLayout)
+	case cellCoordinate:
+		return strconv.FormatFloat(c.number, 'f', c.places, 64)
+	case cellNumber, cellDistance, cellSpeed:
+		v := c.number
+		if t.miles && c.kind != cellNumber {
+			v /= kmPerMile
+		}
+		places := c.places
+		if t.precision >= 0 {
+			places = t.precision
+		}
+		return strconv.FormatFloat(v, 'f', places, 64)
+	}
+	return c.text
+}
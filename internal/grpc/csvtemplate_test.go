@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+func TestNewCSVTemplate(t *testing.T) {
+	tmpl, err := newCSVTemplate(nil, distanceCSVHeader)
+	if err != nil {
+		t.Fatalf("newCSVTemplate failed: %v", err)
+	}
+	if strings.Join(tmpl.header, ",") != strings.Join(distanceCSVHeader, ",") || tmpl.delimiter != ',' || tmpl.footer != footerInline {
+		t.Errorf("expected the default layout, got %+v", tmpl)
+	}
+
+	tmpl, err = newCSVTemplate(&queue.CSVTemplate{
+		Columns: []string{"timestamp", "distance_from_home_mi", "speed_kmh"},
+		Units:   "mi",
+	}, distanceCSVHeader)
+	if err != nil {
+		t.Fatalf("newCSVTemplate failed: %v", err)
+	}
+	if got := strings.Join(tmpl.header, ","); got != "timestamp,distance_from_home_mi,speed_mph" {
+		t.Errorf("unexpected header %q", got)
+	}
+
+	precision := 12
+	invalid := []queue.CSVTemplate{
+		{Columns: []string{"heart_rate"}},
+		{Columns: []string{"timestamp", "timestamp"}},
+		{Units: "furlongs"},
+		{Precision: &precision},
+		{Timezone: "Mars/Olympus_Mons"},
+		{TimestampFormat: "unix"},
+		{Delimiter: "::"},
+		{Footer: "header"},
+	}
+	for _, tt := range invalid {
+		if _, err := newCSVTemplate(&tt, distanceCSVHeader); err == nil {
+			t.Errorf("expected error for %+v", tt)
+		}
+	}
+}
+
+func TestCSVTemplate_Format(t *testing.T) {
+	zero := 0
+	tmpl, err := newCSVTemplate(&queue.CSVTemplate{
+		Units:           "mi",
+		Precision:       &zero,
+		Timezone:        "America/New_York",
+		TimestampFormat: "spreadsheet",
+	}, distanceCSVHeader)
+	if err != nil {
+		t.Fatalf("newCSVTemplate failed: %v", err)
+	}
+
+	tests := []struct {
+		cell csvCell
+		want string
+	}{
+		{timeCell(time.Date(2026, 1, 24, 13, 5, 0, 0, time.UTC)), "2026-01-24 08:05:00"},
+		{coordinateCell(40.736097), "40.736097"},
+		{distanceCell(16.09344, 2), "10"},
+		{speedCell(80.4672, 2), "50"},
+		{numberCell(12.6, 1), "13"},
+		{intCell(7), "7"},
+		{textCell("walking"), "walking"},
+	}
+	for _, tt := range tests {
+		if got := tmpl.format(tt.cell); got != tt.want {
+			t.Errorf("%+v: expected %q, got %q", tt.cell, tt.want, got)
+		}
+	}
+
+	rows := tmpl.summary([]csvSummaryRow{
+		{"Total Distance", distanceCell(1.609344, 2)},
+		{"Max Speed", speedCell(1.609344, 2)},
+		{"Total Ascent (m)", numberCell(3, 1)},
+	})
+	for i, want := range []string{"Total Distance (mi)", "Max Speed (mph)", "Total Ascent (m)"} {
+		if rows[i][0] != want || rows[i][1] == "" {
+			t.Errorf("row %d: expected label %q, got %v", i, want, rows[i])
+		}
+	}
+}
+
+func TestProcessDistanceJob_CSVTemplate(t *testing.T) {
+	start := time.Date(2026, 1, 24, 13, 0, 0, 0, time.UTC)
+	server := newMemoryServer(t, database.NewMemoryStore(walkFromHome("pixel8", start, 20)...))
+
+	precision := 1
+	job := &queue.Job{ID: "job-1", Date: "2026-01-24", CSVTemplate: &queue.CSVTemplate{
+		Columns:   []string{"distance_from_home_km", "timestamp"},
+		Units:     "mi",
+		Precision: &precision,
+		Timezone:  "America/New_York",
+		Delimiter: ";",
+		Footer:    footerSeparate,
+	}}
+	result, err := server.processDistanceJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+	if len(result.Artifacts) != 2 || result.Artifacts[1].Format != formatCSVSummary || result.Artifacts[1].Key != "distance_20260124_job-1_summary.csv" {
+		t.Fatalf("expected the CSV and its summary, got %+v", result.Artifacts)
+	}
+
+	content, err := readArtifact(t, server, result.CSVPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 21 {
+		t.Fatalf("expected a header and 20 rows without a footer, got %d lines", len(lines))
+	}
+	if lines[0] != "distance_from_home_mi;timestamp" || lines[1] != "0.0;2026-01-24T08:00:00-05:00" {
+		t.Errorf("unexpected rows %q, %q", lines[0], lines[1])
+	}
+
+	summary, err := readArtifact(t, server, result.Artifacts[1].Key)
+	if err != nil {
+		t.Fatalf("failed to read summary: %v", err)
+	}
+	if !strings.HasPrefix(string(summary), "metric;value\nTotal Distance (mi);") || !strings.Contains(string(summary), "Total Locations;20\n") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	// Without a footer only the data rows are written
+	job = &queue.Job{ID: "job-2", Date: "2026-01-24", CSVTemplate: &queue.CSVTemplate{Footer: footerNone}}
+	result, err = server.processDistanceJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+	content, _ = readArtifact(t, server, result.CSVPath)
+	if len(result.Artifacts) != 1 || strings.Contains(string(content), "Summary") {
+		t.Errorf("expected no summary, got %+v", result.Artifacts)
+	}
+}
+
+func TestCalculateDistanceFromHome_InvalidCSVTemplate(t *testing.T) {
+	server := newMemoryServer(t, database.NewMemoryStore())
+
+	_, err := server.CalculateDistanceFromHome(context.Background(), &distancev1.CalculateDistanceRequest{
+		Date:        "2026-01-24",
+		CsvTemplate: &distancev1.CsvTemplate{Columns: []string{"heart_rate"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "heart_rate") {
+		t.Errorf("expected an unknown column error, got %v", err)
+	}
+}
@@ -0,0 +1,337 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// errGarminUnsupported is returned by the Garmin RPCs when the configured
+// location store has no Garmin activities
+var errGarminUnsupported = errors.New("garmin activities are not supported by the configured location store")
+
+// activityCSVHeader is the column header of an activity CSV report
+var activityCSVHeader = []string{
+	"timestamp", "activity_id", "latitude", "longitude",
+	"distance_from_home_km", "distance_from_start_km",
+	"altitude_m", "smoothed_altitude_m", "cumulative_ascent_m",
+	"speed_kmh", "heart_rate", "cadence", "temperature_c",
+}
+
+// ListGarminActivities returns activities that started within a date range
+func (s *Server) ListGarminActivities(ctx context.Context, req *distancev1.ListGarminActivitiesRequest) (*distancev1.ListGarminActivitiesResponse, error) {
+	if s.garmin == nil {
+		return nil, errGarminUnsupported
+	}
+
+	if err := validateDateRange(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	activities, err := s.garmin.GetGarminActivitiesByDateRange(ctx, req.StartDate, req.EndDate, req.Sport)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch Garmin activities from database")
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	resp := &distancev1.ListGarminActivitiesResponse{}
+	for _, activity := range activities {
+		resp.Activities = append(resp.Activities, garminActivityToProto(activity))
+	}
+
+	return resp, nil
+}
+
+// GetGarminActivity returns an activity with metrics computed from its track
+func (s *Server) GetGarminActivity(ctx context.Context, req *distancev1.GetGarminActivityRequest) (*distancev1.GetGarminActivityResponse, error) {
+	if s.garmin == nil {
+		return nil, errGarminUnsupported
+	}
+
+	if req.ActivityId <= 0 {
+		return nil, fmt.Errorf("activity_id is required")
+	}
+
+	activity, err := s.garmin.GetGarminActivity(ctx, req.ActivityId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity: %w", err)
+	}
+
+	metrics, _, err := s.analyzeActivity(ctx, req.ActivityId, nil)
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	return &distancev1.GetGarminActivityResponse{
+		Activity: garminActivityToProto(*activity),
+		Metrics:  activityMetricsToProto(metrics),
+	}, nil
+}
+
+// CalculateActivityDistance initiates an async activity metrics and CSV job
+func (s *Server) CalculateActivityDistance(ctx context.Context, req *distancev1.CalculateActivityDistanceRequest) (*distancev1.CalculateDistanceResponse, error) {
+	log.Info().
+		Int64("activity_id", req.ActivityId).
+		Msg("Received activity distance calculation request")
+
+	if s.garmin == nil {
+		return nil, errGarminUnsupported
+	}
+
+	if req.ActivityId <= 0 {
+		return nil, fmt.Errorf("activity_id is required")
+	}
+
+	if err := s.checkAvailable(); err != nil {
+		return nil, err
+	}
+
+	// Look the activity up first so unknown IDs fail fast
+	activity, err := s.garmin.GetGarminActivity(ctx, req.ActivityId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity: %w", err)
+	}
+
+	jobID, err := s.queue.EnqueueActivity(activity.ActivityID, activity.StartTime.UTC().Format(time.DateOnly))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to enqueue job")
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return &distancev1.CalculateDistanceResponse{
+		JobId:    jobID,
+		Status:   "queued",
+		QueuedAt: timestamppb.Now(),
+	}, nil
+}
+
+// processActivityJob computes activity metrics and writes its CSV report
+func (s *Server) processActivityJob(ctx context.Context, job *queue.Job) (*queue.JobResult, error) {
+	log.Info().
+		Str("job_id", job.ID).
+		Int64("activity_id", job.ActivityID).
+		Msg("Processing activity distance job")
+
+	if s.garmin == nil {
+		return nil, errGarminUnsupported
+	}
+
+	report, err := s.createCSVReport(ctx, artifactName(fmt.Sprintf("distance_activity_%d", job.ActivityID), job)+".csv", activityCSVHeader)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate CSV file")
+		return nil, fmt.Errorf("CSV generation failed: %w", err)
+	}
+
+	var writeErr error
+	metrics, summary, err := s.analyzeActivity(ctx, job.ActivityID, func(p database.GarminTrackPoint, point calculator.AnalyzedPoint) error {
+		writeErr = report.writeRow(activityRow(p, point))
+		return writeErr
+	})
+	if writeErr != nil {
+		report.abort()
+		log.Error().Err(writeErr).Msg("Failed to generate CSV file")
+		return nil, fmt.Errorf("CSV generation failed: %w", writeErr)
+	}
+	if err != nil {
+		report.abort()
+		log.Error().Err(err).Msg("Failed to fetch track points from database")
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	if metrics.PointCount == 0 {
+		report.abort()
+		return nil, fmt.Errorf("no track points found for activity %d", job.ActivityID)
+	}
+
+	info, err := report.finish(activityFooter(metrics))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate CSV file")
+		return nil, fmt.Errorf("CSV generation failed: %w", err)
+	}
+
+	log.Info().
+		Int64("activity_id", job.ActivityID).
+		Float64("path_distance_km", metrics.PathDistanceKM).
+		Float64("max_distance_km", metrics.MaxDistanceFromHomeKM).
+		Int("points", metrics.PointCount).
+		Msg("Activity metrics calculated")
+
+	return &queue.JobResult{
+		CSVPath:         info.Key,
+		Artifacts:       []queue.Artifact{newArtifact(formatCSV, info)},
+		TotalDistanceKM: summary.Metrics.TotalDistanceKM,
+		MaxDistanceKM:   summary.Metrics.MaxDistanceKM,
+		MinDistanceKM:   summary.Metrics.MinDistanceKM,
+		TotalLocations:  summary.Metrics.TotalLocations,
+		MaxSpeedKMH:     metrics.MaxSpeedKMH,
+		Elevation:       metrics.Elevation,
+		Activity:        metrics,
+	}, nil
+}
+
+// analyzeActivity streams an activity's track points through the track and
+// sensor analyzers. If onPoint is non-nil it is called with each track point
+// and its analyzed values, in order.
+func (s *Server) analyzeActivity(
+	ctx context.Context,
+	activityID int64,
+	onPoint func(database.GarminTrackPoint, calculator.AnalyzedPoint) error,
+) (*queue.ActivityMetrics, calculator.TrackSummary, error) {
+	track := calculator.NewTrackAnalyzer(s.cfg.HomeLatitude, s.cfg.HomeLongitude)
+	sensors := calculator.NewActivityAnalyzer()
+	points := &pointQueue[database.GarminTrackPoint]{
+		write: func(p database.GarminTrackPoint, point calculator.AnalyzedPoint) error {
+			if onPoint == nil {
+				return nil
+			}
+			return onPoint(p, point)
+		},
+	}
+
+	err := s.garmin.StreamGarminTrackPoints(ctx, activityID, func(p database.GarminTrackPoint) error {
+		sample := calculator.ActivitySample{
+			Location: calculator.Location{
+				Latitude:  p.Latitude,
+				Longitude: p.Longitude,
+				Altitude:  p.Altitude,
+				Timestamp: p.Timestamp,
+			},
+			SpeedKMH:  p.SpeedKMH,
+			HeartRate: p.HeartRate,
+			Cadence:   p.Cadence,
+		}
+		analyzed, err := track.Push(sample.Location)
+		if err != nil {
+			return err
+		}
+		sensors.Add(sample)
+		points.push(p)
+		return points.emit(analyzed)
+	})
+	if err == nil {
+		err = points.emit(track.Flush())
+	}
+	if err != nil {
+		return nil, calculator.TrackSummary{}, err
+	}
+
+	summary := track.Summary()
+	stats := sensors.Stats()
+	metrics := &queue.ActivityMetrics{
+		ActivityID:            activityID,
+		PointCount:            stats.Points,
+		PathDistanceKM:        stats.PathDistanceKM,
+		DurationSeconds:       int64(stats.Duration.Seconds()),
+		AvgSpeedKMH:           stats.AvgSpeedKMH,
+		MaxSpeedKMH:           stats.MaxSpeedKMH,
+		AvgHeartRate:          stats.AvgHeartRate,
+		MaxHeartRate:          stats.MaxHeartRate,
+		AvgCadence:            stats.AvgCadence,
+		MaxCadence:            stats.MaxCadence,
+		MaxDistanceFromHomeKM: summary.Metrics.MaxDistanceKM,
+		MinDistanceFromHomeKM: summary.Metrics.MinDistanceKM,
+		AvgDistanceFromHomeKM: summary.Metrics.AvgDistanceKM,
+		Elevation:             elevationStats(summary.Elevation),
+	}
+
+	return metrics, summary, nil
+}
+
+// activityRow formats the data row for one track point and its analyzed values
+func activityRow(p database.GarminTrackPoint, point calculator.AnalyzedPoint) []string {
+	return []string{
+		p.Timestamp.UTC().Format(time.RFC3339),
+		fmt.Sprintf("%d", p.ActivityID),
+		fmt.Sprintf("%.6f", p.Latitude),
+		fmt.Sprintf("%.6f", p.Longitude),
+		fmt.Sprintf("%.2f", point.DistanceFromHomeKM),
+		fmt.Sprintf("%.3f", p.DistanceFromStartKM),
+		fmt.Sprintf("%.1f", p.Altitude),
+		fmt.Sprintf("%.1f", point.SmoothedAltitudeM),
+		fmt.Sprintf("%.1f", point.CumulativeAscentM),
+		fmt.Sprintf("%.2f", p.SpeedKMH),
+		fmt.Sprintf("%d", p.HeartRate),
+		fmt.Sprintf("%d", p.Cadence),
+		fmt.Sprintf("%.1f", p.TemperatureC),
+	}
+}
+
+// activityFooter formats the summary rows of an activity report
+func activityFooter(m *queue.ActivityMetrics) [][]string {
+	return [][]string{
+		{},
+		{"Summary"},
+		{"Activity ID", fmt.Sprintf("%d", m.ActivityID)},
+		{"Total Points", fmt.Sprintf("%d", m.PointCount)},
+		{"Path Distance (km)", fmt.Sprintf("%.2f", m.PathDistanceKM)},
+		{"Duration (min)", fmt.Sprintf("%.1f", float64(m.DurationSeconds)/60)},
+		{"Average Speed (km/h)", fmt.Sprintf("%.2f", m.AvgSpeedKMH)},
+		{"Max Speed (km/h)", fmt.Sprintf("%.2f", m.MaxSpeedKMH)},
+		{"Average Heart Rate (bpm)", fmt.Sprintf("%.0f", m.AvgHeartRate)},
+		{"Max Heart Rate (bpm)", fmt.Sprintf("%d", m.MaxHeartRate)},
+		{"Average Cadence", fmt.Sprintf("%.0f", m.AvgCadence)},
+		{"Max Cadence", fmt.Sprintf("%d", m.MaxCadence)},
+		{"Max Distance (km)", fmt.Sprintf("%.2f", m.MaxDistanceFromHomeKM)},
+		{"Min Distance (km)", fmt.Sprintf("%.2f", m.MinDistanceFromHomeKM)},
+		{"Average Distance (km)", fmt.Sprintf("%.2f", m.AvgDistanceFromHomeKM)},
+		{"Total Ascent (m)", fmt.Sprintf("%.1f", m.Elevation.AscentM)},
+		{"Total Descent (m)", fmt.Sprintf("%.1f", m.Elevation.DescentM)},
+	}
+}
+
+// garminActivityToProto converts a Garmin activity to its protobuf form
+func garminActivityToProto(a database.GarminActivity) *distancev1.GarminActivity {
+	out := &distancev1.GarminActivity{
+		ActivityId:      a.ActivityID,
+		Name:            a.Name,
+		Sport:           a.Sport,
+		SubSport:        a.SubSport,
+		StartTime:       timestamppb.New(a.StartTime),
+		DurationSeconds: a.DurationSeconds,
+		DistanceKm:      a.DistanceKM,
+		Calories:        int32(a.Calories),     // #nosec G115 -- bounded by a single workout
+		AvgHeartRate:    int32(a.AvgHeartRate), // #nosec G115 -- heart rate fits in int32
+		MaxHeartRate:    int32(a.MaxHeartRate), // #nosec G115 -- heart rate fits in int32
+		AvgCadence:      int32(a.AvgCadence),   // #nosec G115 -- cadence fits in int32
+		MaxCadence:      int32(a.MaxCadence),   // #nosec G115 -- cadence fits in int32
+		AvgSpeedKmh:     a.AvgSpeedKMH,
+		MaxSpeedKmh:     a.MaxSpeedKMH,
+		TotalAscentM:    a.TotalAscentM,
+		TotalDescentM:   a.TotalDescentM,
+	}
+
+	if !a.EndTime.IsZero() {
+		out.EndTime = timestamppb.New(a.EndTime)
+	}
+
+	return out
+}
+
+// activityMetricsToProto converts computed activity metrics to their protobuf form
+func activityMetricsToProto(m *queue.ActivityMetrics) *distancev1.ActivityMetrics {
+	return &distancev1.ActivityMetrics{
+		ActivityId:            m.ActivityID,
+		PointCount:            int32(m.PointCount), // #nosec G115 -- bounded by points per activity
+		PathDistanceKm:        m.PathDistanceKM,
+		DurationSeconds:       m.DurationSeconds,
+		AvgSpeedKmh:           m.AvgSpeedKMH,
+		MaxSpeedKmh:           m.MaxSpeedKMH,
+		AvgHeartRate:          m.AvgHeartRate,
+		MaxHeartRate:          int32(m.MaxHeartRate), // #nosec G115 -- heart rate fits in int32
+		AvgCadence:            m.AvgCadence,
+		MaxCadence:            int32(m.MaxCadence), // #nosec G115 -- cadence fits in int32
+		MaxDistanceFromHomeKm: m.MaxDistanceFromHomeKM,
+		MinDistanceFromHomeKm: m.MinDistanceFromHomeKM,
+		AvgDistanceFromHomeKm: m.AvgDistanceFromHomeKM,
+		Elevation:             elevationStatsToProto(m.Elevation),
+	}
+}
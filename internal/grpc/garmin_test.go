@@ -0,0 +1,231 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// rideFromHome returns one-second track points heading east from home
+func rideFromHome(activityID int64, start time.Time, n int) []database.GarminTrackPoint {
+	points := make([]database.GarminTrackPoint, n)
+	for i := range points {
+		points[i] = database.GarminTrackPoint{
+			ID:         int64(i + 1),
+			ActivityID: activityID,
+			Latitude:   40.736097,
+			Longitude:  -74.039373 + float64(i)*0.0001,
+			Altitude:   5,
+			Timestamp:  start.Add(time.Duration(i) * time.Second),
+			SpeedKMH:   30,
+			HeartRate:  120 + i%10,
+			Cadence:    85,
+		}
+	}
+	return points
+}
+
+func garminFixture() *database.MemoryStore {
+	start := time.Date(2026, 1, 24, 7, 0, 0, 0, time.UTC)
+	store := database.NewMemoryStore()
+	store.AddGarminActivity(database.GarminActivity{
+		ActivityID: 101,
+		Name:       "Morning Ride",
+		Sport:      "cycling",
+		StartTime:  start,
+		EndTime:    start.Add(2 * time.Minute),
+	}, rideFromHome(101, start, 120)...)
+	store.AddGarminActivity(database.GarminActivity{
+		ActivityID: 102,
+		Name:       "Evening Run",
+		Sport:      "running",
+		StartTime:  start.Add(12 * time.Hour),
+	})
+	return store
+}
+
+func TestListGarminActivities(t *testing.T) {
+	server := newMemoryServer(t, garminFixture())
+
+	resp, err := server.ListGarminActivities(context.Background(), &distancev1.ListGarminActivitiesRequest{
+		StartDate: "2026-01-24",
+		EndDate:   "2026-01-24",
+		Sport:     "cycling",
+	})
+	if err != nil {
+		t.Fatalf("ListGarminActivities failed: %v", err)
+	}
+	if len(resp.Activities) != 1 || resp.Activities[0].ActivityId != 101 {
+		t.Errorf("expected only activity 101, got %v", resp.Activities)
+	}
+}
+
+func TestGetGarminActivity(t *testing.T) {
+	server := newMemoryServer(t, garminFixture())
+	ctx := context.Background()
+
+	resp, err := server.GetGarminActivity(ctx, &distancev1.GetGarminActivityRequest{ActivityId: 101})
+	if err != nil {
+		t.Fatalf("GetGarminActivity failed: %v", err)
+	}
+
+	metrics := resp.Metrics
+	if metrics.PointCount != 120 {
+		t.Errorf("expected 120 points, got %d", metrics.PointCount)
+	}
+	if metrics.PathDistanceKm < 0.9 || metrics.PathDistanceKm > 1.1 {
+		t.Errorf("expected ~1 km path, got %.3f", metrics.PathDistanceKm)
+	}
+	if metrics.MaxHeartRate != 129 || metrics.MaxCadence != 85 || metrics.MaxSpeedKmh != 30 {
+		t.Errorf("unexpected sensor metrics %+v", metrics)
+	}
+	if metrics.MinDistanceFromHomeKm != 0 {
+		t.Errorf("expected ride to start at home, got %.3f", metrics.MinDistanceFromHomeKm)
+	}
+
+	_, err = server.GetGarminActivity(ctx, &distancev1.GetGarminActivityRequest{ActivityId: 999})
+	if !errors.Is(err, database.ErrActivityNotFound) {
+		t.Errorf("expected ErrActivityNotFound, got %v", err)
+	}
+}
+
+func TestProcessActivityJob(t *testing.T) {
+	server := newMemoryServer(t, garminFixture())
+
+	result, err := server.processActivityJob(context.Background(), &queue.Job{ID: "job-1", ActivityID: 101})
+	if err != nil {
+		t.Fatalf("processActivityJob failed: %v", err)
+	}
+	if filepath.Base(result.CSVPath) != "distance_activity_101_job-1.csv" {
+		t.Errorf("unexpected CSV path %s", result.CSVPath)
+	}
+	if result.Activity == nil || result.Activity.PointCount != 120 {
+		t.Fatalf("expected activity metrics for 120 points, got %+v", result.Activity)
+	}
+
+	content, err := readArtifact(t, server, result.CSVPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if !strings.Contains(lines[0], "heart_rate,cadence") {
+		t.Errorf("expected sensor columns in header %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "2026-01-24T07:00:00Z,101,") {
+		t.Errorf("unexpected first row %q", lines[1])
+	}
+
+	// An activity without track points fails and leaves no file behind
+	if _, err := server.processActivityJob(context.Background(), &queue.Job{ID: "job-2", ActivityID: 102}); err == nil {
+		t.Error("expected error for activity without track points, got nil")
+	}
+	if _, err := os.Stat(filepath.Join(server.cfg.CSVOutputPath, "distance_activity_102_job-2.csv")); !os.IsNotExist(err) {
+		t.Error("expected partial CSV to be removed")
+	}
+}
+
+func TestGarmin_UnsupportedStore(t *testing.T) {
+	server := newMemoryServer(t, locationsOnly{database.NewMemoryStore()})
+
+	_, err := server.CalculateActivityDistance(context.Background(), &distancev1.CalculateActivityDistanceRequest{ActivityId: 1})
+	if !errors.Is(err, errGarminUnsupported) {
+		t.Errorf("expected errGarminUnsupported, got %v", err)
+	}
+}
+
+func TestProcessDistanceJob_Sources(t *testing.T) {
+	store := garminFixture()
+	start := time.Date(2026, 1, 24, 7, 0, 0, 0, time.UTC)
+	store.Add(
+		// Duplicates the first ride point and is dropped from merged reports
+		database.Location{ID: 1, DeviceID: "pixel8", Latitude: 40.736097, Longitude: -74.039373, CreatedAt: start.Add(time.Second)},
+		database.Location{ID: 2, DeviceID: "pixel8", Latitude: 40.736097, Longitude: -74.039373, CreatedAt: start.Add(time.Hour)},
+	)
+	server := newMemoryServer(t, store)
+	ctx := context.Background()
+
+	tests := []struct {
+		source    string
+		jobID     string
+		csvName   string
+		locations int
+	}{
+		{"", "job-1", "distance_20260124_job-1.csv", 2},
+		{"garmin", "job-2", "distance_20260124_garmin_job-2.csv", 120},
+		{"all", "job-3", "distance_20260124_all_job-3.csv", 121},
+	}
+
+	for _, tt := range tests {
+		result, err := server.processDistanceJob(ctx, &queue.Job{ID: tt.jobID, Date: "2026-01-24", Source: tt.source})
+		if err != nil {
+			t.Fatalf("source %q: processDistanceJob failed: %v", tt.source, err)
+		}
+		if filepath.Base(result.CSVPath) != tt.csvName {
+			t.Errorf("source %q: unexpected CSV path %s", tt.source, result.CSVPath)
+		}
+		if result.TotalLocations != tt.locations {
+			t.Errorf("source %q: expected %d locations, got %d", tt.source, tt.locations, result.TotalLocations)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(server.cfg.CSVOutputPath, "distance_20260124_all_job-3.csv"))
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if !strings.HasSuffix(lines[0], ",source,activity_id,heart_rate") {
+		t.Errorf("expected source columns in header %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ",garmin,101,120") || !strings.Contains(lines[121], ",owntracks,,") {
+		t.Errorf("unexpected rows %q / %q", lines[1], lines[121])
+	}
+}
+
+func TestProcessDistanceJob_SourcesPerDevice(t *testing.T) {
+	store := garminFixture()
+	start := time.Date(2026, 1, 24, 7, 0, 0, 0, time.UTC)
+	// A phone 20 km away reports during the ride
+	for i := 0; i < 4; i++ {
+		at := start.Add(time.Duration(i*30+15) * time.Second)
+		store.Add(database.Location{ID: int64(i + 1), DeviceID: "pixel8", Latitude: 40.916097, Longitude: -74.039373, Timestamp: at.Unix(), CreatedAt: at})
+	}
+	server := newMemoryServer(t, store)
+
+	result, err := server.processDistanceJob(context.Background(), &queue.Job{ID: "job-1", Date: "2026-01-24", Source: "all"})
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+	if result.TotalLocations != 124 {
+		t.Errorf("expected 124 points, got %d", result.TotalLocations)
+	}
+	// The ride is ~30 km/h; jumping between the ride and the phone would not be
+	if result.MaxSpeedKMH > 40 {
+		t.Errorf("expected the ride's max speed, got %.1f km/h", result.MaxSpeedKMH)
+	}
+	if len(result.TripElevations) != 1 {
+		t.Errorf("expected only the ride as a trip, got %+v", result.TripElevations)
+	}
+}
+
+func TestCalculateDistanceFromHome_Source(t *testing.T) {
+	ctx := context.Background()
+
+	server := newMemoryServer(t, garminFixture())
+	if _, err := server.CalculateDistanceFromHome(ctx, &distancev1.CalculateDistanceRequest{Date: "2026-01-24", Source: "strava"}); err == nil {
+		t.Error("expected error for unknown source, got nil")
+	}
+
+	server = newMemoryServer(t, locationsOnly{database.NewMemoryStore()})
+	_, err := server.CalculateDistanceFromHome(ctx, &distancev1.CalculateDistanceRequest{Date: "2026-01-24", Source: "all"})
+	if !errors.Is(err, errUnifiedUnsupported) {
+		t.Errorf("expected errUnifiedUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stuartshay/otel-worker/internal/storage"
+)
+
+// geoJSONFeatureCollection is an RFC 7946 FeatureCollection; name is a
+// foreign member naming the file
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Name     string           `json:"name"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// geoJSONFeature is a Feature whose properties always include its kind
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// geoJSONGeometry is a Point or LineString geometry
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// geoJSONReport writes a GeoJSON FeatureCollection of the home, trip and
+// stay features of a track
+type geoJSONReport struct {
+	key   string
+	file  storage.Writer
+	title string
+}
+
+// createGeoJSONReport creates the artifact filename; title names
+// the collection
+func (s *Server) createGeoJSONReport(ctx context.Context, filename, title string) (*geoJSONReport, error) {
+	file, err := s.artifacts.Create(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GeoJSON file: %w", err)
+	}
+
+	return &geoJSONReport{key: filename, file: file, title: title}, nil
+}
+
+// finish writes the features and closes the file
+func (r *geoJSONReport) finish(features []mapFeature) (storage.Info, error) {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection", Name: r.title, Features: []geoJSONFeature{}}
+	for _, f := range features {
+		properties := map[string]interface{}{"kind": f.kind, "name": f.name}
+		for _, p := range f.properties {
+			properties[p.key] = p.value
+		}
+
+		geometry := geoJSONGeometry{Type: "Point", Coordinates: geoJSONPosition(f.coordinates[0].Longitude, f.coordinates[0].Latitude, f.coordinates[0].Altitude)}
+		if f.kind == featureTrip {
+			line := make([][]float64, len(f.coordinates))
+			for i, loc := range f.coordinates {
+				line[i] = geoJSONPosition(loc.Longitude, loc.Latitude, loc.Altitude)
+			}
+			geometry = geoJSONGeometry{Type: "LineString", Coordinates: line}
+		}
+
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geometry,
+			Properties: properties,
+		})
+	}
+
+	w := bufio.NewWriter(r.file)
+	if err := json.NewEncoder(w).Encode(collection); err != nil {
+		r.abort()
+		return storage.Info{}, fmt.Errorf("failed to write GeoJSON file: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		r.abort()
+		return storage.Info{}, fmt.Errorf("failed to write GeoJSON file: %w", err)
+	}
+	info, err := r.file.Commit()
+	if err != nil {
+		r.file.Abort()
+		return storage.Info{}, fmt.Errorf("failed to store GeoJSON file: %w", err)
+	}
+
+	log.Info().Str("geojson_key", r.key).Str("sha256", info.SHA256).Int("features", len(features)).Msg("GeoJSON file generated successfully")
+
+	return info, nil
+}
+
+// abort discards a partially written report
+func (r *geoJSONReport) abort() {
+	r.file.Abort()
+}
+
+// geoJSONPosition returns a [longitude, latitude] position with coordinates
+// rounded to six decimals, adding the altitude when one was reported
+func geoJSONPosition(lon, lat, alt float64) []float64 {
+	position := []float64{math.Round(lon*1e6) / 1e6, math.Round(lat*1e6) / 1e6}
+	if alt != 0 {
+		position = append(position, math.Round(alt*10)/10)
+	}
+	return position
+}
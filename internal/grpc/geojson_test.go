@@ -0,0 +1,216 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+)
+
+// tripBetweenStays returns a device that waits at home for a quarter of an
+// hour, walks away for five minutes and then waits again
+func tripBetweenStays(deviceID string, start time.Time) []database.Location {
+	locations := walkFromHome(deviceID, start, 32)
+	for i := range locations {
+		step := min(max(i-11, 0), 5)
+		locations[i].Latitude = 40.736097 + float64(step)*0.001
+		locations[i].Altitude = 10 + float64(step)
+	}
+	return locations
+}
+
+// geoJSONFile is the subset of a FeatureCollection the tests inspect
+type geoJSONFile struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Features []struct {
+		Geometry struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	} `json:"features"`
+}
+
+// kmlFile is the subset of a KML document the tests inspect
+type kmlFile struct {
+	Name       string `xml:"Document>name"`
+	Placemarks []struct {
+		Name       string   `xml:"name"`
+		Begin      string   `xml:"TimeSpan>begin"`
+		Data       []string `xml:"ExtendedData>Data>value"`
+		Point      string   `xml:"Point>coordinates"`
+		LineString string   `xml:"LineString>coordinates"`
+	} `xml:"Document>Placemark"`
+}
+
+func TestProcessDistanceJob_MapFormats(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	server := newMemoryServer(t, database.NewMemoryStore(tripBetweenStays("pixel8", start)...))
+
+	job := &queue.Job{ID: "job-1", Date: "2026-01-24", DeviceID: "pixel8", OutputFormats: []string{formatGeoJSON, formatCSV, formatKML}}
+	result, err := server.processDistanceJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+
+	if len(result.Artifacts) != 3 {
+		t.Fatalf("expected 3 artifacts, got %+v", result.Artifacts)
+	}
+	for i, want := range []string{"distance_20260124_pixel8_job-1.geojson", "distance_20260124_pixel8_job-1.csv", "distance_20260124_pixel8_job-1.kml"} {
+		if got := result.Artifacts[i].Key; got != want || result.Artifacts[i].Format != job.OutputFormats[i] {
+			t.Errorf("artifact %d: expected %s, got %+v", i, want, result.Artifacts[i])
+		}
+	}
+	if result.CSVPath != result.Artifacts[1].Key || result.GPXPath != "" {
+		t.Errorf("unexpected CSV path %q and GPX path %q", result.CSVPath, result.GPXPath)
+	}
+
+	t.Run("geojson", func(t *testing.T) {
+		content, err := readArtifact(t, server, result.Artifacts[0].Key)
+		if err != nil {
+			t.Fatalf("failed to read GeoJSON: %v", err)
+		}
+		var doc geoJSONFile
+		if err := json.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("invalid GeoJSON: %v\n%s", err, content)
+		}
+		if doc.Type != "FeatureCollection" || doc.Name != "Distance from home 2026-01-24 pixel8" {
+			t.Errorf("unexpected collection %q named %q", doc.Type, doc.Name)
+		}
+
+		kinds := make(map[string]int)
+		for _, f := range doc.Features {
+			kinds[f.Properties["kind"].(string)]++
+		}
+		if kinds[featureHome] != 1 || kinds[featureTrip] != 1 || kinds[featureStay] != 2 {
+			t.Fatalf("expected home, one trip and two stays, got %v", kinds)
+		}
+
+		home := doc.Features[0]
+		if home.Geometry.Type != "Point" || home.Properties["trip_count"] != 1.0 || home.Properties["total_locations"] != 32.0 {
+			t.Errorf("unexpected home feature %+v", home)
+		}
+		if string(home.Geometry.Coordinates) != "[-74.039373,40.736097]" {
+			t.Errorf("expected home at [lon, lat], got %s", home.Geometry.Coordinates)
+		}
+
+		trip := doc.Features[1]
+		var line [][]float64
+		if err := json.Unmarshal(trip.Geometry.Coordinates, &line); err != nil {
+			t.Fatalf("invalid trip coordinates: %v", err)
+		}
+		if trip.Geometry.Type != "LineString" || len(line) < 2 || len(line[0]) != 3 {
+			t.Errorf("expected a trip line with altitudes, got %s %v", trip.Geometry.Type, line)
+		}
+		if trip.Properties["distance_km"].(float64) <= 0 || trip.Properties["max_distance_from_home_km"].(float64) < 0.5 {
+			t.Errorf("unexpected trip metrics %v", trip.Properties)
+		}
+
+		for _, stay := range doc.Features[2:] {
+			if stay.Geometry.Type != "Point" || stay.Properties["duration_seconds"].(float64) < 600 {
+				t.Errorf("unexpected stay %+v", stay)
+			}
+		}
+	})
+
+	t.Run("kml", func(t *testing.T) {
+		content, err := readArtifact(t, server, result.Artifacts[2].Key)
+		if err != nil {
+			t.Fatalf("failed to read KML: %v", err)
+		}
+		var doc kmlFile
+		if err := xml.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("invalid KML: %v\n%s", err, content)
+		}
+		if doc.Name != "Distance from home 2026-01-24 pixel8" || len(doc.Placemarks) != 4 {
+			t.Fatalf("expected 4 placemarks, got %d in %q", len(doc.Placemarks), doc.Name)
+		}
+
+		home, trip := doc.Placemarks[0], doc.Placemarks[1]
+		if home.Point != "-74.039373,40.736097,0.0" || home.Begin != "" || home.Data[0] != featureHome {
+			t.Errorf("unexpected home placemark %+v", home)
+		}
+		if trip.Name != "Trip 1" || trip.LineString == "" || trip.Begin == "" {
+			t.Errorf("unexpected trip placemark %+v", trip)
+		}
+	})
+}
+
+func TestProcessDistanceJob_MapFormatsPerDevice(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	pixel := tripBetweenStays("pixel8", start)
+	iphone := tripBetweenStays("iphone", start.Add(30*time.Second))
+	for i := range iphone {
+		iphone[i].ID += int64(len(pixel))
+		iphone[i].Latitude += 0.18
+		iphone[i].Altitude += 100
+	}
+
+	single := newMemoryServer(t, database.NewMemoryStore(pixel...))
+	alone, err := single.processDistanceJob(context.Background(), &queue.Job{ID: "job-1", Date: "2026-01-24"})
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+
+	server := newMemoryServer(t, database.NewMemoryStore(append(pixel, iphone...)...))
+	job := &queue.Job{ID: "job-2", Date: "2026-01-24", OutputFormats: []string{formatGeoJSON}}
+	result, err := server.processDistanceJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+
+	// Both devices make the same walk, so the day is exactly twice one device
+	if len(result.TripElevations) != 2 || math.Abs(result.Elevation.AscentM-2*alone.Elevation.AscentM) > 1e-9 || math.Abs(result.Elevation.DescentM-2*alone.Elevation.DescentM) > 1e-9 {
+		t.Errorf("expected twice %+v, got %+v", alone.Elevation, result.Elevation)
+	}
+	for _, trip := range result.TripElevations {
+		want := alone.TripElevations[0]
+		if math.Abs(trip.Elevation.AscentM-want.Elevation.AscentM) > 1e-9 || math.Abs(trip.DistanceKM-want.DistanceKM) > 1e-6 || len(trip.Profile) != len(want.Profile) {
+			t.Errorf("expected each trip to match the single device, got %+v", trip)
+		}
+	}
+
+	content, err := readArtifact(t, server, result.Artifacts[0].Key)
+	if err != nil {
+		t.Fatalf("failed to read GeoJSON: %v", err)
+	}
+	var doc geoJSONFile
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("invalid GeoJSON: %v", err)
+	}
+	kinds := make(map[string]int)
+	for _, f := range doc.Features {
+		kinds[f.Properties["kind"].(string)]++
+		if f.Properties["kind"] != featureTrip {
+			continue
+		}
+		// A trip line stays on one device's side of the 0.18° gap
+		var line [][]float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &line); err != nil {
+			t.Fatalf("invalid trip coordinates: %v", err)
+		}
+		north := line[0][1] > 40.8
+		for _, c := range line {
+			if (c[1] > 40.8) != north {
+				t.Errorf("trip line mixes devices: %v", line)
+				break
+			}
+		}
+	}
+	if kinds[featureTrip] != 2 || kinds[featureStay] != 4 {
+		t.Errorf("expected two trips and four stays, got %v", kinds)
+	}
+}
+
+func TestParseOutputFormats_MapFormats(t *testing.T) {
+	formats, err := parseOutputFormats([]string{"KML", "geojson"})
+	if err != nil || len(formats) != 2 || formats[0] != formatKML || formats[1] != formatGeoJSON {
+		t.Errorf("expected kml and geojson, got %v (%v)", formats, err)
+	}
+}
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+)
+
+// Kinds of map feature written to GeoJSON and KML
+const (
+	featureHome = "home"
+	featureTrip = "trip"
+	featureStay = "stay"
+)
+
+// mapFeature is a point or line drawn in the map formats
+type mapFeature struct {
+	kind        string
+	name        string
+	coordinates []calculator.Location // one for points, two or more for lines
+	properties  []mapProperty
+	start, end  time.Time // zero for home
+}
+
+// mapProperty is one metric of a map feature; properties keep their order
+// in KML
+type mapProperty struct {
+	key   string
+	value interface{} // float64, int or string
+}
+
+// trackGeometry collects the analyzed fixes of a track for the map formats.
+// Trips and stays are known only once the track is complete, so the fixes
+// are held until then, per device, since trip indexes are positions in the
+// track of their own device.
+type trackGeometry struct {
+	homeLat, homeLon float64
+	locations        map[string][]calculator.Location
+	devices          []string // in order of their first fix
+}
+
+// add records the next analyzed fix
+func (g *trackGeometry) add(point calculator.AnalyzedPoint) {
+	if g.locations == nil {
+		g.locations = make(map[string][]calculator.Location)
+	}
+	deviceID := point.DeviceID
+	if _, ok := g.locations[deviceID]; !ok {
+		g.devices = append(g.devices, deviceID)
+	}
+	g.locations[deviceID] = append(g.locations[deviceID], point.Location)
+}
+
+// features returns the home point carrying the track metrics, a line per
+// trip and a point per stay
+func (g *trackGeometry) features(summary calculator.TrackSummary) []mapFeature {
+	deviceTrips := make(map[string][]calculator.Trip)
+	for _, trip := range summary.Trips {
+		deviceTrips[trip.DeviceID] = append(deviceTrips[trip.DeviceID], trip.Trip)
+	}
+	var stays []calculator.Stay
+	for _, deviceID := range g.devices {
+		stays = append(stays, calculator.DetectStays(g.locations[deviceID], deviceTrips[deviceID])...)
+	}
+	sort.SliceStable(stays, func(i, j int) bool { return stays[i].StartTime.Before(stays[j].StartTime) })
+
+	metrics, elevation := summary.Metrics, summary.Elevation
+	features := []mapFeature{{
+		kind:        featureHome,
+		name:        "Home",
+		coordinates: []calculator.Location{{Latitude: g.homeLat, Longitude: g.homeLon}},
+		properties: []mapProperty{
+			{"total_distance_km", round3(metrics.TotalDistanceKM)},
+			{"max_distance_km", round3(metrics.MaxDistanceKM)},
+			{"min_distance_km", round3(metrics.MinDistanceKM)},
+			{"avg_distance_km", round3(metrics.AvgDistanceKM)},
+			{"total_locations", metrics.TotalLocations},
+			{"max_speed_kmh", round3(summary.MaxSpeedKMH)},
+			{"ascent_m", round3(elevation.AscentM)},
+			{"descent_m", round3(elevation.DescentM)},
+			{"trip_count", len(summary.Trips)},
+			{"stay_count", len(stays)},
+		},
+	}}
+
+	for i, trip := range summary.Trips {
+		path := g.locations[trip.DeviceID][trip.StartIndex : trip.EndIndex+1]
+		maxFromHome := 0.0
+		for _, loc := range path {
+			maxFromHome = math.Max(maxFromHome, calculator.DistanceFromHome(g.homeLat, g.homeLon, loc.Latitude, loc.Longitude))
+		}
+
+		features = append(features, mapFeature{
+			kind:        featureTrip,
+			name:        fmt.Sprintf("Trip %d", i+1),
+			coordinates: path,
+			start:       trip.StartTime,
+			end:         trip.EndTime,
+			properties: []mapProperty{
+				{"trip", i + 1},
+				{"start_time", trip.StartTime.UTC().Format(time.RFC3339)},
+				{"end_time", trip.EndTime.UTC().Format(time.RFC3339)},
+				{"duration_seconds", int(trip.Duration().Seconds())},
+				{"distance_km", round3(trip.DistanceKM)},
+				{"max_distance_from_home_km", round3(maxFromHome)},
+				{"ascent_m", round3(trip.AscentM)},
+				{"descent_m", round3(trip.DescentM)},
+				{"point_count", len(path)},
+			},
+		})
+	}
+
+	for i, stay := range stays {
+		features = append(features, mapFeature{
+			kind:        featureStay,
+			name:        fmt.Sprintf("Stay %d", i+1),
+			coordinates: []calculator.Location{{Latitude: stay.Latitude, Longitude: stay.Longitude}},
+			start:       stay.StartTime,
+			end:         stay.EndTime,
+			properties: []mapProperty{
+				{"stay", i + 1},
+				{"start_time", stay.StartTime.UTC().Format(time.RFC3339)},
+				{"end_time", stay.EndTime.UTC().Format(time.RFC3339)},
+				{"duration_seconds", int(stay.Duration().Seconds())},
+				{"distance_from_home_km", round3(calculator.DistanceFromHome(g.homeLat, g.homeLon, stay.Latitude, stay.Longitude))},
+				{"point_count", stay.PointCount},
+			},
+		})
+	}
+
+	return features
+}
+
+// round3 rounds v to three decimal places for the map formats
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
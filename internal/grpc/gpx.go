@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stuartshay/otel-worker/internal/storage"
+)
+
+// gpxNamespace is the GPX 1.1 namespace; gpxExtensionNamespace holds the
+// worker's per-point extensions
+const (
+	gpxNamespace          = "http://www.topografix.com/GPX/1/1"
+	gpxSchemaLocation     = "http://www.topografix.com/GPX/1/1 http://www.topografix.com/GPX/1/1/gpx.xsd"
+	gpxExtensionNamespace = "https://github.com/stuartshay/otel-worker/gpx/1"
+)
+
+// gpxTrackPoint is a GPX trkpt element
+type gpxTrackPoint struct {
+	XMLName    xml.Name       `xml:"trkpt"`
+	Latitude   string         `xml:"lat,attr"`
+	Longitude  string         `xml:"lon,attr"`
+	Elevation  string         `xml:"ele"`
+	Time       string         `xml:"time"`
+	Extensions *gpxExtensions `xml:"extensions,omitempty"`
+}
+
+// gpxExtensions holds the OwnTracks fields GPX has no element for
+type gpxExtensions struct {
+	Accuracy int `xml:"otel:accuracy,omitempty"`
+	Battery  int `xml:"otel:battery,omitempty"`
+}
+
+// gpxReport writes a GPX 1.1 file with one track per device. Fixes arrive
+// interleaved across devices, so each device's points are encoded into a
+// buffer and the tracks are written out by finish.
+type gpxReport struct {
+	key    string
+	file   storage.Writer
+	title  string
+	tracks map[string]*bytes.Buffer
+}
+
+// createGPXReport creates the artifact filename; title names the
+// file in its metadata
+func (s *Server) createGPXReport(ctx context.Context, filename, title string) (*gpxReport, error) {
+	file, err := s.artifacts.Create(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GPX file: %w", err)
+	}
+
+	return &gpxReport{key: filename, file: file, title: title, tracks: make(map[string]*bytes.Buffer)}, nil
+}
+
+// writePoint adds a fix to its device's track
+func (r *gpxReport) writePoint(p trackPoint) error {
+	track, ok := r.tracks[p.DeviceID]
+	if !ok {
+		track = &bytes.Buffer{}
+		r.tracks[p.DeviceID] = track
+	}
+
+	trkpt := gpxTrackPoint{
+		Latitude:  fmt.Sprintf("%.6f", p.Latitude),
+		Longitude: fmt.Sprintf("%.6f", p.Longitude),
+		Elevation: fmt.Sprintf("%.1f", p.Altitude),
+		Time:      p.Time.UTC().Format(time.RFC3339),
+	}
+	if p.Accuracy > 0 || p.Battery > 0 {
+		trkpt.Extensions = &gpxExtensions{Accuracy: p.Accuracy, Battery: p.Battery}
+	}
+
+	// Each point starts a new line; the encoder only indents it
+	track.WriteByte('\n')
+	encoder := xml.NewEncoder(track)
+	encoder.Indent("      ", "  ")
+	if err := encoder.Encode(trkpt); err != nil {
+		return fmt.Errorf("failed to write GPX point: %w", err)
+	}
+	return nil
+}
+
+// finish writes the tracks in device order and closes the file
+func (r *gpxReport) finish() (storage.Info, error) {
+	devices := make([]string, 0, len(r.tracks))
+	for deviceID := range r.tracks {
+		devices = append(devices, deviceID)
+	}
+	sort.Strings(devices)
+
+	w := bufio.NewWriter(r.file)
+	fmt.Fprintf(w, "%s<gpx version=\"1.1\" creator=\"otel-worker\" xmlns=\"%s\" xmlns:otel=\"%s\""+
+		" xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\" xsi:schemaLocation=\"%s\">\n",
+		xml.Header, gpxNamespace, gpxExtensionNamespace, gpxSchemaLocation)
+	fmt.Fprintf(w, "  <metadata>\n    <name>%s</name>\n    <time>%s</time>\n  </metadata>\n",
+		escapeXML(r.title), time.Now().UTC().Format(time.RFC3339))
+	for _, deviceID := range devices {
+		fmt.Fprintf(w, "  <trk>\n    <name>%s</name>\n    <trkseg>", escapeXML(deviceID))
+		_, _ = r.tracks[deviceID].WriteTo(w) // nolint:errcheck // write errors surface in Flush
+		fmt.Fprint(w, "\n    </trkseg>\n  </trk>\n")
+	}
+	fmt.Fprint(w, "</gpx>\n")
+
+	if err := w.Flush(); err != nil {
+		r.abort()
+		return storage.Info{}, fmt.Errorf("failed to write GPX file: %w", err)
+	}
+	info, err := r.file.Commit()
+	if err != nil {
+		r.file.Abort()
+		return storage.Info{}, fmt.Errorf("failed to store GPX file: %w", err)
+	}
+
+	log.Info().Str("gpx_key", r.key).Str("sha256", info.SHA256).Int("tracks", len(devices)).Msg("GPX file generated successfully")
+
+	return info, nil
+}
+
+// abort discards a partially written report
+func (r *gpxReport) abort() {
+	r.file.Abort()
+}
+
+// escapeXML escapes s for use as XML character data
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s)) // nolint:errcheck // writes to a bytes.Buffer do not fail
+	return b.String()
+}
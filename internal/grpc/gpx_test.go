@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"encoding/xml"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+)
+
+// gpxFile is the subset of a GPX document the tests check
+type gpxFile struct {
+	XMLName xml.Name `xml:"http://www.topografix.com/GPX/1/1 gpx"`
+	Version string   `xml:"version,attr"`
+	Tracks  []struct {
+		Name   string `xml:"name"`
+		Points []struct {
+			Latitude  float64 `xml:"lat,attr"`
+			Elevation float64 `xml:"ele"`
+			Time      string  `xml:"time"`
+			Accuracy  int     `xml:"extensions>accuracy"`
+			Battery   int     `xml:"extensions>battery"`
+		} `xml:"trkseg>trkpt"`
+	} `xml:"trk"`
+}
+
+func TestParseOutputFormats(t *testing.T) {
+	formats, err := parseOutputFormats(nil)
+	if err != nil || len(formats) != 1 || formats[0] != formatCSV {
+		t.Errorf("expected csv by default, got %v (%v)", formats, err)
+	}
+
+	formats, err = parseOutputFormats([]string{"GPX", "csv", "gpx"})
+	if err != nil || len(formats) != 2 || formats[0] != formatGPX || formats[1] != formatCSV {
+		t.Errorf("expected gpx and csv, got %v (%v)", formats, err)
+	}
+
+	if _, err := parseOutputFormats([]string{"shp"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestProcessDistanceJob_GPX(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	pixel := walkFromHome("pixel8", start, 5)
+	for i := range pixel {
+		pixel[i].Accuracy = 12
+	}
+	store := database.NewMemoryStore(append(pixel, walkFromHome("iphone", start, 3)...)...)
+	server := newMemoryServer(t, store)
+
+	job := &queue.Job{ID: "job-1", Date: "2026-01-24", OutputFormats: []string{formatGPX}}
+	result, err := server.processDistanceJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+	if result.CSVPath != "" {
+		t.Errorf("expected no CSV, got %s", result.CSVPath)
+	}
+	if filepath.Base(result.GPXPath) != "distance_20260124_job-1.gpx" {
+		t.Fatalf("unexpected GPX path %s", result.GPXPath)
+	}
+
+	content, err := readArtifact(t, server, result.GPXPath)
+	if err != nil {
+		t.Fatalf("failed to read GPX: %v", err)
+	}
+	var doc gpxFile
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("invalid GPX: %v\n%s", err, content)
+	}
+
+	if doc.Version != "1.1" || len(doc.Tracks) != 2 {
+		t.Fatalf("expected GPX 1.1 with 2 tracks, got version %q with %d", doc.Version, len(doc.Tracks))
+	}
+	track := doc.Tracks[1]
+	if track.Name != "pixel8" || len(track.Points) != 5 {
+		t.Fatalf("expected 5 points for pixel8, got %d for %s", len(track.Points), track.Name)
+	}
+	point := track.Points[4]
+	if point.Time != "2026-01-24T08:04:00Z" || point.Elevation != 14 || point.Accuracy != 12 || point.Battery != 86 {
+		t.Errorf("unexpected last point %+v", point)
+	}
+}
+
+func TestProcessDistanceJob_CSVAndGPX(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	server := newMemoryServer(t, database.NewMemoryStore(walkFromHome("pixel8", start, 5)...))
+
+	job := &queue.Job{ID: "job-1", Date: "2026-01-24", DeviceID: "pixel8", OutputFormats: []string{formatCSV, formatGPX}}
+	result, err := server.processDistanceJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+
+	for _, key := range []string{result.CSVPath, result.GPXPath} {
+		if _, err := readArtifact(t, server, key); err != nil {
+			t.Errorf("expected output file: %v", err)
+		}
+	}
+	if filepath.Base(result.GPXPath) != "distance_20260124_pixel8_job-1.gpx" {
+		t.Errorf("unexpected GPX path %s", result.GPXPath)
+	}
+
+	// A day without data leaves no files behind
+	job = &queue.Job{ID: "job-2", Date: "2026-01-25", OutputFormats: []string{formatCSV, formatGPX}}
+	if _, err := server.processDistanceJob(context.Background(), job); err == nil {
+		t.Fatal("expected error for a day without locations")
+	}
+	if files := outputFiles(t, server); len(files) != 2 {
+		t.Errorf("expected only the first job's 2 files, got %v", files)
+	}
+}
@@ -4,10 +4,7 @@ package grpc
 
 import (
 	"context"
-	"encoding/csv"
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -16,29 +13,94 @@ import (
 	"github.com/stuartshay/otel-worker/internal/calculator"
 	"github.com/stuartshay/otel-worker/internal/config"
 	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/download"
 	"github.com/stuartshay/otel-worker/internal/queue"
+	"github.com/stuartshay/otel-worker/internal/storage"
 	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
 )
 
 // Server implements the DistanceService gRPC server
 type Server struct {
 	distancev1.UnimplementedDistanceServiceServer
-	cfg   *config.Config
-	db    *database.Client
-	queue *queue.Queue
+	cfg       *config.Config
+	store     database.LocationStore
+	summaries database.SummaryStore     // nil when the store cannot persist summaries
+	garmin    database.GarminStore      // nil when the store has no Garmin activities
+	unified   database.UnifiedStore     // nil when the store cannot merge GPS sources
+	spatial   database.SpatialStore     // nil when the store cannot search by position
+	available database.Availability     // nil when the store is always available
+	follower  database.LocationFollower // nil when the store cannot follow new locations
+	notifier  database.LocationNotifier // nil when the store cannot LISTEN
+	live      *liveState                // nil unless incremental mode is on
+	artifacts storage.Store             // where job outputs are written
+	catalog   *artifactCatalog          // indexes the artifacts in artifacts
+	signer    *download.Signer          // signs artifact download URLs
+	queue     *queue.Queue
 }
 
-// NewServer creates a new gRPC server instance
-func NewServer(cfg *config.Config, db *database.Client) *Server {
+// NewServer creates a new gRPC server instance reading locations from store.
+// Daily summaries, Garmin activities, multi-source and spatial queries are
+// available only if store also implements SummaryStore, GarminStore,
+// UnifiedStore and SpatialStore. It fails if no download signing key can be
+// set up.
+func NewServer(cfg *config.Config, store database.LocationStore) (*Server, error) {
+	signer, err := newDownloadSigner(cfg)
+	if err != nil {
+		return nil, err
+	}
 	s := &Server{
-		cfg: cfg,
-		db:  db,
+		cfg:       cfg,
+		store:     store,
+		artifacts: storage.NewLocalStore(cfg.CSVOutputPath),
+		signer:    signer,
+		catalog:   newArtifactCatalog(),
+	}
+	s.summaries, _ = store.(database.SummaryStore)
+	s.garmin, _ = store.(database.GarminStore)
+	s.unified, _ = store.(database.UnifiedStore)
+	s.spatial, _ = store.(database.SpatialStore)
+	s.available, _ = store.(database.Availability)
+	s.follower, _ = store.(database.LocationFollower)
+	s.notifier, _ = store.(database.LocationNotifier)
+	if incrementalEnabled(cfg) && s.follower != nil {
+		s.live = newLiveState(cfg)
 	}
 
 	// Initialize job queue with processor
-	s.queue = queue.NewQueue(5, s.processDistanceJob)
+	s.queue = queue.NewQueue(5, s.processJob)
+
+	return s, nil
+}
+
+// SetArtifactStore replaces the local CSVOutputPath directory as the store
+// job outputs are written to. It must be called before jobs are submitted.
+func (s *Server) SetArtifactStore(artifacts storage.Store) {
+	s.artifacts = artifacts
+}
 
-	return s
+// DownloadSigner returns the signer of the download URLs GetJobStatus
+// returns, for the download endpoint to verify them with
+func (s *Server) DownloadSigner() *download.Signer {
+	return s.signer
+}
+
+// newDownloadSigner returns a signer using the configured key, or a random
+// key valid only in this process when none is configured
+func newDownloadSigner(cfg *config.Config) (*download.Signer, error) {
+	key := []byte(cfg.DownloadSigningKey)
+	if len(key) == 0 {
+		var err error
+		if key, err = download.RandomKey(); err != nil {
+			return nil, fmt.Errorf("failed to generate download signing key: %w", err)
+		}
+	} else if len(key) < download.MinKeyLength {
+		return nil, fmt.Errorf("download signing key must be at least %d bytes", download.MinKeyLength)
+	}
+	ttl := cfg.DownloadURLTTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	return download.NewSigner(key, ttl, cfg.DownloadBaseURL), nil
 }
 
 // CalculateDistanceFromHome initiates an async distance calculation job
@@ -46,6 +108,7 @@ func (s *Server) CalculateDistanceFromHome(_ context.Context, req *distancev1.Ca
 	log.Info().
 		Str("date", req.Date).
 		Str("device_id", req.DeviceId).
+		Str("source", req.Source).
 		Msg("Received distance calculation request")
 
 	// Validate date format
@@ -53,8 +116,34 @@ func (s *Server) CalculateDistanceFromHome(_ context.Context, req *distancev1.Ca
 		return nil, fmt.Errorf("date is required")
 	}
 
+	sources, err := database.ParseSourceFilter(req.Source)
+	if err != nil {
+		return nil, err
+	}
+	if !ownTracksOnly(sources) && s.unified == nil {
+		return nil, errUnifiedUnsupported
+	}
+
+	formats, err := parseOutputFormats(req.OutputFormats)
+	if err != nil {
+		return nil, err
+	}
+
+	template := csvTemplateFromProto(req.CsvTemplate)
+	if _, err := newCSVTemplate(template, s.csvHeaderFor(sources)); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAvailable(); err != nil {
+		return nil, err
+	}
+
 	// Enqueue job
-	jobID, err := s.queue.Enqueue(req.Date, req.DeviceId)
+	jobID, err := s.queue.Enqueue(req.Date, req.DeviceId, queue.EnqueueOptions{
+		Source:        req.Source,
+		OutputFormats: formats,
+		CSVTemplate:   template,
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to enqueue job")
 		return nil, fmt.Errorf("failed to enqueue job: %w", err)
@@ -67,6 +156,15 @@ func (s *Server) CalculateDistanceFromHome(_ context.Context, req *distancev1.Ca
 	}, nil
 }
 
+// checkAvailable rejects new jobs while the store reports the database as
+// unreachable, rather than queueing work that would fail
+func (s *Server) checkAvailable() error {
+	if s.available != nil && !s.available.Available() {
+		return fmt.Errorf("job rejected: %w", database.ErrCircuitOpen)
+	}
+	return nil
+}
+
 // GetJobStatus returns the current status of a distance calculation job
 func (s *Server) GetJobStatus(_ context.Context, req *distancev1.GetJobStatusRequest) (*distancev1.GetJobStatusResponse, error) {
 	job, err := s.queue.GetJob(req.JobId)
@@ -93,25 +191,97 @@ func (s *Server) GetJobStatus(_ context.Context, req *distancev1.GetJobStatusReq
 	}
 
 	if job.Result != nil {
-		// Safe conversion: TotalLocations is bounded by database query results
-		totalLocs := job.Result.TotalLocations
-		if totalLocs > 2147483647 {
-			totalLocs = 2147483647 // Cap at int32 max
+		resp.Result = jobResultToProto(job)
+		s.signDownloads(job.ID, resp.Result)
+	}
+
+	return resp, nil
+}
+
+// signDownloads sets fresh signed download URLs on a job's artifacts
+func (s *Server) signDownloads(jobID string, result *distancev1.JobResult) {
+	for i, a := range result.Artifacts {
+		url, expires := s.signer.URL(jobID, a.Key)
+		a.DownloadUrl = url
+		if i == 0 {
+			result.DownloadUrl = url
+			result.DownloadExpiresAt = timestamppb.New(expires)
 		}
+	}
+}
+
+// jobResultToProto converts a completed job's result to its protobuf form
+func jobResultToProto(job *queue.Job) *distancev1.JobResult {
+	// Safe conversion: TotalLocations is bounded by database query results
+	totalLocs := job.Result.TotalLocations
+	if totalLocs > 2147483647 {
+		totalLocs = 2147483647 // Cap at int32 max
+	}
+
+	result := &distancev1.JobResult{
+		CsvPath:          job.Result.CSVPath,
+		GpxPath:          job.Result.GPXPath,
+		TotalDistanceKm:  job.Result.TotalDistanceKM,
+		MaxDistanceKm:    job.Result.MaxDistanceKM,
+		MinDistanceKm:    job.Result.MinDistanceKM,
+		TotalLocations:   int32(totalLocs), // #nosec G115
+		Date:             job.Date,
+		DeviceId:         job.DeviceID,
+		Source:           job.Source,
+		ProcessingTimeMs: job.Result.ProcessingTimeMS,
+		MaxSpeedKmh:      job.Result.MaxSpeedKMH,
+		Elevation:        elevationStatsToProto(job.Result.Elevation),
+		SummariesWritten: int32(job.Result.SummariesWritten), // #nosec G115 -- bounded by devices * days
+	}
 
-		resp.Result = &distancev1.JobResult{
-			CsvPath:          job.Result.CSVPath,
-			TotalDistanceKm:  job.Result.TotalDistanceKM,
-			MaxDistanceKm:    job.Result.MaxDistanceKM,
-			MinDistanceKm:    job.Result.MinDistanceKM,
-			TotalLocations:   int32(totalLocs), // #nosec G115
-			Date:             job.Date,
-			DeviceId:         job.DeviceID,
-			ProcessingTimeMs: job.Result.ProcessingTimeMS,
+	if job.Result.Activity != nil {
+		result.Activity = activityMetricsToProto(job.Result.Activity)
+	}
+
+	for _, a := range job.Result.Artifacts {
+		result.Artifacts = append(result.Artifacts, &distancev1.Artifact{
+			Format:    a.Format,
+			Key:       a.Key,
+			SizeBytes: a.Size,
+			Sha256:    a.SHA256,
+		})
+	}
+
+	for _, mt := range job.Result.ModeTotals {
+		result.ModeTotals = append(result.ModeTotals, &distancev1.ModeTotal{
+			Mode:            mt.Mode,
+			DistanceKm:      mt.DistanceKM,
+			DurationSeconds: mt.DurationSeconds,
+		})
+	}
+
+	for _, te := range job.Result.TripElevations {
+		trip := &distancev1.TripElevation{
+			StartTime:  timestamppb.New(te.StartTime),
+			EndTime:    timestamppb.New(te.EndTime),
+			DistanceKm: te.DistanceKM,
+			Elevation:  elevationStatsToProto(te.Elevation),
 		}
+		for _, sample := range te.Profile {
+			trip.Profile = append(trip.Profile, &distancev1.ElevationSample{
+				DistanceKm: sample.DistanceKM,
+				AltitudeM:  sample.AltitudeM,
+			})
+		}
+		result.TripElevations = append(result.TripElevations, trip)
 	}
 
-	return resp, nil
+	return result
+}
+
+// elevationStatsToProto converts climb statistics to their protobuf form
+func elevationStatsToProto(stats queue.ElevationStats) *distancev1.ElevationStats {
+	return &distancev1.ElevationStats{
+		TotalAscentM:  stats.AscentM,
+		TotalDescentM: stats.DescentM,
+		MinAltitudeM:  stats.MinAltitudeM,
+		MaxAltitudeM:  stats.MaxAltitudeM,
+	}
 }
 
 // ListJobs returns a list of distance calculation jobs with optional filtering
@@ -135,11 +305,15 @@ func (s *Server) ListJobs(_ context.Context, req *distancev1.ListJobsRequest) (*
 
 	for _, job := range jobs {
 		summary := &distancev1.JobSummary{
-			JobId:    job.ID,
-			Status:   string(job.Status),
-			Date:     job.Date,
-			DeviceId: job.DeviceID,
-			QueuedAt: timestamppb.New(job.QueuedAt),
+			JobId:      job.ID,
+			Status:     string(job.Status),
+			Date:       job.Date,
+			DeviceId:   job.DeviceID,
+			QueuedAt:   timestamppb.New(job.QueuedAt),
+			Kind:       string(job.Kind),
+			EndDate:    job.EndDate,
+			ActivityId: job.ActivityID,
+			Source:     job.Source,
 		}
 
 		if job.CompletedAt != nil {
@@ -154,155 +328,218 @@ func (s *Server) ListJobs(_ context.Context, req *distancev1.ListJobsRequest) (*
 	return resp, nil
 }
 
+// processJob is the queue worker function; it dispatches on the job kind
+// and adds the artifacts of a completed job to the catalog
+func (s *Server) processJob(ctx context.Context, job *queue.Job) (*queue.JobResult, error) {
+	var result *queue.JobResult
+	var err error
+	switch job.Kind {
+	case queue.KindBackfill:
+		result, err = s.processBackfillJob(ctx, job)
+	case queue.KindActivity:
+		result, err = s.processActivityJob(ctx, job)
+	default:
+		result, err = s.processDistanceJob(ctx, job)
+	}
+	if err == nil && result != nil {
+		s.catalog.add(job, result.Artifacts, time.Now())
+	}
+	return result, err
+}
+
 // processDistanceJob is the worker function that processes distance calculation jobs
 func (s *Server) processDistanceJob(ctx context.Context, job *queue.Job) (*queue.JobResult, error) {
 	log.Info().
 		Str("job_id", job.ID).
 		Str("date", job.Date).
 		Str("device_id", job.DeviceID).
+		Str("source", job.Source).
 		Msg("Processing distance calculation job")
 
-	// Fetch locations from database
-	locations, err := s.db.GetLocationsByDate(ctx, job.Date, job.DeviceID)
+	sources, err := database.ParseSourceFilter(job.Source)
+	if err != nil {
+		return nil, err
+	}
+	if !ownTracksOnly(sources) {
+		return s.processUnifiedJob(ctx, job, sources)
+	}
+
+	outputs, err := createTrackOutputs(ctx, s, job.OutputFormats, trackOutputSpec[database.Location]{
+		name:      artifactName(distanceName(job.Date, job.DeviceID), job),
+		title:     distanceTitle(job),
+		csvHeader: s.csvHeaderFor(sources),
+		csvRow: func(loc database.Location, point calculator.AnalyzedPoint) []csvCell {
+			row := distanceRow(loc, point)
+			if s.cfg.IncludeRawPayload {
+				row = append(row, payloadColumns(loc.Payload)...)
+			}
+			return row
+		},
+		csvTemplate: job.CSVTemplate,
+		point:       locationTrackPoint,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create output files")
+		return nil, fmt.Errorf("output generation failed: %w", err)
+	}
+
+	// Without a device filter the day holds every device's fixes, so each
+	// device is analyzed on its own
+	tracks := newDeviceTracks(s, outputs.write)
+	daily := s.newDailySummaries(job.Date)
+
+	// Stream locations from the store so memory use does not grow with the day
+	var writeErr error
+	err = s.store.StreamLocationsByDate(ctx, job.Date, job.DeviceID, func(loc database.Location) error {
+		daily.add(loc)
+		writeErr = tracks.push(loc, toCalculatorLocation(loc))
+		return writeErr
+	})
+	if err == nil {
+		writeErr = tracks.flush()
+	}
+	if writeErr != nil {
+		outputs.abort()
+		log.Error().Err(writeErr).Msg("Failed to write output files")
+		return nil, fmt.Errorf("output generation failed: %w", writeErr)
+	}
 	if err != nil {
+		outputs.abort()
 		log.Error().Err(err).Msg("Failed to fetch locations from database")
 		return nil, fmt.Errorf("database query failed: %w", err)
 	}
 
-	if len(locations) == 0 {
+	summary := tracks.summary()
+	metrics := summary.Metrics
+	if metrics.TotalLocations == 0 {
+		outputs.abort()
 		log.Warn().Str("date", job.Date).Msg("No locations found for date")
 		return nil, fmt.Errorf("no locations found for date %s", job.Date)
 	}
 
-	// Convert database locations to calculator locations
-	calcLocations := make([]calculator.Location, len(locations))
-	for i, loc := range locations {
-		calcLocations[i] = calculator.Location{
-			Latitude:  loc.Latitude,
-			Longitude: loc.Longitude,
-		}
-	}
-
-	// Calculate distance metrics
-	metrics := calculator.CalculateMetrics(
-		s.cfg.HomeLatitude,
-		s.cfg.HomeLongitude,
-		calcLocations,
-	)
-
 	log.Info().
 		Float64("total_distance_km", metrics.TotalDistanceKM).
 		Float64("max_distance_km", metrics.MaxDistanceKM).
 		Float64("min_distance_km", metrics.MinDistanceKM).
 		Int("total_locations", metrics.TotalLocations).
+		Int("trips", len(summary.Trips)).
+		Float64("ascent_m", summary.Elevation.AscentM).
 		Msg("Distance metrics calculated")
 
-	// Generate CSV file
-	csvPath, err := s.generateCSV(job.Date, job.DeviceID, locations, metrics)
+	artifacts, err := outputs.finish(summary)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write output files")
+		return nil, fmt.Errorf("output generation failed: %w", err)
+	}
+
+	// Keep the dashboard aggregates in step with the raw data just processed
+	written, err := daily.store(ctx)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to generate CSV file")
-		return nil, fmt.Errorf("CSV generation failed: %w", err)
+		log.Warn().Err(err).Msg("Failed to update daily summaries")
 	}
 
-	return &queue.JobResult{
-		CSVPath:         csvPath,
+	result := trackResult(artifacts, summary)
+	result.SummariesWritten = written
+	return result, nil
+}
+
+// trackResult converts the summary of an analyzed track and its output
+// files to a job result
+func trackResult(artifacts []queue.Artifact, summary calculator.TrackSummary) *queue.JobResult {
+	metrics := summary.Metrics
+	result := &queue.JobResult{
+		CSVPath:         artifactKey(artifacts, formatCSV),
+		GPXPath:         artifactKey(artifacts, formatGPX),
+		Artifacts:       artifacts,
 		TotalDistanceKM: metrics.TotalDistanceKM,
 		MaxDistanceKM:   metrics.MaxDistanceKM,
 		MinDistanceKM:   metrics.MinDistanceKM,
 		TotalLocations:  metrics.TotalLocations,
-	}, nil
-}
-
-// generateCSV creates a CSV file with location data and distance calculations
-func (s *Server) generateCSV(date, deviceID string, locations []database.Location, metrics calculator.DistanceMetrics) (string, error) {
-	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(s.cfg.CSVOutputPath, 0750); err != nil {
-		return "", fmt.Errorf("failed to create output directory: %w", err)
+		MaxSpeedKMH:     summary.MaxSpeedKMH,
+		Elevation:       elevationStats(summary.Elevation),
 	}
 
-	// Generate filename
-	dateStr := date
-	if len(date) == 10 {
-		dateStr = date[0:4] + date[5:7] + date[8:10] // YYYYMMDD
+	for _, mt := range summary.ModeTotals {
+		result.ModeTotals = append(result.ModeTotals, queue.ModeTotal{
+			Mode:            string(mt.Mode),
+			DistanceKM:      mt.DistanceKM,
+			DurationSeconds: int64(mt.Duration.Seconds()),
+		})
 	}
-	filename := fmt.Sprintf("distance_%s.csv", dateStr)
-	if deviceID != "" {
-		filename = fmt.Sprintf("distance_%s_%s.csv", dateStr, deviceID)
-	}
-	csvPath := filepath.Join(s.cfg.CSVOutputPath, filename)
 
-	// Create CSV file
-	// #nosec G304 -- csvPath is constructed from validated date and deviceID parameters
-	file, err := os.Create(csvPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to create CSV file: %w", err)
-	}
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil {
-			log.Error().Err(closeErr).Msg("Failed to close CSV file")
+	for _, te := range summary.Trips {
+		trip := queue.TripElevation{
+			StartTime:  te.StartTime,
+			EndTime:    te.EndTime,
+			DistanceKM: te.DistanceKM,
+			Elevation:  elevationStats(te.ElevationStats),
 		}
-	}()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write header
-	header := []string{
-		"timestamp", "device_id", "latitude", "longitude",
-		"distance_from_home_km", "accuracy", "battery", "velocity",
-	}
-	if err := writer.Write(header); err != nil {
-		return "", fmt.Errorf("failed to write CSV header: %w", err)
-	}
-
-	// Write data rows
-	for _, loc := range locations {
-		distance := calculator.DistanceFromHome(
-			s.cfg.HomeLatitude,
-			s.cfg.HomeLongitude,
-			loc.Latitude,
-			loc.Longitude,
-		)
-
-		row := []string{
-			loc.CreatedAt.Format(time.RFC3339),
-			loc.DeviceID,
-			fmt.Sprintf("%.6f", loc.Latitude),
-			fmt.Sprintf("%.6f", loc.Longitude),
-			fmt.Sprintf("%.2f", distance),
-			fmt.Sprintf("%d", loc.Accuracy),
-			fmt.Sprintf("%d", loc.Battery),
-			fmt.Sprintf("%d", loc.Velocity),
-		}
-
-		if err := writer.Write(row); err != nil {
-			return "", fmt.Errorf("failed to write CSV row: %w", err)
+		for _, sample := range te.Profile {
+			trip.Profile = append(trip.Profile, queue.ElevationSample{
+				DistanceKM: sample.DistanceKM,
+				AltitudeM:  sample.AltitudeM,
+			})
 		}
+		result.TripElevations = append(result.TripElevations, trip)
 	}
 
-	// Write summary footer
-	summaryRows := [][]string{
-		{},
-		{"Summary"},
-		{"Total Distance (km)", fmt.Sprintf("%.2f", metrics.TotalDistanceKM)},
-		{"Max Distance (km)", fmt.Sprintf("%.2f", metrics.MaxDistanceKM)},
-		{"Min Distance (km)", fmt.Sprintf("%.2f", metrics.MinDistanceKM)},
-		{"Total Locations", fmt.Sprintf("%d", metrics.TotalLocations)},
-		{"Average Distance (km)", fmt.Sprintf("%.2f", metrics.AvgDistanceKM)},
-	}
+	return result
+}
 
-	for _, row := range summaryRows {
-		if err := writer.Write(row); err != nil {
-			return "", fmt.Errorf("failed to write CSV summary: %w", err)
-		}
+// elevationStats converts calculator climb statistics to the queue result type
+func elevationStats(stats calculator.ElevationStats) queue.ElevationStats {
+	return queue.ElevationStats{
+		AscentM:      stats.AscentM,
+		DescentM:     stats.DescentM,
+		MinAltitudeM: stats.MinAltitudeM,
+		MaxAltitudeM: stats.MaxAltitudeM,
 	}
+}
 
-	log.Info().Str("csv_path", csvPath).Msg("CSV file generated successfully")
+// toCalculatorLocation converts a database row to a calculator fix
+func toCalculatorLocation(loc database.Location) calculator.Location {
+	return calculator.Location{
+		DeviceID:  loc.DeviceID,
+		Latitude:  loc.Latitude,
+		Longitude: loc.Longitude,
+		Altitude:  loc.Altitude,
+		Timestamp: fixTime(loc),
+	}
+}
 
-	return csvPath, nil
+// fixTime returns when a location was recorded by the device, falling back to
+// the database insert time when the OwnTracks timestamp is missing
+func fixTime(loc database.Location) time.Time {
+	if loc.Timestamp > 0 {
+		return time.Unix(loc.Timestamp, 0).UTC()
+	}
+	return loc.CreatedAt
 }
 
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(timeout time.Duration) error {
 	return s.queue.Shutdown(timeout)
 }
+
+// validateDateRange checks that start and end are YYYY-MM-DD dates in order
+func validateDateRange(startDate, endDate string) error {
+	if startDate == "" || endDate == "" {
+		return fmt.Errorf("start_date and end_date are required")
+	}
+
+	start, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date: %w", err)
+	}
+
+	end, err := time.Parse(time.DateOnly, endDate)
+	if err != nil {
+		return fmt.Errorf("invalid end_date: %w", err)
+	}
+
+	if end.Before(start) {
+		return fmt.Errorf("end_date %s is before start_date %s", endDate, startDate)
+	}
+
+	return nil
+}
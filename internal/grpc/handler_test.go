@@ -34,7 +34,8 @@ func setupTestServer(t *testing.T) (*Server, func()) {
 	require.NoError(t, err, "Failed to connect to database")
 
 	// Create server
-	server := NewServer(cfg, db)
+	server, err := NewServer(cfg, db)
+	require.NoError(t, err, "Failed to create server")
 
 	cleanup := func() {
 		if server.queue != nil {
@@ -365,16 +366,17 @@ func TestCSVGeneration(t *testing.T) {
 		assert.NotNil(t, statusResp.Result)
 		assert.NotEmpty(t, statusResp.Result.CsvPath)
 
-		// Verify CSV file exists
-		_, err := os.Stat(statusResp.Result.CsvPath)
+		// Verify CSV file exists; the result holds its storage key
+		csvFile := filepath.Join(server.cfg.CSVOutputPath, statusResp.Result.CsvPath)
+		_, err := os.Stat(csvFile)
 		assert.NoError(t, err, "CSV file should exist")
 
 		// Verify filename format
 		expectedFilename := "distance_20250122_pixel8.csv"
-		assert.Equal(t, expectedFilename, filepath.Base(statusResp.Result.CsvPath))
+		assert.Equal(t, expectedFilename, statusResp.Result.CsvPath)
 
 		// Verify CSV content
-		content, err := os.ReadFile(statusResp.Result.CsvPath)
+		content, err := os.ReadFile(csvFile)
 		require.NoError(t, err)
 		assert.Contains(t, string(content), "timestamp,device_id,latitude,longitude")
 		assert.Contains(t, string(content), "Summary")
@@ -437,12 +439,8 @@ func TestJobProcessingWithRealDatabase(t *testing.T) {
 		assert.GreaterOrEqual(t, statusResp.Result.MaxDistanceKm, statusResp.Result.MinDistanceKm)
 		assert.GreaterOrEqual(t, statusResp.Result.MaxDistanceKm, float64(0))
 
-		// Verify CSV file was created
-		csvPath := statusResp.Result.CsvPath
-		assert.FileExists(t, csvPath)
-
-		// Verify CSV is in correct directory
-		assert.Equal(t, server.cfg.CSVOutputPath, filepath.Dir(csvPath))
+		// Verify CSV file was created in the local artifact store
+		assert.FileExists(t, filepath.Join(server.cfg.CSVOutputPath, statusResp.Result.CsvPath))
 	}
 
 	// Verify job appears in list
@@ -560,7 +558,7 @@ func TestCSVFilenameFormat(t *testing.T) {
 			require.NoError(t, err)
 
 			if statusResp.Status == "completed" {
-				assert.Equal(t, tt.expectedFilename, filepath.Base(statusResp.Result.CsvPath))
+				assert.Equal(t, tt.expectedFilename, statusResp.Result.CsvPath)
 			}
 		})
 	}
@@ -629,3 +627,102 @@ func TestJobResultMetrics(t *testing.T) {
 		t.Logf("Job failed: %s (expected if no data for date)", statusResp.ErrorMessage)
 	}
 }
+
+func TestGetBatteryReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	server, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	t.Run("invalid date range", func(t *testing.T) {
+		_, err := server.GetBatteryReport(ctx, &distancev1.GetBatteryReportRequest{
+			StartDate: "2025-01-22",
+			EndDate:   "2025-01-20",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "before start_date")
+	})
+
+	t.Run("single device", func(t *testing.T) {
+		resp, err := server.GetBatteryReport(ctx, &distancev1.GetBatteryReportRequest{
+			StartDate: "2025-01-20",
+			EndDate:   "2025-01-22",
+			DeviceId:  "pixel8",
+		})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(resp.Devices), 1)
+
+		for _, device := range resp.Devices {
+			assert.Equal(t, "pixel8", device.DeviceId)
+			assert.GreaterOrEqual(t, device.DrainRatePerHour, float64(0))
+			for _, day := range device.Days {
+				assert.LessOrEqual(t, day.MinLevel, day.MaxLevel)
+			}
+		}
+	})
+}
+
+func TestBackfillDailySummaries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	server, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := server.store.(*database.Client).Migrate(ctx)
+	require.NoError(t, err)
+
+	createResp, err := server.BackfillDailySummaries(ctx, &distancev1.BackfillDailySummariesRequest{
+		StartDate: "2025-01-20",
+		EndDate:   "2025-01-22",
+		DeviceId:  "pixel8",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "queued", createResp.Status)
+
+	time.Sleep(3 * time.Second)
+
+	statusResp, err := server.GetJobStatus(ctx, &distancev1.GetJobStatusRequest{JobId: createResp.JobId})
+	require.NoError(t, err)
+	require.Equal(t, "completed", statusResp.Status, statusResp.ErrorMessage)
+
+	summaries, err := server.GetDailySummaries(ctx, &distancev1.GetDailySummariesRequest{
+		StartDate: "2025-01-20",
+		EndDate:   "2025-01-22",
+		DeviceId:  "pixel8",
+	})
+	require.NoError(t, err)
+	assert.Len(t, summaries.Summaries, int(statusResp.Result.SummariesWritten))
+}
+
+func TestGetDataQualityReport_Database(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	server, cleanup := setupTestServer(t)
+	defer cleanup()
+
+	resp, err := server.GetDataQualityReport(context.Background(), &distancev1.GetDataQualityReportRequest{
+		StartDate: "2025-01-20",
+		EndDate:   "2025-01-22",
+		DeviceId:  "pixel8",
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(resp.Devices), 1)
+
+	for _, device := range resp.Devices {
+		assert.Equal(t, "pixel8", device.DeviceId)
+		assert.LessOrEqual(t, device.DuplicateCount, device.PointCount)
+		assert.LessOrEqual(t, device.Accuracy.ReportedCount, device.PointCount)
+		for _, gap := range device.Gaps {
+			assert.GreaterOrEqual(t, gap.DurationSeconds, int64(time.Hour.Seconds()))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stuartshay/otel-worker/internal/storage"
+)
+
+// kmlNamespace is the KML 2.2 namespace
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+// kmlReport writes a KML document with a placemark per home, trip and stay
+// feature of a track
+type kmlReport struct {
+	key   string
+	file  storage.Writer
+	title string
+}
+
+// createKMLReport creates the artifact filename; title names the
+// document
+func (s *Server) createKMLReport(ctx context.Context, filename, title string) (*kmlReport, error) {
+	file, err := s.artifacts.Create(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create KML file: %w", err)
+	}
+
+	return &kmlReport{key: filename, file: file, title: title}, nil
+}
+
+// finish writes the placemarks and closes the file
+func (r *kmlReport) finish(features []mapFeature) (storage.Info, error) {
+	w := bufio.NewWriter(r.file)
+	fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<kml xmlns=\"%s\">\n  <Document>\n    <name>%s</name>\n",
+		kmlNamespace, escapeXML(r.title))
+	fmt.Fprint(w, "    <Style id=\"trip\">\n      <LineStyle>\n        <color>ff0000ff</color>\n        <width>3</width>\n      </LineStyle>\n    </Style>\n")
+	for _, f := range features {
+		fmt.Fprintf(w, "    <Placemark>\n      <name>%s</name>\n", escapeXML(f.name))
+		if f.kind == featureTrip {
+			fmt.Fprint(w, "      <styleUrl>#trip</styleUrl>\n")
+		}
+		if !f.start.IsZero() {
+			fmt.Fprintf(w, "      <TimeSpan>\n        <begin>%s</begin>\n        <end>%s</end>\n      </TimeSpan>\n",
+				f.start.UTC().Format(time.RFC3339), f.end.UTC().Format(time.RFC3339))
+		}
+
+		fmt.Fprintf(w, "      <ExtendedData>\n        <Data name=\"kind\"><value>%s</value></Data>\n", f.kind)
+		for _, p := range f.properties {
+			fmt.Fprintf(w, "        <Data name=\"%s\"><value>%s</value></Data>\n", p.key, escapeXML(fmt.Sprint(p.value)))
+		}
+		fmt.Fprint(w, "      </ExtendedData>\n")
+
+		coordinates := make([]string, len(f.coordinates))
+		for i, loc := range f.coordinates {
+			coordinates[i] = fmt.Sprintf("%.6f,%.6f,%.1f", loc.Longitude, loc.Latitude, loc.Altitude)
+		}
+		if f.kind == featureTrip {
+			fmt.Fprintf(w, "      <LineString>\n        <tessellate>1</tessellate>\n        <coordinates>%s</coordinates>\n      </LineString>\n",
+				strings.Join(coordinates, " "))
+		} else {
+			fmt.Fprintf(w, "      <Point>\n        <coordinates>%s</coordinates>\n      </Point>\n", coordinates[0])
+		}
+		fmt.Fprint(w, "    </Placemark>\n")
+	}
+	fmt.Fprint(w, "  </Document>\n</kml>\n")
+
+	if err := w.Flush(); err != nil {
+		r.abort()
+		return storage.Info{}, fmt.Errorf("failed to write KML file: %w", err)
+	}
+	info, err := r.file.Commit()
+	if err != nil {
+		r.file.Abort()
+		return storage.Info{}, fmt.Errorf("failed to store KML file: %w", err)
+	}
+
+	log.Info().Str("kml_key", r.key).Str("sha256", info.SHA256).Int("placemarks", len(features)).Msg("KML file generated successfully")
+
+	return info, nil
+}
+
+// abort discards a partially written report
+func (r *kmlReport) abort() {
+	r.file.Abort()
+}
@@ -0,0 +1,363 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/config"
+	"github.com/stuartshay/otel-worker/internal/database"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+var (
+	// errIncrementalDisabled is returned by GetLiveState when incremental
+	// mode is off
+	errIncrementalDisabled = errors.New("incremental mode is disabled: set INCREMENTAL_MODE to poll or listen")
+
+	// errIncrementalUnsupported is returned when incremental mode is on but
+	// the configured location store cannot follow new locations
+	errIncrementalUnsupported = errors.New("incremental mode is not supported by the configured location store")
+)
+
+// incrementalEnabled reports whether cfg turns incremental mode on
+func incrementalEnabled(cfg *config.Config) bool {
+	return cfg.IncrementalMode == "poll" || cfg.IncrementalMode == "listen"
+}
+
+// GetLiveState returns the running aggregates and trip state of each device
+// for its current day
+func (s *Server) GetLiveState(_ context.Context, req *distancev1.GetLiveStateRequest) (*distancev1.GetLiveStateResponse, error) {
+	if s.live == nil {
+		if incrementalEnabled(s.cfg) {
+			return nil, errIncrementalUnsupported
+		}
+		return nil, errIncrementalDisabled
+	}
+	return s.live.snapshot(req.DeviceId), nil
+}
+
+// RunIncremental follows new locations until ctx ends, applying them to the
+// live state and storing each device's running daily summary. It wakes
+// every IncrementalPollInterval and, in listen mode, on each notification.
+// It returns nil at once when incremental mode is off.
+func (s *Server) RunIncremental(ctx context.Context) error {
+	if s.live == nil {
+		if incrementalEnabled(s.cfg) {
+			return errIncrementalUnsupported
+		}
+		return nil
+	}
+
+	var wake <-chan struct{}
+	if s.cfg.IncrementalMode == "listen" {
+		if s.notifier == nil {
+			log.Warn().Msg("Location store cannot LISTEN, incremental mode will poll only")
+		} else if notify, err := s.notifier.ListenLocations(ctx, s.cfg.IncrementalNotifyChannel); err != nil {
+			log.Warn().Err(err).Msg("Failed to LISTEN for new locations, incremental mode will poll only")
+		} else {
+			wake = notify
+		}
+	}
+
+	log.Info().
+		Str("mode", s.cfg.IncrementalMode).
+		Dur("poll_interval", s.cfg.IncrementalPollInterval).
+		Msg("Incremental mode started")
+
+	ticker := time.NewTicker(s.cfg.IncrementalPollInterval)
+	defer ticker.Stop()
+
+	bootstrapped := false
+	for {
+		if !bootstrapped {
+			if err := s.bootstrapLive(ctx); err != nil {
+				if ctx.Err() == nil {
+					log.Warn().Err(err).Msg("Failed to load today's locations, retrying")
+				}
+			} else {
+				bootstrapped = true
+			}
+		}
+		if bootstrapped {
+			if err := s.pollLive(ctx); err != nil && ctx.Err() == nil {
+				log.Warn().Err(err).Msg("Failed to apply new locations, retrying")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Incremental mode stopped")
+			return nil
+		case <-ticker.C:
+		case <-wake:
+		}
+	}
+}
+
+// bootstrapLive rebuilds the live state from today's locations up to the
+// current latest id, which becomes the cursor for pollLive
+func (s *Server) bootstrapLive(ctx context.Context) error {
+	latest, err := s.follower.LatestLocationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	today := s.live.now().Format(time.DateOnly)
+	s.live.reset(today)
+
+	// Rows after latest are left for pollLive, so none is applied twice
+	err = s.store.StreamLocationsByDate(ctx, today, "", func(loc database.Location) error {
+		if loc.ID <= latest {
+			s.live.apply(loc)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read today's locations: %w", err)
+	}
+	s.live.setCursor(latest)
+
+	log.Info().
+		Str("date", today).
+		Int64("last_location_id", latest).
+		Msg("Live state loaded")
+
+	return s.storeLive(ctx)
+}
+
+// pollLive applies the locations inserted since the cursor and stores the
+// summaries they changed
+func (s *Server) pollLive(ctx context.Context) error {
+	applied := 0
+	err := s.follower.StreamLocationsAfter(ctx, s.live.cursor(), func(loc database.Location) error {
+		s.live.apply(loc)
+		applied++
+		return nil
+	})
+
+	// Whatever was applied before a failure is still stored; the cursor
+	// only covers applied rows, so the rest are read next time
+	storeErr := s.storeLive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read new locations: %w", err)
+	}
+
+	if applied > 0 {
+		log.Debug().Int("applied", applied).Int64("last_location_id", s.live.cursor()).Msg("Applied new locations")
+	}
+	return storeErr
+}
+
+// storeLive upserts the daily summaries changed since they were last stored
+func (s *Server) storeLive(ctx context.Context) error {
+	if s.summaries != nil {
+		for _, summary := range s.live.pending() {
+			if err := s.summaries.UpsertDailySummary(ctx, summary); err != nil {
+				return fmt.Errorf("failed to store live summary for %s on %s: %w", summary.DeviceID, summary.Date, err)
+			}
+		}
+	}
+	s.live.markStored()
+	return nil
+}
+
+// liveState holds each device's running aggregates and trip state for its
+// current day. Only the incremental loop writes it; GetLiveState reads it
+// concurrently.
+type liveState struct {
+	homeLat, homeLon float64
+	awayThresholdKM  float64
+	now              func() time.Time
+
+	mu        sync.RWMutex
+	since     string // first day tracked; earlier rows are ignored
+	lastID    int64  // highest location id applied
+	updatedAt time.Time
+	devices   map[string]*liveDevice
+	finished  []database.DailySummary // days that ended before they were stored
+}
+
+// liveDevice is one device's state for its current day
+type liveDevice struct {
+	date     string
+	day      *calculator.DaySummarizer
+	track    *calculator.TrackAnalyzer
+	last     database.Location
+	fromHome float64
+	mode     calculator.MovementMode // of the latest fix TrackAnalyzer has finalized
+	dirty    bool                    // changed since the summary was stored
+}
+
+// newLiveState creates an empty live state measuring from the configured home
+func newLiveState(cfg *config.Config) *liveState {
+	return &liveState{
+		homeLat:         cfg.HomeLatitude,
+		homeLon:         cfg.HomeLongitude,
+		awayThresholdKM: cfg.AwayThresholdKM,
+		now:             time.Now,
+		devices:         make(map[string]*liveDevice),
+	}
+}
+
+// reset discards all state and starts tracking from since
+func (l *liveState) reset(since string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.since = since
+	l.lastID = 0
+	l.devices = make(map[string]*liveDevice)
+	l.finished = nil
+}
+
+// cursor returns the highest location id applied
+func (l *liveState) cursor() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.lastID
+}
+
+// setCursor moves the cursor forward to id
+func (l *liveState) setCursor(id int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastID = max(l.lastID, id)
+}
+
+// apply adds the next location in id order. A location on a later day than
+// its device's current day starts a new day; locations from earlier days
+// are ignored, since the backfill job owns complete past days.
+func (l *liveState) apply(loc database.Location) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lastID = max(l.lastID, loc.ID)
+	l.updatedAt = l.now()
+
+	// Days follow DATE(created_at), as in the distance and backfill jobs
+	date := loc.CreatedAt.Format(time.DateOnly)
+	if date < l.since {
+		return
+	}
+
+	device, ok := l.devices[loc.DeviceID]
+	if ok && date < device.date {
+		return
+	}
+	if ok && date > device.date {
+		if device.dirty {
+			l.finished = append(l.finished, dailySummaryRow(loc.DeviceID, device.date, device.day.Summary()))
+		}
+		ok = false
+	}
+	if !ok {
+		device = &liveDevice{
+			date:  date,
+			day:   calculator.NewDaySummarizer(l.homeLat, l.homeLon, l.awayThresholdKM),
+			track: calculator.NewTrackAnalyzer(l.homeLat, l.homeLon),
+		}
+		l.devices[loc.DeviceID] = device
+	}
+
+	fix := toCalculatorLocation(loc)
+	device.day.Add(fix)
+	// A fix older than the device's last one is rejected and keeps the mode
+	if points, err := device.track.Push(fix); err == nil {
+		for _, point := range points {
+			device.mode = point.Mode
+		}
+	}
+	device.last = loc
+	device.fromHome = calculator.DistanceFromHome(l.homeLat, l.homeLon, loc.Latitude, loc.Longitude)
+	device.dirty = true
+}
+
+// pending returns the summaries changed since markStored was last called
+func (l *liveState) pending() []database.DailySummary {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	summaries := append([]database.DailySummary(nil), l.finished...)
+	for deviceID, device := range l.devices {
+		if device.dirty {
+			summaries = append(summaries, dailySummaryRow(deviceID, device.date, device.day.Summary()))
+		}
+	}
+	return summaries
+}
+
+// markStored records that every pending summary has been stored
+func (l *liveState) markStored() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.finished = nil
+	for _, device := range l.devices {
+		device.dirty = false
+	}
+}
+
+// snapshot returns the state of every device, or only deviceID if set
+func (l *liveState) snapshot(deviceID string) *distancev1.GetLiveStateResponse {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	resp := &distancev1.GetLiveStateResponse{LastLocationId: l.lastID}
+	if !l.updatedAt.IsZero() {
+		resp.UpdatedAt = timestamppb.New(l.updatedAt)
+	}
+
+	for id, device := range l.devices {
+		if deviceID != "" && id != deviceID {
+			continue
+		}
+		resp.Devices = append(resp.Devices, l.deviceToProto(id, device))
+	}
+	sort.Slice(resp.Devices, func(i, j int) bool { return resp.Devices[i].DeviceId < resp.Devices[j].DeviceId })
+	return resp
+}
+
+// deviceToProto converts a device's live state to its protobuf form
+func (l *liveState) deviceToProto(deviceID string, device *liveDevice) *distancev1.DeviceLiveState {
+	day := device.day.Summary()
+	lastFix := fixTime(device.last)
+
+	state := &distancev1.DeviceLiveState{
+		DeviceId: deviceID,
+		Summary: &distancev1.DailySummary{
+			DeviceId:        deviceID,
+			Date:            device.date,
+			PathDistanceKm:  day.PathDistanceKM,
+			MaxDistanceKm:   day.MaxDistanceKM,
+			MinDistanceKm:   day.MinDistanceKM,
+			AvgDistanceKm:   day.AvgDistanceKM,
+			PointCount:      int32(day.PointCount), // #nosec G115 -- bounded by locations per device per day
+			TimeAwaySeconds: int64(day.TimeAway.Seconds()),
+			UpdatedAt:       timestamppb.New(lastFix),
+		},
+		LastFixTime:        timestamppb.New(lastFix),
+		Latitude:           device.last.Latitude,
+		Longitude:          device.last.Longitude,
+		DistanceFromHomeKm: device.fromHome,
+		Away:               device.fromHome > l.awayThresholdKM,
+		Mode:               string(device.mode),
+		TripsCompleted:     int32(len(device.track.Summary().Trips)), // #nosec G115 -- bounded by trips per day
+	}
+
+	if trip, ok := device.track.CurrentTrip(); ok {
+		state.CurrentTrip = &distancev1.LiveTrip{
+			StartTime:    timestamppb.New(trip.StartTime),
+			LastMoveTime: timestamppb.New(trip.EndTime),
+			DistanceKm:   trip.DistanceKM,
+		}
+	}
+	return state
+}
@@ -0,0 +1,203 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/config"
+	"github.com/stuartshay/otel-worker/internal/database"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// newLiveServer creates a server in poll mode over store whose clock reads now
+func newLiveServer(t *testing.T, store database.LocationStore, now time.Time) *Server {
+	t.Helper()
+
+	cfg := &config.Config{
+		HomeLatitude:            40.736097,
+		HomeLongitude:           -74.039373,
+		AwayThresholdKM:         0.5,
+		CSVOutputPath:           t.TempDir(),
+		IncrementalMode:         "poll",
+		IncrementalPollInterval: 10 * time.Millisecond,
+	}
+
+	server, err := NewServer(cfg, store)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	if server.live == nil {
+		t.Fatal("expected live state in poll mode")
+	}
+	server.live.now = func() time.Time { return now }
+	t.Cleanup(func() { _ = server.Shutdown(5 * time.Second) })
+	return server
+}
+
+// renumber gives locations consecutive ids starting at first
+func renumber(locations []database.Location, first int64) []database.Location {
+	for i := range locations {
+		locations[i].ID = first + int64(i)
+	}
+	return locations
+}
+
+func TestLiveState_BootstrapAndPoll(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	walk := walkFromHome("pixel8", start, 20)
+	store := database.NewMemoryStore(walk[:10]...)
+	server := newLiveServer(t, store, start.Add(time.Hour))
+	ctx := context.Background()
+
+	if err := server.bootstrapLive(ctx); err != nil {
+		t.Fatalf("bootstrapLive failed: %v", err)
+	}
+
+	state, err := server.GetLiveState(ctx, &distancev1.GetLiveStateRequest{})
+	if err != nil {
+		t.Fatalf("GetLiveState failed: %v", err)
+	}
+	if state.LastLocationId != 10 || len(state.Devices) != 1 {
+		t.Fatalf("expected 1 device at id 10, got %d devices at id %d", len(state.Devices), state.LastLocationId)
+	}
+	if got := state.Devices[0].Summary.PointCount; got != 10 {
+		t.Errorf("expected 10 points after bootstrap, got %d", got)
+	}
+
+	// New rows are picked up by the next poll and the stored summary follows
+	store.Add(walk[10:]...)
+	if err := server.pollLive(ctx); err != nil {
+		t.Fatalf("pollLive failed: %v", err)
+	}
+
+	state, err = server.GetLiveState(ctx, &distancev1.GetLiveStateRequest{DeviceId: "pixel8"})
+	if err != nil {
+		t.Fatalf("GetLiveState failed: %v", err)
+	}
+	device := state.Devices[0]
+	if state.LastLocationId != 20 || device.Summary.PointCount != 20 {
+		t.Errorf("expected 20 points at id 20, got %d at id %d", device.Summary.PointCount, state.LastLocationId)
+	}
+	if !device.Away || device.DistanceFromHomeKm < 2 {
+		t.Errorf("expected device away over 2 km, got away=%v at %.2f km", device.Away, device.DistanceFromHomeKm)
+	}
+	if device.Latitude != walk[19].Latitude {
+		t.Errorf("expected latest fix latitude %f, got %f", walk[19].Latitude, device.Latitude)
+	}
+
+	summaries, err := store.GetDailySummaries(ctx, "2026-01-24", "2026-01-24", "pixel8")
+	if err != nil {
+		t.Fatalf("GetDailySummaries failed: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].PointCount != 20 {
+		t.Errorf("expected stored summary of 20 points, got %+v", summaries)
+	}
+
+	state, err = server.GetLiveState(ctx, &distancev1.GetLiveStateRequest{DeviceId: "other"})
+	if err != nil {
+		t.Fatalf("GetLiveState failed: %v", err)
+	}
+	if len(state.Devices) != 0 {
+		t.Errorf("expected no devices for unknown device, got %d", len(state.Devices))
+	}
+}
+
+func TestLiveState_DayRollover(t *testing.T) {
+	start := time.Date(2026, 1, 24, 22, 0, 0, 0, time.UTC)
+	yesterday := walkFromHome("pixel8", start.AddDate(0, 0, -1), 3)
+	today := renumber(walkFromHome("pixel8", start, 6), 4)
+	tomorrow := renumber(walkFromHome("pixel8", start.Add(3*time.Hour), 2), 10)
+
+	store := database.NewMemoryStore(append(yesterday, today[:5]...)...)
+	server := newLiveServer(t, store, start)
+	ctx := context.Background()
+
+	if err := server.bootstrapLive(ctx); err != nil {
+		t.Fatalf("bootstrapLive failed: %v", err)
+	}
+	if got := server.live.snapshot("").Devices[0].Summary.PointCount; got != 5 {
+		t.Errorf("expected only today's 5 points, got %d", got)
+	}
+
+	// Apply a late fix and the first one after midnight without storing, as
+	// if the store failed, so the finished day must still be written later
+	server.live.apply(today[5])
+	server.live.apply(tomorrow[0])
+	pending := server.live.pending()
+	if len(pending) != 2 || pending[0].Date != "2026-01-24" || pending[0].PointCount != 6 {
+		t.Fatalf("expected finished day then new day pending, got %+v", pending)
+	}
+
+	store.Add(today[5])
+	store.Add(tomorrow...)
+	if err := server.pollLive(ctx); err != nil {
+		t.Fatalf("pollLive failed: %v", err)
+	}
+
+	state := server.live.snapshot("pixel8")
+	if got := state.Devices[0].Summary; got.Date != "2026-01-25" || got.PointCount != 2 {
+		t.Errorf("expected 2 points on 2026-01-25, got %d on %s", got.PointCount, got.Date)
+	}
+
+	summaries, err := store.GetDailySummaries(ctx, "2026-01-23", "2026-01-25", "pixel8")
+	if err != nil {
+		t.Fatalf("GetDailySummaries failed: %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].Date != "2026-01-24" || summaries[1].Date != "2026-01-25" {
+		t.Errorf("expected summaries for 2026-01-24 and 2026-01-25 only, got %+v", summaries)
+	}
+}
+
+func TestRunIncremental(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	walk := walkFromHome("pixel8", start, 10)
+	store := database.NewMemoryStore(walk[:5]...)
+	server := newLiveServer(t, store, start)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.RunIncremental(ctx) }()
+
+	store.Add(walk[5:]...)
+	deadline := time.Now().Add(5 * time.Second)
+	for server.live.cursor() < 10 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for new locations, cursor at %d", server.live.cursor())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected nil after cancel, got %v", err)
+	}
+}
+
+func TestGetLiveState_Disabled(t *testing.T) {
+	server := newMemoryServer(t, database.NewMemoryStore())
+	ctx := context.Background()
+
+	_, err := server.GetLiveState(ctx, &distancev1.GetLiveStateRequest{})
+	if !errors.Is(err, errIncrementalDisabled) {
+		t.Errorf("expected errIncrementalDisabled, got %v", err)
+	}
+	if err := server.RunIncremental(ctx); err != nil {
+		t.Errorf("expected RunIncremental to return nil when off, got %v", err)
+	}
+
+	// A store that cannot follow new locations cannot serve live state
+	cfg := *server.cfg
+	cfg.IncrementalMode = "poll"
+	unsupported, err := NewServer(&cfg, locationsOnly{database.NewMemoryStore()})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	defer func() { _ = unsupported.Shutdown(5 * time.Second) }()
+
+	_, err = unsupported.GetLiveState(ctx, &distancev1.GetLiveStateRequest{})
+	if !errors.Is(err, errIncrementalUnsupported) {
+		t.Errorf("expected errIncrementalUnsupported, got %v", err)
+	}
+}
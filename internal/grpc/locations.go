@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// StreamLocations streams the locations in a date range as they are read
+// from the store, so large ranges are never held in memory
+func (s *Server) StreamLocations(req *distancev1.StreamLocationsRequest, stream grpc.ServerStreamingServer[distancev1.LocationRecord]) error {
+	log.Info().
+		Str("start_date", req.StartDate).
+		Str("end_date", req.EndDate).
+		Str("device_id", req.DeviceId).
+		Bool("include_payload", req.IncludePayload).
+		Msg("Received stream locations request")
+
+	if err := validateDateRange(req.StartDate, req.EndDate); err != nil {
+		return err
+	}
+	// The store only reads raw_payload when INCLUDE_RAW_PAYLOAD is set, so
+	// payloads cannot be served per request without it
+	if req.IncludePayload && !s.cfg.IncludeRawPayload {
+		return status.Error(codes.FailedPrecondition, "include_payload requires INCLUDE_RAW_PAYLOAD to be enabled")
+	}
+
+	sent := 0
+	err := s.store.StreamLocationsByDateRange(stream.Context(), req.StartDate, req.EndDate, req.DeviceId, func(loc database.Location) error {
+		record := locationToProto(loc)
+		if req.IncludePayload && loc.Payload != nil {
+			record.Payload = payloadToProto(loc.Payload)
+		}
+		sent++
+		return stream.Send(record)
+	})
+	if err != nil {
+		log.Error().Err(err).Int("sent", sent).Msg("Failed to stream locations")
+		return err
+	}
+
+	log.Info().Int("sent", sent).Msg("Locations streamed")
+	return nil
+}
+
+// locationToProto converts a location to its protobuf form
+func locationToProto(loc database.Location) *distancev1.LocationRecord {
+	record := &distancev1.LocationRecord{
+		Id:             loc.ID,
+		DeviceId:       loc.DeviceID,
+		Tid:            loc.TID,
+		Latitude:       loc.Latitude,
+		Longitude:      loc.Longitude,
+		Accuracy:       int32(loc.Accuracy), // #nosec G115 -- meters, bounded by GPS hardware
+		Altitude:       loc.Altitude,
+		Velocity:       int32(loc.Velocity),      // #nosec G115 -- km/h, bounded by GPS hardware
+		Battery:        int32(loc.Battery),       // #nosec G115 -- percent
+		BatteryStatus:  int32(loc.BatteryStatus), // #nosec G115 -- enum 1-4
+		ConnectionType: loc.ConnectionType,
+		Trigger:        loc.Trigger,
+		CreatedAt:      timestamppb.New(loc.CreatedAt),
+	}
+	if loc.Timestamp > 0 {
+		record.Timestamp = timestamppb.New(time.Unix(loc.Timestamp, 0))
+	}
+	return record
+}
+
+// payloadToProto converts a decoded raw payload to its protobuf form
+func payloadToProto(p *database.RawPayload) *distancev1.LocationPayload {
+	payload := &distancev1.LocationPayload{
+		InRegions:         p.InRegions,
+		Ssid:              p.SSID,
+		Bssid:             p.BSSID,
+		VerticalAccuracyM: int32(p.VerticalAccuracy), // #nosec G115 -- meters, bounded by GPS hardware
+		PressureKpa:       p.PressureKPa,
+		MonitoringMode:    int32(p.MonitoringMode), // #nosec G115 -- enum 1-2
+	}
+	if p.CourseDeg != nil {
+		course := int32(*p.CourseDeg) // #nosec G115 -- degrees, 0-360
+		payload.CourseDeg = &course
+	}
+	return payload
+}
@@ -0,0 +1,154 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// recordStream collects the records sent by a server-streaming handler
+type recordStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	records []*distancev1.LocationRecord
+}
+
+func (s *recordStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *recordStream) Send(record *distancev1.LocationRecord) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+// payloadFixture returns a walk whose second fix carries a decoded payload
+func payloadFixture() *database.MemoryStore {
+	locations := walkFromHome("pixel8", time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC), 3)
+	course := 90
+	locations[1].Payload = &database.RawPayload{
+		InRegions:   []string{"home", "gym"},
+		SSID:        "homenet",
+		CourseDeg:   &course,
+		PressureKPa: 101.325,
+	}
+	return database.NewMemoryStore(locations...)
+}
+
+func TestStreamLocations(t *testing.T) {
+	server := newMemoryServer(t, payloadFixture())
+	server.cfg.IncludeRawPayload = true
+
+	stream := &recordStream{ctx: context.Background()}
+	err := server.StreamLocations(&distancev1.StreamLocationsRequest{
+		StartDate:      "2026-01-24",
+		EndDate:        "2026-01-24",
+		IncludePayload: true,
+	}, stream)
+	if err != nil {
+		t.Fatalf("StreamLocations failed: %v", err)
+	}
+
+	if len(stream.records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(stream.records))
+	}
+	if stream.records[0].DeviceId != "pixel8" || stream.records[0].Payload != nil {
+		t.Errorf("unexpected first record %+v", stream.records[0])
+	}
+	if p := stream.records[1].Payload; p == nil || p.Ssid != "homenet" || p.GetCourseDeg() != 90 || len(p.InRegions) != 2 {
+		t.Errorf("expected decoded payload on second record, got %+v", p)
+	}
+
+	// Payloads are omitted unless requested
+	stream = &recordStream{ctx: context.Background()}
+	if err := server.StreamLocations(&distancev1.StreamLocationsRequest{StartDate: "2026-01-24", EndDate: "2026-01-24"}, stream); err != nil {
+		t.Fatalf("StreamLocations failed: %v", err)
+	}
+	if stream.records[1].Payload != nil {
+		t.Error("expected no payload when not requested")
+	}
+
+	if err := server.StreamLocations(&distancev1.StreamLocationsRequest{StartDate: "2026-01-24"}, stream); err == nil {
+		t.Error("expected error for missing end_date, got nil")
+	}
+}
+
+func TestStreamLocations_PayloadsDisabled(t *testing.T) {
+	server := newMemoryServer(t, payloadFixture())
+
+	stream := &recordStream{ctx: context.Background()}
+	err := server.StreamLocations(&distancev1.StreamLocationsRequest{
+		StartDate:      "2026-01-24",
+		EndDate:        "2026-01-24",
+		IncludePayload: true,
+	}, stream)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+	if len(stream.records) != 0 {
+		t.Errorf("expected no records, got %d", len(stream.records))
+	}
+
+	// Streams without payloads still work
+	if err := server.StreamLocations(&distancev1.StreamLocationsRequest{StartDate: "2026-01-24", EndDate: "2026-01-24"}, stream); err != nil {
+		t.Fatalf("StreamLocations failed: %v", err)
+	}
+	if len(stream.records) != 3 {
+		t.Errorf("expected 3 records, got %d", len(stream.records))
+	}
+}
+
+func TestPayloadToProto_Course(t *testing.T) {
+	north := 0
+	if p := payloadToProto(&database.RawPayload{CourseDeg: &north}); p.CourseDeg == nil || *p.CourseDeg != 0 {
+		t.Errorf("expected a course of 0, got %v", p.CourseDeg)
+	}
+	if p := payloadToProto(&database.RawPayload{}); p.CourseDeg != nil {
+		t.Errorf("expected no course, got %d", *p.CourseDeg)
+	}
+}
+
+func TestProcessDistanceJob_PayloadColumns(t *testing.T) {
+	server := newMemoryServer(t, payloadFixture())
+	server.cfg.IncludeRawPayload = true
+
+	result, err := server.processDistanceJob(context.Background(), &queue.Job{ID: "job-1", Date: "2026-01-24"})
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+
+	content, err := readArtifact(t, server, result.CSVPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if !strings.HasSuffix(lines[0], ",regions,ssid,bssid,course_deg,pressure_kpa") {
+		t.Errorf("expected payload columns in header %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ",,,,,") {
+		t.Errorf("expected empty payload columns without a payload, got %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], ",home;gym,homenet,,90,101.325") {
+		t.Errorf("unexpected payload columns %q", lines[2])
+	}
+}
+
+func TestPayloadColumns_DueNorth(t *testing.T) {
+	north := 0
+	cells := payloadColumns(&database.RawPayload{CourseDeg: &north})
+	if got := cells[3].text; got != "0" {
+		t.Errorf("expected a course of 0, got %q", got)
+	}
+	if got := payloadColumns(&database.RawPayload{})[3].text; got != "" {
+		t.Errorf("expected an empty course, got %q", got)
+	}
+}
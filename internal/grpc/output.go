@@ -0,0 +1,355 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	"github.com/stuartshay/otel-worker/internal/storage"
+)
+
+// Output formats a distance job can produce
+const (
+	formatCSV     = "csv"
+	formatGPX     = "gpx"
+	formatGeoJSON = "geojson"
+	formatKML     = "kml"
+	formatParquet = "parquet"
+)
+
+// outputFormats lists the supported output formats
+var outputFormats = []string{formatCSV, formatGPX, formatGeoJSON, formatKML, formatParquet}
+
+// parseOutputFormats validates the requested output formats, dropping
+// duplicates. No formats selects CSV alone.
+func parseOutputFormats(formats []string) ([]string, error) {
+	var parsed []string
+	for _, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if !slices.Contains(outputFormats, format) {
+			return nil, fmt.Errorf("invalid output format %q: want one of %s", format, strings.Join(outputFormats, ", "))
+		}
+		if !slices.Contains(parsed, format) {
+			parsed = append(parsed, format)
+		}
+	}
+	if len(parsed) == 0 {
+		return []string{formatCSV}, nil
+	}
+	return parsed, nil
+}
+
+// distanceTitle names a distance job's output in the map formats, e.g.
+// "Distance from home 2026-01-24 pixel8"
+func distanceTitle(job *queue.Job) string {
+	title := "Distance from home " + job.Date
+	if job.DeviceID != "" {
+		title += " " + job.DeviceID
+	}
+	return title
+}
+
+// trackPoint is a fix in the form the map format writers take
+type trackPoint struct {
+	DeviceID  string
+	Latitude  float64
+	Longitude float64
+	Altitude  float64
+	Time      time.Time
+	Accuracy  int // meters; 0 when not reported
+	Battery   int // percent; 0 when not reported
+}
+
+// locationTrackPoint converts an OwnTracks location to a track point
+func locationTrackPoint(loc database.Location) trackPoint {
+	return trackPoint{
+		DeviceID:  loc.DeviceID,
+		Latitude:  loc.Latitude,
+		Longitude: loc.Longitude,
+		Altitude:  loc.Altitude,
+		Time:      fixTime(loc),
+		Accuracy:  loc.Accuracy,
+		Battery:   loc.Battery,
+	}
+}
+
+// gpsTrackPoint converts a multi-source GPS point to a track point
+func gpsTrackPoint(p database.GPSPoint) trackPoint {
+	return trackPoint{
+		DeviceID:  p.DeviceID,
+		Latitude:  p.Latitude,
+		Longitude: p.Longitude,
+		Altitude:  p.Altitude,
+		Time:      p.Timestamp,
+		Accuracy:  p.Accuracy,
+		Battery:   p.Battery,
+	}
+}
+
+// trackWriter writes one output format of a distance job as its points are
+// analyzed
+type trackWriter[T any] interface {
+	write(row T, point calculator.AnalyzedPoint) error
+	// finish completes the file and returns what was stored
+	finish(summary calculator.TrackSummary) (storage.Info, error)
+	// abort discards the partially written file
+	abort()
+}
+
+// trackOutputs writes every requested output format of a distance job
+type trackOutputs[T any] struct {
+	ctx       context.Context
+	artifacts storage.Store
+	formats   []string
+	writers   []trackWriter[T]
+}
+
+// trackOutputSpec describes how rows of type T are written in each format
+type trackOutputSpec[T any] struct {
+	name        string   // file name without extension
+	csvHeader   []string // every CSV column, before the template selects them
+	csvRow      func(T, calculator.AnalyzedPoint) []csvCell
+	csvTemplate *queue.CSVTemplate
+	point       func(T) trackPoint
+	title       string // human readable name for the map formats
+}
+
+// createTrackOutputs creates one writer per format in the artifact store.
+// Jobs queued without formats write CSV. A CSV template with a separate
+// footer adds a csv_summary file after the requested formats.
+func createTrackOutputs[T any](ctx context.Context, s *Server, formats []string, spec trackOutputSpec[T]) (*trackOutputs[T], error) {
+	if len(formats) == 0 {
+		formats = []string{formatCSV}
+	}
+	template, err := newCSVTemplate(spec.csvTemplate, spec.csvHeader)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(formats, formatCSV) && template.footer == footerSeparate {
+		formats = append(slices.Clone(formats), formatCSVSummary)
+	}
+
+	outputs := &trackOutputs[T]{ctx: ctx, artifacts: s.artifacts, formats: formats}
+	for _, format := range formats {
+		var writer trackWriter[T]
+		var err error
+		switch format {
+		case formatCSV:
+			var report *csvReport
+			report, err = s.createDelimitedReport(ctx, spec.name+".csv", template.header, template.delimiter)
+			writer = &csvTrackWriter[T]{report: report, row: spec.csvRow, template: template}
+		case formatCSVSummary:
+			var report *csvReport
+			report, err = s.createDelimitedReport(ctx, spec.name+"_summary.csv", csvSummaryHeader, template.delimiter)
+			writer = &csvSummaryWriter[T]{report: report, template: template}
+		case formatGPX:
+			var report *gpxReport
+			report, err = s.createGPXReport(ctx, spec.name+".gpx", spec.title)
+			writer = &gpxTrackWriter[T]{report: report, point: spec.point}
+		case formatGeoJSON:
+			var report *geoJSONReport
+			report, err = s.createGeoJSONReport(ctx, spec.name+".geojson", spec.title)
+			writer = &mapTrackWriter[T]{geometry: s.trackGeometry(), report: report}
+		case formatKML:
+			var report *kmlReport
+			report, err = s.createKMLReport(ctx, spec.name+".kml", spec.title)
+			writer = &mapTrackWriter[T]{geometry: s.trackGeometry(), report: report}
+		case formatParquet:
+			var report *parquetReport
+			report, err = s.createParquetReport(ctx, spec.name+".parquet", spec.title)
+			writer = &parquetTrackWriter[T]{report: report, point: spec.point}
+		default:
+			err = fmt.Errorf("unsupported output format %q", format)
+		}
+		if err != nil {
+			outputs.abort()
+			return nil, err
+		}
+		outputs.writers = append(outputs.writers, writer)
+	}
+	return outputs, nil
+}
+
+// write passes an analyzed row to every writer
+func (o *trackOutputs[T]) write(row T, point calculator.AnalyzedPoint) error {
+	for _, w := range o.writers {
+		if err := w.write(row, point); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish completes every file and returns them in the order requested. On
+// error every file is deleted, so a failed job leaves no partial output.
+func (o *trackOutputs[T]) finish(summary calculator.TrackSummary) ([]queue.Artifact, error) {
+	artifacts := make([]queue.Artifact, 0, len(o.writers))
+	for i, w := range o.writers {
+		info, err := w.finish(summary)
+		if err != nil {
+			for _, done := range artifacts {
+				if err := o.artifacts.Delete(o.ctx, done.Key); err != nil {
+					log.Error().Err(err).Str("key", done.Key).Msg("Failed to delete output file")
+				}
+			}
+			for _, rest := range o.writers[i+1:] {
+				rest.abort()
+			}
+			return nil, err
+		}
+		artifacts = append(artifacts, newArtifact(o.formats[i], info))
+	}
+	return artifacts, nil
+}
+
+// newArtifact describes a stored file of a job
+func newArtifact(format string, info storage.Info) queue.Artifact {
+	return queue.Artifact{Format: format, Key: info.Key, Size: info.Size, SHA256: info.SHA256}
+}
+
+// artifactKey returns the key of the artifact in format, or "" when the job
+// did not produce one
+func artifactKey(artifacts []queue.Artifact, format string) string {
+	for _, a := range artifacts {
+		if a.Format == format {
+			return a.Key
+		}
+	}
+	return ""
+}
+
+// abort removes every partially written file
+func (o *trackOutputs[T]) abort() {
+	for _, w := range o.writers {
+		w.abort()
+	}
+}
+
+// csvTrackWriter writes a distance job's CSV report laid out by its template
+type csvTrackWriter[T any] struct {
+	report   *csvReport
+	row      func(T, calculator.AnalyzedPoint) []csvCell
+	template *csvTemplate
+}
+
+// write writes the CSV row for an analyzed row
+func (w *csvTrackWriter[T]) write(row T, point calculator.AnalyzedPoint) error {
+	return w.report.writeRow(w.template.row(w.row(row, point)))
+}
+
+// finish writes the summary footer, when inline, and closes the file
+func (w *csvTrackWriter[T]) finish(summary calculator.TrackSummary) (storage.Info, error) {
+	return w.report.finish(w.template.footerRows(distanceFooter(summary)))
+}
+
+// abort removes the partial CSV file
+func (w *csvTrackWriter[T]) abort() {
+	w.report.abort()
+}
+
+// csvSummaryHeader is the column header of a separate CSV summary
+var csvSummaryHeader = []string{"metric", "value"}
+
+// csvSummaryWriter writes a distance job's summary as its own CSV file
+type csvSummaryWriter[T any] struct {
+	report   *csvReport
+	template *csvTemplate
+}
+
+// write ignores the analyzed rows; only the summary is written
+func (w *csvSummaryWriter[T]) write(T, calculator.AnalyzedPoint) error {
+	return nil
+}
+
+// finish writes the summary rows and closes the file
+func (w *csvSummaryWriter[T]) finish(summary calculator.TrackSummary) (storage.Info, error) {
+	return w.report.finish(w.template.summary(distanceFooter(summary)))
+}
+
+// abort removes the partial summary file
+func (w *csvSummaryWriter[T]) abort() {
+	w.report.abort()
+}
+
+// gpxTrackWriter writes a distance job's GPX track
+type gpxTrackWriter[T any] struct {
+	report *gpxReport
+	point  func(T) trackPoint
+}
+
+// write adds the row's fix to its device's track
+func (w *gpxTrackWriter[T]) write(row T, _ calculator.AnalyzedPoint) error {
+	return w.report.writePoint(w.point(row))
+}
+
+// finish writes the tracks and closes the file
+func (w *gpxTrackWriter[T]) finish(_ calculator.TrackSummary) (storage.Info, error) {
+	return w.report.finish()
+}
+
+// abort removes the partial GPX file
+func (w *gpxTrackWriter[T]) abort() {
+	w.report.abort()
+}
+
+// parquetTrackWriter writes a distance job's analyzed fixes as Parquet
+type parquetTrackWriter[T any] struct {
+	report *parquetReport
+	point  func(T) trackPoint
+}
+
+// write adds the row's analyzed fix
+func (w *parquetTrackWriter[T]) write(row T, point calculator.AnalyzedPoint) error {
+	return w.report.writeRow(w.point(row), point)
+}
+
+// finish stores the summary as file metadata and closes the file
+func (w *parquetTrackWriter[T]) finish(summary calculator.TrackSummary) (storage.Info, error) {
+	return w.report.finish(summary)
+}
+
+// abort removes the partial Parquet file
+func (w *parquetTrackWriter[T]) abort() {
+	w.report.abort()
+}
+
+// mapReport is a GeoJSON or KML file written from the features of a track
+type mapReport interface {
+	finish(features []mapFeature) (storage.Info, error)
+	abort()
+}
+
+// mapTrackWriter writes a distance job's home, trips and stays as GeoJSON or
+// KML features once the track is complete
+type mapTrackWriter[T any] struct {
+	geometry *trackGeometry
+	report   mapReport
+}
+
+// write records the analyzed fix
+func (w *mapTrackWriter[T]) write(_ T, point calculator.AnalyzedPoint) error {
+	w.geometry.add(point)
+	return nil
+}
+
+// finish writes the features and closes the file
+func (w *mapTrackWriter[T]) finish(summary calculator.TrackSummary) (storage.Info, error) {
+	return w.report.finish(w.geometry.features(summary))
+}
+
+// abort removes the partial file
+func (w *mapTrackWriter[T]) abort() {
+	w.report.abort()
+}
+
+// trackGeometry returns an empty geometry around the configured home
+func (s *Server) trackGeometry() *trackGeometry {
+	return &trackGeometry{homeLat: s.cfg.HomeLatitude, homeLon: s.cfg.HomeLongitude}
+}
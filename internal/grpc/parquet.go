@@ -0,0 +1,198 @@
+package grpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/storage"
+)
+
+// Parquet file metadata keys
+const (
+	parquetTitleKey   = "otel_worker.title"
+	parquetSummaryKey = "otel_worker.summary"
+)
+
+// Rows are buffered and written in batches; a row group is closed every
+// parquetRowGroupSize rows so memory use does not grow with the day
+const (
+	parquetBatchSize    = 1024
+	parquetRowGroupSize = 64 * 1024
+)
+
+// parquetRow is one analyzed fix of a distance Parquet file
+type parquetRow struct {
+	Timestamp          time.Time `parquet:"timestamp,timestamp(microsecond)"`
+	DeviceID           string    `parquet:"device_id,dict"`
+	Latitude           float64   `parquet:"latitude"`
+	Longitude          float64   `parquet:"longitude"`
+	DistanceFromHomeKM float64   `parquet:"distance_from_home_km"`
+	Accuracy           int32     `parquet:"accuracy"`
+	Battery            int32     `parquet:"battery"`
+	SpeedKMH           float64   `parquet:"speed_kmh"`
+	AccelerationMS2    float64   `parquet:"acceleration_ms2"`
+	Mode               string    `parquet:"mode,dict"`
+	AltitudeM          float64   `parquet:"altitude_m"`
+	SmoothedAltitudeM  float64   `parquet:"smoothed_altitude_m"`
+	CumulativeAscentM  float64   `parquet:"cumulative_ascent_m"`
+}
+
+// parquetSummary is the track summary stored as JSON in the file metadata,
+// in place of the CSV footer rows
+type parquetSummary struct {
+	TotalDistanceKM float64              `json:"total_distance_km"`
+	MaxDistanceKM   float64              `json:"max_distance_km"`
+	MinDistanceKM   float64              `json:"min_distance_km"`
+	AvgDistanceKM   float64              `json:"avg_distance_km"`
+	TotalLocations  int                  `json:"total_locations"`
+	MaxSpeedKMH     float64              `json:"max_speed_kmh"`
+	AscentM         float64              `json:"ascent_m"`
+	DescentM        float64              `json:"descent_m"`
+	MinAltitudeM    float64              `json:"min_altitude_m"`
+	MaxAltitudeM    float64              `json:"max_altitude_m"`
+	TripCount       int                  `json:"trip_count"`
+	ModeTotals      []parquetModeSummary `json:"mode_totals"`
+}
+
+// parquetModeSummary is the distance and time spent in one movement mode
+type parquetModeSummary struct {
+	Mode            string  `json:"mode"`
+	DistanceKM      float64 `json:"distance_km"`
+	DurationSeconds int64   `json:"duration_seconds"`
+}
+
+// parquetReport writes a Parquet file of analyzed fixes as they arrive
+type parquetReport struct {
+	key    string
+	file   storage.Writer
+	buffer *bufio.Writer
+	writer *parquet.GenericWriter[parquetRow]
+	rows   []parquetRow
+}
+
+// createParquetReport creates the artifact filename; title is
+// stored in the file metadata
+func (s *Server) createParquetReport(ctx context.Context, filename, title string) (*parquetReport, error) {
+	file, err := s.artifacts.Create(ctx, filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet file: %w", err)
+	}
+
+	buffer := bufio.NewWriter(file)
+	writer := parquet.NewGenericWriter[parquetRow](buffer,
+		parquet.Compression(&parquet.Snappy),
+		parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+		parquet.KeyValueMetadata(parquetTitleKey, title),
+	)
+
+	return &parquetReport{
+		key:    filename,
+		file:   file,
+		buffer: buffer,
+		writer: writer,
+		rows:   make([]parquetRow, 0, parquetBatchSize),
+	}, nil
+}
+
+// writeRow adds an analyzed fix, writing the batch once it is full
+func (r *parquetReport) writeRow(p trackPoint, point calculator.AnalyzedPoint) error {
+	r.rows = append(r.rows, parquetRow{
+		Timestamp:          p.Time.UTC(),
+		DeviceID:           p.DeviceID,
+		Latitude:           p.Latitude,
+		Longitude:          p.Longitude,
+		DistanceFromHomeKM: point.DistanceFromHomeKM,
+		Accuracy:           int32(p.Accuracy), // #nosec G115 -- accuracy is meters
+		Battery:            int32(p.Battery),  // #nosec G115 -- battery is a percentage
+		SpeedKMH:           point.SpeedKMH,
+		AccelerationMS2:    point.AccelerationMS2,
+		Mode:               string(point.Mode),
+		AltitudeM:          p.Altitude,
+		SmoothedAltitudeM:  point.SmoothedAltitudeM,
+		CumulativeAscentM:  point.CumulativeAscentM,
+	})
+	if len(r.rows) < parquetBatchSize {
+		return nil
+	}
+	return r.flushRows()
+}
+
+// flushRows writes the buffered rows
+func (r *parquetReport) flushRows() error {
+	if _, err := r.writer.Write(r.rows); err != nil {
+		return fmt.Errorf("failed to write Parquet rows: %w", err)
+	}
+	r.rows = r.rows[:0]
+	return nil
+}
+
+// finish writes the remaining rows and the summary metadata and closes the
+// file
+func (r *parquetReport) finish(summary calculator.TrackSummary) (storage.Info, error) {
+	metadata, err := json.Marshal(newParquetSummary(summary))
+	if err != nil {
+		r.abort()
+		return storage.Info{}, fmt.Errorf("failed to encode Parquet summary: %w", err)
+	}
+	if err := r.flushRows(); err != nil {
+		r.abort()
+		return storage.Info{}, err
+	}
+	r.writer.SetKeyValueMetadata(parquetSummaryKey, string(metadata))
+	if err := r.writer.Close(); err != nil {
+		r.abort()
+		return storage.Info{}, fmt.Errorf("failed to write Parquet footer: %w", err)
+	}
+	if err := r.buffer.Flush(); err != nil {
+		r.abort()
+		return storage.Info{}, fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+	info, err := r.file.Commit()
+	if err != nil {
+		r.file.Abort()
+		return storage.Info{}, fmt.Errorf("failed to store Parquet file: %w", err)
+	}
+
+	log.Info().Str("parquet_key", r.key).Str("sha256", info.SHA256).Int("rows", summary.Metrics.TotalLocations).Msg("Parquet file generated successfully")
+
+	return info, nil
+}
+
+// abort discards a partially written report
+func (r *parquetReport) abort() {
+	r.file.Abort()
+}
+
+// newParquetSummary converts a track summary to its metadata form
+func newParquetSummary(summary calculator.TrackSummary) parquetSummary {
+	metrics, elevation := summary.Metrics, summary.Elevation
+	result := parquetSummary{
+		TotalDistanceKM: metrics.TotalDistanceKM,
+		MaxDistanceKM:   metrics.MaxDistanceKM,
+		MinDistanceKM:   metrics.MinDistanceKM,
+		AvgDistanceKM:   metrics.AvgDistanceKM,
+		TotalLocations:  metrics.TotalLocations,
+		MaxSpeedKMH:     summary.MaxSpeedKMH,
+		AscentM:         elevation.AscentM,
+		DescentM:        elevation.DescentM,
+		MinAltitudeM:    elevation.MinAltitudeM,
+		MaxAltitudeM:    elevation.MaxAltitudeM,
+		TripCount:       len(summary.Trips),
+		ModeTotals:      []parquetModeSummary{},
+	}
+	for _, mt := range summary.ModeTotals {
+		result.ModeTotals = append(result.ModeTotals, parquetModeSummary{
+			Mode:            string(mt.Mode),
+			DistanceKM:      mt.DistanceKM,
+			DurationSeconds: int64(mt.Duration.Seconds()),
+		})
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+)
+
+func TestProcessDistanceJob_Parquet(t *testing.T) {
+	start := time.Date(2026, 1, 24, 0, 0, 0, 0, time.UTC)
+	pixel := walkFromHome("pixel8", start, parquetBatchSize+5)
+	for i := range pixel {
+		pixel[i].Accuracy = 12
+	}
+	server := newMemoryServer(t, database.NewMemoryStore(pixel...))
+
+	job := &queue.Job{ID: "job-1", Date: "2026-01-24", OutputFormats: []string{formatParquet}}
+	result, err := server.processDistanceJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+	if len(result.Artifacts) != 1 || result.Artifacts[0].Key != "distance_20260124_job-1.parquet" {
+		t.Fatalf("unexpected artifacts %+v", result.Artifacts)
+	}
+
+	file, err := os.Open(filepath.Join(server.cfg.CSVOutputPath, result.Artifacts[0].Key))
+	if err != nil {
+		t.Fatalf("failed to open Parquet file: %v", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatalf("failed to stat Parquet file: %v", err)
+	}
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		t.Fatalf("invalid Parquet file: %v", err)
+	}
+
+	timestamp, ok := pf.Schema().Lookup("timestamp")
+	if !ok || timestamp.Node.Type().Kind() != parquet.Int64 {
+		t.Errorf("expected an INT64 timestamp column, got %+v", timestamp)
+	} else if lt := timestamp.Node.Type().LogicalType(); lt == nil || lt.Timestamp == nil || lt.Timestamp.Unit.Micros == nil {
+		t.Errorf("expected microsecond timestamps, got %+v", lt)
+	}
+	for _, column := range []string{"latitude", "longitude", "distance_from_home_km"} {
+		if leaf, ok := pf.Schema().Lookup(column); !ok || leaf.Node.Type().Kind() != parquet.Double {
+			t.Errorf("expected a DOUBLE %s column", column)
+		}
+	}
+
+	if title, _ := pf.Lookup(parquetTitleKey); title != "Distance from home 2026-01-24" {
+		t.Errorf("unexpected title %q", title)
+	}
+	raw, ok := pf.Lookup(parquetSummaryKey)
+	if !ok {
+		t.Fatal("expected summary metadata")
+	}
+	var summary parquetSummary
+	if err := json.Unmarshal([]byte(raw), &summary); err != nil {
+		t.Fatalf("invalid summary metadata: %v", err)
+	}
+	if summary.TotalLocations != len(pixel) || summary.TotalDistanceKM != result.TotalDistanceKM {
+		t.Errorf("summary %+v does not match result %+v", summary, result)
+	}
+
+	reader := parquet.NewGenericReader[parquetRow](file)
+	defer reader.Close()
+	rows := make([]parquetRow, len(pixel)+1)
+	n, err := reader.Read(rows)
+	if err != nil && err != io.EOF {
+		t.Fatalf("failed to read rows: %v", err)
+	}
+	if n != len(pixel) {
+		t.Fatalf("expected %d rows, got %d", len(pixel), n)
+	}
+	last := rows[n-1]
+	if !last.Timestamp.Equal(time.Unix(pixel[n-1].Timestamp, 0)) || last.DeviceID != "pixel8" || last.Accuracy != 12 {
+		t.Errorf("unexpected last row %+v", last)
+	}
+}
@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/database"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// qualityFields are the optional public.locations columns whose missing rate
+// is reported. NULL is read as the zero value, so only columns where zero or
+// empty is never a real reading are checked: altitude and velocity are
+// legitimately 0.
+var qualityFields = []string{"tid", "accuracy", "battery", "battery_status", "connection_type", "trigger", "timestamp"}
+
+// GetDataQualityReport returns coverage gaps, duplicate and out-of-order
+// fixes, missing field rates and the accuracy distribution for each device
+// over a date range. It reads the same rows as GetLocationsByDateRange,
+// streamed in storage order.
+func (s *Server) GetDataQualityReport(ctx context.Context, req *distancev1.GetDataQualityReportRequest) (*distancev1.GetDataQualityReportResponse, error) {
+	log.Info().
+		Str("start_date", req.StartDate).
+		Str("end_date", req.EndDate).
+		Str("device_id", req.DeviceId).
+		Int32("gap_threshold_minutes", req.GapThresholdMinutes).
+		Msg("Received data quality report request")
+
+	if err := validateDateRange(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+	if req.GapThresholdMinutes < 0 {
+		return nil, fmt.Errorf("gap_threshold_minutes must not be negative")
+	}
+	gapThreshold := time.Duration(req.GapThresholdMinutes) * time.Minute
+
+	analyzers := make(map[string]*calculator.QualityAnalyzer)
+	err := s.store.StreamLocationsByDateRange(ctx, req.StartDate, req.EndDate, req.DeviceId, func(loc database.Location) error {
+		analyzer, ok := analyzers[loc.DeviceID]
+		if !ok {
+			analyzer = calculator.NewQualityAnalyzer(gapThreshold)
+			analyzers[loc.DeviceID] = analyzer
+		}
+		analyzer.Add(calculator.QualityFix{
+			Timestamp: fixTime(loc),
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+			Accuracy:  loc.Accuracy,
+			Missing:   missingFields(loc),
+		})
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch locations from database")
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	deviceIDs := make([]string, 0, len(analyzers))
+	for deviceID := range analyzers {
+		deviceIDs = append(deviceIDs, deviceID)
+	}
+	sort.Strings(deviceIDs)
+
+	resp := &distancev1.GetDataQualityReportResponse{}
+	for _, deviceID := range deviceIDs {
+		resp.Devices = append(resp.Devices, qualityReportToProto(deviceID, analyzers[deviceID].Report()))
+	}
+
+	return resp, nil
+}
+
+// missingFields returns the qualityFields that are NULL or empty in loc
+func missingFields(loc database.Location) []string {
+	var missing []string
+	if loc.TID == "" {
+		missing = append(missing, "tid")
+	}
+	if loc.Accuracy == 0 {
+		missing = append(missing, "accuracy")
+	}
+	if loc.Battery == 0 {
+		missing = append(missing, "battery")
+	}
+	if loc.BatteryStatus == 0 {
+		missing = append(missing, "battery_status")
+	}
+	if loc.ConnectionType == "" {
+		missing = append(missing, "connection_type")
+	}
+	if loc.Trigger == "" {
+		missing = append(missing, "trigger")
+	}
+	if loc.Timestamp == 0 {
+		missing = append(missing, "timestamp")
+	}
+	return missing
+}
+
+// qualityReportToProto converts a calculator quality report to its protobuf form
+func qualityReportToProto(deviceID string, report calculator.QualityReport) *distancev1.DeviceDataQuality {
+	out := &distancev1.DeviceDataQuality{
+		DeviceId:        deviceID,
+		PointCount:      int32(report.Points), // #nosec G115 -- bounded by locations per device in range
+		FirstFixTime:    timestamppb.New(report.FirstFix),
+		LastFixTime:     timestamppb.New(report.LastFix),
+		GapSeconds:      int64(report.GapTotal.Seconds()),
+		DuplicateCount:  int32(report.Duplicates), // #nosec G115 -- bounded by point count
+		OutOfOrderCount: int32(report.OutOfOrder), // #nosec G115 -- bounded by point count
+		Accuracy: &distancev1.AccuracyDistribution{
+			ReportedCount: int32(report.Accuracy.Reported), // #nosec G115 -- bounded by point count
+			MedianM:       report.Accuracy.MedianM,
+			P90M:          report.Accuracy.P90M,
+			MaxM:          int32(report.Accuracy.MaxM), // #nosec G115 -- accuracy is an integer column
+		},
+	}
+
+	for _, gap := range report.Gaps {
+		out.Gaps = append(out.Gaps, &distancev1.CoverageGap{
+			StartTime:       timestamppb.New(gap.Start),
+			EndTime:         timestamppb.New(gap.End),
+			DurationSeconds: int64(gap.Duration().Seconds()),
+		})
+	}
+
+	for _, field := range qualityFields {
+		missing := report.Missing[field]
+		rate := 0.0
+		if report.Points > 0 {
+			rate = float64(missing) / float64(report.Points)
+		}
+		out.MissingFields = append(out.MissingFields, &distancev1.FieldMissingRate{
+			Field:        field,
+			MissingCount: int32(missing), // #nosec G115 -- bounded by point count
+			Rate:         rate,
+		})
+	}
+
+	for _, bucket := range report.Accuracy.Buckets {
+		out.Accuracy.Buckets = append(out.Accuracy.Buckets, &distancev1.AccuracyBucket{
+			MaxM:  int32(bucket.MaxM),  // #nosec G115 -- fixed bucket bounds
+			Count: int32(bucket.Count), // #nosec G115 -- bounded by point count
+		})
+	}
+
+	return out
+}
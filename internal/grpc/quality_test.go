@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+func TestGetDataQualityReport(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	walk := walkFromHome("pixel8", start, 6)
+	for i := range walk {
+		walk[i].TID = "p8"
+		walk[i].Accuracy = 10 * (i + 1)
+		walk[i].BatteryStatus = 2
+		walk[i].ConnectionType = "w"
+		walk[i].Trigger = "p"
+	}
+
+	// The phone went quiet for two hours, then a retried upload stored the
+	// last fix again
+	silent := 2 * time.Hour
+	for i := 3; i < len(walk); i++ {
+		walk[i].Timestamp += int64(silent.Seconds())
+		walk[i].CreatedAt = walk[i].CreatedAt.Add(silent)
+	}
+	retry := walk[5]
+	retry.ID = 7
+	retry.CreatedAt = retry.CreatedAt.Add(time.Minute)
+	walk[1].Trigger = ""
+
+	other := walkFromHome("iphone", start, 2)
+	store := database.NewMemoryStore(append(append(walk, retry), other...)...)
+	server := newMemoryServer(t, store)
+	ctx := context.Background()
+
+	resp, err := server.GetDataQualityReport(ctx, &distancev1.GetDataQualityReportRequest{
+		StartDate:           "2026-01-24",
+		EndDate:             "2026-01-24",
+		GapThresholdMinutes: 30,
+	})
+	if err != nil {
+		t.Fatalf("GetDataQualityReport failed: %v", err)
+	}
+	if len(resp.Devices) != 2 || resp.Devices[0].DeviceId != "iphone" || resp.Devices[1].DeviceId != "pixel8" {
+		t.Fatalf("expected iphone and pixel8, got %+v", resp.Devices)
+	}
+
+	device := resp.Devices[1]
+	if device.PointCount != 7 || device.DuplicateCount != 1 || device.OutOfOrderCount != 0 {
+		t.Errorf("expected 7 points with 1 duplicate, got %d points, %d duplicates, %d out of order",
+			device.PointCount, device.DuplicateCount, device.OutOfOrderCount)
+	}
+	if len(device.Gaps) != 1 || device.Gaps[0].DurationSeconds != int64((silent+time.Minute).Seconds()) {
+		t.Errorf("expected one gap of %s, got %+v", silent+time.Minute, device.Gaps)
+	}
+
+	missing := make(map[string]*distancev1.FieldMissingRate)
+	for _, field := range device.MissingFields {
+		missing[field.Field] = field
+	}
+	if len(missing) != len(qualityFields) {
+		t.Errorf("expected a rate for each of %v, got %d", qualityFields, len(missing))
+	}
+	if got := missing["trigger"]; got.MissingCount != 1 || got.Rate != 1.0/7 {
+		t.Errorf("expected trigger missing once in 7, got %+v", got)
+	}
+	if got := missing["tid"]; got.MissingCount != 0 {
+		t.Errorf("expected tid never missing, got %d", got.MissingCount)
+	}
+
+	if device.Accuracy.ReportedCount != 6 || device.Accuracy.MaxM != 60 || device.Accuracy.MedianM != 35 {
+		t.Errorf("unexpected accuracy distribution %+v", device.Accuracy)
+	}
+
+	// The other device reports no accuracy at all
+	if got := resp.Devices[0].Accuracy.ReportedCount; got != 0 {
+		t.Errorf("expected no accuracy for iphone, got %d", got)
+	}
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		_, err := server.GetDataQualityReport(ctx, &distancev1.GetDataQualityReportRequest{
+			StartDate:           "2026-01-24",
+			EndDate:             "2026-01-24",
+			GapThresholdMinutes: -1,
+		})
+		if err == nil {
+			t.Error("expected error for negative gap threshold")
+		}
+	})
+}
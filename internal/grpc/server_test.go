@@ -0,0 +1,435 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/config"
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// newMemoryServer creates a server over an in-memory store, writing CSV files
+// to a temporary directory
+func newMemoryServer(t *testing.T, store database.LocationStore) *Server {
+	t.Helper()
+
+	cfg := &config.Config{
+		HomeLatitude:    40.736097,
+		HomeLongitude:   -74.039373,
+		AwayThresholdKM: 0.5,
+		CSVOutputPath:   t.TempDir(),
+	}
+
+	server, err := NewServer(cfg, store)
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	t.Cleanup(func() { _ = server.Shutdown(5 * time.Second) })
+	return server
+}
+
+// readArtifact returns the content of a job output from the server's
+// artifact store
+func readArtifact(t *testing.T, server *Server, key string) ([]byte, error) {
+	t.Helper()
+	r, _, err := server.artifacts.Open(context.Background(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// walkFromHome returns fixes one minute apart heading north from home
+func walkFromHome(deviceID string, start time.Time, n int) []database.Location {
+	locations := make([]database.Location, n)
+	for i := range locations {
+		at := start.Add(time.Duration(i) * time.Minute)
+		locations[i] = database.Location{
+			ID:        int64(i + 1),
+			DeviceID:  deviceID,
+			Latitude:  40.736097 + float64(i)*0.001,
+			Longitude: -74.039373,
+			Altitude:  10 + float64(i),
+			Battery:   90 - i,
+			Timestamp: at.Unix(),
+			CreatedAt: at,
+		}
+	}
+	return locations
+}
+
+func TestProcessDistanceJob_MemoryStore(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	store := database.NewMemoryStore(walkFromHome("pixel8", start, 20)...)
+	server := newMemoryServer(t, store)
+
+	job := &queue.Job{ID: "job-1", Date: "2026-01-24", DeviceID: "pixel8"}
+	result, err := server.processDistanceJob(context.Background(), job)
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+
+	if result.TotalLocations != 20 {
+		t.Errorf("expected 20 locations, got %d", result.TotalLocations)
+	}
+	if result.MaxDistanceKM < 2 || result.MinDistanceKM != 0 {
+		t.Errorf("unexpected distance range %.2f-%.2f", result.MinDistanceKM, result.MaxDistanceKM)
+	}
+	if result.SummariesWritten != 1 {
+		t.Errorf("expected 1 daily summary, got %d", result.SummariesWritten)
+	}
+	if filepath.Base(result.CSVPath) != "distance_20260124_pixel8_job-1.csv" {
+		t.Errorf("unexpected CSV path %s", result.CSVPath)
+	}
+
+	content, err := readArtifact(t, server, result.CSVPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if !strings.HasPrefix(lines[0], "timestamp,device_id,") {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if !strings.Contains(string(content), "Total Locations,20") {
+		t.Error("expected summary footer with 20 locations")
+	}
+
+	summaries, err := store.GetDailySummaries(context.Background(), "2026-01-24", "2026-01-24", "pixel8")
+	if err != nil {
+		t.Fatalf("GetDailySummaries failed: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].PointCount != 20 {
+		t.Errorf("expected one stored summary with 20 points, got %+v", summaries)
+	}
+}
+
+// interleavedDevices returns two walks 20 km apart whose fixes alternate
+// every 30 seconds
+func interleavedDevices(start time.Time, n int) []database.Location {
+	pixel := walkFromHome("pixel8", start, n)
+	iphone := walkFromHome("iphone", start.Add(30*time.Second), n)
+	for i := range iphone {
+		iphone[i].ID += int64(n)
+		iphone[i].Latitude += 0.18
+	}
+	return append(pixel, iphone...)
+}
+
+func TestProcessDistanceJob_InterleavedDevices(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	server := newMemoryServer(t, database.NewMemoryStore(interleavedDevices(start, 20)...))
+
+	// Without a device filter every device's fixes are in the job
+	result, err := server.processDistanceJob(context.Background(), &queue.Job{ID: "job-1", Date: "2026-01-24"})
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+
+	if result.TotalLocations != 40 {
+		t.Errorf("expected 40 locations, got %d", result.TotalLocations)
+	}
+	// Both devices walk at ~6.7 km/h; jumping between them would be ~2400 km/h
+	if result.MaxSpeedKMH > 10 {
+		t.Errorf("expected walking max speed, got %.1f km/h", result.MaxSpeedKMH)
+	}
+	var moving time.Duration
+	for _, mt := range result.ModeTotals {
+		if mt.Mode != string(calculator.ModeWalking) && mt.DistanceKM > 0 {
+			t.Errorf("expected only walking, got %.3f km %s", mt.DistanceKM, mt.Mode)
+		}
+		moving += time.Duration(mt.DurationSeconds) * time.Second
+	}
+	if moving != 38*time.Minute {
+		t.Errorf("expected 19 minutes per device, got %s", moving)
+	}
+
+	// Each device's rows carry speeds from its own previous fix
+	content, err := readArtifact(t, server, result.CSVPath)
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	if strings.Contains(string(content), ",flying,") || strings.Contains(string(content), ",driving,") {
+		t.Error("expected no flying or driving rows")
+	}
+}
+
+func TestProcessDistanceJob_OutOfOrderFix(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	locations := walkFromHome("pixel8", start, 20)
+	// Inserted in order, but recorded ten minutes before the fix ahead of it
+	locations[10].Timestamp -= 600
+	server := newMemoryServer(t, database.NewMemoryStore(locations...))
+
+	result, err := server.processDistanceJob(context.Background(), &queue.Job{ID: "job-1", Date: "2026-01-24", DeviceID: "pixel8"})
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+	if result.TotalLocations != 19 {
+		t.Errorf("expected the out-of-order fix to be skipped, got %d locations", result.TotalLocations)
+	}
+	if result.MaxSpeedKMH > 10 {
+		t.Errorf("expected walking max speed, got %.1f km/h", result.MaxSpeedKMH)
+	}
+}
+
+func TestProcessBackfillJob_ClearsStaleSummaries(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	store := database.NewMemoryStore(walkFromHome("pixel8", start, 20)...)
+	ctx := context.Background()
+	// Left by an earlier run, before these days' locations were removed
+	for _, stale := range []database.DailySummary{
+		{DeviceID: "pixel8", Date: "2026-01-23", PointCount: 50},
+		{DeviceID: "iphone", Date: "2026-01-24", PointCount: 50},
+		{DeviceID: "pixel8", Date: "2026-01-26", PointCount: 50}, // outside the range
+	} {
+		if err := store.UpsertDailySummary(ctx, stale); err != nil {
+			t.Fatalf("UpsertDailySummary failed: %v", err)
+		}
+	}
+	server := newMemoryServer(t, store)
+
+	result, err := server.processBackfillJob(ctx, &queue.Job{ID: "job-1", Kind: queue.KindBackfill, Date: "2026-01-23", EndDate: "2026-01-25"})
+	if err != nil {
+		t.Fatalf("processBackfillJob failed: %v", err)
+	}
+	if result.SummariesWritten != 1 {
+		t.Errorf("expected 1 summary written, got %d", result.SummariesWritten)
+	}
+
+	summaries, err := store.GetDailySummaries(ctx, "2026-01-20", "2026-01-31", "")
+	if err != nil {
+		t.Fatalf("GetDailySummaries failed: %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].Date != "2026-01-24" || summaries[0].PointCount != 20 || summaries[1].Date != "2026-01-26" {
+		t.Errorf("expected the recomputed day and the day outside the range, got %+v", summaries)
+	}
+}
+
+func TestProcessDistanceJob_NoLocations(t *testing.T) {
+	server := newMemoryServer(t, database.NewMemoryStore())
+
+	job := &queue.Job{ID: "job-1", Date: "2026-01-24"}
+	if _, err := server.processDistanceJob(context.Background(), job); err == nil {
+		t.Fatal("expected error for a day without locations, got nil")
+	}
+
+	entries, err := os.ReadDir(server.cfg.CSVOutputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no CSV files to be left behind, found %d", len(entries))
+	}
+}
+
+// locationsOnly hides the SummaryStore methods of the wrapped store
+type locationsOnly struct {
+	database.LocationStore
+}
+
+func TestSummaries_UnsupportedStore(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	server := newMemoryServer(t, locationsOnly{database.NewMemoryStore(walkFromHome("pixel8", start, 5)...)})
+
+	_, err := server.GetDailySummaries(context.Background(), &distancev1.GetDailySummariesRequest{
+		StartDate: "2026-01-24",
+		EndDate:   "2026-01-24",
+	})
+	if !errors.Is(err, errSummariesUnsupported) {
+		t.Errorf("expected errSummariesUnsupported, got %v", err)
+	}
+
+	// Distance jobs still succeed without persisting summaries
+	result, err := server.processDistanceJob(context.Background(), &queue.Job{ID: "job-1", Date: "2026-01-24"})
+	if err != nil {
+		t.Fatalf("processDistanceJob failed: %v", err)
+	}
+	if result.SummariesWritten != 0 {
+		t.Errorf("expected no summaries written, got %d", result.SummariesWritten)
+	}
+}
+
+// unavailableStore is a store whose database is down
+type unavailableStore struct {
+	*database.MemoryStore
+}
+
+func (unavailableStore) Available() bool {
+	return false
+}
+
+func TestNewJobs_RejectedWhileUnavailable(t *testing.T) {
+	server := newMemoryServer(t, unavailableStore{database.NewMemoryStore()})
+	ctx := context.Background()
+
+	_, err := server.CalculateDistanceFromHome(ctx, &distancev1.CalculateDistanceRequest{Date: "2026-01-24"})
+	if !errors.Is(err, database.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen for distance job, got %v", err)
+	}
+
+	_, err = server.BackfillDailySummaries(ctx, &distancev1.BackfillDailySummariesRequest{StartDate: "2026-01-24", EndDate: "2026-01-24"})
+	if !errors.Is(err, database.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen for backfill job, got %v", err)
+	}
+
+	_, err = server.CalculateActivityDistance(ctx, &distancev1.CalculateActivityDistanceRequest{ActivityId: 1})
+	if !errors.Is(err, database.ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen for activity job, got %v", err)
+	}
+
+	if jobs := server.queue.ListJobs("", 10, 0); len(jobs) != 0 {
+		t.Errorf("expected no queued jobs, got %d", len(jobs))
+	}
+}
+
+func TestProcessDistanceJob_ConcurrentJobs(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	server := newMemoryServer(t, database.NewMemoryStore(walkFromHome("pixel8", start, 20)...))
+
+	// Jobs for the same date write separate artifacts
+	var wg sync.WaitGroup
+	results := make([]*queue.JobResult, 4)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			job := &queue.Job{ID: fmt.Sprintf("job-%d", i), Date: "2026-01-24", OutputFormats: []string{"csv", "gpx"}}
+			results[i], errs[i] = server.processDistanceJob(context.Background(), job)
+		}(i)
+	}
+	wg.Wait()
+
+	keys := make(map[string]bool)
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("job %d failed: %v", i, errs[i])
+		}
+		for _, a := range result.Artifacts {
+			if keys[a.Key] {
+				t.Errorf("artifact %s written by more than one job", a.Key)
+			}
+			keys[a.Key] = true
+
+			content, err := readArtifact(t, server, a.Key)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", a.Key, err)
+			}
+			sum := sha256.Sum256(content)
+			if a.Size != int64(len(content)) || a.SHA256 != hex.EncodeToString(sum[:]) {
+				t.Errorf("%s: size %d and checksum %s do not match the content", a.Key, a.Size, a.SHA256)
+			}
+		}
+	}
+
+	// Only committed artifacts are left in the output directory
+	if files := outputFiles(t, server); len(files) != len(keys) {
+		t.Errorf("expected %d files, got %v", len(keys), files)
+	}
+}
+
+// outputFiles returns the files in the server's output directory, leaving
+// out the checksum records kept next to artifacts
+func outputFiles(t *testing.T, server *Server) []string {
+	t.Helper()
+	entries, err := os.ReadDir(server.cfg.CSVOutputPath)
+	if err != nil {
+		t.Fatalf("failed to list output directory: %v", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if name := entry.Name(); !strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".sha256") {
+			files = append(files, name)
+		}
+	}
+	return files
+}
+
+// waitForJob polls until a queued job finishes
+func waitForJob(t *testing.T, server *Server, jobID string) *distancev1.GetJobStatusResponse {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := server.GetJobStatus(context.Background(), &distancev1.GetJobStatusRequest{JobId: jobID})
+		if err != nil {
+			t.Fatalf("GetJobStatus failed: %v", err)
+		}
+		if resp.Status == string(queue.StatusCompleted) || resp.Status == string(queue.StatusFailed) {
+			return resp
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s still %s", jobID, resp.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNewServer_ShortSigningKey(t *testing.T) {
+	cfg := &config.Config{CSVOutputPath: t.TempDir(), DownloadSigningKey: "too-short"}
+	if _, err := NewServer(cfg, database.NewMemoryStore()); err == nil {
+		t.Fatal("expected an error for a signing key shorter than download.MinKeyLength")
+	}
+}
+
+func TestGetJobStatus_DownloadURLs(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+	server := newMemoryServer(t, database.NewMemoryStore(walkFromHome("pixel8", start, 5)...))
+	server.cfg.DownloadBaseURL = "https://otel-worker.example.com"
+	signer, err := newDownloadSigner(server.cfg)
+	if err != nil {
+		t.Fatalf("newDownloadSigner failed: %v", err)
+	}
+	server.signer = signer
+
+	created, err := server.CalculateDistanceFromHome(context.Background(), &distancev1.CalculateDistanceRequest{
+		Date:          "2026-01-24",
+		OutputFormats: []string{"csv", "gpx"},
+	})
+	if err != nil {
+		t.Fatalf("CalculateDistanceFromHome failed: %v", err)
+	}
+	resp := waitForJob(t, server, created.JobId)
+	if resp.Result == nil {
+		t.Fatalf("job failed: %s", resp.ErrorMessage)
+	}
+
+	result := resp.Result
+	if len(result.Artifacts) != 2 || result.DownloadUrl != result.Artifacts[0].DownloadUrl {
+		t.Fatalf("expected the CSV URL as the job's download URL, got %q and %+v", result.DownloadUrl, result.Artifacts)
+	}
+	if ttl := time.Until(result.DownloadExpiresAt.AsTime()); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("expected URLs valid for an hour, got %s", ttl)
+	}
+
+	for _, a := range result.Artifacts {
+		if a.SizeBytes == 0 || len(a.Sha256) != 64 {
+			t.Errorf("expected %s to report its size and checksum, got %d and %q", a.Key, a.SizeBytes, a.Sha256)
+		}
+		u, err := url.Parse(a.DownloadUrl)
+		if err != nil {
+			t.Fatalf("invalid download URL %q: %v", a.DownloadUrl, err)
+		}
+		if u.Host != "otel-worker.example.com" || u.Path != "/download/"+a.Key {
+			t.Errorf("unexpected download URL %s", a.DownloadUrl)
+		}
+		jobID, err := server.DownloadSigner().Verify(a.Key, u.Query())
+		if err != nil || jobID != created.JobId {
+			t.Errorf("expected %s to verify for job %s, got %q (%v)", a.Key, created.JobId, jobID, err)
+		}
+	}
+}
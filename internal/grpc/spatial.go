@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// errSpatialUnsupported is returned by FindLocationsNear when the configured
+// location store cannot search by position
+var errSpatialUnsupported = errors.New("spatial queries are not supported by the configured location store")
+
+// visitGap is the longest gap between matches of one device within a visit
+const visitGap = 15 * time.Minute
+
+// FindLocationsNear returns the locations within a radius of a point and the
+// visits they form
+func (s *Server) FindLocationsNear(ctx context.Context, req *distancev1.FindLocationsNearRequest) (*distancev1.FindLocationsNearResponse, error) {
+	log.Info().
+		Float64("latitude", req.Latitude).
+		Float64("longitude", req.Longitude).
+		Float64("radius_km", req.RadiusKm).
+		Str("start_date", req.StartDate).
+		Str("end_date", req.EndDate).
+		Str("device_id", req.DeviceId).
+		Msg("Received find locations near request")
+
+	if s.spatial == nil {
+		return nil, errSpatialUnsupported
+	}
+	if err := validateDateRange(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 1000
+	}
+	if limit > 10000 {
+		limit = 10000
+	}
+
+	resp := &distancev1.FindLocationsNearResponse{}
+	visits := &visitTracker{open: make(map[string]*distancev1.NearbyVisit)}
+	total := 0
+
+	err := s.spatial.StreamLocationsNear(ctx, req.Latitude, req.Longitude, req.RadiusKm, req.StartDate, req.EndDate, req.DeviceId, func(near database.NearbyLocation) error {
+		total++
+		visits.add(near)
+		if len(resp.Locations) < limit {
+			resp.Locations = append(resp.Locations, &distancev1.NearbyLocation{
+				Location:   locationToProto(near.Location),
+				DistanceKm: near.DistanceKM,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to find nearby locations")
+		return nil, fmt.Errorf("spatial query failed: %w", err)
+	}
+
+	resp.TotalCount = int32(total) // #nosec G115 -- bounded by rows in the date range
+	resp.Visits = visits.finish()
+
+	log.Info().
+		Int("total_count", total).
+		Int("visits", len(resp.Visits)).
+		Msg("Nearby locations found")
+
+	return resp, nil
+}
+
+// visitTracker groups each device's consecutive nearby matches into visits
+type visitTracker struct {
+	open   map[string]*distancev1.NearbyVisit // current visit of each device
+	closed []*distancev1.NearbyVisit
+}
+
+// add extends the device's current visit with a match, or starts a new one
+// when the previous match is more than visitGap earlier
+func (v *visitTracker) add(near database.NearbyLocation) {
+	at := fixTime(near.Location)
+
+	visit, ok := v.open[near.DeviceID]
+	if ok && at.Sub(visit.EndTime.AsTime()) > visitGap {
+		v.closed = append(v.closed, visit)
+		ok = false
+	}
+	if !ok {
+		visit = &distancev1.NearbyVisit{
+			DeviceId:  near.DeviceID,
+			StartTime: timestamppb.New(at),
+			ClosestKm: near.DistanceKM,
+		}
+		v.open[near.DeviceID] = visit
+	}
+
+	visit.EndTime = timestamppb.New(at)
+	visit.PointCount++
+	if near.DistanceKM < visit.ClosestKm {
+		visit.ClosestKm = near.DistanceKM
+	}
+}
+
+// finish closes every open visit and returns all visits by start time
+func (v *visitTracker) finish() []*distancev1.NearbyVisit {
+	for _, visit := range v.open {
+		v.closed = append(v.closed, visit)
+	}
+	v.open = nil
+
+	sort.Slice(v.closed, func(i, j int) bool {
+		a, b := v.closed[i], v.closed[j]
+		if !a.StartTime.AsTime().Equal(b.StartTime.AsTime()) {
+			return a.StartTime.AsTime().Before(b.StartTime.AsTime())
+		}
+		return a.DeviceId < b.DeviceId
+	})
+	return v.closed
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stuartshay/otel-worker/internal/database"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+func TestFindLocationsNear(t *testing.T) {
+	start := time.Date(2026, 1, 24, 8, 0, 0, 0, time.UTC)
+
+	// pixel8 leaves home, is away for an hour and comes back; iphone stays
+	// far away all morning
+	locations := walkFromHome("pixel8", start, 3)
+	locations = append(locations, walkFromHome("pixel8", start.Add(time.Hour), 2)...)
+	for i := range locations {
+		locations[i].ID = int64(i + 1)
+	}
+	locations = append(locations, database.Location{
+		ID: 10, DeviceID: "iphone", Latitude: 40.7580, Longitude: -73.9855, CreatedAt: start.Add(30 * time.Minute),
+	})
+	server := newMemoryServer(t, database.NewMemoryStore(locations...))
+
+	req := &distancev1.FindLocationsNearRequest{
+		Latitude:  40.736097,
+		Longitude: -74.039373,
+		RadiusKm:  1,
+		StartDate: "2026-01-24",
+		EndDate:   "2026-01-24",
+		Limit:     3,
+	}
+	resp, err := server.FindLocationsNear(context.Background(), req)
+	if err != nil {
+		t.Fatalf("FindLocationsNear failed: %v", err)
+	}
+
+	if resp.TotalCount != 5 || len(resp.Locations) != 3 {
+		t.Errorf("expected 3 of 5 matches, got %d of %d", len(resp.Locations), resp.TotalCount)
+	}
+	if resp.Locations[0].DistanceKm != 0 || resp.Locations[0].Location.DeviceId != "pixel8" {
+		t.Errorf("unexpected first match %+v", resp.Locations[0])
+	}
+
+	if len(resp.Visits) != 2 {
+		t.Fatalf("expected 2 visits, got %d", len(resp.Visits))
+	}
+	first, second := resp.Visits[0], resp.Visits[1]
+	if first.PointCount != 3 || !first.StartTime.AsTime().Equal(start) || !first.EndTime.AsTime().Equal(start.Add(2*time.Minute)) {
+		t.Errorf("unexpected first visit %+v", first)
+	}
+	if second.PointCount != 2 || !second.StartTime.AsTime().Equal(start.Add(time.Hour)) || second.ClosestKm != 0 {
+		t.Errorf("unexpected second visit %+v", second)
+	}
+
+	req.EndDate = ""
+	if _, err := server.FindLocationsNear(context.Background(), req); err == nil {
+		t.Error("expected error for missing end_date, got nil")
+	}
+}
+
+func TestFindLocationsNear_UnsupportedStore(t *testing.T) {
+	server := newMemoryServer(t, locationsOnly{database.NewMemoryStore()})
+
+	_, err := server.FindLocationsNear(context.Background(), &distancev1.FindLocationsNearRequest{RadiusKm: 1, StartDate: "2026-01-24", EndDate: "2026-01-24"})
+	if !errors.Is(err, errSpatialUnsupported) {
+		t.Errorf("expected errSpatialUnsupported, got %v", err)
+	}
+}
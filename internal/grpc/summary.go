@@ -0,0 +1,213 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+	distancev1 "github.com/stuartshay/otel-worker/proto/distance/v1"
+)
+
+// errSummariesUnsupported is returned by the summary RPCs when the configured
+// location store cannot persist daily summaries
+var errSummariesUnsupported = errors.New("daily summaries are not supported by the configured location store")
+
+// GetDailySummaries returns stored per-device daily aggregates for a date range
+func (s *Server) GetDailySummaries(ctx context.Context, req *distancev1.GetDailySummariesRequest) (*distancev1.GetDailySummariesResponse, error) {
+	if err := validateDateRange(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	if s.summaries == nil {
+		return nil, errSummariesUnsupported
+	}
+
+	summaries, err := s.summaries.GetDailySummaries(ctx, req.StartDate, req.EndDate, req.DeviceId)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch daily summaries from database")
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	resp := &distancev1.GetDailySummariesResponse{}
+	for _, summary := range summaries {
+		resp.Summaries = append(resp.Summaries, &distancev1.DailySummary{
+			DeviceId:        summary.DeviceID,
+			Date:            summary.Date,
+			PathDistanceKm:  summary.PathDistanceKM,
+			MaxDistanceKm:   summary.MaxDistanceKM,
+			MinDistanceKm:   summary.MinDistanceKM,
+			AvgDistanceKm:   summary.AvgDistanceKM,
+			PointCount:      int32(summary.PointCount), // #nosec G115 -- bounded by locations per device per day
+			TimeAwaySeconds: summary.TimeAwaySeconds,
+			UpdatedAt:       timestamppb.New(summary.UpdatedAt),
+		})
+	}
+
+	return resp, nil
+}
+
+// BackfillDailySummaries initiates an async job that recomputes daily
+// aggregates for every day in a date range
+func (s *Server) BackfillDailySummaries(_ context.Context, req *distancev1.BackfillDailySummariesRequest) (*distancev1.BackfillDailySummariesResponse, error) {
+	log.Info().
+		Str("start_date", req.StartDate).
+		Str("end_date", req.EndDate).
+		Str("device_id", req.DeviceId).
+		Msg("Received daily summary backfill request")
+
+	if err := validateDateRange(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	if s.summaries == nil {
+		return nil, errSummariesUnsupported
+	}
+
+	if err := s.checkAvailable(); err != nil {
+		return nil, err
+	}
+
+	jobID, err := s.queue.EnqueueBackfill(req.StartDate, req.EndDate, req.DeviceId)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to enqueue backfill job")
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return &distancev1.BackfillDailySummariesResponse{
+		JobId:    jobID,
+		Status:   "queued",
+		QueuedAt: timestamppb.Now(),
+	}, nil
+}
+
+// processBackfillJob recomputes daily summaries one day at a time, streaming
+// each day's locations so that multi-month backfills use constant memory
+func (s *Server) processBackfillJob(ctx context.Context, job *queue.Job) (*queue.JobResult, error) {
+	log.Info().
+		Str("job_id", job.ID).
+		Str("start_date", job.Date).
+		Str("end_date", job.EndDate).
+		Str("device_id", job.DeviceID).
+		Msg("Processing daily summary backfill job")
+
+	start, err := time.Parse(time.DateOnly, job.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := time.Parse(time.DateOnly, job.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	result := &queue.JobResult{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("backfill canceled: %w", err)
+		}
+
+		date := day.Format(time.DateOnly)
+
+		// Clear the day first so rows from an earlier run do not survive for
+		// devices that no longer have locations on it
+		if s.summaries != nil {
+			if _, err := s.summaries.DeleteDailySummaries(ctx, date, job.DeviceID); err != nil {
+				return nil, fmt.Errorf("failed to clear daily summaries for %s: %w", date, err)
+			}
+		}
+
+		daily := s.newDailySummaries(date)
+		err := s.store.StreamLocationsByDate(ctx, date, job.DeviceID, func(loc database.Location) error {
+			daily.add(loc)
+			result.TotalLocations++
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("database query failed for %s: %w", date, err)
+		}
+
+		written, err := daily.store(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		result.SummariesWritten += written
+	}
+
+	log.Info().
+		Str("job_id", job.ID).
+		Int("summaries_written", result.SummariesWritten).
+		Int("total_locations", result.TotalLocations).
+		Msg("Daily summary backfill completed")
+
+	return result, nil
+}
+
+// dailySummaries accumulates one day's aggregates per device as locations
+// stream past, so a day is summarized without holding its rows in memory
+type dailySummaries struct {
+	server  *Server
+	date    string
+	devices map[string]*calculator.DaySummarizer
+}
+
+// newDailySummaries starts accumulating aggregates for date
+func (s *Server) newDailySummaries(date string) *dailySummaries {
+	return &dailySummaries{
+		server:  s,
+		date:    date,
+		devices: make(map[string]*calculator.DaySummarizer),
+	}
+}
+
+// add records the next chronological location of its device
+func (d *dailySummaries) add(loc database.Location) {
+	summarizer, ok := d.devices[loc.DeviceID]
+	if !ok {
+		cfg := d.server.cfg
+		summarizer = calculator.NewDaySummarizer(cfg.HomeLatitude, cfg.HomeLongitude, cfg.AwayThresholdKM)
+		d.devices[loc.DeviceID] = summarizer
+	}
+	summarizer.Add(calculator.Location{
+		Latitude:  loc.Latitude,
+		Longitude: loc.Longitude,
+		Timestamp: fixTime(loc),
+	})
+}
+
+// store writes one aggregate row per device, returning the number of rows
+// written; it writes nothing when the server has no summary store
+func (d *dailySummaries) store(ctx context.Context) (int, error) {
+	if d.server.summaries == nil {
+		return 0, nil
+	}
+
+	for deviceID, summarizer := range d.devices {
+		err := d.server.summaries.UpsertDailySummary(ctx, dailySummaryRow(deviceID, d.date, summarizer.Summary()))
+		if err != nil {
+			return 0, fmt.Errorf("failed to store daily summary for %s on %s: %w", deviceID, d.date, err)
+		}
+	}
+
+	return len(d.devices), nil
+}
+
+// dailySummaryRow converts a device's aggregates for date to a stored row
+func dailySummaryRow(deviceID, date string, day calculator.DaySummary) database.DailySummary {
+	return database.DailySummary{
+		DeviceID:        deviceID,
+		Date:            date,
+		PathDistanceKM:  day.PathDistanceKM,
+		MaxDistanceKM:   day.MaxDistanceKM,
+		MinDistanceKM:   day.MinDistanceKM,
+		AvgDistanceKM:   day.AvgDistanceKM,
+		PointCount:      day.PointCount,
+		TimeAwaySeconds: int64(day.TimeAway.Seconds()),
+	}
+}
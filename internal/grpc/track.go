@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+)
+
+// deviceTracks analyzes the rows of a job with one TrackAnalyzer per device,
+// so speeds, trips and elevation are never computed between fixes of
+// different devices that happen to be adjacent in time. Rows are written as
+// each device's fixes become final, so rows of different devices may be a
+// few fixes out of chronological order.
+type deviceTracks[T any] struct {
+	homeLat, homeLon float64
+	write            func(T, calculator.AnalyzedPoint) error
+	tracks           map[string]*deviceTrack[T]
+	devices          []string // in order of their first fix
+	skipped          int      // fixes older than their device's previous fix
+}
+
+// deviceTrack is the analyzer of one device and its rows awaiting a result
+type deviceTrack[T any] struct {
+	analyzer *calculator.TrackAnalyzer
+	rows     pointQueue[T]
+}
+
+// newDeviceTracks returns an empty set of tracks measuring distance from
+// the configured home and passing each analyzed row to write
+func newDeviceTracks[T any](s *Server, write func(T, calculator.AnalyzedPoint) error) *deviceTracks[T] {
+	return &deviceTracks[T]{
+		homeLat: s.cfg.HomeLatitude,
+		homeLon: s.cfg.HomeLongitude,
+		write:   write,
+		tracks:  make(map[string]*deviceTrack[T]),
+	}
+}
+
+// push adds the next chronological fix of loc.DeviceID and writes the rows
+// of that device that are now final. A fix older than the device's previous
+// fix is left out of the track and the outputs.
+func (d *deviceTracks[T]) push(row T, loc calculator.Location) error {
+	track, ok := d.tracks[loc.DeviceID]
+	if !ok {
+		track = &deviceTrack[T]{
+			analyzer: calculator.NewTrackAnalyzer(d.homeLat, d.homeLon),
+			rows:     pointQueue[T]{write: d.write},
+		}
+		d.tracks[loc.DeviceID] = track
+		d.devices = append(d.devices, loc.DeviceID)
+	}
+	points, err := track.analyzer.Push(loc)
+	if errors.Is(err, calculator.ErrOutOfOrder) {
+		d.skipped++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	track.rows.push(row)
+	return track.rows.emit(points)
+}
+
+// flush writes the remaining rows of every device
+func (d *deviceTracks[T]) flush() error {
+	if d.skipped > 0 {
+		log.Warn().Int("skipped", d.skipped).Msg("Skipped fixes recorded before their device's previous fix")
+	}
+	for _, deviceID := range d.devices {
+		track := d.tracks[deviceID]
+		if err := track.rows.emit(track.analyzer.Flush()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// summary returns the combined summary of every device's track
+func (d *deviceTracks[T]) summary() calculator.TrackSummary {
+	summaries := make([]calculator.TrackSummary, 0, len(d.devices))
+	for _, deviceID := range d.devices {
+		summaries = append(summaries, d.tracks[deviceID].analyzer.Summary())
+	}
+	return calculator.MergeSummaries(summaries...)
+}
@@ -0,0 +1,147 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stuartshay/otel-worker/internal/calculator"
+	"github.com/stuartshay/otel-worker/internal/database"
+	"github.com/stuartshay/otel-worker/internal/queue"
+)
+
+// errUnifiedUnsupported is returned when a distance request selects Garmin
+// points but the configured location store cannot merge GPS sources
+var errUnifiedUnsupported = errors.New("garmin and multi-source queries are not supported by the configured location store")
+
+// unifiedCSVHeader is the column header of a multi-source distance CSV report:
+// the distance columns followed by the source and Garmin sensor columns
+var unifiedCSVHeader = append(append([]string{}, distanceCSVHeader...),
+	"source", "activity_id", "heart_rate",
+)
+
+// ownTracksOnly reports whether a source filter reads only OwnTracks fixes,
+// which keeps the original distance report and daily summaries
+func ownTracksOnly(sources []database.Source) bool {
+	return len(sources) == 1 && sources[0] == database.SourceOwnTracks
+}
+
+// processUnifiedJob calculates distance metrics over the de-duplicated points
+// of one or more GPS sources. Daily summaries are not updated: they track
+// OwnTracks devices, and merged points no longer belong to one device.
+func (s *Server) processUnifiedJob(ctx context.Context, job *queue.Job, sources []database.Source) (*queue.JobResult, error) {
+	if s.unified == nil {
+		return nil, errUnifiedUnsupported
+	}
+
+	outputs, err := createTrackOutputs(ctx, s, job.OutputFormats, trackOutputSpec[database.GPSPoint]{
+		name:        artifactName(unifiedName(job.Date, job.DeviceID, sources), job),
+		title:       distanceTitle(job),
+		csvHeader:   unifiedCSVHeader,
+		csvRow:      unifiedRow,
+		csvTemplate: job.CSVTemplate,
+		point:       gpsTrackPoint,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create output files")
+		return nil, fmt.Errorf("output generation failed: %w", err)
+	}
+
+	// Garmin points and each OwnTracks device form separate tracks
+	tracks := newDeviceTracks(s, outputs.write)
+
+	var writeErr error
+	err = s.unified.StreamGPSPointsByDate(ctx, job.Date, job.DeviceID, sources, func(p database.GPSPoint) error {
+		writeErr = tracks.push(p, gpsPointToCalculatorLocation(p))
+		return writeErr
+	})
+	if err == nil {
+		writeErr = tracks.flush()
+	}
+	if writeErr != nil {
+		outputs.abort()
+		log.Error().Err(writeErr).Msg("Failed to write output files")
+		return nil, fmt.Errorf("output generation failed: %w", writeErr)
+	}
+	if err != nil {
+		outputs.abort()
+		log.Error().Err(err).Msg("Failed to fetch GPS points from database")
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	summary := tracks.summary()
+	if summary.Metrics.TotalLocations == 0 {
+		outputs.abort()
+		log.Warn().Str("date", job.Date).Str("source", job.Source).Msg("No GPS points found for date")
+		return nil, fmt.Errorf("no GPS points found for date %s", job.Date)
+	}
+
+	log.Info().
+		Float64("total_distance_km", summary.Metrics.TotalDistanceKM).
+		Int("total_locations", summary.Metrics.TotalLocations).
+		Str("source", job.Source).
+		Msg("Multi-source distance metrics calculated")
+
+	artifacts, err := outputs.finish(summary)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write output files")
+		return nil, fmt.Errorf("output generation failed: %w", err)
+	}
+
+	return trackResult(artifacts, summary), nil
+}
+
+// unifiedName returns the output file name, without extension, for a date,
+// optional device and source filter, e.g. distance_20260124_garmin or
+// distance_20260124_all
+func unifiedName(date, deviceID string, sources []database.Source) string {
+	label := "all"
+	if len(sources) == 1 {
+		label = string(sources[0])
+	}
+	return distanceName(date, deviceID) + "_" + label
+}
+
+// unifiedRow returns the cells of the data row for one GPS point and its
+// analyzed values
+func unifiedRow(p database.GPSPoint, point calculator.AnalyzedPoint) []csvCell {
+	activityID, heartRate := textCell(""), textCell("")
+	if p.Source == database.SourceGarmin {
+		activityID = textCell(strconv.FormatInt(p.ActivityID, 10))
+		heartRate = intCell(p.HeartRate)
+	}
+
+	return []csvCell{
+		timeCell(p.Timestamp),
+		textCell(p.DeviceID),
+		coordinateCell(p.Latitude),
+		coordinateCell(p.Longitude),
+		distanceCell(point.DistanceFromHomeKM, 2),
+		intCell(p.Accuracy),
+		intCell(p.Battery),
+		textCell(strconv.FormatFloat(p.SpeedKMH, 'f', 0, 64)),
+		speedCell(point.SpeedKMH, 2),
+		numberCell(point.AccelerationMS2, 3),
+		textCell(string(point.Mode)),
+		numberCell(p.Altitude, 1),
+		numberCell(point.SmoothedAltitudeM, 1),
+		numberCell(point.CumulativeAscentM, 1),
+		textCell(string(p.Source)),
+		activityID,
+		heartRate,
+	}
+}
+
+// gpsPointToCalculatorLocation converts a GPS point for track analysis
+func gpsPointToCalculatorLocation(p database.GPSPoint) calculator.Location {
+	return calculator.Location{
+		DeviceID:  p.DeviceID,
+		Latitude:  p.Latitude,
+		Longitude: p.Longitude,
+		Altitude:  p.Altitude,
+		Timestamp: p.Timestamp,
+	}
+}
@@ -22,27 +22,123 @@ const (
 	StatusFailed     JobStatus = "failed"
 )
 
+// JobKind identifies which processing pipeline a job runs through
+type JobKind string
+
+// Job kind constants
+const (
+	// KindDistance calculates distance metrics and a CSV for a single date
+	KindDistance JobKind = "distance"
+	// KindBackfill recomputes daily summaries for every day from Date to EndDate
+	KindBackfill JobKind = "backfill"
+	// KindActivity calculates metrics and a CSV for one Garmin activity
+	KindActivity JobKind = "activity"
+)
+
 // Job represents a distance calculation job
 type Job struct {
-	ID           string
-	Date         string
-	DeviceID     string
-	Status       JobStatus
-	QueuedAt     time.Time
-	StartedAt    *time.Time
-	CompletedAt  *time.Time
-	ErrorMessage string
-	Result       *JobResult
+	ID            string
+	Kind          JobKind
+	Date          string
+	EndDate       string // last date (inclusive) for range jobs such as backfills
+	DeviceID      string
+	ActivityID    int64        // Garmin activity for activity jobs
+	Source        string       // GPS source filter for distance jobs, empty for OwnTracks
+	OutputFormats []string     // output files of distance jobs, e.g. "csv", "gpx" and "geojson"
+	CSVTemplate   *CSVTemplate // layout of a distance job's CSV report; nil for the default
+	Status        JobStatus
+	QueuedAt      time.Time
+	StartedAt     *time.Time
+	CompletedAt   *time.Time
+	ErrorMessage  string
+	Result        *JobResult
 }
 
 // JobResult contains the output of a completed distance calculation
 type JobResult struct {
-	CSVPath          string
+	CSVPath          string     // storage key of the CSV report
+	GPXPath          string     // storage key of the GPX file
+	Artifacts        []Artifact // every output file, in the order requested
 	TotalDistanceKM  float64
 	MaxDistanceKM    float64
 	MinDistanceKM    float64
 	TotalLocations   int
 	ProcessingTimeMS int64
+	MaxSpeedKMH      float64
+	ModeTotals       []ModeTotal
+	Elevation        ElevationStats
+	TripElevations   []TripElevation
+	SummariesWritten int
+	Activity         *ActivityMetrics
+}
+
+// CSVTemplate shapes the CSV report of a distance job. Zero values keep the
+// default layout.
+type CSVTemplate struct {
+	Columns         []string // selection and order of columns; empty for all
+	Units           string   // "km" or "mi" for distances and speeds
+	Precision       *int     // decimals of measurement columns; nil keeps each default
+	Timezone        string   // IANA zone timestamps are written in; empty for UTC
+	TimestampFormat string   // "rfc3339" or "spreadsheet"
+	Delimiter       string   // one of ",", ";", "\t" or "|"
+	Footer          string   // "inline", "separate" or "none"
+}
+
+// Artifact is one output file of a job
+type Artifact struct {
+	Format string // e.g. "csv", "gpx", "geojson", "kml" or "parquet"
+	Key    string // storage key, e.g. "distance_20260124_<job ID>.csv"
+	Size   int64  // bytes
+	SHA256 string // hex encoded checksum of the content
+}
+
+// ActivityMetrics holds the path, sensor and distance-from-home metrics of
+// one Garmin activity
+type ActivityMetrics struct {
+	ActivityID            int64
+	PointCount            int
+	PathDistanceKM        float64
+	DurationSeconds       int64
+	AvgSpeedKMH           float64
+	MaxSpeedKMH           float64
+	AvgHeartRate          float64
+	MaxHeartRate          int
+	AvgCadence            float64
+	MaxCadence            int
+	MaxDistanceFromHomeKM float64
+	MinDistanceFromHomeKM float64
+	AvgDistanceFromHomeKM float64
+	Elevation             ElevationStats
+}
+
+// ModeTotal is the distance and time spent in one movement mode
+type ModeTotal struct {
+	Mode            string
+	DistanceKM      float64
+	DurationSeconds int64
+}
+
+// ElevationStats holds climb statistics in meters
+type ElevationStats struct {
+	AscentM      float64
+	DescentM     float64
+	MinAltitudeM float64
+	MaxAltitudeM float64
+}
+
+// TripElevation is the elevation profile and climb statistics for one trip
+type TripElevation struct {
+	StartTime  time.Time
+	EndTime    time.Time
+	DistanceKM float64
+	Elevation  ElevationStats
+	Profile    []ElevationSample
+}
+
+// ElevationSample is one point on a trip elevation profile
+type ElevationSample struct {
+	DistanceKM float64
+	AltitudeM  float64
 }
 
 // ProcessFunc is a function that processes a job
@@ -81,22 +177,58 @@ func NewQueue(workers int, processor ProcessFunc) *Queue {
 	return q
 }
 
-// Enqueue adds a new job to the queue
-func (q *Queue) Enqueue(date, deviceID string) (string, error) {
+// EnqueueOptions are the optional settings of a distance calculation job;
+// the zero value reads OwnTracks and writes the default CSV report
+type EnqueueOptions struct {
+	Source        string       // GPS source filter: "owntracks", "garmin" or "all"
+	OutputFormats []string     // empty writes CSV only
+	CSVTemplate   *CSVTemplate // nil keeps the default CSV layout
+}
+
+// Enqueue adds a new distance calculation job to the queue
+func (q *Queue) Enqueue(date, deviceID string, opts EnqueueOptions) (string, error) {
+	return q.enqueue(&Job{
+		Kind:          KindDistance,
+		Date:          date,
+		DeviceID:      deviceID,
+		Source:        opts.Source,
+		OutputFormats: opts.OutputFormats,
+		CSVTemplate:   opts.CSVTemplate,
+	})
+}
+
+// EnqueueBackfill adds a daily summary backfill job covering startDate to
+// endDate (inclusive) to the queue
+func (q *Queue) EnqueueBackfill(startDate, endDate, deviceID string) (string, error) {
+	return q.enqueue(&Job{
+		Kind:     KindBackfill,
+		Date:     startDate,
+		EndDate:  endDate,
+		DeviceID: deviceID,
+	})
+}
+
+// EnqueueActivity adds a Garmin activity metrics job to the queue; date is
+// the activity's start date, recorded for listing
+func (q *Queue) EnqueueActivity(activityID int64, date string) (string, error) {
+	return q.enqueue(&Job{
+		Kind:       KindActivity,
+		Date:       date,
+		ActivityID: activityID,
+	})
+}
+
+// enqueue assigns an ID to job, stores it and adds it to the pending queue
+func (q *Queue) enqueue(job *Job) (string, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	// Generate unique job ID
 	jobID := uuid.New().String()
 
-	// Create job
-	job := &Job{
-		ID:       jobID,
-		Date:     date,
-		DeviceID: deviceID,
-		Status:   StatusQueued,
-		QueuedAt: time.Now().UTC(),
-	}
+	job.ID = jobID
+	job.Status = StatusQueued
+	job.QueuedAt = time.Now().UTC()
 
 	// Store job
 	q.jobs[jobID] = job
@@ -132,8 +264,25 @@ func (q *Queue) GetJob(jobID string) (*Job, error) {
 		completedCopy := *job.CompletedAt
 		jobCopy.CompletedAt = &completedCopy
 	}
+	jobCopy.OutputFormats = append([]string(nil), job.OutputFormats...)
+	if job.CSVTemplate != nil {
+		templateCopy := *job.CSVTemplate
+		templateCopy.Columns = append([]string(nil), job.CSVTemplate.Columns...)
+		if job.CSVTemplate.Precision != nil {
+			precisionCopy := *job.CSVTemplate.Precision
+			templateCopy.Precision = &precisionCopy
+		}
+		jobCopy.CSVTemplate = &templateCopy
+	}
 	if job.Result != nil {
 		resultCopy := *job.Result
+		resultCopy.ModeTotals = append([]ModeTotal(nil), job.Result.ModeTotals...)
+		resultCopy.TripElevations = append([]TripElevation(nil), job.Result.TripElevations...)
+		resultCopy.Artifacts = append([]Artifact(nil), job.Result.Artifacts...)
+		if job.Result.Activity != nil {
+			activityCopy := *job.Result.Activity
+			resultCopy.Activity = &activityCopy
+		}
 		jobCopy.Result = &resultCopy
 	}
 
@@ -32,7 +32,7 @@ func TestEnqueue(t *testing.T) {
 	q := NewQueue(1, processor)
 	defer func() { _ = q.Shutdown(time.Second) }()
 
-	jobID, err := q.Enqueue("2026-01-24", "test-device")
+	jobID, err := q.Enqueue("2026-01-24", "test-device", EnqueueOptions{})
 	if err != nil {
 		t.Fatalf("Enqueue() failed: %v", err)
 	}
@@ -58,6 +58,98 @@ func TestEnqueue(t *testing.T) {
 	}
 }
 
+func TestEnqueue_Options(t *testing.T) {
+	processor := func(_ context.Context, _ *Job) (*JobResult, error) {
+		return &JobResult{}, nil
+	}
+
+	q := NewQueue(1, processor)
+	defer func() { _ = q.Shutdown(time.Second) }()
+
+	jobID, err := q.Enqueue("2026-01-24", "", EnqueueOptions{
+		Source:        "all",
+		OutputFormats: []string{"csv", "gpx"},
+		CSVTemplate:   &CSVTemplate{Units: "mi"},
+	})
+	if err != nil {
+		t.Fatalf("Enqueue() failed: %v", err)
+	}
+
+	job, err := q.GetJob(jobID)
+	if err != nil {
+		t.Fatalf("GetJob() failed: %v", err)
+	}
+	if job.Kind != KindDistance || job.Source != "all" || len(job.OutputFormats) != 2 || job.CSVTemplate == nil || job.CSVTemplate.Units != "mi" {
+		t.Errorf("options not applied: %+v", job)
+	}
+}
+
+func TestEnqueueBackfill(t *testing.T) {
+	var gotKind atomic.Value
+	processor := func(_ context.Context, job *Job) (*JobResult, error) {
+		gotKind.Store(job.Kind)
+		return &JobResult{SummariesWritten: 3}, nil
+	}
+
+	q := NewQueue(1, processor)
+	defer func() { _ = q.Shutdown(time.Second) }()
+
+	jobID, err := q.EnqueueBackfill("2026-01-01", "2026-01-31", "test-device")
+	if err != nil {
+		t.Fatalf("EnqueueBackfill() failed: %v", err)
+	}
+
+	job, err := q.GetJob(jobID)
+	if err != nil {
+		t.Fatalf("GetJob() failed: %v", err)
+	}
+	if job.Kind != KindBackfill {
+		t.Errorf("expected kind 'backfill', got '%s'", job.Kind)
+	}
+	if job.Date != "2026-01-01" || job.EndDate != "2026-01-31" {
+		t.Errorf("expected range 2026-01-01..2026-01-31, got %s..%s", job.Date, job.EndDate)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if kind, _ := gotKind.Load().(JobKind); kind != KindBackfill {
+		t.Errorf("expected processor to receive backfill job, got '%s'", kind)
+	}
+}
+
+func TestEnqueueActivity(t *testing.T) {
+	processor := func(_ context.Context, job *Job) (*JobResult, error) {
+		return &JobResult{Activity: &ActivityMetrics{ActivityID: job.ActivityID, PointCount: 10}}, nil
+	}
+
+	q := NewQueue(1, processor)
+	defer func() { _ = q.Shutdown(time.Second) }()
+
+	jobID, err := q.EnqueueActivity(12345, "2026-01-24")
+	if err != nil {
+		t.Fatalf("EnqueueActivity() failed: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	job, err := q.GetJob(jobID)
+	if err != nil {
+		t.Fatalf("GetJob() failed: %v", err)
+	}
+	if job.Kind != KindActivity || job.ActivityID != 12345 || job.Date != "2026-01-24" {
+		t.Errorf("unexpected job %+v", job)
+	}
+	if job.Result == nil || job.Result.Activity == nil || job.Result.Activity.ActivityID != 12345 {
+		t.Fatalf("expected activity result, got %+v", job.Result)
+	}
+
+	// The returned copy must not share the activity metrics
+	job.Result.Activity.PointCount = 0
+	again, _ := q.GetJob(jobID)
+	if again.Result.Activity.PointCount != 10 {
+		t.Error("GetJob returned a shared ActivityMetrics pointer")
+	}
+}
+
 func TestGetJob_NotFound(t *testing.T) {
 	processor := func(_ context.Context, _ *Job) (*JobResult, error) {
 		return nil, nil
@@ -72,6 +164,38 @@ func TestGetJob_NotFound(t *testing.T) {
 	}
 }
 
+func TestGetJob_ReturnsCopy(t *testing.T) {
+	processor := func(_ context.Context, _ *Job) (*JobResult, error) {
+		return &JobResult{Artifacts: []Artifact{{Format: "csv", Key: "distance_20260124.csv"}}}, nil
+	}
+
+	q := NewQueue(1, processor)
+	defer func() { _ = q.Shutdown(time.Second) }()
+
+	precision := 2
+	jobID, _ := q.Enqueue("2026-01-24", "test-device", EnqueueOptions{
+		OutputFormats: []string{"csv"},
+		CSVTemplate:   &CSVTemplate{Columns: []string{"timestamp"}, Precision: &precision},
+	})
+
+	// Wait for processing
+	time.Sleep(100 * time.Millisecond)
+
+	job, err := q.GetJob(jobID)
+	if err != nil || job.Result == nil {
+		t.Fatalf("expected a completed job, got %+v (%v)", job, err)
+	}
+	job.OutputFormats[0] = "gpx"
+	job.CSVTemplate.Columns[0] = "device_id"
+	*job.CSVTemplate.Precision = 5
+	job.Result.Artifacts[0].Key = "changed.csv"
+
+	job, _ = q.GetJob(jobID)
+	if job.OutputFormats[0] != "csv" || job.CSVTemplate.Columns[0] != "timestamp" || *job.CSVTemplate.Precision != 2 || job.Result.Artifacts[0].Key != "distance_20260124.csv" {
+		t.Errorf("changes to a returned job reached the queue: %+v", job)
+	}
+}
+
 func TestListJobs(t *testing.T) {
 	processor := func(_ context.Context, _ *Job) (*JobResult, error) {
 		time.Sleep(50 * time.Millisecond)
@@ -82,9 +206,9 @@ func TestListJobs(t *testing.T) {
 	defer func() { _ = q.Shutdown(time.Second) }()
 
 	// Enqueue multiple jobs
-	_, _ = q.Enqueue("2026-01-24", "device1")
-	_, _ = q.Enqueue("2026-01-25", "device2")
-	_, _ = q.Enqueue("2026-01-26", "device3")
+	_, _ = q.Enqueue("2026-01-24", "device1", EnqueueOptions{})
+	_, _ = q.Enqueue("2026-01-25", "device2", EnqueueOptions{})
+	_, _ = q.Enqueue("2026-01-26", "device3", EnqueueOptions{})
 
 	// List all jobs
 	jobs := q.ListJobs("", 10, 0)
@@ -121,7 +245,7 @@ func TestProcessJob_Success(t *testing.T) {
 	q := NewQueue(1, processor)
 	defer func() { _ = q.Shutdown(time.Second) }()
 
-	jobID, _ := q.Enqueue("2026-01-24", "test-device")
+	jobID, _ := q.Enqueue("2026-01-24", "test-device", EnqueueOptions{})
 
 	// Wait for processing
 	time.Sleep(100 * time.Millisecond)
@@ -150,7 +274,7 @@ func TestProcessJob_Failure(t *testing.T) {
 	q := NewQueue(1, processor)
 	defer func() { _ = q.Shutdown(time.Second) }()
 
-	jobID, _ := q.Enqueue("2026-01-24", "test-device")
+	jobID, _ := q.Enqueue("2026-01-24", "test-device", EnqueueOptions{})
 
 	// Wait for processing
 	time.Sleep(100 * time.Millisecond)
@@ -174,8 +298,8 @@ func TestGetStats(t *testing.T) {
 	defer func() { _ = q.Shutdown(time.Second) }()
 
 	// Enqueue jobs
-	_, _ = q.Enqueue("2026-01-24", "device1")
-	_, _ = q.Enqueue("2026-01-25", "device2")
+	_, _ = q.Enqueue("2026-01-24", "device1", EnqueueOptions{})
+	_, _ = q.Enqueue("2026-01-25", "device2", EnqueueOptions{})
 
 	stats := q.GetStats()
 	if stats["total"] < 2 {
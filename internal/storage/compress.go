@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog/log"
+)
+
+// Content encodings artifacts can be pre-compressed with, as named in
+// Accept-Encoding
+const (
+	EncodingGzip = "gzip"
+	EncodingZstd = "zstd"
+)
+
+// encodingExtensions maps each encoding to the suffix of its variant's key
+var encodingExtensions = map[string]string{
+	EncodingGzip: ".gz",
+	EncodingZstd: ".zst",
+}
+
+// incompressibleExtensions lists artifact formats that are compressed
+// internally and gain nothing from a compressed variant
+var incompressibleExtensions = []string{".parquet"}
+
+// Compressible reports whether an artifact benefits from content encoding
+func Compressible(key string) bool {
+	return !slices.Contains(incompressibleExtensions, filepath.Ext(key))
+}
+
+// EncodedKey returns the key of an artifact's variant compressed with
+// encoding, e.g. "distance_20260124_<job ID>.csv.gz"
+func EncodedKey(key, encoding string) string {
+	return key + encodingExtensions[encoding]
+}
+
+// IsEncodedKey reports whether key names a compressed variant rather than an
+// artifact
+func IsEncodedKey(key string) bool {
+	for _, extension := range encodingExtensions {
+		if strings.HasSuffix(key, extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewEncoder returns a writer that compresses to w with encoding; closing it
+// flushes the compressed stream but does not close w
+func NewEncoder(w io.Writer, encoding string) (io.WriteCloser, error) {
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewWriter(w), nil
+	case EncodingZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+}
+
+// CompressedStore wraps a store so that every artifact is also written
+// compressed with each configured encoding, under EncodedKey, in the same
+// pass. The download handler serves those variants to clients that accept
+// them.
+type CompressedStore struct {
+	Store
+	encodings []string
+}
+
+// NewCompressedStore returns store writing a variant per encoding
+func NewCompressedStore(store Store, encodings []string) *CompressedStore {
+	return &CompressedStore{Store: store, encodings: encodings}
+}
+
+// Create starts writing the artifact and, unless it is not Compressible,
+// its compressed variants
+func (s *CompressedStore) Create(ctx context.Context, key string) (Writer, error) {
+	plain, err := s.Store.Create(ctx, key)
+	if err != nil || !Compressible(key) {
+		return plain, err
+	}
+
+	w := &compressedWriter{ctx: ctx, store: s.Store, plain: plain}
+	targets := []io.Writer{plain}
+	for _, encoding := range s.encodings {
+		file, err := s.Store.Create(ctx, EncodedKey(key, encoding))
+		if err != nil {
+			w.Abort()
+			return nil, err
+		}
+		encoder, err := NewEncoder(file, encoding)
+		if err != nil {
+			file.Abort()
+			w.Abort()
+			return nil, err
+		}
+		w.variants = append(w.variants, compressedVariant{key: EncodedKey(key, encoding), file: file, encoder: encoder})
+		targets = append(targets, encoder)
+	}
+	w.out = io.MultiWriter(targets...)
+	return w, nil
+}
+
+// Delete removes the artifact and its variants in every known encoding, not
+// just the configured ones, so variants written before the encodings changed
+// are removed too. The wrapped store removes each key's checksum record.
+func (s *CompressedStore) Delete(ctx context.Context, key string) error {
+	for _, encoding := range slices.Sorted(maps.Keys(encodingExtensions)) {
+		if err := s.Store.Delete(ctx, EncodedKey(key, encoding)); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+	return s.Store.Delete(ctx, key)
+}
+
+// List returns the stored artifacts without their compressed variants
+func (s *CompressedStore) List(ctx context.Context) ([]Info, error) {
+	infos, err := s.Store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(infos, func(info Info) bool { return IsEncodedKey(info.Key) }), nil
+}
+
+// compressedVariant is one compressed copy being written
+type compressedVariant struct {
+	key     string
+	file    Writer
+	encoder io.WriteCloser
+}
+
+// compressedWriter writes an artifact and its compressed variants
+type compressedWriter struct {
+	ctx      context.Context
+	store    Store
+	plain    Writer
+	variants []compressedVariant
+	out      io.Writer
+}
+
+// Write writes to the artifact and every encoder
+func (w *compressedWriter) Write(p []byte) (int, error) {
+	return w.out.Write(p)
+}
+
+// Commit stores the variants, then the artifact, so a stored artifact
+// always has its variants. On error nothing is left stored.
+func (w *compressedWriter) Commit() (Info, error) {
+	for i, v := range w.variants {
+		if err := v.encoder.Close(); err != nil {
+			w.rollback(i)
+			return Info{}, fmt.Errorf("failed to compress %s: %w", v.key, err)
+		}
+		if _, err := v.file.Commit(); err != nil {
+			w.rollback(i)
+			return Info{}, err
+		}
+	}
+	info, err := w.plain.Commit()
+	if err != nil {
+		w.rollback(len(w.variants))
+		return Info{}, err
+	}
+	return info, nil
+}
+
+// rollback deletes the first n variants, which were committed, and aborts
+// everything else
+func (w *compressedWriter) rollback(n int) {
+	for _, v := range w.variants[:n] {
+		if err := w.store.Delete(w.ctx, v.key); err != nil {
+			log.Error().Err(err).Str("key", v.key).Msg("Failed to delete compressed artifact")
+		}
+	}
+	for _, v := range w.variants[n:] {
+		_ = v.encoder.Close() // nolint:errcheck // the output is discarded
+		v.file.Abort()
+	}
+	w.plain.Abort()
+}
+
+// Abort discards the artifact and its variants
+func (w *compressedWriter) Abort() {
+	w.rollback(0)
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompressedStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewCompressedStore(NewLocalStore(dir), []string{EncodingGzip, EncodingZstd})
+	testStore(t, store)
+
+	// Aborted and deleted artifacts leave no variants behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to list store directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected an empty directory, got %d entries", len(entries))
+	}
+
+	ctx := context.Background()
+	w, err := store.Create(ctx, "distance_20260124.csv")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, "timestamp,device_id\n")
+	info, err := w.Commit()
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if info.Key != "distance_20260124.csv" || info.Size != 20 {
+		t.Errorf("expected the plain artifact's info, got %+v", info)
+	}
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		EncodingGzip: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		EncodingZstd: func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+	}
+	for encoding, decode := range decoders {
+		r, _, err := store.Open(ctx, EncodedKey("distance_20260124.csv", encoding))
+		if err != nil {
+			t.Fatalf("%s: Open failed: %v", encoding, err)
+		}
+		decoded, err := decode(r)
+		if err != nil {
+			t.Fatalf("%s: failed to decode: %v", encoding, err)
+		}
+		content, err := io.ReadAll(decoded)
+		_ = r.Close()
+		if err != nil || string(content) != "timestamp,device_id\n" {
+			t.Errorf("%s: unexpected content %q (%v)", encoding, content, err)
+		}
+	}
+
+	if err := store.Delete(ctx, "distance_20260124.csv"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected Delete to remove the variants, got %d entries", len(entries))
+	}
+}
+
+func TestCompressedStore_DeleteAfterEncodingsChange(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	before := NewCompressedStore(NewLocalStore(dir), []string{EncodingGzip, EncodingZstd})
+	w, err := before.Create(ctx, "distance_20260124.csv")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, "timestamp,device_id\n")
+	if _, err := w.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	// A restart with fewer encodings still removes the older variants and
+	// their checksum records
+	after := NewCompressedStore(NewLocalStore(dir), []string{EncodingGzip})
+	if err := after.Delete(ctx, "distance_20260124.csv"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to list store directory: %v", err)
+	}
+	for _, entry := range entries {
+		t.Errorf("expected Delete to remove every variant, found %s", entry.Name())
+	}
+}
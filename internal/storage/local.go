@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// checksumSuffix ends the name of the hidden file next to each artifact that
+// records its checksum, so Open need not read the whole artifact
+const checksumSuffix = ".sha256"
+
+// LocalStore keeps artifacts as files in a directory
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore returns a store for dir; the directory is created on the
+// first write
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+// Path returns the file an artifact is stored in
+func (s *LocalStore) Path(key string) string {
+	return filepath.Join(s.dir, key)
+}
+
+// checksumPath returns the file an artifact's checksum is recorded in
+func (s *LocalStore) checksumPath(key string) string {
+	return filepath.Join(s.dir, "."+key+checksumSuffix)
+}
+
+// Create writes the artifact to a hidden temporary file in the store's
+// directory, which is renamed into place on commit, so readers never see a
+// partially written file
+func (s *LocalStore) Create(_ context.Context, key string) (Writer, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(s.dir, "."+key+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", key, err)
+	}
+	return &localWriter{key: key, file: file, path: s.Path(key), sumPath: s.checksumPath(key), digest: newDigest()}, nil
+}
+
+// Open opens the artifact's file. Its checksum is the one recorded on commit;
+// it is computed from the content only when that record is missing or
+// describes an earlier version of the file.
+func (s *LocalStore) Open(_ context.Context, key string) (io.ReadSeekCloser, Info, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, Info{}, err
+	}
+
+	// #nosec G304 -- key is validated to be a plain file name
+	file, err := os.Open(s.Path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, Info{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, Info{}, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close() // nolint:errcheck // the stat error is reported
+		return nil, Info{}, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+	if stat.IsDir() {
+		_ = file.Close() // nolint:errcheck // read-only file
+		return nil, Info{}, ErrNotFound
+	}
+	sum, ok := readChecksum(s.checksumPath(key), stat)
+	if !ok {
+		if sum, err = checksum(file); err != nil {
+			_ = file.Close() // nolint:errcheck // the read error is reported
+			return nil, Info{}, fmt.Errorf("failed to read %s: %w", key, err)
+		}
+	}
+	return file, Info{Key: key, Size: stat.Size(), SHA256: sum, ModTime: stat.ModTime()}, nil
+}
+
+// Delete removes the artifact's file and its checksum record
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	for _, path := range []string{s.Path(key), s.checksumPath(key)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// List returns the files in the store's directory, skipping the hidden
+// temporary files of artifacts being written. A missing directory holds no
+// artifacts.
+func (s *LocalStore) List(_ context.Context) ([]Info, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list output directory: %w", err)
+	}
+
+	var infos []Info
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		stat, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue // deleted while listing
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", entry.Name(), err)
+		}
+		infos = append(infos, Info{Key: entry.Name(), Size: stat.Size(), ModTime: stat.ModTime()})
+	}
+	return infos, nil
+}
+
+// localWriter writes an artifact to a temporary file and renames it into
+// place on commit
+type localWriter struct {
+	key     string
+	file    *os.File
+	path    string // final path
+	sumPath string // checksum record
+	digest  *digest
+}
+
+// Write appends to the temporary file
+func (w *localWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	_, _ = w.digest.Write(p[:n]) // nolint:errcheck // hashes never fail
+	return n, err
+}
+
+// Commit flushes the temporary file to disk and renames it to the
+// artifact's path, replacing any previous artifact in one step
+func (w *localWriter) Commit() (Info, error) {
+	if err := w.file.Sync(); err != nil {
+		return Info{}, fmt.Errorf("failed to sync %s: %w", w.key, err)
+	}
+	if err := w.file.Close(); err != nil {
+		return Info{}, fmt.Errorf("failed to close %s: %w", w.key, err)
+	}
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		return Info{}, fmt.Errorf("failed to store %s: %w", w.key, err)
+	}
+
+	info := Info{Key: w.key, Size: w.digest.size, SHA256: w.digest.sum()}
+	if stat, err := os.Stat(w.path); err == nil {
+		info.ModTime = stat.ModTime()
+		// Without a record Open computes the checksum, so failing to write
+		// one only costs time
+		if err := writeChecksum(w.sumPath, info.SHA256, stat); err != nil {
+			log.Warn().Err(err).Str("key", w.key).Msg("Failed to record artifact checksum")
+		}
+	}
+	return info, nil
+}
+
+// Abort closes and removes the temporary file
+func (w *localWriter) Abort() {
+	if err := w.file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		log.Error().Err(err).Str("key", w.key).Msg("Failed to close artifact file")
+	}
+	if err := os.Remove(w.file.Name()); err != nil && !os.IsNotExist(err) {
+		log.Error().Err(err).Str("key", w.key).Msg("Failed to remove partial artifact file")
+	}
+}
+
+// writeChecksum records an artifact's checksum with the size and
+// modification time it was computed for, replacing any earlier record in one
+// step
+func writeChecksum(path, sum string, stat os.FileInfo) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(tmp, "%s %d %d\n", sum, stat.Size(), stat.ModTime().UnixNano())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name()) // nolint:errcheck // best effort cleanup
+	}
+	return err
+}
+
+// readChecksum returns the recorded checksum of an artifact if the record
+// matches the file's current size and modification time
+func readChecksum(path string, stat os.FileInfo) (string, bool) {
+	// #nosec G304 -- path is built from a validated key
+	record, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	var sum string
+	var size, modTime int64
+	if _, err := fmt.Sscanf(string(record), "%64s %d %d", &sum, &size, &modTime); err != nil || len(sum) != 64 {
+		return "", false
+	}
+	if size != stat.Size() || modTime != stat.ModTime().UnixNano() {
+		return "", false
+	}
+	return sum, true
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testStore writes, reads and deletes an artifact through store
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	w, err := store.Create(ctx, "distance_20260124.csv")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := io.WriteString(w, "timestamp,device_id\n"); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// Nothing is readable until the artifact is committed
+	if _, _, err := store.Open(ctx, "distance_20260124.csv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound before Commit, got %v", err)
+	}
+	committed, err := w.Commit()
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	// sha256 of "timestamp,device_id\n"
+	wantSum := "3c71ad2eac44e7e37acf7244daca84790f19c2594992aa23b4b914309e28710f"
+	if committed.Key != "distance_20260124.csv" || committed.Size != 20 || committed.SHA256 != wantSum {
+		t.Errorf("unexpected committed info %+v", committed)
+	}
+
+	r, info, err := store.Open(ctx, "distance_20260124.csv")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	content, err := io.ReadAll(r)
+	_ = r.Close()
+	if err != nil || string(content) != "timestamp,device_id\n" {
+		t.Errorf("unexpected content %q (%v)", content, err)
+	}
+	if info.Key != "distance_20260124.csv" || info.Size != int64(len(content)) || info.SHA256 != wantSum || info.ModTime.IsZero() {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	// An aborted artifact is never stored
+	w, err = store.Create(ctx, "distance_20260125.csv")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, "partial")
+	// Only committed artifacts are listed
+	infos, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Key != "distance_20260124.csv" || infos[0].Size != 20 || infos[0].ModTime.IsZero() {
+		t.Errorf("unexpected listing %+v", infos)
+	}
+	w.Abort()
+	if _, _, err := store.Open(ctx, "distance_20260125.csv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an aborted artifact, got %v", err)
+	}
+
+	if err := store.Delete(ctx, "distance_20260124.csv"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, _, err := store.Open(ctx, "distance_20260124.csv"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after Delete, got %v", err)
+	}
+	if err := store.Delete(ctx, "distance_20260124.csv"); err != nil {
+		t.Errorf("expected deleting a missing artifact to succeed, got %v", err)
+	}
+	if infos, err := store.List(ctx); err != nil || len(infos) != 0 {
+		t.Errorf("expected an empty listing, got %+v (%v)", infos, err)
+	}
+
+	for _, key := range []string{"", "../secret.csv", "reports/distance.csv", `..\distance.csv`} {
+		if _, err := store.Create(ctx, key); err == nil {
+			t.Errorf("expected error creating %q", key)
+		}
+		if _, _, err := store.Open(ctx, key); err == nil {
+			t.Errorf("expected error opening %q", key)
+		}
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir() + "/csv"
+	store := NewLocalStore(dir)
+	testStore(t, store)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to list store directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected an empty directory, got %d entries", len(entries))
+	}
+
+	if infos, err := NewLocalStore(t.TempDir() + "/missing").List(t.Context()); err != nil || len(infos) != 0 {
+		t.Errorf("expected an empty listing for a missing directory, got %+v (%v)", infos, err)
+	}
+}
+
+func TestLocalStore_ChecksumRecord(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir)
+	ctx := t.Context()
+
+	w, err := store.Create(ctx, "distance_20260124.csv")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, _ = io.WriteString(w, "timestamp,device_id\n")
+	committed, err := w.Commit()
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	// Open trusts a record that matches the file rather than reading it
+	record, err := os.ReadFile(store.checksumPath("distance_20260124.csv"))
+	if err != nil {
+		t.Fatalf("expected a checksum record: %v", err)
+	}
+	fake := strings.Repeat("0", 64)
+	if err := os.WriteFile(store.checksumPath("distance_20260124.csv"), []byte(strings.Replace(string(record), committed.SHA256, fake, 1)), 0600); err != nil {
+		t.Fatalf("failed to rewrite record: %v", err)
+	}
+	r, info, err := store.Open(ctx, "distance_20260124.csv")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	_ = r.Close()
+	if info.SHA256 != fake {
+		t.Errorf("expected the recorded checksum, got %s", info.SHA256)
+	}
+
+	// A file changed behind the store's back is hashed again
+	later := info.ModTime.Add(time.Minute)
+	if err := os.WriteFile(store.Path("distance_20260124.csv"), []byte("timestamp,device_ix\n"), 0600); err != nil {
+		t.Fatalf("failed to rewrite artifact: %v", err)
+	}
+	if err := os.Chtimes(store.Path("distance_20260124.csv"), later, later); err != nil {
+		t.Fatalf("failed to touch artifact: %v", err)
+	}
+	r, info, err = store.Open(ctx, "distance_20260124.csv")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	_ = r.Close()
+	if info.SHA256 == fake || info.SHA256 == committed.SHA256 || len(info.SHA256) != 64 {
+		t.Errorf("expected a recomputed checksum, got %s", info.SHA256)
+	}
+
+	// Records are hidden from listings and deleted with their artifact
+	if infos, err := store.List(ctx); err != nil || len(infos) != 1 {
+		t.Errorf("expected one listed artifact, got %+v (%v)", infos, err)
+	}
+	if err := store.Delete(ctx, "distance_20260124.csv"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected an empty directory after Delete, got %d entries", len(entries))
+	}
+}
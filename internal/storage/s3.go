@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/rs/zerolog/log"
+)
+
+// S3Config locates a bucket on AWS S3 or an S3-compatible server such as
+// MinIO
+type S3Config struct {
+	Endpoint        string // host[:port], e.g. "minio.minio.svc:9000"
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UseSSL          bool
+	Prefix          string // prepended to every key, e.g. "otel-worker/"
+}
+
+// s3ChecksumMetadata is the user metadata field holding an object's SHA-256
+// checksum, as minio-go reports it
+const s3ChecksumMetadata = "Sha256"
+
+// S3Store keeps artifacts as objects in a bucket
+type S3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store returns a store for the configured bucket; the bucket must
+// already exist
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 endpoint and bucket are required")
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	return &S3Store{client: client, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+// HealthCheck verifies that the bucket is reachable
+func (s *S3Store) HealthCheck(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("failed to reach bucket %s: %w", s.bucket, err)
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucket)
+	}
+	return nil
+}
+
+// Create spools the artifact to a temporary file and uploads it on commit,
+// so the upload has a known size and a failed job uploads nothing
+func (s *S3Store) Create(ctx context.Context, key string) (Writer, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+	spool, err := os.CreateTemp("", "otel-worker-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spool file for %s: %w", key, err)
+	}
+	return &s3Writer{ctx: ctx, store: s, key: key, spool: spool, digest: newDigest()}, nil
+}
+
+// Open returns a reader over the object. The checksum comes from the
+// object's metadata and is empty for objects uploaded without one.
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadSeekCloser, Info, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, Info{}, err
+	}
+
+	stat, err := s.client.StatObject(ctx, s.bucket, s.prefix+key, minio.StatObjectOptions{})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, Info{}, ErrNotFound
+		}
+		return nil, Info{}, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+	object, err := s.client.GetObject(ctx, s.bucket, s.prefix+key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, Info{}, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+	return object, Info{Key: key, Size: stat.Size, SHA256: stat.UserMetadata[s3ChecksumMetadata], ModTime: stat.LastModified}, nil
+}
+
+// Delete removes the object
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, s.prefix+key, minio.RemoveObjectOptions{}); err != nil && !isS3NotFound(err) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// List returns the objects under the store's prefix. Listings carry no user
+// metadata, so checksums are left empty.
+func (s *S3Store) List(ctx context.Context) ([]Info, error) {
+	var infos []Info
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.prefix}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list artifacts: %w", object.Err)
+		}
+		key := strings.TrimPrefix(object.Key, s.prefix)
+		if ValidateKey(key) != nil {
+			continue // nested under the prefix, not written by this store
+		}
+		infos = append(infos, Info{Key: key, Size: object.Size, ModTime: object.LastModified})
+	}
+	return infos, nil
+}
+
+// isS3NotFound reports whether err means the object does not exist
+func isS3NotFound(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	return resp.Code == "NoSuchKey" || resp.StatusCode == http.StatusNotFound
+}
+
+// s3Writer spools an artifact before uploading it
+type s3Writer struct {
+	ctx    context.Context
+	store  *S3Store
+	key    string
+	spool  *os.File
+	digest *digest
+}
+
+// Write appends to the spool file
+func (w *s3Writer) Write(p []byte) (int, error) {
+	n, err := w.spool.Write(p)
+	_, _ = w.digest.Write(p[:n]) // nolint:errcheck // hashes never fail
+	return n, err
+}
+
+// Commit uploads the spool file with its checksum and removes it. S3
+// replaces objects atomically, so readers see the old or the new artifact.
+func (w *s3Writer) Commit() (Info, error) {
+	defer w.Abort()
+
+	if _, err := w.spool.Seek(0, io.SeekStart); err != nil {
+		return Info{}, fmt.Errorf("failed to rewind %s: %w", w.key, err)
+	}
+	sum := w.digest.sum()
+	upload, err := w.store.client.PutObject(w.ctx, w.store.bucket, w.store.prefix+w.key, w.spool, w.digest.size, minio.PutObjectOptions{
+		UserMetadata: map[string]string{s3ChecksumMetadata: sum},
+	})
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to upload %s: %w", w.key, err)
+	}
+	return Info{Key: w.key, Size: w.digest.size, SHA256: sum, ModTime: upload.LastModified}, nil
+}
+
+// Abort removes the spool file
+func (w *s3Writer) Abort() {
+	if err := w.spool.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		log.Error().Err(err).Str("key", w.key).Msg("Failed to close spool file")
+	}
+	if err := os.Remove(w.spool.Name()); err != nil && !os.IsNotExist(err) {
+		log.Error().Err(err).Str("key", w.key).Msg("Failed to remove spool file")
+	}
+}
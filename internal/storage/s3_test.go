@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is an in-memory S3 server for one bucket, with just enough of the
+// API for S3Store
+type fakeS3 struct {
+	mu       sync.Mutex
+	bucket   string
+	objects  map[string][]byte
+	metadata map[string]http.Header // X-Amz-Meta- headers per object
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if key == "" {
+		if r.Method == http.MethodGet {
+			f.list(w, r.URL.Query().Get("prefix"))
+		}
+		// HEAD bucket for BucketExists
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := readS3Body(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.metadata[key] = http.Header{}
+		for name, values := range r.Header {
+			if strings.HasPrefix(name, "X-Amz-Meta-") {
+				f.metadata[key][name] = values
+			}
+		}
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodHead, http.MethodGet:
+		content, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>missing</Message></Error>`)
+			}
+			return
+		}
+		for name, values := range f.metadata[key] {
+			w.Header()[name] = values
+		}
+		w.Header().Set("ETag", `"etag"`)
+		http.ServeContent(w, r, key, time.Date(2026, 1, 24, 12, 0, 0, 0, time.UTC), bytes.NewReader(content))
+	case http.MethodDelete:
+		delete(f.objects, key)
+		delete(f.metadata, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// s3Listing is a ListObjectsV2 response
+type s3Listing struct {
+	XMLName     xml.Name `xml:"ListBucketResult"`
+	Name        string
+	Prefix      string
+	KeyCount    int
+	MaxKeys     int
+	IsTruncated bool
+	Contents    []s3ListedObject
+}
+
+// s3ListedObject is one object of a listing
+type s3ListedObject struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int
+}
+
+// list writes the objects under prefix in key order
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	listing := s3Listing{Name: f.bucket, Prefix: prefix, MaxKeys: 1000}
+	for key, content := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			listing.Contents = append(listing.Contents, s3ListedObject{Key: key, LastModified: "2026-01-24T12:00:00.000Z", ETag: `"etag"`, Size: len(content)})
+		}
+	}
+	sort.Slice(listing.Contents, func(i, j int) bool { return listing.Contents[i].Key < listing.Contents[j].Key })
+	listing.KeyCount = len(listing.Contents)
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(listing)
+}
+
+// readS3Body returns an upload's content, decoding aws-chunked streaming
+// uploads
+func readS3Body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+	var content []byte
+	reader := bufio.NewReader(r.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return content, nil
+		}
+		chunk := make([]byte, size+2) // data and CRLF
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return nil, err
+		}
+		content = append(content, chunk[:size]...)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{bucket: "artifacts", objects: make(map[string][]byte), metadata: make(map[string]http.Header)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		Bucket:          "artifacts",
+		Region:          "us-east-1",
+		AccessKeyID:     "minio",
+		SecretAccessKey: "minio123",
+		Prefix:          "otel-worker-",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store failed: %v", err)
+	}
+	if err := store.HealthCheck(t.Context()); err != nil {
+		t.Fatalf("HealthCheck failed: %v", err)
+	}
+
+	testStore(t, store)
+
+	if len(fake.objects) != 0 {
+		t.Errorf("expected an empty bucket, got %v", fake.objects)
+	}
+
+	t.Run("prefix", func(t *testing.T) {
+		w, err := store.Create(t.Context(), "distance_20260126.gpx")
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		_, _ = io.WriteString(w, "<gpx/>")
+		if _, err := w.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		if got := string(fake.objects["otel-worker-distance_20260126.gpx"]); got != "<gpx/>" {
+			t.Errorf("expected the object under the prefix, got %v", fake.objects)
+		}
+
+		// Objects outside the prefix are not the store's
+		fake.objects["other-distance_20260127.gpx"] = []byte("<gpx/>")
+		infos, err := store.List(t.Context())
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if len(infos) != 1 || infos[0].Key != "distance_20260126.gpx" || infos[0].Size != 6 {
+			t.Errorf("unexpected listing %+v", infos)
+		}
+	})
+
+	t.Run("missing bucket", func(t *testing.T) {
+		missing, err := NewS3Store(S3Config{Endpoint: strings.TrimPrefix(server.URL, "http://"), Bucket: "other", Region: "us-east-1"})
+		if err != nil {
+			t.Fatalf("NewS3Store failed: %v", err)
+		}
+		if err := missing.HealthCheck(t.Context()); err == nil {
+			t.Error("expected error for a missing bucket")
+		}
+	})
+
+	if _, err := NewS3Store(S3Config{Bucket: "artifacts"}); err == nil {
+		t.Error("expected error without an endpoint")
+	}
+}
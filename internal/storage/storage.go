@@ -0,0 +1,97 @@
+// Package storage keeps the files jobs produce, on a local volume or in an
+// S3-compatible object store, so any replica can serve them.
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when no artifact is stored under a key
+var ErrNotFound = errors.New("artifact not found")
+
+// Store holds job artifacts under flat keys such as
+// "distance_20260124.csv". LocalStore writes to a directory; S3Store to a
+// bucket.
+type Store interface {
+	// Create starts writing the artifact; it is stored once the writer is
+	// committed and discarded if the writer is aborted
+	Create(ctx context.Context, key string) (Writer, error)
+	// Open returns the artifact's content and details, or ErrNotFound
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, Info, error)
+	// Delete removes the artifact; deleting a missing artifact is not an error
+	Delete(ctx context.Context, key string) error
+	// List returns every stored artifact. Checksums are left empty where
+	// the backend cannot report them without reading the content.
+	List(ctx context.Context) ([]Info, error)
+}
+
+// Writer writes one artifact
+type Writer interface {
+	io.Writer
+	// Commit stores what was written and returns its details
+	Commit() (Info, error)
+	// Abort discards what was written; it is safe to call after Commit
+	// failed
+	Abort()
+}
+
+// Info describes a stored artifact
+type Info struct {
+	Key     string
+	Size    int64
+	SHA256  string // hex encoded checksum of the content
+	ModTime time.Time
+}
+
+// ValidateKey rejects keys that are empty or could leave the store's
+// directory or prefix
+func ValidateKey(key string) error {
+	if key == "" || key == "." || strings.ContainsAny(key, `/\`) || strings.Contains(key, "..") {
+		return fmt.Errorf("invalid artifact key %q", key)
+	}
+	return nil
+}
+
+// digest tracks the size and SHA-256 checksum of an artifact as it is
+// written
+type digest struct {
+	hash hash.Hash
+	size int64
+}
+
+// newDigest returns an empty digest
+func newDigest() *digest {
+	return &digest{hash: sha256.New()}
+}
+
+// Write adds p to the checksum
+func (d *digest) Write(p []byte) (int, error) {
+	d.size += int64(len(p))
+	return d.hash.Write(p)
+}
+
+// sum returns the hex encoded checksum
+func (d *digest) sum() string {
+	return hex.EncodeToString(d.hash.Sum(nil))
+}
+
+// checksum returns the hex encoded SHA-256 checksum of r's content and
+// rewinds it
+func checksum(r io.ReadSeeker) (string, error) {
+	d := newDigest()
+	if _, err := io.Copy(d, r); err != nil {
+		return "", err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return d.sum(), nil
+}
@@ -30,7 +30,18 @@ type CalculateDistanceRequest struct {
 	Date string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
 	// device_id optionally filters results to a specific OwnTracks device
 	// If empty, processes all devices for the date
-	DeviceId      string `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	DeviceId string `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// source selects the GPS sources: "owntracks" (default when empty),
+	// "garmin", or "all". Garmin points are not filtered by device_id, and
+	// OwnTracks fixes that duplicate a Garmin point are dropped.
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	// output_formats selects the files written: any of "csv", "gpx",
+	// "geojson", "kml" and "parquet" (default: csv). GPX 1.1 has one track per
+	// device; GeoJSON and KML hold a line per trip and points for stays and
+	// home; Parquet has typed columns with the summary in the file metadata.
+	OutputFormats []string `protobuf:"bytes,4,rep,name=output_formats,json=outputFormats,proto3" json:"output_formats,omitempty"`
+	// csv_template shapes the CSV report; unset keeps the default layout
+	CsvTemplate   *CsvTemplate `protobuf:"bytes,5,opt,name=csv_template,json=csvTemplate,proto3" json:"csv_template,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -79,6 +90,135 @@ func (x *CalculateDistanceRequest) GetDeviceId() string {
 	return ""
 }
 
+func (x *CalculateDistanceRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CalculateDistanceRequest) GetOutputFormats() []string {
+	if x != nil {
+		return x.OutputFormats
+	}
+	return nil
+}
+
+func (x *CalculateDistanceRequest) GetCsvTemplate() *CsvTemplate {
+	if x != nil {
+		return x.CsvTemplate
+	}
+	return nil
+}
+
+// CsvTemplate selects the columns, units and formatting of a distance CSV
+// report so it opens cleanly in a spreadsheet.
+type CsvTemplate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// columns selects and orders the columns by header name, e.g.
+	// ["timestamp", "distance_from_home_km"]; empty keeps every column
+	Columns []string `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	// units is "km" (default) or "mi". With "mi", distance_from_home_km and
+	// speed_kmh become distance_from_home_mi and speed_mph, and summary
+	// distances and speeds are converted too.
+	Units string `protobuf:"bytes,2,opt,name=units,proto3" json:"units,omitempty"`
+	// precision sets the decimals of measurement columns and summary values;
+	// unset keeps each column's default. Coordinates keep six decimals.
+	Precision *int32 `protobuf:"varint,3,opt,name=precision,proto3,oneof" json:"precision,omitempty"`
+	// timezone is the IANA zone timestamps are written in, e.g.
+	// "America/New_York"; empty keeps UTC
+	Timezone string `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// timestamp_format is "rfc3339" (default, with offset) or "spreadsheet"
+	// ("2006-01-02 15:04:05", no offset)
+	TimestampFormat string `protobuf:"bytes,5,opt,name=timestamp_format,json=timestampFormat,proto3" json:"timestamp_format,omitempty"`
+	// delimiter separates fields: "," (default), ";", "\t" (tab) or "|"
+	Delimiter string `protobuf:"bytes,6,opt,name=delimiter,proto3" json:"delimiter,omitempty"`
+	// footer places the summary: "inline" (default, after the data rows),
+	// "separate" (a second CSV artifact with format "csv_summary") or "none"
+	Footer        string `protobuf:"bytes,7,opt,name=footer,proto3" json:"footer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CsvTemplate) Reset() {
+	*x = CsvTemplate{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CsvTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CsvTemplate) ProtoMessage() {}
+
+func (x *CsvTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CsvTemplate.ProtoReflect.Descriptor instead.
+func (*CsvTemplate) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CsvTemplate) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *CsvTemplate) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *CsvTemplate) GetPrecision() int32 {
+	if x != nil && x.Precision != nil {
+		return *x.Precision
+	}
+	return 0
+}
+
+func (x *CsvTemplate) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *CsvTemplate) GetTimestampFormat() string {
+	if x != nil {
+		return x.TimestampFormat
+	}
+	return ""
+}
+
+func (x *CsvTemplate) GetDelimiter() string {
+	if x != nil {
+		return x.Delimiter
+	}
+	return ""
+}
+
+func (x *CsvTemplate) GetFooter() string {
+	if x != nil {
+		return x.Footer
+	}
+	return ""
+}
+
 // CalculateDistanceResponse contains the job ID for async processing.
 type CalculateDistanceResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -94,7 +234,7 @@ type CalculateDistanceResponse struct {
 
 func (x *CalculateDistanceResponse) Reset() {
 	*x = CalculateDistanceResponse{}
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[1]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -106,7 +246,7 @@ func (x *CalculateDistanceResponse) String() string {
 func (*CalculateDistanceResponse) ProtoMessage() {}
 
 func (x *CalculateDistanceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[1]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -119,7 +259,7 @@ func (x *CalculateDistanceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CalculateDistanceResponse.ProtoReflect.Descriptor instead.
 func (*CalculateDistanceResponse) Descriptor() ([]byte, []int) {
-	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{1}
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CalculateDistanceResponse) GetJobId() string {
@@ -154,7 +294,7 @@ type GetJobStatusRequest struct {
 
 func (x *GetJobStatusRequest) Reset() {
 	*x = GetJobStatusRequest{}
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[2]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -166,7 +306,7 @@ func (x *GetJobStatusRequest) String() string {
 func (*GetJobStatusRequest) ProtoMessage() {}
 
 func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[2]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -179,7 +319,7 @@ func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetJobStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetJobStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{2}
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetJobStatusRequest) GetJobId() string {
@@ -212,7 +352,7 @@ type GetJobStatusResponse struct {
 
 func (x *GetJobStatusResponse) Reset() {
 	*x = GetJobStatusResponse{}
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[3]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -224,7 +364,7 @@ func (x *GetJobStatusResponse) String() string {
 func (*GetJobStatusResponse) ProtoMessage() {}
 
 func (x *GetJobStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[3]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -237,7 +377,7 @@ func (x *GetJobStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetJobStatusResponse.ProtoReflect.Descriptor instead.
 func (*GetJobStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{3}
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetJobStatusResponse) GetJobId() string {
@@ -309,7 +449,7 @@ type ListJobsRequest struct {
 
 func (x *ListJobsRequest) Reset() {
 	*x = ListJobsRequest{}
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[4]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -321,7 +461,7 @@ func (x *ListJobsRequest) String() string {
 func (*ListJobsRequest) ProtoMessage() {}
 
 func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[4]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,7 +474,7 @@ func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
 func (*ListJobsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{4}
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ListJobsRequest) GetStatus() string {
@@ -389,7 +529,7 @@ type ListJobsResponse struct {
 
 func (x *ListJobsResponse) Reset() {
 	*x = ListJobsResponse{}
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[5]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -401,7 +541,7 @@ func (x *ListJobsResponse) String() string {
 func (*ListJobsResponse) ProtoMessage() {}
 
 func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[5]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -414,7 +554,7 @@ func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
 func (*ListJobsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{5}
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ListJobsResponse) GetJobs() []*JobSummary {
@@ -459,14 +599,22 @@ type JobSummary struct {
 	// queued_at is the UTC timestamp when the job was created
 	QueuedAt *timestamp.Timestamp `protobuf:"bytes,5,opt,name=queued_at,json=queuedAt,proto3" json:"queued_at,omitempty"`
 	// completed_at is the UTC timestamp when the job finished (null if not complete)
-	CompletedAt   *timestamp.Timestamp `protobuf:"bytes,6,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	CompletedAt *timestamp.Timestamp `protobuf:"bytes,6,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// kind is the job type: "distance" or "backfill"
+	Kind string `protobuf:"bytes,7,opt,name=kind,proto3" json:"kind,omitempty"`
+	// end_date is the last date (inclusive) for range jobs such as backfills
+	EndDate string `protobuf:"bytes,8,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// activity_id is the Garmin activity for "activity" jobs
+	ActivityId int64 `protobuf:"varint,9,opt,name=activity_id,json=activityId,proto3" json:"activity_id,omitempty"`
+	// source is the GPS source filter of "distance" jobs
+	Source        string `protobuf:"bytes,10,opt,name=source,proto3" json:"source,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *JobSummary) Reset() {
 	*x = JobSummary{}
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[6]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -478,7 +626,7 @@ func (x *JobSummary) String() string {
 func (*JobSummary) ProtoMessage() {}
 
 func (x *JobSummary) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[6]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -491,7 +639,7 @@ func (x *JobSummary) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobSummary.ProtoReflect.Descriptor instead.
 func (*JobSummary) Descriptor() ([]byte, []int) {
-	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{6}
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *JobSummary) GetJobId() string {
@@ -536,11 +684,40 @@ func (x *JobSummary) GetCompletedAt() *timestamp.Timestamp {
 	return nil
 }
 
+func (x *JobSummary) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *JobSummary) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *JobSummary) GetActivityId() int64 {
+	if x != nil {
+		return x.ActivityId
+	}
+	return 0
+}
+
+func (x *JobSummary) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
 // JobResult contains the output of a completed distance calculation job.
 type JobResult struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// csv_path is the Kubernetes volume path to the generated CSV file
-	// Format: distance_YYYYMMDD.csv
+	// csv_path is the storage key of the generated CSV file; download it with
+	// download_url
+	// Format: distance_YYYYMMDD_{job_id}.csv
 	CsvPath string `protobuf:"bytes,1,opt,name=csv_path,json=csvPath,proto3" json:"csv_path,omitempty"`
 	// total_distance_km is the sum of all distance segments in kilometers
 	TotalDistanceKm float64 `protobuf:"fixed64,2,opt,name=total_distance_km,json=totalDistanceKm,proto3" json:"total_distance_km,omitempty"`
@@ -556,13 +733,39 @@ type JobResult struct {
 	DeviceId string `protobuf:"bytes,7,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
 	// processing_time_ms is the job execution duration in milliseconds
 	ProcessingTimeMs int64 `protobuf:"varint,8,opt,name=processing_time_ms,json=processingTimeMs,proto3" json:"processing_time_ms,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// max_speed_kmh is the highest computed speed between consecutive fixes
+	MaxSpeedKmh float64 `protobuf:"fixed64,9,opt,name=max_speed_kmh,json=maxSpeedKmh,proto3" json:"max_speed_kmh,omitempty"`
+	// mode_totals is the distance and time spent in each movement mode
+	// (stationary, walking, cycling, driving, flying)
+	ModeTotals []*ModeTotal `protobuf:"bytes,10,rep,name=mode_totals,json=modeTotals,proto3" json:"mode_totals,omitempty"`
+	// elevation is the smoothed climb statistics for all locations processed
+	Elevation *ElevationStats `protobuf:"bytes,11,opt,name=elevation,proto3" json:"elevation,omitempty"`
+	// trip_elevations is the elevation profile and climb statistics per trip
+	TripElevations []*TripElevation `protobuf:"bytes,12,rep,name=trip_elevations,json=tripElevations,proto3" json:"trip_elevations,omitempty"`
+	// summaries_written is the number of daily summary rows upserted
+	SummariesWritten int32 `protobuf:"varint,13,opt,name=summaries_written,json=summariesWritten,proto3" json:"summaries_written,omitempty"`
+	// activity is the per-activity metrics for "activity" jobs
+	Activity *ActivityMetrics `protobuf:"bytes,14,opt,name=activity,proto3" json:"activity,omitempty"`
+	// source is the GPS source filter the job was run with
+	Source string `protobuf:"bytes,15,opt,name=source,proto3" json:"source,omitempty"`
+	// gpx_path is the storage key of the GPX file, when "gpx" was requested
+	// Format: distance_YYYYMMDD_{job_id}.gpx
+	GpxPath string `protobuf:"bytes,16,opt,name=gpx_path,json=gpxPath,proto3" json:"gpx_path,omitempty"`
+	// artifacts lists every file the job wrote, in the order requested
+	Artifacts []*Artifact `protobuf:"bytes,17,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	// download_url is a signed URL for the job's first artifact (the CSV
+	// unless other formats were requested), set by GetJobStatus
+	DownloadUrl string `protobuf:"bytes,18,opt,name=download_url,json=downloadUrl,proto3" json:"download_url,omitempty"`
+	// download_expires_at is when download_url and the artifacts'
+	// download_url stop working; call GetJobStatus again for fresh URLs
+	DownloadExpiresAt *timestamp.Timestamp `protobuf:"bytes,19,opt,name=download_expires_at,json=downloadExpiresAt,proto3" json:"download_expires_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *JobResult) Reset() {
 	*x = JobResult{}
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[7]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -574,7 +777,7 @@ func (x *JobResult) String() string {
 func (*JobResult) ProtoMessage() {}
 
 func (x *JobResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_distance_v1_distance_proto_msgTypes[7]
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -587,7 +790,7 @@ func (x *JobResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JobResult.ProtoReflect.Descriptor instead.
 func (*JobResult) Descriptor() ([]byte, []int) {
-	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{7}
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *JobResult) GetCsvPath() string {
@@ -646,62 +849,4134 @@ func (x *JobResult) GetProcessingTimeMs() int64 {
 	return 0
 }
 
-var File_proto_distance_v1_distance_proto protoreflect.FileDescriptor
+func (x *JobResult) GetMaxSpeedKmh() float64 {
+	if x != nil {
+		return x.MaxSpeedKmh
+	}
+	return 0
+}
 
-const file_proto_distance_v1_distance_proto_rawDesc = "" +
-	"\n" +
-	" proto/distance/v1/distance.proto\x12\vdistance.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"K\n" +
-	"\x18CalculateDistanceRequest\x12\x12\n" +
-	"\x04date\x18\x01 \x01(\tR\x04date\x12\x1b\n" +
-	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\"\x83\x01\n" +
-	"\x19CalculateDistanceResponse\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\x127\n" +
-	"\tqueued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bqueuedAt\",\n" +
-	"\x13GetJobStatusRequest\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xcd\x02\n" +
-	"\x14GetJobStatusResponse\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\x127\n" +
-	"\tqueued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bqueuedAt\x129\n" +
-	"\n" +
-	"started_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
-	"\fcompleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12#\n" +
-	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage\x12.\n" +
-	"\x06result\x18\a \x01(\v2\x16.distance.v1.JobResultR\x06result\"\x88\x01\n" +
-	"\x0fListJobsRequest\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x12\n" +
-	"\x04date\x18\x04 \x01(\tR\x04date\x12\x1b\n" +
-	"\tdevice_id\x18\x05 \x01(\tR\bdeviceId\"\x8e\x01\n" +
-	"\x10ListJobsResponse\x12+\n" +
-	"\x04jobs\x18\x01 \x03(\v2\x17.distance.v1.JobSummaryR\x04jobs\x12\x1f\n" +
-	"\vtotal_count\x18\x02 \x01(\x05R\n" +
-	"totalCount\x12\x14\n" +
-	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\xe4\x01\n" +
-	"\n" +
-	"JobSummary\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
-	"\x04date\x18\x03 \x01(\tR\x04date\x12\x1b\n" +
-	"\tdevice_id\x18\x04 \x01(\tR\bdeviceId\x127\n" +
-	"\tqueued_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bqueuedAt\x12=\n" +
-	"\fcompleted_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\xaa\x02\n" +
-	"\tJobResult\x12\x19\n" +
-	"\bcsv_path\x18\x01 \x01(\tR\acsvPath\x12*\n" +
-	"\x11total_distance_km\x18\x02 \x01(\x01R\x0ftotalDistanceKm\x12'\n" +
-	"\x0ftotal_locations\x18\x03 \x01(\x05R\x0etotalLocations\x12&\n" +
-	"\x0fmax_distance_km\x18\x04 \x01(\x01R\rmaxDistanceKm\x12&\n" +
-	"\x0fmin_distance_km\x18\x05 \x01(\x01R\rminDistanceKm\x12\x12\n" +
-	"\x04date\x18\x06 \x01(\tR\x04date\x12\x1b\n" +
-	"\tdevice_id\x18\a \x01(\tR\bdeviceId\x12,\n" +
-	"\x12processing_time_ms\x18\b \x01(\x03R\x10processingTimeMs2\x9b\x02\n" +
+func (x *JobResult) GetModeTotals() []*ModeTotal {
+	if x != nil {
+		return x.ModeTotals
+	}
+	return nil
+}
+
+func (x *JobResult) GetElevation() *ElevationStats {
+	if x != nil {
+		return x.Elevation
+	}
+	return nil
+}
+
+func (x *JobResult) GetTripElevations() []*TripElevation {
+	if x != nil {
+		return x.TripElevations
+	}
+	return nil
+}
+
+func (x *JobResult) GetSummariesWritten() int32 {
+	if x != nil {
+		return x.SummariesWritten
+	}
+	return 0
+}
+
+func (x *JobResult) GetActivity() *ActivityMetrics {
+	if x != nil {
+		return x.Activity
+	}
+	return nil
+}
+
+func (x *JobResult) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *JobResult) GetGpxPath() string {
+	if x != nil {
+		return x.GpxPath
+	}
+	return ""
+}
+
+func (x *JobResult) GetArtifacts() []*Artifact {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *JobResult) GetDownloadUrl() string {
+	if x != nil {
+		return x.DownloadUrl
+	}
+	return ""
+}
+
+func (x *JobResult) GetDownloadExpiresAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.DownloadExpiresAt
+	}
+	return nil
+}
+
+// Artifact is one output file of a job.
+type Artifact struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// format is the file format: "csv", "gpx", "geojson", "kml" or "parquet"
+	Format string `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	// key is the file's storage key
+	// Format: distance_YYYYMMDD_{job_id}.geojson
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// download_url is a signed, expiring URL for the file, set only by
+	// GetJobStatus
+	DownloadUrl string `protobuf:"bytes,3,opt,name=download_url,json=downloadUrl,proto3" json:"download_url,omitempty"`
+	// size_bytes is the file's size
+	SizeBytes int64 `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	// sha256 is the hex encoded SHA-256 checksum of the file, also sent as
+	// the X-Checksum-SHA256 header of its download. Empty in listings of
+	// files found in storage on startup until GetArtifact reads them.
+	Sha256 string `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// job_id is the job that wrote the file, set by the artifact catalog RPCs
+	JobId string `protobuf:"bytes,6,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// date is the job's calculation date (YYYY-MM-DD); empty for activity
+	// files
+	Date string `protobuf:"bytes,7,opt,name=date,proto3" json:"date,omitempty"`
+	// device_id is the job's OwnTracks device (empty if all devices)
+	DeviceId string `protobuf:"bytes,8,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// created_at is when the file was stored
+	CreatedAt     *timestamp.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Artifact) Reset() {
+	*x = Artifact{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Artifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Artifact) ProtoMessage() {}
+
+func (x *Artifact) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Artifact.ProtoReflect.Descriptor instead.
+func (*Artifact) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Artifact) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *Artifact) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Artifact) GetDownloadUrl() string {
+	if x != nil {
+		return x.DownloadUrl
+	}
+	return ""
+}
+
+func (x *Artifact) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Artifact) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Artifact) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *Artifact) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Artifact) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Artifact) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// ModeTotal summarizes the time and distance spent in one movement mode.
+type ModeTotal struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// mode is one of: "stationary", "walking", "cycling", "driving", "flying"
+	Mode string `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	// distance_km is the path distance travelled in this mode in kilometers
+	DistanceKm float64 `protobuf:"fixed64,2,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	// duration_seconds is the time spent in this mode
+	DurationSeconds int64 `protobuf:"varint,3,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ModeTotal) Reset() {
+	*x = ModeTotal{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModeTotal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModeTotal) ProtoMessage() {}
+
+func (x *ModeTotal) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModeTotal.ProtoReflect.Descriptor instead.
+func (*ModeTotal) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ModeTotal) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ModeTotal) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *ModeTotal) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+// ElevationStats contains climb statistics derived from smoothed GPS altitude.
+type ElevationStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// total_ascent_m is the cumulative elevation gain in meters
+	TotalAscentM float64 `protobuf:"fixed64,1,opt,name=total_ascent_m,json=totalAscentM,proto3" json:"total_ascent_m,omitempty"`
+	// total_descent_m is the cumulative elevation loss in meters
+	TotalDescentM float64 `protobuf:"fixed64,2,opt,name=total_descent_m,json=totalDescentM,proto3" json:"total_descent_m,omitempty"`
+	// min_altitude_m is the lowest smoothed altitude in meters
+	MinAltitudeM float64 `protobuf:"fixed64,3,opt,name=min_altitude_m,json=minAltitudeM,proto3" json:"min_altitude_m,omitempty"`
+	// max_altitude_m is the highest smoothed altitude in meters
+	MaxAltitudeM  float64 `protobuf:"fixed64,4,opt,name=max_altitude_m,json=maxAltitudeM,proto3" json:"max_altitude_m,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ElevationStats) Reset() {
+	*x = ElevationStats{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ElevationStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ElevationStats) ProtoMessage() {}
+
+func (x *ElevationStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ElevationStats.ProtoReflect.Descriptor instead.
+func (*ElevationStats) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ElevationStats) GetTotalAscentM() float64 {
+	if x != nil {
+		return x.TotalAscentM
+	}
+	return 0
+}
+
+func (x *ElevationStats) GetTotalDescentM() float64 {
+	if x != nil {
+		return x.TotalDescentM
+	}
+	return 0
+}
+
+func (x *ElevationStats) GetMinAltitudeM() float64 {
+	if x != nil {
+		return x.MinAltitudeM
+	}
+	return 0
+}
+
+func (x *ElevationStats) GetMaxAltitudeM() float64 {
+	if x != nil {
+		return x.MaxAltitudeM
+	}
+	return 0
+}
+
+// TripElevation describes the elevation profile of a single trip.
+type TripElevation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_time is the UTC timestamp of the first fix in the trip
+	StartTime *timestamp.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// end_time is the UTC timestamp of the last fix in the trip
+	EndTime *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// distance_km is the path distance of the trip in kilometers
+	DistanceKm float64 `protobuf:"fixed64,3,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	// elevation is the climb statistics for the trip
+	Elevation *ElevationStats `protobuf:"bytes,4,opt,name=elevation,proto3" json:"elevation,omitempty"`
+	// profile is the smoothed altitude against distance along the trip
+	// (downsampled to at most 200 samples)
+	Profile       []*ElevationSample `protobuf:"bytes,5,rep,name=profile,proto3" json:"profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TripElevation) Reset() {
+	*x = TripElevation{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripElevation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripElevation) ProtoMessage() {}
+
+func (x *TripElevation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripElevation.ProtoReflect.Descriptor instead.
+func (*TripElevation) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TripElevation) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *TripElevation) GetEndTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *TripElevation) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *TripElevation) GetElevation() *ElevationStats {
+	if x != nil {
+		return x.Elevation
+	}
+	return nil
+}
+
+func (x *TripElevation) GetProfile() []*ElevationSample {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+// ElevationSample is a single point on an elevation profile.
+type ElevationSample struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// distance_km is the distance from the start of the trip in kilometers
+	DistanceKm float64 `protobuf:"fixed64,1,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	// altitude_m is the smoothed altitude in meters
+	AltitudeM     float64 `protobuf:"fixed64,2,opt,name=altitude_m,json=altitudeM,proto3" json:"altitude_m,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ElevationSample) Reset() {
+	*x = ElevationSample{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ElevationSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ElevationSample) ProtoMessage() {}
+
+func (x *ElevationSample) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ElevationSample.ProtoReflect.Descriptor instead.
+func (*ElevationSample) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ElevationSample) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *ElevationSample) GetAltitudeM() float64 {
+	if x != nil {
+		return x.AltitudeM
+	}
+	return 0
+}
+
+// GetBatteryReportRequest selects the date range and device to analyse.
+type GetBatteryReportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_date is the first day of the range in YYYY-MM-DD format
+	StartDate string `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date is the last day of the range (inclusive) in YYYY-MM-DD format
+	EndDate string `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// device_id optionally restricts the report to a single OwnTracks device
+	// If empty, a report is returned for every device with data in the range
+	DeviceId      string `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatteryReportRequest) Reset() {
+	*x = GetBatteryReportRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatteryReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatteryReportRequest) ProtoMessage() {}
+
+func (x *GetBatteryReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatteryReportRequest.ProtoReflect.Descriptor instead.
+func (*GetBatteryReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetBatteryReportRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetBatteryReportRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetBatteryReportRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+// GetBatteryReportResponse contains one battery report per device.
+type GetBatteryReportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// devices is the battery report for each device, ordered by device_id
+	Devices       []*DeviceBatteryReport `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatteryReportResponse) Reset() {
+	*x = GetBatteryReportResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatteryReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatteryReportResponse) ProtoMessage() {}
+
+func (x *GetBatteryReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatteryReportResponse.ProtoReflect.Descriptor instead.
+func (*GetBatteryReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetBatteryReportResponse) GetDevices() []*DeviceBatteryReport {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+// DeviceBatteryReport summarizes battery behaviour for a single device.
+type DeviceBatteryReport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// device_id is the OwnTracks device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// drain_rate_per_hour is the average percent lost per unplugged hour
+	DrainRatePerHour float64 `protobuf:"fixed64,2,opt,name=drain_rate_per_hour,json=drainRatePerHour,proto3" json:"drain_rate_per_hour,omitempty"`
+	// distance_correlation is the Pearson correlation between daily distance
+	// travelled and daily drain rate (0 when fewer than three days have data)
+	DistanceCorrelation float64 `protobuf:"fixed64,3,opt,name=distance_correlation,json=distanceCorrelation,proto3" json:"distance_correlation,omitempty"`
+	// days is the per-day breakdown in chronological order
+	Days []*DailyBattery `protobuf:"bytes,4,rep,name=days,proto3" json:"days,omitempty"`
+	// charging_sessions lists each period the device was charging or full
+	ChargingSessions []*ChargingSession `protobuf:"bytes,5,rep,name=charging_sessions,json=chargingSessions,proto3" json:"charging_sessions,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeviceBatteryReport) Reset() {
+	*x = DeviceBatteryReport{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceBatteryReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceBatteryReport) ProtoMessage() {}
+
+func (x *DeviceBatteryReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceBatteryReport.ProtoReflect.Descriptor instead.
+func (*DeviceBatteryReport) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeviceBatteryReport) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeviceBatteryReport) GetDrainRatePerHour() float64 {
+	if x != nil {
+		return x.DrainRatePerHour
+	}
+	return 0
+}
+
+func (x *DeviceBatteryReport) GetDistanceCorrelation() float64 {
+	if x != nil {
+		return x.DistanceCorrelation
+	}
+	return 0
+}
+
+func (x *DeviceBatteryReport) GetDays() []*DailyBattery {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *DeviceBatteryReport) GetChargingSessions() []*ChargingSession {
+	if x != nil {
+		return x.ChargingSessions
+	}
+	return nil
+}
+
+// DailyBattery holds battery statistics for a single UTC day.
+type DailyBattery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// date is the day in YYYY-MM-DD format
+	Date string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// drain_rate_per_hour is the percent lost per unplugged hour
+	DrainRatePerHour float64 `protobuf:"fixed64,2,opt,name=drain_rate_per_hour,json=drainRatePerHour,proto3" json:"drain_rate_per_hour,omitempty"`
+	// drained_percent is the total battery percentage lost while unplugged
+	DrainedPercent float64 `protobuf:"fixed64,3,opt,name=drained_percent,json=drainedPercent,proto3" json:"drained_percent,omitempty"`
+	// discharge_hours is the time spent unplugged with regular reporting
+	DischargeHours float64 `protobuf:"fixed64,4,opt,name=discharge_hours,json=dischargeHours,proto3" json:"discharge_hours,omitempty"`
+	// min_level is the lowest battery level reported
+	MinLevel int32 `protobuf:"varint,5,opt,name=min_level,json=minLevel,proto3" json:"min_level,omitempty"`
+	// max_level is the highest battery level reported
+	MaxLevel int32 `protobuf:"varint,6,opt,name=max_level,json=maxLevel,proto3" json:"max_level,omitempty"`
+	// charging_sessions is the number of charging sessions that started this day
+	ChargingSessions int32 `protobuf:"varint,7,opt,name=charging_sessions,json=chargingSessions,proto3" json:"charging_sessions,omitempty"`
+	// distance_km is the path distance travelled in kilometers
+	DistanceKm float64 `protobuf:"fixed64,8,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	// readings is the number of locations with a battery level
+	Readings      int32 `protobuf:"varint,9,opt,name=readings,proto3" json:"readings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyBattery) Reset() {
+	*x = DailyBattery{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyBattery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyBattery) ProtoMessage() {}
+
+func (x *DailyBattery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyBattery.ProtoReflect.Descriptor instead.
+func (*DailyBattery) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DailyBattery) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailyBattery) GetDrainRatePerHour() float64 {
+	if x != nil {
+		return x.DrainRatePerHour
+	}
+	return 0
+}
+
+func (x *DailyBattery) GetDrainedPercent() float64 {
+	if x != nil {
+		return x.DrainedPercent
+	}
+	return 0
+}
+
+func (x *DailyBattery) GetDischargeHours() float64 {
+	if x != nil {
+		return x.DischargeHours
+	}
+	return 0
+}
+
+func (x *DailyBattery) GetMinLevel() int32 {
+	if x != nil {
+		return x.MinLevel
+	}
+	return 0
+}
+
+func (x *DailyBattery) GetMaxLevel() int32 {
+	if x != nil {
+		return x.MaxLevel
+	}
+	return 0
+}
+
+func (x *DailyBattery) GetChargingSessions() int32 {
+	if x != nil {
+		return x.ChargingSessions
+	}
+	return 0
+}
+
+func (x *DailyBattery) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *DailyBattery) GetReadings() int32 {
+	if x != nil {
+		return x.Readings
+	}
+	return 0
+}
+
+// ChargingSession is a contiguous period with battery status charging or full.
+type ChargingSession struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_time is the UTC timestamp of the first charging reading
+	StartTime *timestamp.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// end_time is the UTC timestamp of the last charging reading
+	EndTime *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	// start_level is the battery percentage when charging began
+	StartLevel int32 `protobuf:"varint,3,opt,name=start_level,json=startLevel,proto3" json:"start_level,omitempty"`
+	// end_level is the battery percentage when charging ended
+	EndLevel      int32 `protobuf:"varint,4,opt,name=end_level,json=endLevel,proto3" json:"end_level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChargingSession) Reset() {
+	*x = ChargingSession{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChargingSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChargingSession) ProtoMessage() {}
+
+func (x *ChargingSession) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChargingSession.ProtoReflect.Descriptor instead.
+func (*ChargingSession) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ChargingSession) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ChargingSession) GetEndTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ChargingSession) GetStartLevel() int32 {
+	if x != nil {
+		return x.StartLevel
+	}
+	return 0
+}
+
+func (x *ChargingSession) GetEndLevel() int32 {
+	if x != nil {
+		return x.EndLevel
+	}
+	return 0
+}
+
+// GetDailySummariesRequest selects stored daily aggregates.
+type GetDailySummariesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_date is the first day of the range in YYYY-MM-DD format
+	StartDate string `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date is the last day of the range (inclusive) in YYYY-MM-DD format
+	EndDate string `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// device_id optionally filters results to a specific OwnTracks device
+	DeviceId      string `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDailySummariesRequest) Reset() {
+	*x = GetDailySummariesRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDailySummariesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDailySummariesRequest) ProtoMessage() {}
+
+func (x *GetDailySummariesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDailySummariesRequest.ProtoReflect.Descriptor instead.
+func (*GetDailySummariesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetDailySummariesRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetDailySummariesRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetDailySummariesRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+// GetDailySummariesResponse returns stored aggregates ordered by date then device.
+type GetDailySummariesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// summaries is one row per device per day with data
+	Summaries     []*DailySummary `protobuf:"bytes,1,rep,name=summaries,proto3" json:"summaries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDailySummariesResponse) Reset() {
+	*x = GetDailySummariesResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDailySummariesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDailySummariesResponse) ProtoMessage() {}
+
+func (x *GetDailySummariesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDailySummariesResponse.ProtoReflect.Descriptor instead.
+func (*GetDailySummariesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetDailySummariesResponse) GetSummaries() []*DailySummary {
+	if x != nil {
+		return x.Summaries
+	}
+	return nil
+}
+
+// DailySummary is a per-device per-day aggregate.
+type DailySummary struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// device_id is the OwnTracks device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// date is the day in YYYY-MM-DD format
+	Date string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	// path_distance_km is the distance travelled between consecutive fixes
+	PathDistanceKm float64 `protobuf:"fixed64,3,opt,name=path_distance_km,json=pathDistanceKm,proto3" json:"path_distance_km,omitempty"`
+	// max_distance_km is the maximum distance from home
+	MaxDistanceKm float64 `protobuf:"fixed64,4,opt,name=max_distance_km,json=maxDistanceKm,proto3" json:"max_distance_km,omitempty"`
+	// min_distance_km is the minimum distance from home
+	MinDistanceKm float64 `protobuf:"fixed64,5,opt,name=min_distance_km,json=minDistanceKm,proto3" json:"min_distance_km,omitempty"`
+	// avg_distance_km is the average distance from home across all fixes
+	AvgDistanceKm float64 `protobuf:"fixed64,6,opt,name=avg_distance_km,json=avgDistanceKm,proto3" json:"avg_distance_km,omitempty"`
+	// point_count is the number of location records for the day
+	PointCount int32 `protobuf:"varint,7,opt,name=point_count,json=pointCount,proto3" json:"point_count,omitempty"`
+	// time_away_seconds is the time spent beyond the away threshold from home
+	TimeAwaySeconds int64 `protobuf:"varint,8,opt,name=time_away_seconds,json=timeAwaySeconds,proto3" json:"time_away_seconds,omitempty"`
+	// updated_at is when the aggregate was last recomputed
+	UpdatedAt     *timestamp.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailySummary) Reset() {
+	*x = DailySummary{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailySummary) ProtoMessage() {}
+
+func (x *DailySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailySummary.ProtoReflect.Descriptor instead.
+func (*DailySummary) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DailySummary) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DailySummary) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailySummary) GetPathDistanceKm() float64 {
+	if x != nil {
+		return x.PathDistanceKm
+	}
+	return 0
+}
+
+func (x *DailySummary) GetMaxDistanceKm() float64 {
+	if x != nil {
+		return x.MaxDistanceKm
+	}
+	return 0
+}
+
+func (x *DailySummary) GetMinDistanceKm() float64 {
+	if x != nil {
+		return x.MinDistanceKm
+	}
+	return 0
+}
+
+func (x *DailySummary) GetAvgDistanceKm() float64 {
+	if x != nil {
+		return x.AvgDistanceKm
+	}
+	return 0
+}
+
+func (x *DailySummary) GetPointCount() int32 {
+	if x != nil {
+		return x.PointCount
+	}
+	return 0
+}
+
+func (x *DailySummary) GetTimeAwaySeconds() int64 {
+	if x != nil {
+		return x.TimeAwaySeconds
+	}
+	return 0
+}
+
+func (x *DailySummary) GetUpdatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// BackfillDailySummariesRequest selects the days to recompute.
+type BackfillDailySummariesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_date is the first day to backfill in YYYY-MM-DD format
+	StartDate string `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date is the last day to backfill (inclusive) in YYYY-MM-DD format
+	EndDate string `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// device_id optionally restricts the backfill to a specific OwnTracks device
+	DeviceId      string `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackfillDailySummariesRequest) Reset() {
+	*x = BackfillDailySummariesRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackfillDailySummariesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackfillDailySummariesRequest) ProtoMessage() {}
+
+func (x *BackfillDailySummariesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackfillDailySummariesRequest.ProtoReflect.Descriptor instead.
+func (*BackfillDailySummariesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BackfillDailySummariesRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *BackfillDailySummariesRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *BackfillDailySummariesRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+// BackfillDailySummariesResponse contains the job ID for async processing.
+type BackfillDailySummariesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// job_id uniquely identifies the backfill job
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// status indicates the initial job state (typically "queued")
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// queued_at is the UTC timestamp when the job was created
+	QueuedAt      *timestamp.Timestamp `protobuf:"bytes,3,opt,name=queued_at,json=queuedAt,proto3" json:"queued_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackfillDailySummariesResponse) Reset() {
+	*x = BackfillDailySummariesResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackfillDailySummariesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackfillDailySummariesResponse) ProtoMessage() {}
+
+func (x *BackfillDailySummariesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackfillDailySummariesResponse.ProtoReflect.Descriptor instead.
+func (*BackfillDailySummariesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BackfillDailySummariesResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *BackfillDailySummariesResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BackfillDailySummariesResponse) GetQueuedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.QueuedAt
+	}
+	return nil
+}
+
+// ListGarminActivitiesRequest selects Garmin activities by start date.
+type ListGarminActivitiesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_date is the first day of the range in YYYY-MM-DD format
+	StartDate string `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date is the last day of the range (inclusive) in YYYY-MM-DD format
+	EndDate string `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// sport optionally filters activities (e.g. "cycling", "running")
+	Sport         string `protobuf:"bytes,3,opt,name=sport,proto3" json:"sport,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGarminActivitiesRequest) Reset() {
+	*x = ListGarminActivitiesRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGarminActivitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGarminActivitiesRequest) ProtoMessage() {}
+
+func (x *ListGarminActivitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGarminActivitiesRequest.ProtoReflect.Descriptor instead.
+func (*ListGarminActivitiesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListGarminActivitiesRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *ListGarminActivitiesRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *ListGarminActivitiesRequest) GetSport() string {
+	if x != nil {
+		return x.Sport
+	}
+	return ""
+}
+
+// ListGarminActivitiesResponse returns activities ordered by start time.
+type ListGarminActivitiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Activities    []*GarminActivity      `protobuf:"bytes,1,rep,name=activities,proto3" json:"activities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGarminActivitiesResponse) Reset() {
+	*x = ListGarminActivitiesResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGarminActivitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGarminActivitiesResponse) ProtoMessage() {}
+
+func (x *ListGarminActivitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGarminActivitiesResponse.ProtoReflect.Descriptor instead.
+func (*ListGarminActivitiesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListGarminActivitiesResponse) GetActivities() []*GarminActivity {
+	if x != nil {
+		return x.Activities
+	}
+	return nil
+}
+
+// GarminActivity is workout metadata as recorded by Garmin Connect.
+// Sensor summaries are zero when not recorded.
+type GarminActivity struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActivityId      int64                  `protobuf:"varint,1,opt,name=activity_id,json=activityId,proto3" json:"activity_id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Sport           string                 `protobuf:"bytes,3,opt,name=sport,proto3" json:"sport,omitempty"`
+	SubSport        string                 `protobuf:"bytes,4,opt,name=sub_sport,json=subSport,proto3" json:"sub_sport,omitempty"`
+	StartTime       *timestamp.Timestamp   `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime         *timestamp.Timestamp   `protobuf:"bytes,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	DurationSeconds float64                `protobuf:"fixed64,7,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	DistanceKm      float64                `protobuf:"fixed64,8,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	Calories        int32                  `protobuf:"varint,9,opt,name=calories,proto3" json:"calories,omitempty"`
+	AvgHeartRate    int32                  `protobuf:"varint,10,opt,name=avg_heart_rate,json=avgHeartRate,proto3" json:"avg_heart_rate,omitempty"`
+	MaxHeartRate    int32                  `protobuf:"varint,11,opt,name=max_heart_rate,json=maxHeartRate,proto3" json:"max_heart_rate,omitempty"`
+	AvgCadence      int32                  `protobuf:"varint,12,opt,name=avg_cadence,json=avgCadence,proto3" json:"avg_cadence,omitempty"`
+	MaxCadence      int32                  `protobuf:"varint,13,opt,name=max_cadence,json=maxCadence,proto3" json:"max_cadence,omitempty"`
+	AvgSpeedKmh     float64                `protobuf:"fixed64,14,opt,name=avg_speed_kmh,json=avgSpeedKmh,proto3" json:"avg_speed_kmh,omitempty"`
+	MaxSpeedKmh     float64                `protobuf:"fixed64,15,opt,name=max_speed_kmh,json=maxSpeedKmh,proto3" json:"max_speed_kmh,omitempty"`
+	TotalAscentM    float64                `protobuf:"fixed64,16,opt,name=total_ascent_m,json=totalAscentM,proto3" json:"total_ascent_m,omitempty"`
+	TotalDescentM   float64                `protobuf:"fixed64,17,opt,name=total_descent_m,json=totalDescentM,proto3" json:"total_descent_m,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GarminActivity) Reset() {
+	*x = GarminActivity{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GarminActivity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GarminActivity) ProtoMessage() {}
+
+func (x *GarminActivity) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GarminActivity.ProtoReflect.Descriptor instead.
+func (*GarminActivity) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GarminActivity) GetActivityId() int64 {
+	if x != nil {
+		return x.ActivityId
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GarminActivity) GetSport() string {
+	if x != nil {
+		return x.Sport
+	}
+	return ""
+}
+
+func (x *GarminActivity) GetSubSport() string {
+	if x != nil {
+		return x.SubSport
+	}
+	return ""
+}
+
+func (x *GarminActivity) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GarminActivity) GetEndTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GarminActivity) GetDurationSeconds() float64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetCalories() int32 {
+	if x != nil {
+		return x.Calories
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetAvgHeartRate() int32 {
+	if x != nil {
+		return x.AvgHeartRate
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetMaxHeartRate() int32 {
+	if x != nil {
+		return x.MaxHeartRate
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetAvgCadence() int32 {
+	if x != nil {
+		return x.AvgCadence
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetMaxCadence() int32 {
+	if x != nil {
+		return x.MaxCadence
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetAvgSpeedKmh() float64 {
+	if x != nil {
+		return x.AvgSpeedKmh
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetMaxSpeedKmh() float64 {
+	if x != nil {
+		return x.MaxSpeedKmh
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetTotalAscentM() float64 {
+	if x != nil {
+		return x.TotalAscentM
+	}
+	return 0
+}
+
+func (x *GarminActivity) GetTotalDescentM() float64 {
+	if x != nil {
+		return x.TotalDescentM
+	}
+	return 0
+}
+
+// GetGarminActivityRequest selects a single activity.
+type GetGarminActivityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActivityId    int64                  `protobuf:"varint,1,opt,name=activity_id,json=activityId,proto3" json:"activity_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGarminActivityRequest) Reset() {
+	*x = GetGarminActivityRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGarminActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGarminActivityRequest) ProtoMessage() {}
+
+func (x *GetGarminActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGarminActivityRequest.ProtoReflect.Descriptor instead.
+func (*GetGarminActivityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetGarminActivityRequest) GetActivityId() int64 {
+	if x != nil {
+		return x.ActivityId
+	}
+	return 0
+}
+
+// GetGarminActivityResponse contains activity metadata and computed metrics.
+type GetGarminActivityResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Activity *GarminActivity        `protobuf:"bytes,1,opt,name=activity,proto3" json:"activity,omitempty"`
+	// metrics is computed from the activity's track points
+	Metrics       *ActivityMetrics `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGarminActivityResponse) Reset() {
+	*x = GetGarminActivityResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGarminActivityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGarminActivityResponse) ProtoMessage() {}
+
+func (x *GetGarminActivityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGarminActivityResponse.ProtoReflect.Descriptor instead.
+func (*GetGarminActivityResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetGarminActivityResponse) GetActivity() *GarminActivity {
+	if x != nil {
+		return x.Activity
+	}
+	return nil
+}
+
+func (x *GetGarminActivityResponse) GetMetrics() *ActivityMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+// ActivityMetrics is computed from an activity's track points.
+type ActivityMetrics struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// activity_id is the Garmin activity
+	ActivityId int64 `protobuf:"varint,1,opt,name=activity_id,json=activityId,proto3" json:"activity_id,omitempty"`
+	// point_count is the number of track points processed
+	PointCount int32 `protobuf:"varint,2,opt,name=point_count,json=pointCount,proto3" json:"point_count,omitempty"`
+	// path_distance_km is the distance travelled between consecutive points
+	PathDistanceKm float64 `protobuf:"fixed64,3,opt,name=path_distance_km,json=pathDistanceKm,proto3" json:"path_distance_km,omitempty"`
+	// duration_seconds is the time from the first to the last point
+	DurationSeconds int64 `protobuf:"varint,4,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	// avg_speed_kmh is path distance over duration
+	AvgSpeedKmh float64 `protobuf:"fixed64,5,opt,name=avg_speed_kmh,json=avgSpeedKmh,proto3" json:"avg_speed_kmh,omitempty"`
+	// max_speed_kmh is the device-reported maximum, or the fastest segment
+	// between points when the device recorded no speed
+	MaxSpeedKmh float64 `protobuf:"fixed64,6,opt,name=max_speed_kmh,json=maxSpeedKmh,proto3" json:"max_speed_kmh,omitempty"`
+	// avg_heart_rate is the mean over points with a heart rate reading
+	AvgHeartRate float64 `protobuf:"fixed64,7,opt,name=avg_heart_rate,json=avgHeartRate,proto3" json:"avg_heart_rate,omitempty"`
+	MaxHeartRate int32   `protobuf:"varint,8,opt,name=max_heart_rate,json=maxHeartRate,proto3" json:"max_heart_rate,omitempty"`
+	// avg_cadence is the mean over points with a cadence reading
+	AvgCadence float64 `protobuf:"fixed64,9,opt,name=avg_cadence,json=avgCadence,proto3" json:"avg_cadence,omitempty"`
+	MaxCadence int32   `protobuf:"varint,10,opt,name=max_cadence,json=maxCadence,proto3" json:"max_cadence,omitempty"`
+	// max/min/avg distance from the configured home location in kilometers
+	MaxDistanceFromHomeKm float64 `protobuf:"fixed64,11,opt,name=max_distance_from_home_km,json=maxDistanceFromHomeKm,proto3" json:"max_distance_from_home_km,omitempty"`
+	MinDistanceFromHomeKm float64 `protobuf:"fixed64,12,opt,name=min_distance_from_home_km,json=minDistanceFromHomeKm,proto3" json:"min_distance_from_home_km,omitempty"`
+	AvgDistanceFromHomeKm float64 `protobuf:"fixed64,13,opt,name=avg_distance_from_home_km,json=avgDistanceFromHomeKm,proto3" json:"avg_distance_from_home_km,omitempty"`
+	// elevation is the smoothed climb statistics for the track
+	Elevation     *ElevationStats `protobuf:"bytes,14,opt,name=elevation,proto3" json:"elevation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityMetrics) Reset() {
+	*x = ActivityMetrics{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityMetrics) ProtoMessage() {}
+
+func (x *ActivityMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityMetrics.ProtoReflect.Descriptor instead.
+func (*ActivityMetrics) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ActivityMetrics) GetActivityId() int64 {
+	if x != nil {
+		return x.ActivityId
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetPointCount() int32 {
+	if x != nil {
+		return x.PointCount
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetPathDistanceKm() float64 {
+	if x != nil {
+		return x.PathDistanceKm
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetAvgSpeedKmh() float64 {
+	if x != nil {
+		return x.AvgSpeedKmh
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetMaxSpeedKmh() float64 {
+	if x != nil {
+		return x.MaxSpeedKmh
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetAvgHeartRate() float64 {
+	if x != nil {
+		return x.AvgHeartRate
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetMaxHeartRate() int32 {
+	if x != nil {
+		return x.MaxHeartRate
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetAvgCadence() float64 {
+	if x != nil {
+		return x.AvgCadence
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetMaxCadence() int32 {
+	if x != nil {
+		return x.MaxCadence
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetMaxDistanceFromHomeKm() float64 {
+	if x != nil {
+		return x.MaxDistanceFromHomeKm
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetMinDistanceFromHomeKm() float64 {
+	if x != nil {
+		return x.MinDistanceFromHomeKm
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetAvgDistanceFromHomeKm() float64 {
+	if x != nil {
+		return x.AvgDistanceFromHomeKm
+	}
+	return 0
+}
+
+func (x *ActivityMetrics) GetElevation() *ElevationStats {
+	if x != nil {
+		return x.Elevation
+	}
+	return nil
+}
+
+// CalculateActivityDistanceRequest selects the activity to process.
+type CalculateActivityDistanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActivityId    int64                  `protobuf:"varint,1,opt,name=activity_id,json=activityId,proto3" json:"activity_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalculateActivityDistanceRequest) Reset() {
+	*x = CalculateActivityDistanceRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateActivityDistanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateActivityDistanceRequest) ProtoMessage() {}
+
+func (x *CalculateActivityDistanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateActivityDistanceRequest.ProtoReflect.Descriptor instead.
+func (*CalculateActivityDistanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CalculateActivityDistanceRequest) GetActivityId() int64 {
+	if x != nil {
+		return x.ActivityId
+	}
+	return 0
+}
+
+// StreamLocationsRequest selects the locations to stream.
+type StreamLocationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_date and end_date (inclusive) bound the range in YYYY-MM-DD format
+	StartDate string `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   string `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// device_id optionally restricts the stream to a single OwnTracks device
+	DeviceId string `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// include_payload adds the decoded raw_payload fields to each record. The
+	// request fails with FAILED_PRECONDITION unless the server sets
+	// INCLUDE_RAW_PAYLOAD.
+	IncludePayload bool `protobuf:"varint,4,opt,name=include_payload,json=includePayload,proto3" json:"include_payload,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StreamLocationsRequest) Reset() {
+	*x = StreamLocationsRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamLocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLocationsRequest) ProtoMessage() {}
+
+func (x *StreamLocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLocationsRequest.ProtoReflect.Descriptor instead.
+func (*StreamLocationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *StreamLocationsRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *StreamLocationsRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *StreamLocationsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *StreamLocationsRequest) GetIncludePayload() bool {
+	if x != nil {
+		return x.IncludePayload
+	}
+	return false
+}
+
+// LocationRecord is one row of public.locations.
+type LocationRecord struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	DeviceId  string                 `protobuf:"bytes,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Tid       string                 `protobuf:"bytes,3,opt,name=tid,proto3" json:"tid,omitempty"`
+	Latitude  float64                `protobuf:"fixed64,4,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64                `protobuf:"fixed64,5,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// accuracy is the horizontal accuracy in meters
+	Accuracy int32 `protobuf:"varint,6,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	// altitude is meters above sea level
+	Altitude float64 `protobuf:"fixed64,7,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	// velocity is the device-reported speed in km/h
+	Velocity int32 `protobuf:"varint,8,opt,name=velocity,proto3" json:"velocity,omitempty"`
+	// battery is the charge level in percent
+	Battery int32 `protobuf:"varint,9,opt,name=battery,proto3" json:"battery,omitempty"`
+	// battery_status is 1=unknown, 2=unplugged, 3=charging, 4=full
+	BatteryStatus  int32  `protobuf:"varint,10,opt,name=battery_status,json=batteryStatus,proto3" json:"battery_status,omitempty"`
+	ConnectionType string `protobuf:"bytes,11,opt,name=connection_type,json=connectionType,proto3" json:"connection_type,omitempty"`
+	Trigger        string `protobuf:"bytes,12,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	// timestamp is when the device recorded the fix (unset if missing)
+	Timestamp *timestamp.Timestamp `protobuf:"bytes,13,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// created_at is when the fix was stored
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// payload is set when include_payload was requested and available
+	Payload       *LocationPayload `protobuf:"bytes,15,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationRecord) Reset() {
+	*x = LocationRecord{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationRecord) ProtoMessage() {}
+
+func (x *LocationRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationRecord.ProtoReflect.Descriptor instead.
+func (*LocationRecord) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *LocationRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LocationRecord) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *LocationRecord) GetTid() string {
+	if x != nil {
+		return x.Tid
+	}
+	return ""
+}
+
+func (x *LocationRecord) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *LocationRecord) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *LocationRecord) GetAccuracy() int32 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *LocationRecord) GetAltitude() float64 {
+	if x != nil {
+		return x.Altitude
+	}
+	return 0
+}
+
+func (x *LocationRecord) GetVelocity() int32 {
+	if x != nil {
+		return x.Velocity
+	}
+	return 0
+}
+
+func (x *LocationRecord) GetBattery() int32 {
+	if x != nil {
+		return x.Battery
+	}
+	return 0
+}
+
+func (x *LocationRecord) GetBatteryStatus() int32 {
+	if x != nil {
+		return x.BatteryStatus
+	}
+	return 0
+}
+
+func (x *LocationRecord) GetConnectionType() string {
+	if x != nil {
+		return x.ConnectionType
+	}
+	return ""
+}
+
+func (x *LocationRecord) GetTrigger() string {
+	if x != nil {
+		return x.Trigger
+	}
+	return ""
+}
+
+func (x *LocationRecord) GetTimestamp() *timestamp.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *LocationRecord) GetCreatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *LocationRecord) GetPayload() *LocationPayload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// LocationPayload holds OwnTracks message fields without a column of their
+// own, decoded from raw_payload.
+type LocationPayload struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// in_regions is the regions the device is currently inside
+	InRegions []string `protobuf:"bytes,1,rep,name=in_regions,json=inRegions,proto3" json:"in_regions,omitempty"`
+	// ssid and bssid identify the connected Wi-Fi network and access point
+	Ssid  string `protobuf:"bytes,2,opt,name=ssid,proto3" json:"ssid,omitempty"`
+	Bssid string `protobuf:"bytes,3,opt,name=bssid,proto3" json:"bssid,omitempty"`
+	// course_deg is the course over ground in degrees, unset when the device
+	// did not report one
+	CourseDeg *int32 `protobuf:"varint,4,opt,name=course_deg,json=courseDeg,proto3,oneof" json:"course_deg,omitempty"`
+	// vertical_accuracy_m is the altitude accuracy in meters
+	VerticalAccuracyM int32 `protobuf:"varint,5,opt,name=vertical_accuracy_m,json=verticalAccuracyM,proto3" json:"vertical_accuracy_m,omitempty"`
+	// pressure_kpa is the barometric pressure in kilopascals
+	PressureKpa float64 `protobuf:"fixed64,6,opt,name=pressure_kpa,json=pressureKpa,proto3" json:"pressure_kpa,omitempty"`
+	// monitoring_mode is 1 for significant changes, 2 for move mode
+	MonitoringMode int32 `protobuf:"varint,7,opt,name=monitoring_mode,json=monitoringMode,proto3" json:"monitoring_mode,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *LocationPayload) Reset() {
+	*x = LocationPayload{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationPayload) ProtoMessage() {}
+
+func (x *LocationPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationPayload.ProtoReflect.Descriptor instead.
+func (*LocationPayload) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LocationPayload) GetInRegions() []string {
+	if x != nil {
+		return x.InRegions
+	}
+	return nil
+}
+
+func (x *LocationPayload) GetSsid() string {
+	if x != nil {
+		return x.Ssid
+	}
+	return ""
+}
+
+func (x *LocationPayload) GetBssid() string {
+	if x != nil {
+		return x.Bssid
+	}
+	return ""
+}
+
+func (x *LocationPayload) GetCourseDeg() int32 {
+	if x != nil && x.CourseDeg != nil {
+		return *x.CourseDeg
+	}
+	return 0
+}
+
+func (x *LocationPayload) GetVerticalAccuracyM() int32 {
+	if x != nil {
+		return x.VerticalAccuracyM
+	}
+	return 0
+}
+
+func (x *LocationPayload) GetPressureKpa() float64 {
+	if x != nil {
+		return x.PressureKpa
+	}
+	return 0
+}
+
+func (x *LocationPayload) GetMonitoringMode() int32 {
+	if x != nil {
+		return x.MonitoringMode
+	}
+	return 0
+}
+
+// FindLocationsNearRequest selects a circle and date range to search.
+type FindLocationsNearRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// latitude and longitude are the centre in decimal degrees
+	Latitude  float64 `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64 `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// radius_km is the search radius in kilometers (great-circle distance)
+	RadiusKm float64 `protobuf:"fixed64,3,opt,name=radius_km,json=radiusKm,proto3" json:"radius_km,omitempty"`
+	// start_date and end_date (inclusive) bound the range in YYYY-MM-DD format
+	StartDate string `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   string `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// device_id optionally restricts the search to a single OwnTracks device
+	DeviceId string `protobuf:"bytes,6,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// limit caps the locations returned (default: 1000, max: 10000); visits
+	// and total_count cover every match
+	Limit         int32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindLocationsNearRequest) Reset() {
+	*x = FindLocationsNearRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindLocationsNearRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindLocationsNearRequest) ProtoMessage() {}
+
+func (x *FindLocationsNearRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindLocationsNearRequest.ProtoReflect.Descriptor instead.
+func (*FindLocationsNearRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *FindLocationsNearRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *FindLocationsNearRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *FindLocationsNearRequest) GetRadiusKm() float64 {
+	if x != nil {
+		return x.RadiusKm
+	}
+	return 0
+}
+
+func (x *FindLocationsNearRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *FindLocationsNearRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *FindLocationsNearRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *FindLocationsNearRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// FindLocationsNearResponse lists the matching locations and visits.
+type FindLocationsNearResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// locations is the matches in created_at order, up to limit
+	Locations []*NearbyLocation `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	// total_count is the number of matches before limit was applied
+	TotalCount int32 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// visits groups consecutive matches of each device, ordered by start_time
+	Visits        []*NearbyVisit `protobuf:"bytes,3,rep,name=visits,proto3" json:"visits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindLocationsNearResponse) Reset() {
+	*x = FindLocationsNearResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindLocationsNearResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindLocationsNearResponse) ProtoMessage() {}
+
+func (x *FindLocationsNearResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindLocationsNearResponse.ProtoReflect.Descriptor instead.
+func (*FindLocationsNearResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *FindLocationsNearResponse) GetLocations() []*NearbyLocation {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *FindLocationsNearResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *FindLocationsNearResponse) GetVisits() []*NearbyVisit {
+	if x != nil {
+		return x.Visits
+	}
+	return nil
+}
+
+// NearbyLocation is a location and its distance from the search centre.
+type NearbyLocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      *LocationRecord        `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	DistanceKm    float64                `protobuf:"fixed64,2,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearbyLocation) Reset() {
+	*x = NearbyLocation{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearbyLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyLocation) ProtoMessage() {}
+
+func (x *NearbyLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyLocation.ProtoReflect.Descriptor instead.
+func (*NearbyLocation) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *NearbyLocation) GetLocation() *LocationRecord {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *NearbyLocation) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+// NearbyVisit is a period a device stayed within the search radius. Matches
+// more than 15 minutes apart start a new visit.
+type NearbyVisit struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId   string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	StartTime  *timestamp.Timestamp   `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime    *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PointCount int32                  `protobuf:"varint,4,opt,name=point_count,json=pointCount,proto3" json:"point_count,omitempty"`
+	// closest_km is the smallest distance from the centre during the visit
+	ClosestKm     float64 `protobuf:"fixed64,5,opt,name=closest_km,json=closestKm,proto3" json:"closest_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearbyVisit) Reset() {
+	*x = NearbyVisit{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearbyVisit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyVisit) ProtoMessage() {}
+
+func (x *NearbyVisit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyVisit.ProtoReflect.Descriptor instead.
+func (*NearbyVisit) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *NearbyVisit) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *NearbyVisit) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *NearbyVisit) GetEndTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *NearbyVisit) GetPointCount() int32 {
+	if x != nil {
+		return x.PointCount
+	}
+	return 0
+}
+
+func (x *NearbyVisit) GetClosestKm() float64 {
+	if x != nil {
+		return x.ClosestKm
+	}
+	return 0
+}
+
+// GetLiveStateRequest selects the devices to report.
+type GetLiveStateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// device_id optionally restricts the response to a single OwnTracks device
+	DeviceId      string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLiveStateRequest) Reset() {
+	*x = GetLiveStateRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLiveStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLiveStateRequest) ProtoMessage() {}
+
+func (x *GetLiveStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLiveStateRequest.ProtoReflect.Descriptor instead.
+func (*GetLiveStateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetLiveStateRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+// GetLiveStateResponse is the incremental state as of the last applied location.
+type GetLiveStateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// devices is one entry per device seen since tracking began, ordered by device_id
+	Devices []*DeviceLiveState `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	// last_location_id is the highest public.locations id applied
+	LastLocationId int64 `protobuf:"varint,2,opt,name=last_location_id,json=lastLocationId,proto3" json:"last_location_id,omitempty"`
+	// updated_at is when new locations were last applied
+	UpdatedAt     *timestamp.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLiveStateResponse) Reset() {
+	*x = GetLiveStateResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLiveStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLiveStateResponse) ProtoMessage() {}
+
+func (x *GetLiveStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLiveStateResponse.ProtoReflect.Descriptor instead.
+func (*GetLiveStateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetLiveStateResponse) GetDevices() []*DeviceLiveState {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *GetLiveStateResponse) GetLastLocationId() int64 {
+	if x != nil {
+		return x.LastLocationId
+	}
+	return 0
+}
+
+func (x *GetLiveStateResponse) GetUpdatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// DeviceLiveState is one device's running state for its current day.
+type DeviceLiveState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// device_id is the OwnTracks device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// summary holds the running daily aggregates; updated_at is the time of
+	// the last fix applied
+	Summary *DailySummary `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	// last_fix_time, latitude and longitude locate the latest fix
+	LastFixTime *timestamp.Timestamp `protobuf:"bytes,3,opt,name=last_fix_time,json=lastFixTime,proto3" json:"last_fix_time,omitempty"`
+	Latitude    float64              `protobuf:"fixed64,4,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude   float64              `protobuf:"fixed64,5,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// distance_from_home_km is the latest fix's distance from home
+	DistanceFromHomeKm float64 `protobuf:"fixed64,6,opt,name=distance_from_home_km,json=distanceFromHomeKm,proto3" json:"distance_from_home_km,omitempty"`
+	// away is true when the latest fix is beyond the away threshold
+	Away bool `protobuf:"varint,7,opt,name=away,proto3" json:"away,omitempty"`
+	// mode is the movement mode of the latest analyzed fix. Speed smoothing
+	// looks two fixes ahead, so it trails last_fix_time by two fixes.
+	Mode string `protobuf:"bytes,8,opt,name=mode,proto3" json:"mode,omitempty"`
+	// trips_completed is the number of trips finished today
+	TripsCompleted int32 `protobuf:"varint,9,opt,name=trips_completed,json=tripsCompleted,proto3" json:"trips_completed,omitempty"`
+	// current_trip is set while a trip is in progress
+	CurrentTrip   *LiveTrip `protobuf:"bytes,10,opt,name=current_trip,json=currentTrip,proto3" json:"current_trip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceLiveState) Reset() {
+	*x = DeviceLiveState{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceLiveState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceLiveState) ProtoMessage() {}
+
+func (x *DeviceLiveState) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceLiveState.ProtoReflect.Descriptor instead.
+func (*DeviceLiveState) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeviceLiveState) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeviceLiveState) GetSummary() *DailySummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *DeviceLiveState) GetLastFixTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastFixTime
+	}
+	return nil
+}
+
+func (x *DeviceLiveState) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *DeviceLiveState) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *DeviceLiveState) GetDistanceFromHomeKm() float64 {
+	if x != nil {
+		return x.DistanceFromHomeKm
+	}
+	return 0
+}
+
+func (x *DeviceLiveState) GetAway() bool {
+	if x != nil {
+		return x.Away
+	}
+	return false
+}
+
+func (x *DeviceLiveState) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *DeviceLiveState) GetTripsCompleted() int32 {
+	if x != nil {
+		return x.TripsCompleted
+	}
+	return 0
+}
+
+func (x *DeviceLiveState) GetCurrentTrip() *LiveTrip {
+	if x != nil {
+		return x.CurrentTrip
+	}
+	return nil
+}
+
+// LiveTrip is a trip in progress up to its last moving fix.
+type LiveTrip struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamp.Timestamp   `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	LastMoveTime  *timestamp.Timestamp   `protobuf:"bytes,2,opt,name=last_move_time,json=lastMoveTime,proto3" json:"last_move_time,omitempty"`
+	DistanceKm    float64                `protobuf:"fixed64,3,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LiveTrip) Reset() {
+	*x = LiveTrip{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LiveTrip) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LiveTrip) ProtoMessage() {}
+
+func (x *LiveTrip) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LiveTrip.ProtoReflect.Descriptor instead.
+func (*LiveTrip) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *LiveTrip) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *LiveTrip) GetLastMoveTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastMoveTime
+	}
+	return nil
+}
+
+func (x *LiveTrip) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+// GetDataQualityReportRequest selects the date range and device to check.
+type GetDataQualityReportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_date is the first day of the range in YYYY-MM-DD format
+	StartDate string `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date is the last day of the range (inclusive) in YYYY-MM-DD format
+	EndDate string `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// device_id optionally restricts the report to a single OwnTracks device
+	// If empty, a report is returned for every device with data in the range
+	DeviceId string `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// gap_threshold_minutes is the shortest interval without fixes reported
+	// as a coverage gap (default: 60)
+	GapThresholdMinutes int32 `protobuf:"varint,4,opt,name=gap_threshold_minutes,json=gapThresholdMinutes,proto3" json:"gap_threshold_minutes,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GetDataQualityReportRequest) Reset() {
+	*x = GetDataQualityReportRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDataQualityReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDataQualityReportRequest) ProtoMessage() {}
+
+func (x *GetDataQualityReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDataQualityReportRequest.ProtoReflect.Descriptor instead.
+func (*GetDataQualityReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetDataQualityReportRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetDataQualityReportRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *GetDataQualityReportRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *GetDataQualityReportRequest) GetGapThresholdMinutes() int32 {
+	if x != nil {
+		return x.GapThresholdMinutes
+	}
+	return 0
+}
+
+// GetDataQualityReportResponse contains one data quality report per device.
+type GetDataQualityReportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// devices is the report for each device, ordered by device_id
+	Devices       []*DeviceDataQuality `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDataQualityReportResponse) Reset() {
+	*x = GetDataQualityReportResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDataQualityReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDataQualityReportResponse) ProtoMessage() {}
+
+func (x *GetDataQualityReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDataQualityReportResponse.ProtoReflect.Descriptor instead.
+func (*GetDataQualityReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetDataQualityReportResponse) GetDevices() []*DeviceDataQuality {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+// DeviceDataQuality summarizes data quality problems for a single device.
+type DeviceDataQuality struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// device_id is the OwnTracks device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// point_count is the number of rows, including duplicates
+	PointCount int32 `protobuf:"varint,2,opt,name=point_count,json=pointCount,proto3" json:"point_count,omitempty"`
+	// first_fix_time and last_fix_time bound the device timestamps seen
+	FirstFixTime *timestamp.Timestamp `protobuf:"bytes,3,opt,name=first_fix_time,json=firstFixTime,proto3" json:"first_fix_time,omitempty"`
+	LastFixTime  *timestamp.Timestamp `protobuf:"bytes,4,opt,name=last_fix_time,json=lastFixTime,proto3" json:"last_fix_time,omitempty"`
+	// gaps lists each period longer than the threshold without fixes, in
+	// chronological order
+	Gaps []*CoverageGap `protobuf:"bytes,5,rep,name=gaps,proto3" json:"gaps,omitempty"`
+	// gap_seconds is the total duration of the gaps
+	GapSeconds int64 `protobuf:"varint,6,opt,name=gap_seconds,json=gapSeconds,proto3" json:"gap_seconds,omitempty"`
+	// duplicate_count is the number of rows repeating an earlier row's
+	// timestamp and position, as left by retried uploads
+	DuplicateCount int32 `protobuf:"varint,7,opt,name=duplicate_count,json=duplicateCount,proto3" json:"duplicate_count,omitempty"`
+	// out_of_order_count is the number of rows whose timestamp is earlier
+	// than that of the row stored before them
+	OutOfOrderCount int32 `protobuf:"varint,8,opt,name=out_of_order_count,json=outOfOrderCount,proto3" json:"out_of_order_count,omitempty"`
+	// missing_fields is the NULL or empty rate of each optional column
+	MissingFields []*FieldMissingRate `protobuf:"bytes,9,rep,name=missing_fields,json=missingFields,proto3" json:"missing_fields,omitempty"`
+	// accuracy describes the reported horizontal accuracy
+	Accuracy      *AccuracyDistribution `protobuf:"bytes,10,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceDataQuality) Reset() {
+	*x = DeviceDataQuality{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceDataQuality) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceDataQuality) ProtoMessage() {}
+
+func (x *DeviceDataQuality) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceDataQuality.ProtoReflect.Descriptor instead.
+func (*DeviceDataQuality) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DeviceDataQuality) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeviceDataQuality) GetPointCount() int32 {
+	if x != nil {
+		return x.PointCount
+	}
+	return 0
+}
+
+func (x *DeviceDataQuality) GetFirstFixTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.FirstFixTime
+	}
+	return nil
+}
+
+func (x *DeviceDataQuality) GetLastFixTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.LastFixTime
+	}
+	return nil
+}
+
+func (x *DeviceDataQuality) GetGaps() []*CoverageGap {
+	if x != nil {
+		return x.Gaps
+	}
+	return nil
+}
+
+func (x *DeviceDataQuality) GetGapSeconds() int64 {
+	if x != nil {
+		return x.GapSeconds
+	}
+	return 0
+}
+
+func (x *DeviceDataQuality) GetDuplicateCount() int32 {
+	if x != nil {
+		return x.DuplicateCount
+	}
+	return 0
+}
+
+func (x *DeviceDataQuality) GetOutOfOrderCount() int32 {
+	if x != nil {
+		return x.OutOfOrderCount
+	}
+	return 0
+}
+
+func (x *DeviceDataQuality) GetMissingFields() []*FieldMissingRate {
+	if x != nil {
+		return x.MissingFields
+	}
+	return nil
+}
+
+func (x *DeviceDataQuality) GetAccuracy() *AccuracyDistribution {
+	if x != nil {
+		return x.Accuracy
+	}
+	return nil
+}
+
+// CoverageGap is a period in which a device reported no fixes.
+type CoverageGap struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_time is the last fix before the gap
+	StartTime *timestamp.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// end_time is the first fix after the gap
+	EndTime         *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	DurationSeconds int64                `protobuf:"varint,3,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CoverageGap) Reset() {
+	*x = CoverageGap{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoverageGap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoverageGap) ProtoMessage() {}
+
+func (x *CoverageGap) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoverageGap.ProtoReflect.Descriptor instead.
+func (*CoverageGap) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *CoverageGap) GetStartTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *CoverageGap) GetEndTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *CoverageGap) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+// FieldMissingRate is how often a column was NULL or empty.
+type FieldMissingRate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// field is the public.locations column name
+	Field        string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	MissingCount int32  `protobuf:"varint,2,opt,name=missing_count,json=missingCount,proto3" json:"missing_count,omitempty"`
+	// rate is missing_count divided by point_count
+	Rate          float64 `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldMissingRate) Reset() {
+	*x = FieldMissingRate{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldMissingRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldMissingRate) ProtoMessage() {}
+
+func (x *FieldMissingRate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldMissingRate.ProtoReflect.Descriptor instead.
+func (*FieldMissingRate) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *FieldMissingRate) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldMissingRate) GetMissingCount() int32 {
+	if x != nil {
+		return x.MissingCount
+	}
+	return 0
+}
+
+func (x *FieldMissingRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+// AccuracyDistribution describes the accuracy of the fixes that report one.
+type AccuracyDistribution struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// reported_count is the number of fixes with an accuracy
+	ReportedCount int32   `protobuf:"varint,1,opt,name=reported_count,json=reportedCount,proto3" json:"reported_count,omitempty"`
+	MedianM       float64 `protobuf:"fixed64,2,opt,name=median_m,json=medianM,proto3" json:"median_m,omitempty"`
+	P90M          float64 `protobuf:"fixed64,3,opt,name=p90_m,json=p90M,proto3" json:"p90_m,omitempty"`
+	MaxM          int32   `protobuf:"varint,4,opt,name=max_m,json=maxM,proto3" json:"max_m,omitempty"`
+	// buckets is the histogram in increasing order of max_m
+	Buckets       []*AccuracyBucket `protobuf:"bytes,5,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccuracyDistribution) Reset() {
+	*x = AccuracyDistribution{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccuracyDistribution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccuracyDistribution) ProtoMessage() {}
+
+func (x *AccuracyDistribution) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccuracyDistribution.ProtoReflect.Descriptor instead.
+func (*AccuracyDistribution) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *AccuracyDistribution) GetReportedCount() int32 {
+	if x != nil {
+		return x.ReportedCount
+	}
+	return 0
+}
+
+func (x *AccuracyDistribution) GetMedianM() float64 {
+	if x != nil {
+		return x.MedianM
+	}
+	return 0
+}
+
+func (x *AccuracyDistribution) GetP90M() float64 {
+	if x != nil {
+		return x.P90M
+	}
+	return 0
+}
+
+func (x *AccuracyDistribution) GetMaxM() int32 {
+	if x != nil {
+		return x.MaxM
+	}
+	return 0
+}
+
+func (x *AccuracyDistribution) GetBuckets() []*AccuracyBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// AccuracyBucket counts fixes with an accuracy above the previous bucket's
+// max_m and at most its own.
+type AccuracyBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// max_m is the upper bound in meters, or 0 for the open-ended last bucket
+	MaxM          int32 `protobuf:"varint,1,opt,name=max_m,json=maxM,proto3" json:"max_m,omitempty"`
+	Count         int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccuracyBucket) Reset() {
+	*x = AccuracyBucket{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccuracyBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccuracyBucket) ProtoMessage() {}
+
+func (x *AccuracyBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccuracyBucket.ProtoReflect.Descriptor instead.
+func (*AccuracyBucket) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *AccuracyBucket) GetMaxM() int32 {
+	if x != nil {
+		return x.MaxM
+	}
+	return 0
+}
+
+func (x *AccuracyBucket) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// ListArtifactsRequest filters the artifact catalog. Empty filters match
+// every artifact.
+type ListArtifactsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// job_id filters artifacts to those written by one job
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// date filters artifacts to a calculation date (YYYY-MM-DD)
+	Date string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	// device_id filters artifacts to an OwnTracks device
+	DeviceId string `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	// format filters artifacts to a file format, e.g. "csv" or "csv_summary"
+	Format string `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	// limit is the maximum number of artifacts to return (default: 50, max: 500)
+	Limit int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// offset is the starting position for pagination (default: 0)
+	Offset        int32 `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListArtifactsRequest) Reset() {
+	*x = ListArtifactsRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListArtifactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArtifactsRequest) ProtoMessage() {}
+
+func (x *ListArtifactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArtifactsRequest.ProtoReflect.Descriptor instead.
+func (*ListArtifactsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListArtifactsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ListArtifactsRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ListArtifactsRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *ListArtifactsRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ListArtifactsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListArtifactsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// ListArtifactsResponse returns a page of the artifact catalog.
+type ListArtifactsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// artifacts are the matching files, newest first; download URLs are
+	// only signed by GetJobStatus
+	Artifacts []*Artifact `protobuf:"bytes,1,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	// total_count is the number of artifacts matching the filters
+	TotalCount int32 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	// limit is the maximum number of artifacts returned in this response
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// offset is the starting position used for this request
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListArtifactsResponse) Reset() {
+	*x = ListArtifactsResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListArtifactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArtifactsResponse) ProtoMessage() {}
+
+func (x *ListArtifactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArtifactsResponse.ProtoReflect.Descriptor instead.
+func (*ListArtifactsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListArtifactsResponse) GetArtifacts() []*Artifact {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *ListArtifactsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListArtifactsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListArtifactsResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// GetArtifactRequest names a stored file.
+type GetArtifactRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key is the file's storage key, e.g. distance_20260124_{job_id}.csv
+	Key           string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArtifactRequest) Reset() {
+	*x = GetArtifactRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArtifactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArtifactRequest) ProtoMessage() {}
+
+func (x *GetArtifactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArtifactRequest.ProtoReflect.Descriptor instead.
+func (*GetArtifactRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetArtifactRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// GetArtifactResponse describes a stored file.
+type GetArtifactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Artifact      *Artifact              `protobuf:"bytes,1,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArtifactResponse) Reset() {
+	*x = GetArtifactResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArtifactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArtifactResponse) ProtoMessage() {}
+
+func (x *GetArtifactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArtifactResponse.ProtoReflect.Descriptor instead.
+func (*GetArtifactResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetArtifactResponse) GetArtifact() *Artifact {
+	if x != nil {
+		return x.Artifact
+	}
+	return nil
+}
+
+// DeleteArtifactRequest names the stored file to remove.
+type DeleteArtifactRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key is the file's storage key
+	Key           string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteArtifactRequest) Reset() {
+	*x = DeleteArtifactRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteArtifactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteArtifactRequest) ProtoMessage() {}
+
+func (x *DeleteArtifactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteArtifactRequest.ProtoReflect.Descriptor instead.
+func (*DeleteArtifactRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *DeleteArtifactRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// DeleteArtifactResponse confirms a removal.
+type DeleteArtifactResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key is the removed file's storage key
+	Key           string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteArtifactResponse) Reset() {
+	*x = DeleteArtifactResponse{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteArtifactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteArtifactResponse) ProtoMessage() {}
+
+func (x *DeleteArtifactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteArtifactResponse.ProtoReflect.Descriptor instead.
+func (*DeleteArtifactResponse) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *DeleteArtifactResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+// DownloadArtifactRequest names the stored file to stream.
+type DownloadArtifactRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key is the file's storage key
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// offset is the byte position to start from; pass the bytes already
+	// received to resume (default: 0)
+	Offset        int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadArtifactRequest) Reset() {
+	*x = DownloadArtifactRequest{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadArtifactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadArtifactRequest) ProtoMessage() {}
+
+func (x *DownloadArtifactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadArtifactRequest.ProtoReflect.Descriptor instead.
+func (*DownloadArtifactRequest) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *DownloadArtifactRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DownloadArtifactRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// ArtifactChunk is a consecutive piece of a streamed file.
+type ArtifactChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// offset is the byte position of data in the file
+	Offset int64  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data   []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// size_bytes is the size of the whole file
+	SizeBytes     int64 `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtifactChunk) Reset() {
+	*x = ArtifactChunk{}
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtifactChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtifactChunk) ProtoMessage() {}
+
+func (x *ArtifactChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_distance_v1_distance_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtifactChunk.ProtoReflect.Descriptor instead.
+func (*ArtifactChunk) Descriptor() ([]byte, []int) {
+	return file_proto_distance_v1_distance_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ArtifactChunk) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ArtifactChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ArtifactChunk) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+var File_proto_distance_v1_distance_proto protoreflect.FileDescriptor
+
+const file_proto_distance_v1_distance_proto_rawDesc = "" +
+	"\n" +
+	" proto/distance/v1/distance.proto\x12\vdistance.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc7\x01\n" +
+	"\x18CalculateDistanceRequest\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12%\n" +
+	"\x0eoutput_formats\x18\x04 \x03(\tR\routputFormats\x12;\n" +
+	"\fcsv_template\x18\x05 \x01(\v2\x18.distance.v1.CsvTemplateR\vcsvTemplate\"\xeb\x01\n" +
+	"\vCsvTemplate\x12\x18\n" +
+	"\acolumns\x18\x01 \x03(\tR\acolumns\x12\x14\n" +
+	"\x05units\x18\x02 \x01(\tR\x05units\x12!\n" +
+	"\tprecision\x18\x03 \x01(\x05H\x00R\tprecision\x88\x01\x01\x12\x1a\n" +
+	"\btimezone\x18\x04 \x01(\tR\btimezone\x12)\n" +
+	"\x10timestamp_format\x18\x05 \x01(\tR\x0ftimestampFormat\x12\x1c\n" +
+	"\tdelimiter\x18\x06 \x01(\tR\tdelimiter\x12\x16\n" +
+	"\x06footer\x18\a \x01(\tR\x06footerB\f\n" +
+	"\n" +
+	"_precision\"\x83\x01\n" +
+	"\x19CalculateDistanceResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x127\n" +
+	"\tqueued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bqueuedAt\",\n" +
+	"\x13GetJobStatusRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xcd\x02\n" +
+	"\x14GetJobStatusResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x127\n" +
+	"\tqueued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bqueuedAt\x129\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12#\n" +
+	"\rerror_message\x18\x06 \x01(\tR\ferrorMessage\x12.\n" +
+	"\x06result\x18\a \x01(\v2\x16.distance.v1.JobResultR\x06result\"\x88\x01\n" +
+	"\x0fListJobsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x12\n" +
+	"\x04date\x18\x04 \x01(\tR\x04date\x12\x1b\n" +
+	"\tdevice_id\x18\x05 \x01(\tR\bdeviceId\"\x8e\x01\n" +
+	"\x10ListJobsResponse\x12+\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x17.distance.v1.JobSummaryR\x04jobs\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\xcc\x02\n" +
+	"\n" +
+	"JobSummary\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\x12\x1b\n" +
+	"\tdevice_id\x18\x04 \x01(\tR\bdeviceId\x127\n" +
+	"\tqueued_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bqueuedAt\x12=\n" +
+	"\fcompleted_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x12\n" +
+	"\x04kind\x18\a \x01(\tR\x04kind\x12\x19\n" +
+	"\bend_date\x18\b \x01(\tR\aendDate\x12\x1f\n" +
+	"\vactivity_id\x18\t \x01(\x03R\n" +
+	"activityId\x12\x16\n" +
+	"\x06source\x18\n" +
+	" \x01(\tR\x06source\"\xc5\x06\n" +
+	"\tJobResult\x12\x19\n" +
+	"\bcsv_path\x18\x01 \x01(\tR\acsvPath\x12*\n" +
+	"\x11total_distance_km\x18\x02 \x01(\x01R\x0ftotalDistanceKm\x12'\n" +
+	"\x0ftotal_locations\x18\x03 \x01(\x05R\x0etotalLocations\x12&\n" +
+	"\x0fmax_distance_km\x18\x04 \x01(\x01R\rmaxDistanceKm\x12&\n" +
+	"\x0fmin_distance_km\x18\x05 \x01(\x01R\rminDistanceKm\x12\x12\n" +
+	"\x04date\x18\x06 \x01(\tR\x04date\x12\x1b\n" +
+	"\tdevice_id\x18\a \x01(\tR\bdeviceId\x12,\n" +
+	"\x12processing_time_ms\x18\b \x01(\x03R\x10processingTimeMs\x12\"\n" +
+	"\rmax_speed_kmh\x18\t \x01(\x01R\vmaxSpeedKmh\x127\n" +
+	"\vmode_totals\x18\n" +
+	" \x03(\v2\x16.distance.v1.ModeTotalR\n" +
+	"modeTotals\x129\n" +
+	"\televation\x18\v \x01(\v2\x1b.distance.v1.ElevationStatsR\televation\x12C\n" +
+	"\x0ftrip_elevations\x18\f \x03(\v2\x1a.distance.v1.TripElevationR\x0etripElevations\x12+\n" +
+	"\x11summaries_written\x18\r \x01(\x05R\x10summariesWritten\x128\n" +
+	"\bactivity\x18\x0e \x01(\v2\x1c.distance.v1.ActivityMetricsR\bactivity\x12\x16\n" +
+	"\x06source\x18\x0f \x01(\tR\x06source\x12\x19\n" +
+	"\bgpx_path\x18\x10 \x01(\tR\agpxPath\x123\n" +
+	"\tartifacts\x18\x11 \x03(\v2\x15.distance.v1.ArtifactR\tartifacts\x12!\n" +
+	"\fdownload_url\x18\x12 \x01(\tR\vdownloadUrl\x12J\n" +
+	"\x13download_expires_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\x11downloadExpiresAt\"\x91\x02\n" +
+	"\bArtifact\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12!\n" +
+	"\fdownload_url\x18\x03 \x01(\tR\vdownloadUrl\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\x12\x16\n" +
+	"\x06sha256\x18\x05 \x01(\tR\x06sha256\x12\x15\n" +
+	"\x06job_id\x18\x06 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04date\x18\a \x01(\tR\x04date\x12\x1b\n" +
+	"\tdevice_id\x18\b \x01(\tR\bdeviceId\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"k\n" +
+	"\tModeTotal\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x12\x1f\n" +
+	"\vdistance_km\x18\x02 \x01(\x01R\n" +
+	"distanceKm\x12)\n" +
+	"\x10duration_seconds\x18\x03 \x01(\x03R\x0fdurationSeconds\"\xaa\x01\n" +
+	"\x0eElevationStats\x12$\n" +
+	"\x0etotal_ascent_m\x18\x01 \x01(\x01R\ftotalAscentM\x12&\n" +
+	"\x0ftotal_descent_m\x18\x02 \x01(\x01R\rtotalDescentM\x12$\n" +
+	"\x0emin_altitude_m\x18\x03 \x01(\x01R\fminAltitudeM\x12$\n" +
+	"\x0emax_altitude_m\x18\x04 \x01(\x01R\fmaxAltitudeM\"\x95\x02\n" +
+	"\rTripElevation\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1f\n" +
+	"\vdistance_km\x18\x03 \x01(\x01R\n" +
+	"distanceKm\x129\n" +
+	"\televation\x18\x04 \x01(\v2\x1b.distance.v1.ElevationStatsR\televation\x126\n" +
+	"\aprofile\x18\x05 \x03(\v2\x1c.distance.v1.ElevationSampleR\aprofile\"Q\n" +
+	"\x0fElevationSample\x12\x1f\n" +
+	"\vdistance_km\x18\x01 \x01(\x01R\n" +
+	"distanceKm\x12\x1d\n" +
+	"\n" +
+	"altitude_m\x18\x02 \x01(\x01R\taltitudeM\"p\n" +
+	"\x17GetBatteryReportRequest\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\"V\n" +
+	"\x18GetBatteryReportResponse\x12:\n" +
+	"\adevices\x18\x01 \x03(\v2 .distance.v1.DeviceBatteryReportR\adevices\"\x8e\x02\n" +
+	"\x13DeviceBatteryReport\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12-\n" +
+	"\x13drain_rate_per_hour\x18\x02 \x01(\x01R\x10drainRatePerHour\x121\n" +
+	"\x14distance_correlation\x18\x03 \x01(\x01R\x13distanceCorrelation\x12-\n" +
+	"\x04days\x18\x04 \x03(\v2\x19.distance.v1.DailyBatteryR\x04days\x12I\n" +
+	"\x11charging_sessions\x18\x05 \x03(\v2\x1c.distance.v1.ChargingSessionR\x10chargingSessions\"\xc7\x02\n" +
+	"\fDailyBattery\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12-\n" +
+	"\x13drain_rate_per_hour\x18\x02 \x01(\x01R\x10drainRatePerHour\x12'\n" +
+	"\x0fdrained_percent\x18\x03 \x01(\x01R\x0edrainedPercent\x12'\n" +
+	"\x0fdischarge_hours\x18\x04 \x01(\x01R\x0edischargeHours\x12\x1b\n" +
+	"\tmin_level\x18\x05 \x01(\x05R\bminLevel\x12\x1b\n" +
+	"\tmax_level\x18\x06 \x01(\x05R\bmaxLevel\x12+\n" +
+	"\x11charging_sessions\x18\a \x01(\x05R\x10chargingSessions\x12\x1f\n" +
+	"\vdistance_km\x18\b \x01(\x01R\n" +
+	"distanceKm\x12\x1a\n" +
+	"\breadings\x18\t \x01(\x05R\breadings\"\xc1\x01\n" +
+	"\x0fChargingSession\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1f\n" +
+	"\vstart_level\x18\x03 \x01(\x05R\n" +
+	"startLevel\x12\x1b\n" +
+	"\tend_level\x18\x04 \x01(\x05R\bendLevel\"q\n" +
+	"\x18GetDailySummariesRequest\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\"T\n" +
+	"\x19GetDailySummariesResponse\x127\n" +
+	"\tsummaries\x18\x01 \x03(\v2\x19.distance.v1.DailySummaryR\tsummaries\"\xe9\x02\n" +
+	"\fDailySummary\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12(\n" +
+	"\x10path_distance_km\x18\x03 \x01(\x01R\x0epathDistanceKm\x12&\n" +
+	"\x0fmax_distance_km\x18\x04 \x01(\x01R\rmaxDistanceKm\x12&\n" +
+	"\x0fmin_distance_km\x18\x05 \x01(\x01R\rminDistanceKm\x12&\n" +
+	"\x0favg_distance_km\x18\x06 \x01(\x01R\ravgDistanceKm\x12\x1f\n" +
+	"\vpoint_count\x18\a \x01(\x05R\n" +
+	"pointCount\x12*\n" +
+	"\x11time_away_seconds\x18\b \x01(\x03R\x0ftimeAwaySeconds\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"v\n" +
+	"\x1dBackfillDailySummariesRequest\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\"\x88\x01\n" +
+	"\x1eBackfillDailySummariesResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x127\n" +
+	"\tqueued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bqueuedAt\"m\n" +
+	"\x1bListGarminActivitiesRequest\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12\x14\n" +
+	"\x05sport\x18\x03 \x01(\tR\x05sport\"[\n" +
+	"\x1cListGarminActivitiesResponse\x12;\n" +
+	"\n" +
+	"activities\x18\x01 \x03(\v2\x1b.distance.v1.GarminActivityR\n" +
+	"activities\"\xf6\x04\n" +
+	"\x0eGarminActivity\x12\x1f\n" +
+	"\vactivity_id\x18\x01 \x01(\x03R\n" +
+	"activityId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05sport\x18\x03 \x01(\tR\x05sport\x12\x1b\n" +
+	"\tsub_sport\x18\x04 \x01(\tR\bsubSport\x129\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12)\n" +
+	"\x10duration_seconds\x18\a \x01(\x01R\x0fdurationSeconds\x12\x1f\n" +
+	"\vdistance_km\x18\b \x01(\x01R\n" +
+	"distanceKm\x12\x1a\n" +
+	"\bcalories\x18\t \x01(\x05R\bcalories\x12$\n" +
+	"\x0eavg_heart_rate\x18\n" +
+	" \x01(\x05R\favgHeartRate\x12$\n" +
+	"\x0emax_heart_rate\x18\v \x01(\x05R\fmaxHeartRate\x12\x1f\n" +
+	"\vavg_cadence\x18\f \x01(\x05R\n" +
+	"avgCadence\x12\x1f\n" +
+	"\vmax_cadence\x18\r \x01(\x05R\n" +
+	"maxCadence\x12\"\n" +
+	"\ravg_speed_kmh\x18\x0e \x01(\x01R\vavgSpeedKmh\x12\"\n" +
+	"\rmax_speed_kmh\x18\x0f \x01(\x01R\vmaxSpeedKmh\x12$\n" +
+	"\x0etotal_ascent_m\x18\x10 \x01(\x01R\ftotalAscentM\x12&\n" +
+	"\x0ftotal_descent_m\x18\x11 \x01(\x01R\rtotalDescentM\";\n" +
+	"\x18GetGarminActivityRequest\x12\x1f\n" +
+	"\vactivity_id\x18\x01 \x01(\x03R\n" +
+	"activityId\"\x8c\x01\n" +
+	"\x19GetGarminActivityResponse\x127\n" +
+	"\bactivity\x18\x01 \x01(\v2\x1b.distance.v1.GarminActivityR\bactivity\x126\n" +
+	"\ametrics\x18\x02 \x01(\v2\x1c.distance.v1.ActivityMetricsR\ametrics\"\xe7\x04\n" +
+	"\x0fActivityMetrics\x12\x1f\n" +
+	"\vactivity_id\x18\x01 \x01(\x03R\n" +
+	"activityId\x12\x1f\n" +
+	"\vpoint_count\x18\x02 \x01(\x05R\n" +
+	"pointCount\x12(\n" +
+	"\x10path_distance_km\x18\x03 \x01(\x01R\x0epathDistanceKm\x12)\n" +
+	"\x10duration_seconds\x18\x04 \x01(\x03R\x0fdurationSeconds\x12\"\n" +
+	"\ravg_speed_kmh\x18\x05 \x01(\x01R\vavgSpeedKmh\x12\"\n" +
+	"\rmax_speed_kmh\x18\x06 \x01(\x01R\vmaxSpeedKmh\x12$\n" +
+	"\x0eavg_heart_rate\x18\a \x01(\x01R\favgHeartRate\x12$\n" +
+	"\x0emax_heart_rate\x18\b \x01(\x05R\fmaxHeartRate\x12\x1f\n" +
+	"\vavg_cadence\x18\t \x01(\x01R\n" +
+	"avgCadence\x12\x1f\n" +
+	"\vmax_cadence\x18\n" +
+	" \x01(\x05R\n" +
+	"maxCadence\x128\n" +
+	"\x19max_distance_from_home_km\x18\v \x01(\x01R\x15maxDistanceFromHomeKm\x128\n" +
+	"\x19min_distance_from_home_km\x18\f \x01(\x01R\x15minDistanceFromHomeKm\x128\n" +
+	"\x19avg_distance_from_home_km\x18\r \x01(\x01R\x15avgDistanceFromHomeKm\x129\n" +
+	"\televation\x18\x0e \x01(\v2\x1b.distance.v1.ElevationStatsR\televation\"C\n" +
+	" CalculateActivityDistanceRequest\x12\x1f\n" +
+	"\vactivity_id\x18\x01 \x01(\x03R\n" +
+	"activityId\"\x98\x01\n" +
+	"\x16StreamLocationsRequest\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12'\n" +
+	"\x0finclude_payload\x18\x04 \x01(\bR\x0eincludePayload\"\x8e\x04\n" +
+	"\x0eLocationRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\tR\bdeviceId\x12\x10\n" +
+	"\x03tid\x18\x03 \x01(\tR\x03tid\x12\x1a\n" +
+	"\blatitude\x18\x04 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x05 \x01(\x01R\tlongitude\x12\x1a\n" +
+	"\baccuracy\x18\x06 \x01(\x05R\baccuracy\x12\x1a\n" +
+	"\baltitude\x18\a \x01(\x01R\baltitude\x12\x1a\n" +
+	"\bvelocity\x18\b \x01(\x05R\bvelocity\x12\x18\n" +
+	"\abattery\x18\t \x01(\x05R\abattery\x12%\n" +
+	"\x0ebattery_status\x18\n" +
+	" \x01(\x05R\rbatteryStatus\x12'\n" +
+	"\x0fconnection_type\x18\v \x01(\tR\x0econnectionType\x12\x18\n" +
+	"\atrigger\x18\f \x01(\tR\atrigger\x128\n" +
+	"\ttimestamp\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x126\n" +
+	"\apayload\x18\x0f \x01(\v2\x1c.distance.v1.LocationPayloadR\apayload\"\x89\x02\n" +
+	"\x0fLocationPayload\x12\x1d\n" +
+	"\n" +
+	"in_regions\x18\x01 \x03(\tR\tinRegions\x12\x12\n" +
+	"\x04ssid\x18\x02 \x01(\tR\x04ssid\x12\x14\n" +
+	"\x05bssid\x18\x03 \x01(\tR\x05bssid\x12\"\n" +
+	"\n" +
+	"course_deg\x18\x04 \x01(\x05H\x00R\tcourseDeg\x88\x01\x01\x12.\n" +
+	"\x13vertical_accuracy_m\x18\x05 \x01(\x05R\x11verticalAccuracyM\x12!\n" +
+	"\fpressure_kpa\x18\x06 \x01(\x01R\vpressureKpa\x12'\n" +
+	"\x0fmonitoring_mode\x18\a \x01(\x05R\x0emonitoringModeB\r\n" +
+	"\v_course_deg\"\xde\x01\n" +
+	"\x18FindLocationsNearRequest\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x1b\n" +
+	"\tradius_km\x18\x03 \x01(\x01R\bradiusKm\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x05 \x01(\tR\aendDate\x12\x1b\n" +
+	"\tdevice_id\x18\x06 \x01(\tR\bdeviceId\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\"\xa9\x01\n" +
+	"\x19FindLocationsNearResponse\x129\n" +
+	"\tlocations\x18\x01 \x03(\v2\x1b.distance.v1.NearbyLocationR\tlocations\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x120\n" +
+	"\x06visits\x18\x03 \x03(\v2\x18.distance.v1.NearbyVisitR\x06visits\"j\n" +
+	"\x0eNearbyLocation\x127\n" +
+	"\blocation\x18\x01 \x01(\v2\x1b.distance.v1.LocationRecordR\blocation\x12\x1f\n" +
+	"\vdistance_km\x18\x02 \x01(\x01R\n" +
+	"distanceKm\"\xdc\x01\n" +
+	"\vNearbyVisit\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1f\n" +
+	"\vpoint_count\x18\x04 \x01(\x05R\n" +
+	"pointCount\x12\x1d\n" +
+	"\n" +
+	"closest_km\x18\x05 \x01(\x01R\tclosestKm\"2\n" +
+	"\x13GetLiveStateRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\"\xb3\x01\n" +
+	"\x14GetLiveStateResponse\x126\n" +
+	"\adevices\x18\x01 \x03(\v2\x1c.distance.v1.DeviceLiveStateR\adevices\x12(\n" +
+	"\x10last_location_id\x18\x02 \x01(\x03R\x0elastLocationId\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x9b\x03\n" +
+	"\x0fDeviceLiveState\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x123\n" +
+	"\asummary\x18\x02 \x01(\v2\x19.distance.v1.DailySummaryR\asummary\x12>\n" +
+	"\rlast_fix_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vlastFixTime\x12\x1a\n" +
+	"\blatitude\x18\x04 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x05 \x01(\x01R\tlongitude\x121\n" +
+	"\x15distance_from_home_km\x18\x06 \x01(\x01R\x12distanceFromHomeKm\x12\x12\n" +
+	"\x04away\x18\a \x01(\bR\x04away\x12\x12\n" +
+	"\x04mode\x18\b \x01(\tR\x04mode\x12'\n" +
+	"\x0ftrips_completed\x18\t \x01(\x05R\x0etripsCompleted\x128\n" +
+	"\fcurrent_trip\x18\n" +
+	" \x01(\v2\x15.distance.v1.LiveTripR\vcurrentTrip\"\xa8\x01\n" +
+	"\bLiveTrip\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12@\n" +
+	"\x0elast_move_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\flastMoveTime\x12\x1f\n" +
+	"\vdistance_km\x18\x03 \x01(\x01R\n" +
+	"distanceKm\"\xa8\x01\n" +
+	"\x1bGetDataQualityReportRequest\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x02 \x01(\tR\aendDate\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x122\n" +
+	"\x15gap_threshold_minutes\x18\x04 \x01(\x05R\x13gapThresholdMinutes\"X\n" +
+	"\x1cGetDataQualityReportResponse\x128\n" +
+	"\adevices\x18\x01 \x03(\v2\x1e.distance.v1.DeviceDataQualityR\adevices\"\xfd\x03\n" +
+	"\x11DeviceDataQuality\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x1f\n" +
+	"\vpoint_count\x18\x02 \x01(\x05R\n" +
+	"pointCount\x12@\n" +
+	"\x0efirst_fix_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ffirstFixTime\x12>\n" +
+	"\rlast_fix_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vlastFixTime\x12,\n" +
+	"\x04gaps\x18\x05 \x03(\v2\x18.distance.v1.CoverageGapR\x04gaps\x12\x1f\n" +
+	"\vgap_seconds\x18\x06 \x01(\x03R\n" +
+	"gapSeconds\x12'\n" +
+	"\x0fduplicate_count\x18\a \x01(\x05R\x0eduplicateCount\x12+\n" +
+	"\x12out_of_order_count\x18\b \x01(\x05R\x0foutOfOrderCount\x12D\n" +
+	"\x0emissing_fields\x18\t \x03(\v2\x1d.distance.v1.FieldMissingRateR\rmissingFields\x12=\n" +
+	"\baccuracy\x18\n" +
+	" \x01(\v2!.distance.v1.AccuracyDistributionR\baccuracy\"\xaa\x01\n" +
+	"\vCoverageGap\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12)\n" +
+	"\x10duration_seconds\x18\x03 \x01(\x03R\x0fdurationSeconds\"a\n" +
+	"\x10FieldMissingRate\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12#\n" +
+	"\rmissing_count\x18\x02 \x01(\x05R\fmissingCount\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x01R\x04rate\"\xb9\x01\n" +
+	"\x14AccuracyDistribution\x12%\n" +
+	"\x0ereported_count\x18\x01 \x01(\x05R\rreportedCount\x12\x19\n" +
+	"\bmedian_m\x18\x02 \x01(\x01R\amedianM\x12\x13\n" +
+	"\x05p90_m\x18\x03 \x01(\x01R\x04p90M\x12\x13\n" +
+	"\x05max_m\x18\x04 \x01(\x05R\x04maxM\x125\n" +
+	"\abuckets\x18\x05 \x03(\v2\x1b.distance.v1.AccuracyBucketR\abuckets\";\n" +
+	"\x0eAccuracyBucket\x12\x13\n" +
+	"\x05max_m\x18\x01 \x01(\x05R\x04maxM\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"\xa4\x01\n" +
+	"\x14ListArtifactsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12\x16\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x05R\x06offset\"\x9b\x01\n" +
+	"\x15ListArtifactsResponse\x123\n" +
+	"\tartifacts\x18\x01 \x03(\v2\x15.distance.v1.ArtifactR\tartifacts\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"&\n" +
+	"\x12GetArtifactRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"H\n" +
+	"\x13GetArtifactResponse\x121\n" +
+	"\bartifact\x18\x01 \x01(\v2\x15.distance.v1.ArtifactR\bartifact\")\n" +
+	"\x15DeleteArtifactRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"*\n" +
+	"\x16DeleteArtifactResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"C\n" +
+	"\x17DownloadArtifactRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\"Z\n" +
+	"\rArtifactChunk\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x03 \x01(\x03R\tsizeBytes2\xf2\f\n" +
 	"\x0fDistanceService\x12j\n" +
 	"\x19CalculateDistanceFromHome\x12%.distance.v1.CalculateDistanceRequest\x1a&.distance.v1.CalculateDistanceResponse\x12S\n" +
 	"\fGetJobStatus\x12 .distance.v1.GetJobStatusRequest\x1a!.distance.v1.GetJobStatusResponse\x12G\n" +
-	"\bListJobs\x12\x1c.distance.v1.ListJobsRequest\x1a\x1d.distance.v1.ListJobsResponseB@Z>github.com/stuartshay/otel-worker/proto/distance/v1;distancev1b\x06proto3"
+	"\bListJobs\x12\x1c.distance.v1.ListJobsRequest\x1a\x1d.distance.v1.ListJobsResponse\x12_\n" +
+	"\x10GetBatteryReport\x12$.distance.v1.GetBatteryReportRequest\x1a%.distance.v1.GetBatteryReportResponse\x12b\n" +
+	"\x11GetDailySummaries\x12%.distance.v1.GetDailySummariesRequest\x1a&.distance.v1.GetDailySummariesResponse\x12q\n" +
+	"\x16BackfillDailySummaries\x12*.distance.v1.BackfillDailySummariesRequest\x1a+.distance.v1.BackfillDailySummariesResponse\x12k\n" +
+	"\x14ListGarminActivities\x12(.distance.v1.ListGarminActivitiesRequest\x1a).distance.v1.ListGarminActivitiesResponse\x12b\n" +
+	"\x11GetGarminActivity\x12%.distance.v1.GetGarminActivityRequest\x1a&.distance.v1.GetGarminActivityResponse\x12r\n" +
+	"\x19CalculateActivityDistance\x12-.distance.v1.CalculateActivityDistanceRequest\x1a&.distance.v1.CalculateDistanceResponse\x12U\n" +
+	"\x0fStreamLocations\x12#.distance.v1.StreamLocationsRequest\x1a\x1b.distance.v1.LocationRecord0\x01\x12b\n" +
+	"\x11FindLocationsNear\x12%.distance.v1.FindLocationsNearRequest\x1a&.distance.v1.FindLocationsNearResponse\x12S\n" +
+	"\fGetLiveState\x12 .distance.v1.GetLiveStateRequest\x1a!.distance.v1.GetLiveStateResponse\x12k\n" +
+	"\x14GetDataQualityReport\x12(.distance.v1.GetDataQualityReportRequest\x1a).distance.v1.GetDataQualityReportResponse\x12V\n" +
+	"\rListArtifacts\x12!.distance.v1.ListArtifactsRequest\x1a\".distance.v1.ListArtifactsResponse\x12P\n" +
+	"\vGetArtifact\x12\x1f.distance.v1.GetArtifactRequest\x1a .distance.v1.GetArtifactResponse\x12Y\n" +
+	"\x0eDeleteArtifact\x12\".distance.v1.DeleteArtifactRequest\x1a#.distance.v1.DeleteArtifactResponse\x12V\n" +
+	"\x10DownloadArtifact\x12$.distance.v1.DownloadArtifactRequest\x1a\x1a.distance.v1.ArtifactChunk0\x01B@Z>github.com/stuartshay/otel-worker/proto/distance/v1;distancev1b\x06proto3"
 
 var (
 	file_proto_distance_v1_distance_proto_rawDescOnce sync.Once
@@ -715,38 +4990,167 @@ func file_proto_distance_v1_distance_proto_rawDescGZIP() []byte {
 	return file_proto_distance_v1_distance_proto_rawDescData
 }
 
-var file_proto_distance_v1_distance_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_distance_v1_distance_proto_msgTypes = make([]protoimpl.MessageInfo, 57)
 var file_proto_distance_v1_distance_proto_goTypes = []any{
-	(*CalculateDistanceRequest)(nil),  // 0: distance.v1.CalculateDistanceRequest
-	(*CalculateDistanceResponse)(nil), // 1: distance.v1.CalculateDistanceResponse
-	(*GetJobStatusRequest)(nil),       // 2: distance.v1.GetJobStatusRequest
-	(*GetJobStatusResponse)(nil),      // 3: distance.v1.GetJobStatusResponse
-	(*ListJobsRequest)(nil),           // 4: distance.v1.ListJobsRequest
-	(*ListJobsResponse)(nil),          // 5: distance.v1.ListJobsResponse
-	(*JobSummary)(nil),                // 6: distance.v1.JobSummary
-	(*JobResult)(nil),                 // 7: distance.v1.JobResult
-	(*timestamp.Timestamp)(nil),       // 8: google.protobuf.Timestamp
+	(*CalculateDistanceRequest)(nil),         // 0: distance.v1.CalculateDistanceRequest
+	(*CsvTemplate)(nil),                      // 1: distance.v1.CsvTemplate
+	(*CalculateDistanceResponse)(nil),        // 2: distance.v1.CalculateDistanceResponse
+	(*GetJobStatusRequest)(nil),              // 3: distance.v1.GetJobStatusRequest
+	(*GetJobStatusResponse)(nil),             // 4: distance.v1.GetJobStatusResponse
+	(*ListJobsRequest)(nil),                  // 5: distance.v1.ListJobsRequest
+	(*ListJobsResponse)(nil),                 // 6: distance.v1.ListJobsResponse
+	(*JobSummary)(nil),                       // 7: distance.v1.JobSummary
+	(*JobResult)(nil),                        // 8: distance.v1.JobResult
+	(*Artifact)(nil),                         // 9: distance.v1.Artifact
+	(*ModeTotal)(nil),                        // 10: distance.v1.ModeTotal
+	(*ElevationStats)(nil),                   // 11: distance.v1.ElevationStats
+	(*TripElevation)(nil),                    // 12: distance.v1.TripElevation
+	(*ElevationSample)(nil),                  // 13: distance.v1.ElevationSample
+	(*GetBatteryReportRequest)(nil),          // 14: distance.v1.GetBatteryReportRequest
+	(*GetBatteryReportResponse)(nil),         // 15: distance.v1.GetBatteryReportResponse
+	(*DeviceBatteryReport)(nil),              // 16: distance.v1.DeviceBatteryReport
+	(*DailyBattery)(nil),                     // 17: distance.v1.DailyBattery
+	(*ChargingSession)(nil),                  // 18: distance.v1.ChargingSession
+	(*GetDailySummariesRequest)(nil),         // 19: distance.v1.GetDailySummariesRequest
+	(*GetDailySummariesResponse)(nil),        // 20: distance.v1.GetDailySummariesResponse
+	(*DailySummary)(nil),                     // 21: distance.v1.DailySummary
+	(*BackfillDailySummariesRequest)(nil),    // 22: distance.v1.BackfillDailySummariesRequest
+	(*BackfillDailySummariesResponse)(nil),   // 23: distance.v1.BackfillDailySummariesResponse
+	(*ListGarminActivitiesRequest)(nil),      // 24: distance.v1.ListGarminActivitiesRequest
+	(*ListGarminActivitiesResponse)(nil),     // 25: distance.v1.ListGarminActivitiesResponse
+	(*GarminActivity)(nil),                   // 26: distance.v1.GarminActivity
+	(*GetGarminActivityRequest)(nil),         // 27: distance.v1.GetGarminActivityRequest
+	(*GetGarminActivityResponse)(nil),        // 28: distance.v1.GetGarminActivityResponse
+	(*ActivityMetrics)(nil),                  // 29: distance.v1.ActivityMetrics
+	(*CalculateActivityDistanceRequest)(nil), // 30: distance.v1.CalculateActivityDistanceRequest
+	(*StreamLocationsRequest)(nil),           // 31: distance.v1.StreamLocationsRequest
+	(*LocationRecord)(nil),                   // 32: distance.v1.LocationRecord
+	(*LocationPayload)(nil),                  // 33: distance.v1.LocationPayload
+	(*FindLocationsNearRequest)(nil),         // 34: distance.v1.FindLocationsNearRequest
+	(*FindLocationsNearResponse)(nil),        // 35: distance.v1.FindLocationsNearResponse
+	(*NearbyLocation)(nil),                   // 36: distance.v1.NearbyLocation
+	(*NearbyVisit)(nil),                      // 37: distance.v1.NearbyVisit
+	(*GetLiveStateRequest)(nil),              // 38: distance.v1.GetLiveStateRequest
+	(*GetLiveStateResponse)(nil),             // 39: distance.v1.GetLiveStateResponse
+	(*DeviceLiveState)(nil),                  // 40: distance.v1.DeviceLiveState
+	(*LiveTrip)(nil),                         // 41: distance.v1.LiveTrip
+	(*GetDataQualityReportRequest)(nil),      // 42: distance.v1.GetDataQualityReportRequest
+	(*GetDataQualityReportResponse)(nil),     // 43: distance.v1.GetDataQualityReportResponse
+	(*DeviceDataQuality)(nil),                // 44: distance.v1.DeviceDataQuality
+	(*CoverageGap)(nil),                      // 45: distance.v1.CoverageGap
+	(*FieldMissingRate)(nil),                 // 46: distance.v1.FieldMissingRate
+	(*AccuracyDistribution)(nil),             // 47: distance.v1.AccuracyDistribution
+	(*AccuracyBucket)(nil),                   // 48: distance.v1.AccuracyBucket
+	(*ListArtifactsRequest)(nil),             // 49: distance.v1.ListArtifactsRequest
+	(*ListArtifactsResponse)(nil),            // 50: distance.v1.ListArtifactsResponse
+	(*GetArtifactRequest)(nil),               // 51: distance.v1.GetArtifactRequest
+	(*GetArtifactResponse)(nil),              // 52: distance.v1.GetArtifactResponse
+	(*DeleteArtifactRequest)(nil),            // 53: distance.v1.DeleteArtifactRequest
+	(*DeleteArtifactResponse)(nil),           // 54: distance.v1.DeleteArtifactResponse
+	(*DownloadArtifactRequest)(nil),          // 55: distance.v1.DownloadArtifactRequest
+	(*ArtifactChunk)(nil),                    // 56: distance.v1.ArtifactChunk
+	(*timestamp.Timestamp)(nil),              // 57: google.protobuf.Timestamp
 }
 var file_proto_distance_v1_distance_proto_depIdxs = []int32{
-	8,  // 0: distance.v1.CalculateDistanceResponse.queued_at:type_name -> google.protobuf.Timestamp
-	8,  // 1: distance.v1.GetJobStatusResponse.queued_at:type_name -> google.protobuf.Timestamp
-	8,  // 2: distance.v1.GetJobStatusResponse.started_at:type_name -> google.protobuf.Timestamp
-	8,  // 3: distance.v1.GetJobStatusResponse.completed_at:type_name -> google.protobuf.Timestamp
-	7,  // 4: distance.v1.GetJobStatusResponse.result:type_name -> distance.v1.JobResult
-	6,  // 5: distance.v1.ListJobsResponse.jobs:type_name -> distance.v1.JobSummary
-	8,  // 6: distance.v1.JobSummary.queued_at:type_name -> google.protobuf.Timestamp
-	8,  // 7: distance.v1.JobSummary.completed_at:type_name -> google.protobuf.Timestamp
-	0,  // 8: distance.v1.DistanceService.CalculateDistanceFromHome:input_type -> distance.v1.CalculateDistanceRequest
-	2,  // 9: distance.v1.DistanceService.GetJobStatus:input_type -> distance.v1.GetJobStatusRequest
-	4,  // 10: distance.v1.DistanceService.ListJobs:input_type -> distance.v1.ListJobsRequest
-	1,  // 11: distance.v1.DistanceService.CalculateDistanceFromHome:output_type -> distance.v1.CalculateDistanceResponse
-	3,  // 12: distance.v1.DistanceService.GetJobStatus:output_type -> distance.v1.GetJobStatusResponse
-	5,  // 13: distance.v1.DistanceService.ListJobs:output_type -> distance.v1.ListJobsResponse
-	11, // [11:14] is the sub-list for method output_type
-	8,  // [8:11] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	1,  // 0: distance.v1.CalculateDistanceRequest.csv_template:type_name -> distance.v1.CsvTemplate
+	57, // 1: distance.v1.CalculateDistanceResponse.queued_at:type_name -> google.protobuf.Timestamp
+	57, // 2: distance.v1.GetJobStatusResponse.queued_at:type_name -> google.protobuf.Timestamp
+	57, // 3: distance.v1.GetJobStatusResponse.started_at:type_name -> google.protobuf.Timestamp
+	57, // 4: distance.v1.GetJobStatusResponse.completed_at:type_name -> google.protobuf.Timestamp
+	8,  // 5: distance.v1.GetJobStatusResponse.result:type_name -> distance.v1.JobResult
+	7,  // 6: distance.v1.ListJobsResponse.jobs:type_name -> distance.v1.JobSummary
+	57, // 7: distance.v1.JobSummary.queued_at:type_name -> google.protobuf.Timestamp
+	57, // 8: distance.v1.JobSummary.completed_at:type_name -> google.protobuf.Timestamp
+	10, // 9: distance.v1.JobResult.mode_totals:type_name -> distance.v1.ModeTotal
+	11, // 10: distance.v1.JobResult.elevation:type_name -> distance.v1.ElevationStats
+	12, // 11: distance.v1.JobResult.trip_elevations:type_name -> distance.v1.TripElevation
+	29, // 12: distance.v1.JobResult.activity:type_name -> distance.v1.ActivityMetrics
+	9,  // 13: distance.v1.JobResult.artifacts:type_name -> distance.v1.Artifact
+	57, // 14: distance.v1.JobResult.download_expires_at:type_name -> google.protobuf.Timestamp
+	57, // 15: distance.v1.Artifact.created_at:type_name -> google.protobuf.Timestamp
+	57, // 16: distance.v1.TripElevation.start_time:type_name -> google.protobuf.Timestamp
+	57, // 17: distance.v1.TripElevation.end_time:type_name -> google.protobuf.Timestamp
+	11, // 18: distance.v1.TripElevation.elevation:type_name -> distance.v1.ElevationStats
+	13, // 19: distance.v1.TripElevation.profile:type_name -> distance.v1.ElevationSample
+	16, // 20: distance.v1.GetBatteryReportResponse.devices:type_name -> distance.v1.DeviceBatteryReport
+	17, // 21: distance.v1.DeviceBatteryReport.days:type_name -> distance.v1.DailyBattery
+	18, // 22: distance.v1.DeviceBatteryReport.charging_sessions:type_name -> distance.v1.ChargingSession
+	57, // 23: distance.v1.ChargingSession.start_time:type_name -> google.protobuf.Timestamp
+	57, // 24: distance.v1.ChargingSession.end_time:type_name -> google.protobuf.Timestamp
+	21, // 25: distance.v1.GetDailySummariesResponse.summaries:type_name -> distance.v1.DailySummary
+	57, // 26: distance.v1.DailySummary.updated_at:type_name -> google.protobuf.Timestamp
+	57, // 27: distance.v1.BackfillDailySummariesResponse.queued_at:type_name -> google.protobuf.Timestamp
+	26, // 28: distance.v1.ListGarminActivitiesResponse.activities:type_name -> distance.v1.GarminActivity
+	57, // 29: distance.v1.GarminActivity.start_time:type_name -> google.protobuf.Timestamp
+	57, // 30: distance.v1.GarminActivity.end_time:type_name -> google.protobuf.Timestamp
+	26, // 31: distance.v1.GetGarminActivityResponse.activity:type_name -> distance.v1.GarminActivity
+	29, // 32: distance.v1.GetGarminActivityResponse.metrics:type_name -> distance.v1.ActivityMetrics
+	11, // 33: distance.v1.ActivityMetrics.elevation:type_name -> distance.v1.ElevationStats
+	57, // 34: distance.v1.LocationRecord.timestamp:type_name -> google.protobuf.Timestamp
+	57, // 35: distance.v1.LocationRecord.created_at:type_name -> google.protobuf.Timestamp
+	33, // 36: distance.v1.LocationRecord.payload:type_name -> distance.v1.LocationPayload
+	36, // 37: distance.v1.FindLocationsNearResponse.locations:type_name -> distance.v1.NearbyLocation
+	37, // 38: distance.v1.FindLocationsNearResponse.visits:type_name -> distance.v1.NearbyVisit
+	32, // 39: distance.v1.NearbyLocation.location:type_name -> distance.v1.LocationRecord
+	57, // 40: distance.v1.NearbyVisit.start_time:type_name -> google.protobuf.Timestamp
+	57, // 41: distance.v1.NearbyVisit.end_time:type_name -> google.protobuf.Timestamp
+	40, // 42: distance.v1.GetLiveStateResponse.devices:type_name -> distance.v1.DeviceLiveState
+	57, // 43: distance.v1.GetLiveStateResponse.updated_at:type_name -> google.protobuf.Timestamp
+	21, // 44: distance.v1.DeviceLiveState.summary:type_name -> distance.v1.DailySummary
+	57, // 45: distance.v1.DeviceLiveState.last_fix_time:type_name -> google.protobuf.Timestamp
+	41, // 46: distance.v1.DeviceLiveState.current_trip:type_name -> distance.v1.LiveTrip
+	57, // 47: distance.v1.LiveTrip.start_time:type_name -> google.protobuf.Timestamp
+	57, // 48: distance.v1.LiveTrip.last_move_time:type_name -> google.protobuf.Timestamp
+	44, // 49: distance.v1.GetDataQualityReportResponse.devices:type_name -> distance.v1.DeviceDataQuality
+	57, // 50: distance.v1.DeviceDataQuality.first_fix_time:type_name -> google.protobuf.Timestamp
+	57, // 51: distance.v1.DeviceDataQuality.last_fix_time:type_name -> google.protobuf.Timestamp
+	45, // 52: distance.v1.DeviceDataQuality.gaps:type_name -> distance.v1.CoverageGap
+	46, // 53: distance.v1.DeviceDataQuality.missing_fields:type_name -> distance.v1.FieldMissingRate
+	47, // 54: distance.v1.DeviceDataQuality.accuracy:type_name -> distance.v1.AccuracyDistribution
+	57, // 55: distance.v1.CoverageGap.start_time:type_name -> google.protobuf.Timestamp
+	57, // 56: distance.v1.CoverageGap.end_time:type_name -> google.protobuf.Timestamp
+	48, // 57: distance.v1.AccuracyDistribution.buckets:type_name -> distance.v1.AccuracyBucket
+	9,  // 58: distance.v1.ListArtifactsResponse.artifacts:type_name -> distance.v1.Artifact
+	9,  // 59: distance.v1.GetArtifactResponse.artifact:type_name -> distance.v1.Artifact
+	0,  // 60: distance.v1.DistanceService.CalculateDistanceFromHome:input_type -> distance.v1.CalculateDistanceRequest
+	3,  // 61: distance.v1.DistanceService.GetJobStatus:input_type -> distance.v1.GetJobStatusRequest
+	5,  // 62: distance.v1.DistanceService.ListJobs:input_type -> distance.v1.ListJobsRequest
+	14, // 63: distance.v1.DistanceService.GetBatteryReport:input_type -> distance.v1.GetBatteryReportRequest
+	19, // 64: distance.v1.DistanceService.GetDailySummaries:input_type -> distance.v1.GetDailySummariesRequest
+	22, // 65: distance.v1.DistanceService.BackfillDailySummaries:input_type -> distance.v1.BackfillDailySummariesRequest
+	24, // 66: distance.v1.DistanceService.ListGarminActivities:input_type -> distance.v1.ListGarminActivitiesRequest
+	27, // 67: distance.v1.DistanceService.GetGarminActivity:input_type -> distance.v1.GetGarminActivityRequest
+	30, // 68: distance.v1.DistanceService.CalculateActivityDistance:input_type -> distance.v1.CalculateActivityDistanceRequest
+	31, // 69: distance.v1.DistanceService.StreamLocations:input_type -> distance.v1.StreamLocationsRequest
+	34, // 70: distance.v1.DistanceService.FindLocationsNear:input_type -> distance.v1.FindLocationsNearRequest
+	38, // 71: distance.v1.DistanceService.GetLiveState:input_type -> distance.v1.GetLiveStateRequest
+	42, // 72: distance.v1.DistanceService.GetDataQualityReport:input_type -> distance.v1.GetDataQualityReportRequest
+	49, // 73: distance.v1.DistanceService.ListArtifacts:input_type -> distance.v1.ListArtifactsRequest
+	51, // 74: distance.v1.DistanceService.GetArtifact:input_type -> distance.v1.GetArtifactRequest
+	53, // 75: distance.v1.DistanceService.DeleteArtifact:input_type -> distance.v1.DeleteArtifactRequest
+	55, // 76: distance.v1.DistanceService.DownloadArtifact:input_type -> distance.v1.DownloadArtifactRequest
+	2,  // 77: distance.v1.DistanceService.CalculateDistanceFromHome:output_type -> distance.v1.CalculateDistanceResponse
+	4,  // 78: distance.v1.DistanceService.GetJobStatus:output_type -> distance.v1.GetJobStatusResponse
+	6,  // 79: distance.v1.DistanceService.ListJobs:output_type -> distance.v1.ListJobsResponse
+	15, // 80: distance.v1.DistanceService.GetBatteryReport:output_type -> distance.v1.GetBatteryReportResponse
+	20, // 81: distance.v1.DistanceService.GetDailySummaries:output_type -> distance.v1.GetDailySummariesResponse
+	23, // 82: distance.v1.DistanceService.BackfillDailySummaries:output_type -> distance.v1.BackfillDailySummariesResponse
+	25, // 83: distance.v1.DistanceService.ListGarminActivities:output_type -> distance.v1.ListGarminActivitiesResponse
+	28, // 84: distance.v1.DistanceService.GetGarminActivity:output_type -> distance.v1.GetGarminActivityResponse
+	2,  // 85: distance.v1.DistanceService.CalculateActivityDistance:output_type -> distance.v1.CalculateDistanceResponse
+	32, // 86: distance.v1.DistanceService.StreamLocations:output_type -> distance.v1.LocationRecord
+	35, // 87: distance.v1.DistanceService.FindLocationsNear:output_type -> distance.v1.FindLocationsNearResponse
+	39, // 88: distance.v1.DistanceService.GetLiveState:output_type -> distance.v1.GetLiveStateResponse
+	43, // 89: distance.v1.DistanceService.GetDataQualityReport:output_type -> distance.v1.GetDataQualityReportResponse
+	50, // 90: distance.v1.DistanceService.ListArtifacts:output_type -> distance.v1.ListArtifactsResponse
+	52, // 91: distance.v1.DistanceService.GetArtifact:output_type -> distance.v1.GetArtifactResponse
+	54, // 92: distance.v1.DistanceService.DeleteArtifact:output_type -> distance.v1.DeleteArtifactResponse
+	56, // 93: distance.v1.DistanceService.DownloadArtifact:output_type -> distance.v1.ArtifactChunk
+	77, // [77:94] is the sub-list for method output_type
+	60, // [60:77] is the sub-list for method input_type
+	60, // [60:60] is the sub-list for extension type_name
+	60, // [60:60] is the sub-list for extension extendee
+	0,  // [0:60] is the sub-list for field type_name
 }
 
 func init() { file_proto_distance_v1_distance_proto_init() }
@@ -754,13 +5158,15 @@ func file_proto_distance_v1_distance_proto_init() {
 	if File_proto_distance_v1_distance_proto != nil {
 		return
 	}
+	file_proto_distance_v1_distance_proto_msgTypes[1].OneofWrappers = []any{}
+	file_proto_distance_v1_distance_proto_msgTypes[33].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_distance_v1_distance_proto_rawDesc), len(file_proto_distance_v1_distance_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   57,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,6 +23,20 @@ const (
 	DistanceService_CalculateDistanceFromHome_FullMethodName = "/distance.v1.DistanceService/CalculateDistanceFromHome"
 	DistanceService_GetJobStatus_FullMethodName              = "/distance.v1.DistanceService/GetJobStatus"
 	DistanceService_ListJobs_FullMethodName                  = "/distance.v1.DistanceService/ListJobs"
+	DistanceService_GetBatteryReport_FullMethodName          = "/distance.v1.DistanceService/GetBatteryReport"
+	DistanceService_GetDailySummaries_FullMethodName         = "/distance.v1.DistanceService/GetDailySummaries"
+	DistanceService_BackfillDailySummaries_FullMethodName    = "/distance.v1.DistanceService/BackfillDailySummaries"
+	DistanceService_ListGarminActivities_FullMethodName      = "/distance.v1.DistanceService/ListGarminActivities"
+	DistanceService_GetGarminActivity_FullMethodName         = "/distance.v1.DistanceService/GetGarminActivity"
+	DistanceService_CalculateActivityDistance_FullMethodName = "/distance.v1.DistanceService/CalculateActivityDistance"
+	DistanceService_StreamLocations_FullMethodName           = "/distance.v1.DistanceService/StreamLocations"
+	DistanceService_FindLocationsNear_FullMethodName         = "/distance.v1.DistanceService/FindLocationsNear"
+	DistanceService_GetLiveState_FullMethodName              = "/distance.v1.DistanceService/GetLiveState"
+	DistanceService_GetDataQualityReport_FullMethodName      = "/distance.v1.DistanceService/GetDataQualityReport"
+	DistanceService_ListArtifacts_FullMethodName             = "/distance.v1.DistanceService/ListArtifacts"
+	DistanceService_GetArtifact_FullMethodName               = "/distance.v1.DistanceService/GetArtifact"
+	DistanceService_DeleteArtifact_FullMethodName            = "/distance.v1.DistanceService/DeleteArtifact"
+	DistanceService_DownloadArtifact_FullMethodName          = "/distance.v1.DistanceService/DownloadArtifact"
 )
 
 // DistanceServiceClient is the client API for DistanceService service.
@@ -41,6 +55,51 @@ type DistanceServiceClient interface {
 	GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*GetJobStatusResponse, error)
 	// ListJobs returns a list of distance calculation jobs with optional filtering.
 	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	// GetBatteryReport analyses battery drain, charging sessions and the
+	// correlation between distance travelled and drain for a date range.
+	GetBatteryReport(ctx context.Context, in *GetBatteryReportRequest, opts ...grpc.CallOption) (*GetBatteryReportResponse, error)
+	// GetDailySummaries returns per-device daily aggregates for a date range
+	// from the daily_distance_summary table without scanning raw locations.
+	GetDailySummaries(ctx context.Context, in *GetDailySummariesRequest, opts ...grpc.CallOption) (*GetDailySummariesResponse, error)
+	// BackfillDailySummaries initiates an async job that recomputes daily
+	// aggregates from raw locations for every day in a date range. Existing
+	// aggregates in the range are replaced, and removed for devices that no
+	// longer have locations on a day.
+	BackfillDailySummaries(ctx context.Context, in *BackfillDailySummariesRequest, opts ...grpc.CallOption) (*BackfillDailySummariesResponse, error)
+	// ListGarminActivities returns Garmin Connect activities that started
+	// within a date range, optionally filtered by sport.
+	ListGarminActivities(ctx context.Context, in *ListGarminActivitiesRequest, opts ...grpc.CallOption) (*ListGarminActivitiesResponse, error)
+	// GetGarminActivity returns an activity with distance-from-home, path and
+	// sensor metrics computed from its track points.
+	GetGarminActivity(ctx context.Context, in *GetGarminActivityRequest, opts ...grpc.CallOption) (*GetGarminActivityResponse, error)
+	// CalculateActivityDistance initiates an async job that computes activity
+	// metrics and writes a CSV of its track points with heart rate, cadence
+	// and speed columns. Poll GetJobStatus for the result.
+	CalculateActivityDistance(ctx context.Context, in *CalculateActivityDistanceRequest, opts ...grpc.CallOption) (*CalculateDistanceResponse, error)
+	// StreamLocations streams the OwnTracks locations in a date range in
+	// created_at order, optionally with fields decoded from raw_payload.
+	StreamLocations(ctx context.Context, in *StreamLocationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LocationRecord], error)
+	// FindLocationsNear returns the locations within a radius of a point over
+	// a date range, and the visits they form.
+	FindLocationsNear(ctx context.Context, in *FindLocationsNearRequest, opts ...grpc.CallOption) (*FindLocationsNearResponse, error)
+	// GetLiveState returns today's running aggregates and trip state of each
+	// device, kept current by incremental mode (INCREMENTAL_MODE).
+	GetLiveState(ctx context.Context, in *GetLiveStateRequest, opts ...grpc.CallOption) (*GetLiveStateResponse, error)
+	// GetDataQualityReport reports coverage gaps, duplicate and out-of-order
+	// fixes, missing fields and accuracy for each device over a date range.
+	GetDataQualityReport(ctx context.Context, in *GetDataQualityReportRequest, opts ...grpc.CallOption) (*GetDataQualityReportResponse, error)
+	// ListArtifacts returns the stored output files of jobs, newest first,
+	// optionally filtered by job, date, device and format. Files written or
+	// deleted by other replicas are listed within 30 seconds.
+	ListArtifacts(ctx context.Context, in *ListArtifactsRequest, opts ...grpc.CallOption) (*ListArtifactsResponse, error)
+	// GetArtifact returns the details of one stored output file.
+	GetArtifact(ctx context.Context, in *GetArtifactRequest, opts ...grpc.CallOption) (*GetArtifactResponse, error)
+	// DeleteArtifact removes a stored output file and its compressed variants.
+	DeleteArtifact(ctx context.Context, in *DeleteArtifactRequest, opts ...grpc.CallOption) (*DeleteArtifactResponse, error)
+	// DownloadArtifact streams a stored output file in chunks from offset,
+	// so an interrupted download resumes where it stopped. The trailer
+	// carries the file's hex SHA-256 checksum as x-checksum-sha256.
+	DownloadArtifact(ctx context.Context, in *DownloadArtifactRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArtifactChunk], error)
 }
 
 type distanceServiceClient struct {
@@ -81,6 +140,164 @@ func (c *distanceServiceClient) ListJobs(ctx context.Context, in *ListJobsReques
 	return out, nil
 }
 
+func (c *distanceServiceClient) GetBatteryReport(ctx context.Context, in *GetBatteryReportRequest, opts ...grpc.CallOption) (*GetBatteryReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBatteryReportResponse)
+	err := c.cc.Invoke(ctx, DistanceService_GetBatteryReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) GetDailySummaries(ctx context.Context, in *GetDailySummariesRequest, opts ...grpc.CallOption) (*GetDailySummariesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDailySummariesResponse)
+	err := c.cc.Invoke(ctx, DistanceService_GetDailySummaries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) BackfillDailySummaries(ctx context.Context, in *BackfillDailySummariesRequest, opts ...grpc.CallOption) (*BackfillDailySummariesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackfillDailySummariesResponse)
+	err := c.cc.Invoke(ctx, DistanceService_BackfillDailySummaries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) ListGarminActivities(ctx context.Context, in *ListGarminActivitiesRequest, opts ...grpc.CallOption) (*ListGarminActivitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGarminActivitiesResponse)
+	err := c.cc.Invoke(ctx, DistanceService_ListGarminActivities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) GetGarminActivity(ctx context.Context, in *GetGarminActivityRequest, opts ...grpc.CallOption) (*GetGarminActivityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetGarminActivityResponse)
+	err := c.cc.Invoke(ctx, DistanceService_GetGarminActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) CalculateActivityDistance(ctx context.Context, in *CalculateActivityDistanceRequest, opts ...grpc.CallOption) (*CalculateDistanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CalculateDistanceResponse)
+	err := c.cc.Invoke(ctx, DistanceService_CalculateActivityDistance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) StreamLocations(ctx context.Context, in *StreamLocationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LocationRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DistanceService_ServiceDesc.Streams[0], DistanceService_StreamLocations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamLocationsRequest, LocationRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DistanceService_StreamLocationsClient = grpc.ServerStreamingClient[LocationRecord]
+
+func (c *distanceServiceClient) FindLocationsNear(ctx context.Context, in *FindLocationsNearRequest, opts ...grpc.CallOption) (*FindLocationsNearResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindLocationsNearResponse)
+	err := c.cc.Invoke(ctx, DistanceService_FindLocationsNear_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) GetLiveState(ctx context.Context, in *GetLiveStateRequest, opts ...grpc.CallOption) (*GetLiveStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLiveStateResponse)
+	err := c.cc.Invoke(ctx, DistanceService_GetLiveState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) GetDataQualityReport(ctx context.Context, in *GetDataQualityReportRequest, opts ...grpc.CallOption) (*GetDataQualityReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDataQualityReportResponse)
+	err := c.cc.Invoke(ctx, DistanceService_GetDataQualityReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) ListArtifacts(ctx context.Context, in *ListArtifactsRequest, opts ...grpc.CallOption) (*ListArtifactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListArtifactsResponse)
+	err := c.cc.Invoke(ctx, DistanceService_ListArtifacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) GetArtifact(ctx context.Context, in *GetArtifactRequest, opts ...grpc.CallOption) (*GetArtifactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetArtifactResponse)
+	err := c.cc.Invoke(ctx, DistanceService_GetArtifact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) DeleteArtifact(ctx context.Context, in *DeleteArtifactRequest, opts ...grpc.CallOption) (*DeleteArtifactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteArtifactResponse)
+	err := c.cc.Invoke(ctx, DistanceService_DeleteArtifact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *distanceServiceClient) DownloadArtifact(ctx context.Context, in *DownloadArtifactRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArtifactChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DistanceService_ServiceDesc.Streams[1], DistanceService_DownloadArtifact_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadArtifactRequest, ArtifactChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DistanceService_DownloadArtifactClient = grpc.ServerStreamingClient[ArtifactChunk]
+
 // DistanceServiceServer is the server API for DistanceService service.
 // All implementations must embed UnimplementedDistanceServiceServer
 // for forward compatibility.
@@ -97,6 +314,51 @@ type DistanceServiceServer interface {
 	GetJobStatus(context.Context, *GetJobStatusRequest) (*GetJobStatusResponse, error)
 	// ListJobs returns a list of distance calculation jobs with optional filtering.
 	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	// GetBatteryReport analyses battery drain, charging sessions and the
+	// correlation between distance travelled and drain for a date range.
+	GetBatteryReport(context.Context, *GetBatteryReportRequest) (*GetBatteryReportResponse, error)
+	// GetDailySummaries returns per-device daily aggregates for a date range
+	// from the daily_distance_summary table without scanning raw locations.
+	GetDailySummaries(context.Context, *GetDailySummariesRequest) (*GetDailySummariesResponse, error)
+	// BackfillDailySummaries initiates an async job that recomputes daily
+	// aggregates from raw locations for every day in a date range. Existing
+	// aggregates in the range are replaced, and removed for devices that no
+	// longer have locations on a day.
+	BackfillDailySummaries(context.Context, *BackfillDailySummariesRequest) (*BackfillDailySummariesResponse, error)
+	// ListGarminActivities returns Garmin Connect activities that started
+	// within a date range, optionally filtered by sport.
+	ListGarminActivities(context.Context, *ListGarminActivitiesRequest) (*ListGarminActivitiesResponse, error)
+	// GetGarminActivity returns an activity with distance-from-home, path and
+	// sensor metrics computed from its track points.
+	GetGarminActivity(context.Context, *GetGarminActivityRequest) (*GetGarminActivityResponse, error)
+	// CalculateActivityDistance initiates an async job that computes activity
+	// metrics and writes a CSV of its track points with heart rate, cadence
+	// and speed columns. Poll GetJobStatus for the result.
+	CalculateActivityDistance(context.Context, *CalculateActivityDistanceRequest) (*CalculateDistanceResponse, error)
+	// StreamLocations streams the OwnTracks locations in a date range in
+	// created_at order, optionally with fields decoded from raw_payload.
+	StreamLocations(*StreamLocationsRequest, grpc.ServerStreamingServer[LocationRecord]) error
+	// FindLocationsNear returns the locations within a radius of a point over
+	// a date range, and the visits they form.
+	FindLocationsNear(context.Context, *FindLocationsNearRequest) (*FindLocationsNearResponse, error)
+	// GetLiveState returns today's running aggregates and trip state of each
+	// device, kept current by incremental mode (INCREMENTAL_MODE).
+	GetLiveState(context.Context, *GetLiveStateRequest) (*GetLiveStateResponse, error)
+	// GetDataQualityReport reports coverage gaps, duplicate and out-of-order
+	// fixes, missing fields and accuracy for each device over a date range.
+	GetDataQualityReport(context.Context, *GetDataQualityReportRequest) (*GetDataQualityReportResponse, error)
+	// ListArtifacts returns the stored output files of jobs, newest first,
+	// optionally filtered by job, date, device and format. Files written or
+	// deleted by other replicas are listed within 30 seconds.
+	ListArtifacts(context.Context, *ListArtifactsRequest) (*ListArtifactsResponse, error)
+	// GetArtifact returns the details of one stored output file.
+	GetArtifact(context.Context, *GetArtifactRequest) (*GetArtifactResponse, error)
+	// DeleteArtifact removes a stored output file and its compressed variants.
+	DeleteArtifact(context.Context, *DeleteArtifactRequest) (*DeleteArtifactResponse, error)
+	// DownloadArtifact streams a stored output file in chunks from offset,
+	// so an interrupted download resumes where it stopped. The trailer
+	// carries the file's hex SHA-256 checksum as x-checksum-sha256.
+	DownloadArtifact(*DownloadArtifactRequest, grpc.ServerStreamingServer[ArtifactChunk]) error
 	mustEmbedUnimplementedDistanceServiceServer()
 }
 
@@ -116,6 +378,48 @@ func (UnimplementedDistanceServiceServer) GetJobStatus(context.Context, *GetJobS
 func (UnimplementedDistanceServiceServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListJobs not implemented")
 }
+func (UnimplementedDistanceServiceServer) GetBatteryReport(context.Context, *GetBatteryReportRequest) (*GetBatteryReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBatteryReport not implemented")
+}
+func (UnimplementedDistanceServiceServer) GetDailySummaries(context.Context, *GetDailySummariesRequest) (*GetDailySummariesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDailySummaries not implemented")
+}
+func (UnimplementedDistanceServiceServer) BackfillDailySummaries(context.Context, *BackfillDailySummariesRequest) (*BackfillDailySummariesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BackfillDailySummaries not implemented")
+}
+func (UnimplementedDistanceServiceServer) ListGarminActivities(context.Context, *ListGarminActivitiesRequest) (*ListGarminActivitiesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListGarminActivities not implemented")
+}
+func (UnimplementedDistanceServiceServer) GetGarminActivity(context.Context, *GetGarminActivityRequest) (*GetGarminActivityResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetGarminActivity not implemented")
+}
+func (UnimplementedDistanceServiceServer) CalculateActivityDistance(context.Context, *CalculateActivityDistanceRequest) (*CalculateDistanceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CalculateActivityDistance not implemented")
+}
+func (UnimplementedDistanceServiceServer) StreamLocations(*StreamLocationsRequest, grpc.ServerStreamingServer[LocationRecord]) error {
+	return status.Error(codes.Unimplemented, "method StreamLocations not implemented")
+}
+func (UnimplementedDistanceServiceServer) FindLocationsNear(context.Context, *FindLocationsNearRequest) (*FindLocationsNearResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FindLocationsNear not implemented")
+}
+func (UnimplementedDistanceServiceServer) GetLiveState(context.Context, *GetLiveStateRequest) (*GetLiveStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLiveState not implemented")
+}
+func (UnimplementedDistanceServiceServer) GetDataQualityReport(context.Context, *GetDataQualityReportRequest) (*GetDataQualityReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDataQualityReport not implemented")
+}
+func (UnimplementedDistanceServiceServer) ListArtifacts(context.Context, *ListArtifactsRequest) (*ListArtifactsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListArtifacts not implemented")
+}
+func (UnimplementedDistanceServiceServer) GetArtifact(context.Context, *GetArtifactRequest) (*GetArtifactResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetArtifact not implemented")
+}
+func (UnimplementedDistanceServiceServer) DeleteArtifact(context.Context, *DeleteArtifactRequest) (*DeleteArtifactResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteArtifact not implemented")
+}
+func (UnimplementedDistanceServiceServer) DownloadArtifact(*DownloadArtifactRequest, grpc.ServerStreamingServer[ArtifactChunk]) error {
+	return status.Error(codes.Unimplemented, "method DownloadArtifact not implemented")
+}
 func (UnimplementedDistanceServiceServer) mustEmbedUnimplementedDistanceServiceServer() {}
 func (UnimplementedDistanceServiceServer) testEmbeddedByValue()                         {}
 
@@ -191,6 +495,244 @@ func _DistanceService_ListJobs_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DistanceService_GetBatteryReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBatteryReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).GetBatteryReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_GetBatteryReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).GetBatteryReport(ctx, req.(*GetBatteryReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_GetDailySummaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDailySummariesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).GetDailySummaries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_GetDailySummaries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).GetDailySummaries(ctx, req.(*GetDailySummariesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_BackfillDailySummaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackfillDailySummariesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).BackfillDailySummaries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_BackfillDailySummaries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).BackfillDailySummaries(ctx, req.(*BackfillDailySummariesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_ListGarminActivities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGarminActivitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).ListGarminActivities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_ListGarminActivities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).ListGarminActivities(ctx, req.(*ListGarminActivitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_GetGarminActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGarminActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).GetGarminActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_GetGarminActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).GetGarminActivity(ctx, req.(*GetGarminActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_CalculateActivityDistance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalculateActivityDistanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).CalculateActivityDistance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_CalculateActivityDistance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).CalculateActivityDistance(ctx, req.(*CalculateActivityDistanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_StreamLocations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamLocationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DistanceServiceServer).StreamLocations(m, &grpc.GenericServerStream[StreamLocationsRequest, LocationRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DistanceService_StreamLocationsServer = grpc.ServerStreamingServer[LocationRecord]
+
+func _DistanceService_FindLocationsNear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindLocationsNearRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).FindLocationsNear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_FindLocationsNear_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).FindLocationsNear(ctx, req.(*FindLocationsNearRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_GetLiveState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLiveStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).GetLiveState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_GetLiveState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).GetLiveState(ctx, req.(*GetLiveStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_GetDataQualityReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDataQualityReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).GetDataQualityReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_GetDataQualityReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).GetDataQualityReport(ctx, req.(*GetDataQualityReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_ListArtifacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArtifactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).ListArtifacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_ListArtifacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).ListArtifacts(ctx, req.(*ListArtifactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_GetArtifact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArtifactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).GetArtifact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_GetArtifact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).GetArtifact(ctx, req.(*GetArtifactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_DeleteArtifact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteArtifactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DistanceServiceServer).DeleteArtifact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DistanceService_DeleteArtifact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DistanceServiceServer).DeleteArtifact(ctx, req.(*DeleteArtifactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DistanceService_DownloadArtifact_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadArtifactRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DistanceServiceServer).DownloadArtifact(m, &grpc.GenericServerStream[DownloadArtifactRequest, ArtifactChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DistanceService_DownloadArtifactServer = grpc.ServerStreamingServer[ArtifactChunk]
+
 // DistanceService_ServiceDesc is the grpc.ServiceDesc for DistanceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -210,7 +752,66 @@ var DistanceService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListJobs",
 			Handler:    _DistanceService_ListJobs_Handler,
 		},
+		{
+			MethodName: "GetBatteryReport",
+			Handler:    _DistanceService_GetBatteryReport_Handler,
+		},
+		{
+			MethodName: "GetDailySummaries",
+			Handler:    _DistanceService_GetDailySummaries_Handler,
+		},
+		{
+			MethodName: "BackfillDailySummaries",
+			Handler:    _DistanceService_BackfillDailySummaries_Handler,
+		},
+		{
+			MethodName: "ListGarminActivities",
+			Handler:    _DistanceService_ListGarminActivities_Handler,
+		},
+		{
+			MethodName: "GetGarminActivity",
+			Handler:    _DistanceService_GetGarminActivity_Handler,
+		},
+		{
+			MethodName: "CalculateActivityDistance",
+			Handler:    _DistanceService_CalculateActivityDistance_Handler,
+		},
+		{
+			MethodName: "FindLocationsNear",
+			Handler:    _DistanceService_FindLocationsNear_Handler,
+		},
+		{
+			MethodName: "GetLiveState",
+			Handler:    _DistanceService_GetLiveState_Handler,
+		},
+		{
+			MethodName: "GetDataQualityReport",
+			Handler:    _DistanceService_GetDataQualityReport_Handler,
+		},
+		{
+			MethodName: "ListArtifacts",
+			Handler:    _DistanceService_ListArtifacts_Handler,
+		},
+		{
+			MethodName: "GetArtifact",
+			Handler:    _DistanceService_GetArtifact_Handler,
+		},
+		{
+			MethodName: "DeleteArtifact",
+			Handler:    _DistanceService_DeleteArtifact_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLocations",
+			Handler:       _DistanceService_StreamLocations_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadArtifact",
+			Handler:       _DistanceService_DownloadArtifact_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/distance/v1/distance.proto",
 }
@@ -1,5 +1,7 @@
 -- Verify Database Schema vs Go Struct Contract
 -- This query shows actual database types and sample data
+-- The same contract is checked automatically by the /readyz probe
+-- (see internal/database/schema.go)
 
 SELECT
     column_name,